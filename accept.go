@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// PeerInfo is information about the peer of a connection accepted by [AcceptLoop].
+type PeerInfo struct {
+	// Addr is the remote address of the connection.
+	Addr net.Addr
+
+	// Creds are credentials of the peer process, as returned by
+	// [PeerCredentials]. This is nil if connection is not a unix socket
+	// connection or if credentials could not be determined.
+	Creds *Creds
+}
+
+// AcceptLoop accepts connections on the listener and calls handler for each
+// of them in a new goroutine, with a context which is canceled when handler
+// returns or when the loop stops. Connection is closed after handler returns.
+// For unix socket connections, peer credentials are populated in [PeerInfo].
+//
+// Temporary accept errors, like running out of file descriptors, are retried
+// with exponential backoff from 5ms up to 1s. AcceptLoop blocks until ctx is
+// done or the listener fails with a non-temporary error. Listener is closed
+// before returning, and AcceptLoop waits for all handlers to return.
+// Returned error is nil if ctx is done.
+//
+//   - [syscall.EINVAL] is returned if listener or handler is nil.
+func AcceptLoop(ctx context.Context, l net.Listener, handler func(context.Context, net.Conn, *PeerInfo)) error {
+	if l == nil || handler == nil {
+		return fmt.Errorf("launchd: listener and handler must not be nil: %w", syscall.EINVAL)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	stop := context.AfterFunc(ctx, func() {
+		l.Close()
+	})
+	defer stop()
+
+	for {
+		conn, err := accept(ctx, l)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			l.Close()
+			return fmt.Errorf("launchd: accept error: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+
+			cctx, ccancel := context.WithCancel(ctx)
+			defer ccancel()
+			handler(cctx, conn, newPeerInfo(conn))
+		}()
+	}
+}
+
+// accept accepts a connection on the listener, retrying temporary errors
+// with exponential backoff from 5ms up to 1s, until ctx is done.
+func accept(ctx context.Context, l net.Listener) (net.Conn, error) {
+	var delay time.Duration
+	for {
+		conn, err := l.Accept()
+		if err == nil {
+			return conn, nil
+		}
+
+		var ne net.Error
+		//nolint:staticcheck // Temporary is the only way to detect EMFILE and friends.
+		if ctx.Err() != nil || !errors.As(err, &ne) || !ne.Temporary() {
+			return nil, err
+		}
+
+		delay = min(max(2*delay, 5*time.Millisecond), time.Second)
+		debug("launchd: accept error, retrying",
+			slog.String("addr", l.Addr().String()),
+			slog.Duration("delay", delay),
+			slog.Any("err", err),
+		)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// newPeerInfo returns [PeerInfo] of the accepted connection.
+func newPeerInfo(conn net.Conn) *PeerInfo {
+	peer := &PeerInfo{Addr: conn.RemoteAddr()}
+	if _, ok := conn.(*net.UnixConn); ok {
+		creds, err := PeerCredentials(conn)
+		if err != nil {
+			debug("launchd: failed to get peer credentials", slog.Any("err", err))
+		} else {
+			peer.Creds = creds
+		}
+	}
+	return peer
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestAcceptLoop(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peers := make(chan *PeerInfo, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- AcceptLoop(ctx, l, func(ctx context.Context, conn net.Conn, peer *PeerInfo) {
+			peers <- peer
+			<-ctx.Done()
+		})
+	}()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer conn.Close()
+
+	select {
+	case peer := <-peers:
+		if peer.Addr == nil || peer.Addr.String() != conn.LocalAddr().String() {
+			t.Errorf("expected addr=%s, got=%v", conn.LocalAddr(), peer.Addr)
+		}
+		if peer.Creds != nil {
+			t.Errorf("expected no credentials for TCP connection, got=%+v", peer.Creds)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("handler was not called")
+	}
+
+	// Handler context is canceled and connection is closed when loop stops.
+	cancel()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("accept loop did not stop after context was canceled")
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if _, err = conn.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+		t.Errorf("expected error=%s, got=%s", io.EOF, err)
+	}
+}
+
+func TestAcceptLoop_ListenerClosed(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	l.Close()
+
+	err = AcceptLoop(context.Background(), l, func(context.Context, net.Conn, *PeerInfo) {})
+	if !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected error=%s, got=%s", net.ErrClosed, err)
+	}
+}
+
+func TestAcceptLoop_Invalid(t *testing.T) {
+	err := AcceptLoop(context.Background(), nil, func(context.Context, net.Conn, *PeerInfo) {})
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
@@ -5,26 +5,176 @@
 //
 // Supports [launch_activate_socket] without using cgo.
 //
+// By default, launch_activate_socket is imported at link time and called via
+// syscall.syscall from the runtime, which is accessed with go:linkname.
+// Building with "launchd_dlsym" build tag resolves it at runtime with
+// dlopen/dlsym instead, which does not require per-function assembly
+// trampolines in this package. However, it is still called via
+// syscall.syscall, thus it does not remove the dependency on go:linkname,
+// and it is not the default.
+// Building with "launchd_cgo" build tag and cgo enabled, calls it via cgo,
+// without using go:linkname at all, for environments which forbid it.
+//
+// # Concurrency
+//
+// [Files], [FilesBatch], [Listeners], [PacketListeners] and [Conns] are safe
+// for concurrent use.
+// Calls for different socket names proceed concurrently, while calls for
+// the same socket name are serialized, so that exactly one of them activates
+// the socket and the others return [syscall.EALREADY].
+//
 // [launch_activate_socket]: https://developer.apple.com/documentation/xpc/1505523-launch_activate_socket
 package launchd
 
 import (
+	"errors"
 	"net"
 	"os"
+	"slices"
+	"time"
 )
 
+// ErrProtocol is returned when launch_activate_socket returns an inconsistent
+// response, for example a non-zero count of file descriptors with a NULL array.
+var ErrProtocol = errors.New("launchd: inconsistent response from launch_activate_socket")
+
 // Files returns slice of [*os.File] backed by file descriptors for given socket.
 //
+// File descriptors returned by launchd are validated before use. If any of them
+// are invalid, an appropriate error is returned, along with a partial list of
+// files. It is the responsibility of the caller to close returned files
+// whenever required.
+//
 //   - [syscall.EALREADY] is returned if socket is already activated.
 //   - [syscall.ENOENT] or [syscall.ESRCH] is returned if socket is not found.
 //   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
 //   - [syscall.EINVAL] is returned if socket name is invalid.
+//   - [syscall.EBADF] is returned if launchd returned an invalid file descriptor.
+//   - [ErrProtocol] is returned if launchd returned an inconsistent response.
 //   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
 //
 // This must be called exactly once for given socket name. Subsequent calls
-// with the same socket name will return [syscall.EALREADY].
+// with the same socket name will return [syscall.EALREADY]. Concurrent calls
+// with the same socket name are serialized.
+//
+// Activation is recorded as a [runtime/trace] task named "launchd.Files",
+// with regions around calls to libc functions, so that time spent in
+// launchd IPC is visible with "go tool trace".
 func Files(name string) ([]*os.File, error) {
-	return files(name)
+	unlock := activationLocks.lock(name)
+	defer unlock()
+
+	start := time.Now()
+	f, err := files(name)
+	recordActivation(name, start, len(f), err)
+	return f, err
+}
+
+// ActivateRaw returns raw file descriptors for given socket, as returned by
+// launch_activate_socket. Unlike [Files], file descriptors are neither
+// validated nor wrapped in [*os.File], which is useful when file descriptors
+// are handed over to code not managed by go runtime, like C event loops.
+// It is the responsibility of the caller to close the returned file
+// descriptors whenever required.
+//
+//   - [syscall.EALREADY] is returned if socket is already activated.
+//   - [syscall.ENOENT] or [syscall.ESRCH] is returned if socket is not found.
+//   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
+//   - [syscall.EINVAL] is returned if socket name is invalid.
+//   - [ErrProtocol] is returned if launchd returned an inconsistent response.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// This must be called exactly once for given socket name. Subsequent calls
+// with the same socket name will return [syscall.EALREADY].
+func ActivateRaw(name string) ([]int32, error) {
+	unlock := activationLocks.lock(name)
+	defer unlock()
+
+	start := time.Now()
+	fds, err := activateRaw(name)
+	recordActivation(name, start, len(fds), err)
+	return fds, err
+}
+
+// FilesBatch is like [Files], but activates multiple sockets at once.
+// Applications which activate many sockets at startup should prefer this
+// over calling [Files] for each socket, as per call overheads of
+// calling libc functions are amortized across all socket names.
+//
+// Returned map contains files for each of the sockets which were activated,
+// including partial list of files for sockets with invalid file descriptors.
+// Errors for sockets which could not be activated are joined with
+// [errors.Join]. Duplicate socket names are ignored.
+//
+// Activation is recorded as a [runtime/trace] task named "launchd.FilesBatch".
+func FilesBatch(names ...string) (map[string][]*os.File, error) {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	// Lock names in sorted order to avoid deadlocks with concurrent batches.
+	for _, name := range names {
+		unlock := activationLocks.lock(name)
+		defer unlock()
+	}
+
+	start := time.Now()
+	files, errs := filesBatch(names)
+	rv := make(map[string][]*os.File, len(names))
+	for i, name := range names {
+		recordActivation(name, start, len(files[i]), errs[i])
+		if len(files[i]) > 0 {
+			rv[name] = files[i]
+		}
+	}
+	return rv, errors.Join(errs...)
+}
+
+// Activate returns slice of [Socket] for given socket, each classified by its
+// type, address family and local address in a single pass. This is useful
+// for jobs with sockets of mixed types, as [Socket.Listener] and
+// [Socket.PacketConn] can be used to build listeners without querying
+// the file descriptors again.
+//
+// In case of error classifying sockets, an appropriate error is returned,
+// along with a partial list of sockets. It is the responsibility of the
+// caller to close files of returned sockets whenever required.
+//
+//   - [syscall.EALREADY] is returned if socket is already activated.
+//   - [syscall.ENOENT] or [syscall.ESRCH] is returned if socket is not found.
+//   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
+//   - [syscall.EINVAL] is returned if socket name is invalid.
+//   - [syscall.EBADF] is returned if launchd returned an invalid file descriptor.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// This must be called exactly once for a given socket name. Subsequent calls
+// with the same socket name will return [syscall.EALREADY].
+func Activate(name string) ([]Socket, error) {
+	return activate(name)
+}
+
+// Open returns [Result] with listeners, packet connections and connections
+// for all the file descriptors of the specified socket, which may be of mixed
+// types. Sockets which are not passive are returned as connections.
+//
+// By default, Open either returns all the activated sockets as listeners or
+// none at all. If any of the activated sockets cannot be used, listeners
+// built are closed, and an error is returned along with [Result] describing
+// the skipped sockets. Use [WithPartial] to return usable listeners instead.
+// It is the responsibility of the caller to close the result whenever required.
+//
+//   - [syscall.EALREADY] is returned if socket is already activated.
+//   - [syscall.ENOENT] or [syscall.ESRCH] is returned if socket is not found.
+//   - [syscall.ESOCKTNOSUPPORT] is returned if socket is of unsupported type.
+//   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
+//   - [syscall.EINVAL] is returned if socket name is invalid.
+//   - [syscall.EBADF] is returned if launchd returned an invalid file descriptor.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// This must be called exactly once for a given socket name. Subsequent calls
+// with the same socket name will return [syscall.EALREADY].
+func Open(name string, opts ...Option) (*Result, error) {
+	return open(name, newOptions(opts))
 }
 
 // Listeners returns slice of [net.Listener] for specified TCP/stream socket.
@@ -41,6 +191,7 @@ func Files(name string) ([]*os.File, error) {
 //   - [syscall.ESOCKTNOSUPPORT] is returned if socket is of incorrect type.
 //   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
 //   - [syscall.EINVAL] is returned if socket name is invalid.
+//   - [syscall.EBADF] is returned if launchd returned an invalid file descriptor.
 //   - [syscall.ENOTSUP] is returned on non macOS platforms (including iOS).
 //
 // This must be called exactly once for a given socket name. Subsequent calls
@@ -63,6 +214,7 @@ func Listeners(name string) ([]net.Listener, error) {
 //   - [syscall.ESOCKTNOSUPPORT] is returned if socket is of incorrect type.
 //   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
 //   - [syscall.EINVAL] is returned if socket name is invalid.
+//   - [syscall.EBADF] is returned if launchd returned an invalid file descriptor.
 //   - [syscall.ENOTSUP] is returned on non macOS platforms (including iOS).
 //
 // This must be called exactly once for a given socket name. Subsequent calls
@@ -71,6 +223,31 @@ func PacketListeners(name string) ([]net.PacketConn, error) {
 	return packetListeners(name)
 }
 
+// Conns returns slice of [net.Conn] for specified client-mode socket, i.e.
+// sockets configured with SockPassive set to false, which are connected by
+// launchd on behalf of the job.
+//
+// In case of error building connections, an appropriate error is returned,
+// along with a partial list of connections. It is the responsibility of the
+// caller to close the returned non-nil connections whenever required.
+//
+// Closing returned connections does not close underlying file descriptor
+// and closing files does not affect the connections.
+//
+//   - [syscall.EALREADY] is returned if socket is already activated.
+//   - [syscall.ENOENT] or [syscall.ESRCH] is returned if socket is not found.
+//   - [syscall.ENOTCONN] is returned if socket is passive.
+//   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
+//   - [syscall.EINVAL] is returned if socket name is invalid.
+//   - [syscall.EBADF] is returned if launchd returned an invalid file descriptor.
+//   - [syscall.ENOTSUP] is returned on non macOS platforms (including iOS).
+//
+// This must be called exactly once for a given socket name. Subsequent calls
+// with the same socket name will return [syscall.EALREADY].
+func Conns(name string) ([]net.Conn, error) {
+	return conns(name)
+}
+
 // Deprecated: Use [Listeners].
 func TCPListeners(name string) ([]net.Listener, error) {
 	return Listeners(name)
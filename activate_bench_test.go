@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build (darwin && !ios) || (unix && launchd_simulator)
+
+package launchd_test
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/launchdtest"
+)
+
+// benchmarkNames returns socket names used by benchmarks. As benchmarks
+// do not run under launchd, activation fails with [syscall.ESRCH],
+// but still exercises all the calls to libc functions.
+func benchmarkNames(b *testing.B, n int) []string {
+	b.Helper()
+	b.Setenv(launchdtest.SimulatorSocketEnv, "")
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("benchmark-socket-%d", i)
+	}
+	return names
+}
+
+func BenchmarkFiles(b *testing.B) {
+	for _, n := range []int{1, 16} {
+		b.Run(fmt.Sprintf("Sockets-%d", n), func(b *testing.B) {
+			names := benchmarkNames(b, n)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, name := range names {
+					_, err := launchd.Files(name)
+					if !errors.Is(err, syscall.ESRCH) {
+						b.Fatalf("expected error=%s, got=%s", syscall.ESRCH, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFilesBatch(b *testing.B) {
+	for _, n := range []int{1, 16} {
+		b.Run(fmt.Sprintf("Sockets-%d", n), func(b *testing.B) {
+			names := benchmarkNames(b, n)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := launchd.FilesBatch(names...)
+				if !errors.Is(err, syscall.ESRCH) {
+					b.Fatalf("expected error=%s, got=%s", syscall.ESRCH, err)
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,17 @@
 // SPDX-FileCopyrightText: Copyright 2023 Prasad Tengse
 // SPDX-License-Identifier: MIT
 
-//go:build darwin && !ios
+//go:build darwin && !ios && !launchd_simulator && !launchd_dlsym && !(cgo && launchd_cgo)
 
 package launchd
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"net"
-	"os"
+	"log/slog"
 	"runtime"
-	"slices"
+	"runtime/trace"
+	"strings"
 	"syscall"
 	"unsafe"
 )
@@ -24,203 +24,171 @@ var libc_trampoline_launch_activate_socket_addr uintptr
 //nolint:revive,stylecheck,gochecknoglobals // ignore
 var libc_trampoline_free_addr uintptr
 
-// syscall_syscall is implemented in package [runtime] and pushed to [syscall].
-//
-// Go 1.23 introduces limitations on use of linknames([GH-67401]). However,
-// it keeps backward compatibility for [runtime.syscall_syscall] via [ef225d1].
-//
-// [runtime.syscall_syscall]: https://go.googlesource.com/go/+/ef225d1c57a97af984af114ee52005314530bbe2/src/runtime/sys_darwin.go#23
-// [ef225d1]: https://go.googlesource.com/go/+/ef225d1c57a97af984af114ee52005314530bbe2
-// [GH-67401]: https://github.com/golang/go/issues/67401
-//
-//go:linkname syscall_syscall syscall.syscall
-//nolint:revive // for linkname
-func syscall_syscall(fn, a1, a2, a3 uintptr) (r1, r2 uintptr, err syscall.Errno)
+// activationBackend is the implementation of launch_activate_socket.
+const activationBackend = "libc"
 
 // listenerFdsWithName returns file descriptors corresponding to the named socket.
-func listenerFdsWithName(name string) ([]int32, error) {
-	libcName, err := syscall.BytePtrFromString(name)
-	if err != nil {
-		return nil, fmt.Errorf("launchd: invalid socket name(%s): %w", name, err)
-	}
-
-	// Call libc function, launch_activate_socket.
-	//
-	// int launch_activate_socket(const char *name, int * _Nonnull *fds, size_t *cnt);
-	//
-	// # Parameters
-	//
-	//   - name: The name of the socket entry in the service’s Sockets dictionary.
-	//   - fds: On return, this parameter is populated with an array of file descriptors.
-	//     One socket can have many descriptors associated with it depending on the
-	//     characteristics of the network interfaces on the system.
-	//     The descriptors in this array are the results of calling getaddrinfo(3) with
-	//     the parameters described in launchd.plist. The caller is responsible for
-	//     calling free(3) on the returned pointer.
-	//   - count: The number of file descriptor entries in the returned array.
-	//
-	// # Returns
-	//
-	// On success, 0 is returned. Otherwise, an appropriate POSIX-domain is returned.
-	//
-	//   - ENOENT, if there was no socket of the specified name owned by the caller.
-	//   - ESRCH, if the caller isn’t a process managed by launchd.
-	//   - EALREADY, if socket has already been activated by the caller.
-	//
-	// See - https://developer.apple.com/documentation/xpc/1505523-launch_activate_socket
-
-	var fd uintptr // starting address of fds slice (int32)
-	var count uint // number of fds
-
-	// Because we are not using syscall.Syscall, but syscall_syscall directly,
-	// which does not use "go:uintptrkeepalive" directive. Pin go pointers
-	// passed to libc code.
-
-	var pinner runtime.Pinner
-	pinner.Pin(&fd)
-	pinner.Pin(&count)
-	pinner.Pin(&libcName)
-	defer pinner.Unpin()
-
-	// Use syscall_syscall as it does some magic to avoid errors.
-	// Using syscall.Syscall will result in invalid args and panic.
-	// Though syscall.syscall_syscall is not exported, it is extensively
-	// used by the [golang.org/x/sys/unix] package and thus is fairly
-	// reliable.
-	//
-	// https://github.com/golang/go/issues/65355 (check if syscall.syscall_syscall is moved here)
-	// https://github.com/golang/go/issues/67401 (resolved)
-	// https://github.com/golang/go/issues/51087
-	r1, _, e1 := syscall_syscall(
-		libc_trampoline_launch_activate_socket_addr,
-		uintptr(unsafe.Pointer(libcName)), // socket name to filter by
-		uintptr(unsafe.Pointer(&fd)),      // Pointer to *fds
-		uintptr(unsafe.Pointer(&count)),   // number of sockets
-	)
-
-	if e1 != 0 {
-		return nil, fmt.Errorf("launchd: error calling launch_activate_socket: %w", e1)
-	}
-
-	// return code from c-function launch_activate_socket.
-	switch r1 {
-	case 0:
-		if count == 0 {
-			// This code is not reachable, according do docs, but here for completeness.
-			return nil, fmt.Errorf("launchd: no sockets found: %w", syscall.ENOENT)
-		}
-
-		// - As *fd points to memory not managed by go runtime, make a copy
-		//   of the slice after building it.
-		// - Unsafe trick is used to silence govet.
-		fdSlice := slices.Clone(
-			unsafe.Slice((*int32)(*(*unsafe.Pointer)(unsafe.Pointer(&fd))), int(count)),
-		)
-
-		// de-allocate *fd.
-		_, _, e1 = syscall_syscall(libc_trampoline_free_addr, fd, 0, 0)
-		if e1 != 0 {
-			return nil, fmt.Errorf("launchd: error calling free on *fd: %w", e1)
-		}
+// Calls to libc functions are wrapped in [runtime/trace] regions of ctx,
+// so that time spent in launchd IPC is visible in execution traces.
+func listenerFdsWithName(ctx context.Context, name string) ([]int32, error) {
+	fds, errs := listenerFdsWithNames(ctx, []string{name})
+	return fds[0], errs[0]
+}
 
-		// Return file descriptors.
-		return fdSlice, nil
-	case uintptr(syscall.ENOENT):
-		return nil, fmt.Errorf("launchd: no such socket(%s): %w", name, syscall.ENOENT)
-	case uintptr(syscall.ESRCH):
-		// Weirdly, ESRCH is returned when the socket is not present in launchd,
-		// not ENOENT as documented. This is most likely a bug in macOS or its
-		// documentation.
-		//
-		// https://developer.apple.com/documentation/xpc/1505523-launch_activate_socket
-		return nil, fmt.Errorf("launchd: socket/process is not managed by launchd: %w", syscall.ESRCH)
-	case uintptr(syscall.EALREADY):
-		return nil, fmt.Errorf("launchd: socket(%s) has been already activated: %w", name, syscall.EALREADY)
-	default:
-		return nil, fmt.Errorf("launchd: unknown error code : %w", syscall.Errno(r1))
-	}
+// activateResult holds out parameters of launch_activate_socket.
+type activateResult struct {
+	fd    uintptr // starting address of fds slice (int32)
+	count uint    // number of fds
 }
 
-// Os specific implementation of [Files].
-func files(name string) ([]*os.File, error) {
-	fdSlice, err := listenerFdsWithName(name)
-	if err != nil {
-		return nil, err
+// listenerFdsWithNames returns file descriptors corresponding to each of the
+// named sockets, along with per socket errors. Socket names, out parameters and
+// returned file descriptors are each backed by a single allocation, and pinned
+// once for all names, so that activating many sockets does not pay per socket
+// allocation and pinning overhead.
+func listenerFdsWithNames(ctx context.Context, names []string) ([][]int32, []error) {
+	fds := make([][]int32, len(names))
+	errs := make([]error, len(names))
+
+	// Build NUL terminated socket names in a single buffer.
+	size := 0
+	for _, name := range names {
+		size += len(name) + 1
 	}
-	files := make([]*os.File, 0, len(fdSlice))
-	for _, fd := range fdSlice {
-		if fd != 0 {
-			files = append(files, os.NewFile(uintptr(fd),
-				fmt.Sprintf("%s-io.github.tprasadtp.go-launchd.socket", name)))
+	buf := make([]byte, 0, size)
+	offsets := make([]int, len(names))
+	for i, name := range names {
+		if strings.IndexByte(name, 0) != -1 {
+			errs[i] = fmt.Errorf("launchd: invalid socket name(%s): %w", name, syscall.EINVAL)
+			continue
 		}
+		offsets[i] = len(buf)
+		buf = append(buf, name...)
+		buf = append(buf, 0)
 	}
-	return slices.Clip(files), nil
-}
+	results := make([]activateResult, len(names))
 
-// Os specific implementation of [Listeners].
-func listeners(name string) ([]net.Listener, error) {
-	files, err := Files(name)
-	if err != nil {
-		return nil, err
+	// Because we are not using syscall.Syscall, but syscall_syscall directly,
+	// which does not use "go:uintptrkeepalive" directive. Pin go pointers
+	// passed to libc code. Pinning first element pins the entire backing array.
+	var pinner runtime.Pinner
+	if len(buf) > 0 {
+		pinner.Pin(&buf[0])
 	}
+	if len(results) > 0 {
+		pinner.Pin(&results[0])
+	}
+	defer pinner.Unpin()
 
-	listeners := make([]net.Listener, 0, len(files))
-	for _, file := range files {
-		stype, stypeErr := syscall.GetsockoptInt(int(file.Fd()), syscall.SOL_SOCKET, syscall.SO_TYPE)
-		if stypeErr != nil {
-			err = errors.Join(err, os.NewSyscallError("getsockopt", stypeErr))
+	total := 0
+	for i, name := range names {
+		if errs[i] != nil {
 			continue
 		}
 
-		if stype != syscall.SOCK_STREAM {
-			err = errors.Join(err, fmt.Errorf("%s: %w", name, syscall.ESOCKTNOSUPPORT))
-			continue
-		}
+		// Call libc function, launch_activate_socket.
+		//
+		// int launch_activate_socket(const char *name, int * _Nonnull *fds, size_t *cnt);
+		//
+		// # Parameters
+		//
+		//   - name: The name of the socket entry in the service’s Sockets dictionary.
+		//   - fds: On return, this parameter is populated with an array of file descriptors.
+		//     One socket can have many descriptors associated with it depending on the
+		//     characteristics of the network interfaces on the system.
+		//     The descriptors in this array are the results of calling getaddrinfo(3) with
+		//     the parameters described in launchd.plist. The caller is responsible for
+		//     calling free(3) on the returned pointer.
+		//   - count: The number of file descriptor entries in the returned array.
+		//
+		// # Returns
+		//
+		// On success, 0 is returned. Otherwise, an appropriate POSIX-domain is returned.
+		//
+		//   - ENOENT, if there was no socket of the specified name owned by the caller.
+		//   - ESRCH, if the caller isn’t a process managed by launchd.
+		//   - EALREADY, if socket has already been activated by the caller.
+		//
+		// See - https://developer.apple.com/documentation/xpc/1505523-launch_activate_socket
+		//
+		// Use syscall_syscall as it does some magic to avoid errors.
+		// Using syscall.Syscall will result in invalid args and panic.
+		// Though syscall.syscall_syscall is not exported, it is extensively
+		// used by the [golang.org/x/sys/unix] package and thus is fairly
+		// reliable.
+		//
+		// https://github.com/golang/go/issues/65355 (check if syscall.syscall_syscall is moved here)
+		// https://github.com/golang/go/issues/67401 (resolved)
+		// https://github.com/golang/go/issues/51087
+		//
+		// Calls interrupted by signals are retried with ignoringEINTR.
+		res := &results[i]
+		region := trace.StartRegion(ctx, "launch_activate_socket")
+		r1, e1 := ignoringEINTR(func() (uintptr, syscall.Errno) {
+			r1, _, e1 := syscall_syscall(
+				libc_trampoline_launch_activate_socket_addr,
+				uintptr(unsafe.Pointer(&buf[offsets[i]])), // socket name to filter by
+				uintptr(unsafe.Pointer(&res.fd)),          // Pointer to *fds
+				uintptr(unsafe.Pointer(&res.count)),       // number of sockets
+			)
+			return r1, e1
+		})
+		region.End()
+
+		debug("launchd: launch_activate_socket",
+			slog.String("name", name),
+			slog.Uint64("rc", uint64(r1)),
+			slog.Uint64("count", uint64(res.count)),
+			slog.Int("errno", int(e1)),
+		)
 
-		l, el := net.FileListener(file)
-		if el != nil {
-			err = errors.Join(err, el)
-		} else {
-			listeners = append(listeners, l)
+		switch {
+		case e1 != 0:
+			errs[i] = fmt.Errorf("launchd: error calling launch_activate_socket: %w", e1)
+		case r1 != 0:
+			errs[i] = activationError(name, syscall.Errno(r1))
+		default:
+			errs[i] = checkActivated(name, res.fd, uint64(res.count))
+			if errs[i] == nil {
+				total += int(res.count)
+			}
 		}
 	}
 
-	if err != nil {
-		return slices.Clip(listeners), fmt.Errorf("launchd: error building listeners: %w", err)
-	}
-	return slices.Clip(listeners), nil
-}
-
-// Os specific implementation of [PacketListeners].
-func packetListeners(name string) ([]net.PacketConn, error) {
-	files, err := Files(name)
-	if err != nil {
-		return nil, err
-	}
-
-	listeners := make([]net.PacketConn, 0, len(files))
-	for _, file := range files {
-		stype, stypeErr := syscall.GetsockoptInt(int(file.Fd()), syscall.SOL_SOCKET, syscall.SO_TYPE)
-		if stypeErr != nil {
-			err = errors.Join(err, os.NewSyscallError("getsockopt", stypeErr))
+	// As *fd points to memory not managed by go runtime, copy file descriptors
+	// of all sockets into a single slice, and de-allocate *fd. Inconsistent
+	// responses are not dereferenced, but non-NULL *fd is still de-allocated.
+	all := make([]int32, 0, total)
+	for i, name := range names {
+		res := &results[i]
+		if res.fd == 0 {
 			continue
 		}
 
-		if stype != syscall.SOCK_DGRAM {
-			err = errors.Join(err, fmt.Errorf("%s: %w", name, syscall.ESOCKTNOSUPPORT))
-			continue
+		if errs[i] == nil {
+			// Unsafe trick is used to silence govet.
+			start := len(all)
+			all = append(all,
+				unsafe.Slice((*int32)(*(*unsafe.Pointer)(unsafe.Pointer(&res.fd))), int(res.count))...)
+			fds[i] = all[start:len(all):len(all)]
+
+			debug("launchd: activated file descriptors",
+				slog.String("name", name),
+				slog.Any("fds", fds[i]),
+			)
 		}
 
-		l, el := net.FilePacketConn(file)
-		if el != nil {
-			err = errors.Join(err, el)
-		} else {
-			listeners = append(listeners, l)
+		region := trace.StartRegion(ctx, "free")
+		_, _, e1 := syscall_syscall(libc_trampoline_free_addr, res.fd, 0, 0)
+		region.End()
+		debug("launchd: free", slog.Int("errno", int(e1)))
+		if e1 != 0 && errs[i] == nil {
+			// File descriptors are already copied, close them
+			// instead of leaking them along with the error.
+			closeFDs(fds[i])
+			fds[i] = nil
+			errs[i] = fmt.Errorf("launchd: error calling free on *fd: %w", e1)
 		}
 	}
-
-	if err != nil {
-		return slices.Clip(listeners), fmt.Errorf("launchd: %w", err)
-	}
-	return slices.Clip(listeners), nil
+	return fds, errs
 }
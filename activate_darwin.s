@@ -1,7 +1,7 @@
 // SPDX-FileCopyrightText: Copyright 2023 Prasad Tengse
 // SPDX-License-Identifier: MIT
 
-//go:build darwin && !ios
+//go:build darwin && !ios && !launchd_simulator && !launchd_dlsym && !(cgo && launchd_cgo)
 
 #include "textflag.h"
 
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !launchd_simulator && launchd_dlsym && !(cgo && launchd_cgo)
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"runtime/trace"
+	"slices"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// libSystem is the path of libSystem, which provides launch_activate_socket and free.
+const libSystem = "/usr/lib/libSystem.B.dylib"
+
+// activationBackend is the implementation of launch_activate_socket.
+const activationBackend = "dlsym"
+
+// activateFuncs are addresses of libc functions used by socket activation.
+type activateFuncs struct {
+	activate uintptr
+	free     uintptr
+}
+
+// resolveActivateFuncs resolves libc functions with dlopen/dlsym
+// on first use, instead of importing them at link time.
+//
+//nolint:gochecknoglobals // resolved once.
+var resolveActivateFuncs = sync.OnceValues(func() (activateFuncs, error) {
+	handle, err := macos.Open(libSystem)
+	if err != nil {
+		return activateFuncs{}, fmt.Errorf("launchd: %w", err)
+	}
+
+	fns := activateFuncs{
+		activate: macos.Lookup(handle, "launch_activate_socket"),
+		free:     macos.Lookup(handle, "free"),
+	}
+	if fns.activate == 0 || fns.free == 0 {
+		return activateFuncs{}, fmt.Errorf("launchd: failed to resolve launch_activate_socket: %w", syscall.ENOTSUP)
+	}
+	return fns, nil
+})
+
+// listenerFdsWithName returns file descriptors corresponding to the named socket.
+//
+// Unlike the default implementation, which imports launch_activate_socket
+// and free with go:cgo_import_dynamic, this resolves them at runtime with
+// dlopen/dlsym. Calls still go through [macos.Call], which like the default
+// implementation, relies on syscall.syscall via go:linkname. Use the cgo
+// backend to avoid go:linkname. See activate_darwin.go for documentation
+// of launch_activate_socket.
+func listenerFdsWithName(ctx context.Context, name string) ([]int32, error) {
+	libcName, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: invalid socket name(%s): %w", name, err)
+	}
+
+	fns, err := resolveActivateFuncs()
+	if err != nil {
+		return nil, err
+	}
+
+	var fd uintptr // starting address of fds slice (int32)
+	var count uint // number of fds
+
+	var pinner runtime.Pinner
+	pinner.Pin(&fd)
+	pinner.Pin(&count)
+	pinner.Pin(libcName)
+	defer pinner.Unpin()
+
+	region := trace.StartRegion(ctx, "launch_activate_socket")
+	r1, e1 := ignoringEINTR(func() (uintptr, syscall.Errno) {
+		return macos.Call(
+			fns.activate,
+			uintptr(unsafe.Pointer(libcName)), // socket name to filter by
+			uintptr(unsafe.Pointer(&fd)),      // Pointer to *fds
+			uintptr(unsafe.Pointer(&count)),   // number of sockets
+		)
+	})
+	region.End()
+
+	debug("launchd: launch_activate_socket",
+		slog.String("name", name),
+		slog.String("backend", "dlsym"),
+		slog.Uint64("rc", uint64(r1)),
+		slog.Uint64("count", uint64(count)),
+		slog.Int("errno", int(e1)),
+	)
+
+	if e1 != 0 {
+		return nil, fmt.Errorf("launchd: error calling launch_activate_socket: %w", e1)
+	}
+	if r1 != 0 {
+		return nil, activationError(name, syscall.Errno(r1))
+	}
+	if err = checkActivated(name, fd, uint64(count)); err != nil {
+		if fd != 0 {
+			_, _ = macos.Call(fns.free, fd)
+		}
+		return nil, err
+	}
+
+	// As *fd points to memory not managed by go runtime, make a copy
+	// of the slice after building it.
+	fdSlice := slices.Clone(
+		unsafe.Slice((*int32)(*(*unsafe.Pointer)(unsafe.Pointer(&fd))), int(count)),
+	)
+
+	debug("launchd: activated file descriptors",
+		slog.String("name", name),
+		slog.Any("fds", fdSlice),
+	)
+
+	// de-allocate *fd.
+	region = trace.StartRegion(ctx, "free")
+	_, e1 = macos.Call(fns.free, fd)
+	region.End()
+	debug("launchd: free", slog.Int("errno", int(e1)))
+	if e1 != 0 {
+		// File descriptors are already copied, close them
+		// instead of leaking them along with the error.
+		closeFDs(fdSlice)
+		return nil, fmt.Errorf("launchd: error calling free on *fd: %w", e1)
+	}
+	return fdSlice, nil
+}
+
+// listenerFdsWithNames returns file descriptors corresponding to each of the
+// named sockets, along with per socket errors.
+func listenerFdsWithNames(ctx context.Context, names []string) ([][]int32, []error) {
+	return listenerFdsEach(ctx, names)
+}
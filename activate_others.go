@@ -1,7 +1,7 @@
 // SPDX-FileCopyrightText: Copyright 2023 Prasad Tengse
 // SPDX-License-Identifier: MIT
 
-//go:build !darwin || ios
+//go:build (!darwin || ios) && !(unix && launchd_simulator)
 
 package launchd
 
@@ -17,6 +17,30 @@ func files(_ string) ([]*os.File, error) {
 	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
 }
 
+// Os specific implementation of [ActivateRaw].
+func activateRaw(_ string) ([]int32, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [FilesBatch].
+func filesBatch(names []string) ([][]*os.File, []error) {
+	errs := make([]error, len(names))
+	for i := range names {
+		errs[i] = fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+	}
+	return make([][]*os.File, len(names)), errs
+}
+
+// Os specific implementation of [Activate].
+func activate(_ string) ([]Socket, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Open].
+func open(_ string, _ *options) (*Result, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
 // Os specific implementation of [Listeners].
 func listeners(_ string) ([]net.Listener, error) {
 	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
@@ -26,3 +50,8 @@ func listeners(_ string) ([]net.Listener, error) {
 func packetListeners(_ string) ([]net.PacketConn, error) {
 	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
 }
+
+// Os specific implementation of [Conns].
+func conns(_ string) ([]net.Conn, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
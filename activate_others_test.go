@@ -1,7 +1,7 @@
 // SPDX-FileCopyrightText: Copyright 2023 Prasad Tengse
 // SPDX-License-Identifier: MIT
 
-//go:build !darwin || ios
+//go:build (!darwin || ios) && !(unix && launchd_simulator)
 
 package launchd_test
 
@@ -82,3 +82,150 @@ func TestUDPListeners(t *testing.T) {
 		t.Errorf("expected error=%s, got=%s", errors.ErrUnsupported, err)
 	}
 }
+
+func TestInetdConn(t *testing.T) {
+	conn, err := launchd.InetdConn()
+	if conn != nil {
+		t.Errorf("expected no connection on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestInetdListener(t *testing.T) {
+	l, err := launchd.InetdListener()
+	if l != nil {
+		t.Errorf("expected no listener on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestBeginTransaction(t *testing.T) {
+	tx, err := launchd.BeginTransaction("com.example.svc.write")
+	if tx != nil {
+		t.Errorf("expected no transaction on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+
+	// Must not panic.
+	launchd.EndTransaction(tx)
+}
+
+func TestActivate(t *testing.T) {
+	sockets, err := launchd.Activate("b39422da-351b-50ad-a7cc-9dea5ae436ea")
+	if len(sockets) != 0 {
+		t.Errorf("expected no sockets on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestFilesBatch(t *testing.T) {
+	rv, err := launchd.FilesBatch("b39422da-351b-50ad-a7cc-9dea5ae436ea", "tcp")
+	if len(rv) != 0 {
+		t.Errorf("expected no files on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestActivateRaw(t *testing.T) {
+	fds, err := launchd.ActivateRaw("b39422da-351b-50ad-a7cc-9dea5ae436ea")
+	if len(fds) != 0 {
+		t.Errorf("expected no file descriptors on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	r, err := launchd.Open("b39422da-351b-50ad-a7cc-9dea5ae436ea", launchd.WithPartial())
+	if r != nil {
+		t.Errorf("expected no result on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestPeerCredentials(t *testing.T) {
+	creds, err := launchd.PeerCredentials(nil)
+	if creds != nil {
+		t.Errorf("expected no credentials on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestVerifyPeer(t *testing.T) {
+	err := launchd.VerifyPeer(nil, "anchor apple")
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestPeerAuditToken(t *testing.T) {
+	_, err := launchd.PeerAuditToken(nil)
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+
+	_, err = launchd.AuditToken{}.SigningIdentifier()
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestConns(t *testing.T) {
+	conns, err := launchd.Conns("b39422da-351b-50ad-a7cc-9dea5ae436ea")
+	if len(conns) != 0 {
+		t.Errorf("expected no conns on non-darwin platform")
+	}
+
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+	if !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("expected error=%s, got=%s", errors.ErrUnsupported, err)
+	}
+}
+
+func TestDualStack(t *testing.T) {
+	d, err := launchd.DualStack("b39422da-351b-50ad-a7cc-9dea5ae436ea")
+	if d != nil {
+		t.Errorf("expected no listeners on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestBind(t *testing.T) {
+	b, err := launchd.Bind(launchd.Expect{
+		"http":    {Type: launchd.Stream},
+		"metrics": {Type: launchd.Stream, Optional: true},
+	})
+	if b != nil {
+		t.Errorf("expected no sockets on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
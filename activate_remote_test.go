@@ -0,0 +1,551 @@
+// SPDX-FileCopyrightText: Copyright 2023 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build (darwin && !ios) || (unix && launchd_simulator)
+
+package launchd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/launchdtest"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// testSockets returns the socket definitions used by [TestRemote].
+// SockServiceName of inet sockets is returned by port.
+func testSockets(t *testing.T, port func() string) map[string]plist.Socket {
+	t.Helper()
+	dir := t.TempDir()
+	return map[string]plist.Socket{
+		// IPv4 ensures only single socket is returned.
+		"tcp": {
+			SockServiceName: port(),
+			SockType:        "stream",
+			SockFamily:      "IPv4",
+			SockNodeName:    "localhost",
+		},
+		"udp": {
+			SockServiceName: port(),
+			SockType:        "dgram",
+			SockFamily:      "IPv4",
+			SockNodeName:    "localhost",
+		},
+		"tcp-multiple": {
+			SockServiceName: port(),
+			SockType:        "stream",
+			SockNodeName:    "localhost",
+		},
+		"udp-multiple": {
+			SockServiceName: port(),
+			SockType:        "dgram",
+			SockNodeName:    "localhost",
+		},
+		"tcp-dualstack-single-socket": {
+			SockServiceName: port(),
+			SockType:        "stream",
+			SockFamily:      "IPv4v6",
+			SockNodeName:    "localhost",
+		},
+		"udp-dualstack-single-socket": {
+			SockServiceName: port(),
+			SockType:        "dgram",
+			SockFamily:      "IPv4v6",
+			SockNodeName:    "localhost",
+		},
+		"tcp-invalid-type": {
+			SockServiceName: port(),
+			SockType:        "dgram",
+			SockFamily:      "IPv4",
+			SockNodeName:    "localhost",
+		},
+		"udp-invalid-type": {
+			SockServiceName: port(),
+			SockType:        "stream",
+			SockFamily:      "IPv4",
+			SockNodeName:    "localhost",
+		},
+		"unix-stream": {
+			SockPathName: filepath.Join(dir, "unix-stream.socket"),
+			SockPathMode: 0o700,
+			SockType:     "stream",
+		},
+		"unix-datagram": {
+			SockPathName: filepath.Join(dir, "unix-datagram.socket"),
+			SockPathMode: 0o700,
+			SockType:     "dgram",
+		},
+	}
+}
+
+// checkRemoteResult checks results published by [TestRemote].
+func checkRemoteResult(t *testing.T, result *launchdtest.Result) {
+	t.Helper()
+	t.Logf("Remote test counters errors=%d, ok=%d", result.Errors(), result.OK())
+
+	switch {
+	case len(result.Events) == 0:
+		t.Errorf("Remote test did not post its results")
+	case result.Errors() == 0:
+		t.Logf("%d Remote tests successful", result.OK())
+	default:
+		t.Errorf("%d Remote tests returned errors", result.Errors())
+	}
+}
+
+// Start a simple http server binding to socket and test if it is reachable.
+func streamServerPing(t *testing.T, listener net.Listener) {
+	t.Helper()
+	t.Logf("Listener: %s", listener.Addr())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		t.Logf("StreamSocketServer, method=%s, url=%s, host=%s", r.Method, r.URL, r.Host)
+		if r.Method == http.MethodDelete {
+			cancel()
+		}
+	})
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: time.Second * 30,
+	}
+
+	var w sync.WaitGroup
+	w.Add(1)
+	go func() {
+		defer w.Done()
+		t.Logf("Starting server on launchd socket: %s", listener.Addr())
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			msg := fmt.Sprintf("Failed to listen on %s: %s", listener.Addr(), err)
+			t.Error(msg)
+			launchdtest.Report(t, false, msg)
+			cancel()
+		}
+	}()
+
+	// Wait for context to be cancelled and shut down the server.
+	w.Add(1)
+	go func() {
+		defer w.Done()
+		var err error
+		for {
+			select {
+			case <-ctx.Done():
+				t.Logf("Stopping socket server: %s", listener.Addr())
+				err = server.Shutdown(context.Background())
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					t.Errorf("Failed to stop socket server: %s", listener.Addr())
+				}
+				return
+			}
+		}
+	}()
+
+	var url string
+	_, isUnixListener := listener.(*net.UnixListener)
+
+	if isUnixListener {
+		url = "http://unix"
+	} else {
+		url = fmt.Sprintf("http://%s", listener.Addr())
+	}
+
+	// Try to send HTTP request to socket server.
+	request, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodDelete,
+		url,
+		nil)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to build HTTP request: %s", err)
+		launchdtest.Report(t, false, msg)
+		t.Error(msg)
+		cancel()
+		w.Wait()
+		return
+	}
+	client := &http.Client{}
+	if isUnixListener {
+		t.Logf("Using UNIX socket: %s", listener.Addr())
+		dialer := &net.Dialer{}
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", listener.Addr().String())
+			},
+		}
+	} else {
+		t.Logf("Using TCP socket: %s", listener.Addr())
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to do HTTP request: %s", err)
+		t.Errorf(msg)
+		launchdtest.Report(t, false, msg)
+		return
+	}
+	if response != nil {
+		if response.Body != nil {
+			defer response.Body.Close()
+		}
+	}
+
+	if response.StatusCode == http.StatusOK {
+		launchdtest.Report(t, true, "")
+	} else {
+		msg := fmt.Sprintf("Failed to do HTTP request: %s", response.Status)
+		t.Error(msg)
+		launchdtest.Report(t, true, "")
+	}
+
+	t.Logf("Waiting for socket server to stop...")
+	w.Wait()
+}
+
+// cleanupNetListeners.
+func cleanupNetListeners(t *testing.T, listeners []net.Listener) {
+	t.Helper()
+	if len(listeners) > 0 {
+		t.Cleanup(func() {
+			for _, item := range listeners {
+				t.Logf("Closing listener(stream): %s", item.Addr())
+				item.Close()
+			}
+		})
+	}
+}
+
+// cleanupPacketListeners.
+func cleanupPacketListeners(t *testing.T, listeners []net.PacketConn) {
+	t.Helper()
+	if len(listeners) > 0 {
+		t.Cleanup(func() {
+			for _, item := range listeners {
+				t.Logf("Closing listener(datagram): %s", item.LocalAddr())
+				item.Close()
+			}
+		})
+	}
+}
+
+// TestRemote runs tests under launchd and reports the results to the harness.
+func TestRemote(t *testing.T) {
+	if !launchdtest.Remote() {
+		t.SkipNow()
+	}
+	defer launchdtest.Done(t)
+
+	t.Logf("Args=%s", os.Args)
+
+	tt := []struct {
+		name   string
+		socket string
+		errs   []error
+		count  int
+		dgram  bool
+	}{
+		{
+			name:   "TCP-NoSuchSocket",
+			socket: "5bf300ce-6993-4fd5-bfa9-bc1c9e49f996",
+			errs:   []error{syscall.ENOENT, syscall.ESRCH},
+		},
+		{
+			name:   "TCP-SingleSocket",
+			socket: "tcp",
+			count:  1,
+		},
+		{
+			name:   "TCP-ActivateMultipleTimesMustError",
+			socket: "tcp",
+			errs:   []error{syscall.EALREADY},
+		},
+		{
+			name:   "TCP-MultipleSockets",
+			socket: "tcp-multiple",
+			count:  2, // one for ipv6 and ipv4
+		},
+		{
+			name:   "TCP-DualStack-SingleSocket",
+			socket: "tcp-dualstack-single-socket",
+			count:  1,
+		},
+		{
+			name:   "TCP-InvalidType",
+			socket: "tcp-invalid-type",
+			errs:   []error{syscall.ESOCKTNOSUPPORT},
+		},
+		{
+			name:   "UnixStreamSocket",
+			socket: "unix-stream",
+			count:  1,
+		},
+		// UDP/Stream sockets.
+		{
+			name:   "UDP-NoSuchSocket",
+			socket: "9f712891-ca0b-4de7-8750-645c74008ecd",
+			errs:   []error{syscall.ENOENT, syscall.ESRCH},
+			dgram:  true,
+		},
+		{
+			name:   "UDP-SingleSocket",
+			socket: "udp",
+			count:  1,
+			dgram:  true,
+		},
+		{
+			name:   "UDP-ActivateMultipleTimesMustError",
+			socket: "udp",
+			errs:   []error{syscall.EALREADY},
+			dgram:  true,
+		},
+		{
+			name:   "UDP-MultipleSockets",
+			socket: "udp-multiple",
+			count:  2, // one for ipv6 and ipv4
+			dgram:  true,
+		},
+		{
+			name:   "UDP-DualStack-SingleSocket",
+			socket: "udp-dualstack-single-socket",
+			count:  1,
+			dgram:  true,
+		},
+		{
+			name:   "UDP-InvalidType",
+			socket: "udp-invalid-type",
+			errs:   []error{syscall.ESOCKTNOSUPPORT},
+			dgram:  true,
+		},
+		{
+			name:   "UnixDatagramSocket",
+			socket: "unix-datagram",
+			count:  1,
+			dgram:  true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var listeners []net.Listener
+			var packetListeners []net.PacketConn
+			var listenerCount int
+			var err error
+
+			if tc.dgram {
+				packetListeners, err = launchd.PacketListeners(tc.socket)
+				listenerCount = len(packetListeners)
+				cleanupPacketListeners(t, packetListeners)
+			} else {
+				listeners, err = launchd.Listeners(tc.socket)
+				listenerCount = len(listeners)
+				cleanupNetListeners(t, listeners)
+			}
+
+			// Check if error is one of specified or nil.
+			t.Run("CheckError", func(t *testing.T) {
+				if len(tc.errs) > 0 {
+					ok := false
+					for i := range tc.errs {
+						if errors.Is(err, tc.errs[i]) {
+							ok = true
+							break
+						}
+					}
+					if !ok {
+						msg := fmt.Sprintf("expected error(%v), but got=%s", tc.errs, err)
+						t.Error(msg)
+						launchdtest.Report(t, false, msg)
+					} else {
+						launchdtest.Report(t, true, "")
+					}
+				} else {
+					if err != nil {
+						msg := fmt.Sprintf("expected no error, but got=%s", err)
+						t.Error(msg)
+						launchdtest.Report(t, false, msg)
+					} else {
+						launchdtest.Report(t, true, "")
+					}
+				}
+			})
+
+			// Check listener count.
+			t.Run("ListenerCount", func(t *testing.T) {
+				if listenerCount != tc.count {
+					msg := fmt.Sprintf("expected listeners=%d, but got=%d", tc.count, listenerCount)
+					t.Error(msg)
+					launchdtest.Report(t, false, msg)
+				} else {
+					launchdtest.Report(t, true, "")
+				}
+			})
+
+			// Ensure listening on the steam socket works.
+			if len(listeners) > 0 {
+				for i := range listeners {
+					t.Run(fmt.Sprintf("ServerPing-%d", i+1), func(t *testing.T) {
+						streamServerPing(t, listeners[i])
+					})
+				}
+			}
+		})
+	}
+}
+
+// TestRemoteConcurrent activates sockets concurrently under launchd
+// and reports the results to the harness.
+func TestRemoteConcurrent(t *testing.T) {
+	if !launchdtest.Remote() {
+		t.SkipNow()
+	}
+	defer launchdtest.Done(t)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var ok, already int
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			listeners, err := launchd.Listeners("tcp")
+			cleanupNetListeners(t, listeners)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil && len(listeners) == 1:
+				ok++
+			case errors.Is(err, syscall.EALREADY):
+				already++
+			default:
+				t.Errorf("unexpected result listeners=%d, err=%s", len(listeners), err)
+			}
+		}()
+	}
+
+	for _, name := range []string{"udp", "unix-datagram"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			listeners, err := launchd.PacketListeners(name)
+			cleanupPacketListeners(t, listeners)
+			if err != nil || len(listeners) != 1 {
+				msg := fmt.Sprintf("expected listeners=1 for %s, got=%d, err=%s", name, len(listeners), err)
+				t.Error(msg)
+				launchdtest.Report(t, false, msg)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	if ok != 1 || already != 7 {
+		msg := fmt.Sprintf("expected ok=1, already=7, got ok=%d, already=%d", ok, already)
+		t.Error(msg)
+		launchdtest.Report(t, false, msg)
+		return
+	}
+	launchdtest.Report(t, true, "")
+}
+
+// TestRemoteBatch activates sockets with [launchd.FilesBatch] under launchd
+// and reports the results to the harness.
+func TestRemoteBatch(t *testing.T) {
+	if !launchdtest.Remote() {
+		t.SkipNow()
+	}
+	defer launchdtest.Done(t)
+
+	rv, err := launchd.FilesBatch("tcp", "tcp-multiple", "udp", "unix-stream", "tcp",
+		"5bf300ce-6993-4fd5-bfa9-bc1c9e49f996")
+	t.Cleanup(func() {
+		for _, files := range rv {
+			for _, f := range files {
+				f.Close()
+			}
+		}
+	})
+
+	if !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ESRCH) {
+		msg := fmt.Sprintf("expected error(%v), but got=%s", []error{syscall.ENOENT, syscall.ESRCH}, err)
+		t.Error(msg)
+		launchdtest.Report(t, false, msg)
+	}
+
+	expect := map[string]int{"tcp": 1, "tcp-multiple": 2, "udp": 1, "unix-stream": 1}
+	if len(rv) != len(expect) {
+		msg := fmt.Sprintf("expected sockets=%d, but got=%d", len(expect), len(rv))
+		t.Error(msg)
+		launchdtest.Report(t, false, msg)
+	}
+	for name, count := range expect {
+		if len(rv[name]) != count {
+			msg := fmt.Sprintf("expected files=%d for %s, but got=%d", count, name, len(rv[name]))
+			t.Error(msg)
+			launchdtest.Report(t, false, msg)
+			return
+		}
+	}
+
+	// Sockets activated in a batch must not be activated again.
+	_, err = launchd.FilesBatch("tcp", "udp")
+	if !errors.Is(err, syscall.EALREADY) {
+		msg := fmt.Sprintf("expected error=%s, but got=%s", syscall.EALREADY, err)
+		t.Error(msg)
+		launchdtest.Report(t, false, msg)
+		return
+	}
+	launchdtest.Report(t, true, "")
+}
+
+// TestRemoteRaw activates sockets with [launchd.ActivateRaw] under launchd
+// and reports the results to the harness.
+func TestRemoteRaw(t *testing.T) {
+	if !launchdtest.Remote() {
+		t.SkipNow()
+	}
+	defer launchdtest.Done(t)
+
+	fds, err := launchd.ActivateRaw("tcp-multiple")
+	t.Cleanup(func() {
+		for _, fd := range fds {
+			syscall.Close(int(fd))
+		}
+	})
+	if err != nil || len(fds) != 2 {
+		msg := fmt.Sprintf("expected fds=2, got=%d, err=%s", len(fds), err)
+		t.Error(msg)
+		launchdtest.Report(t, false, msg)
+		return
+	}
+
+	// Raw file descriptors must be usable as is.
+	for _, fd := range fds {
+		sotype, err := syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_TYPE)
+		if err != nil || sotype != syscall.SOCK_STREAM {
+			msg := fmt.Sprintf("expected stream socket for fd=%d, got=%d, err=%s", fd, sotype, err)
+			t.Error(msg)
+			launchdtest.Report(t, false, msg)
+			return
+		}
+	}
+
+	_, err = launchd.ActivateRaw("tcp-multiple")
+	if !errors.Is(err, syscall.EALREADY) {
+		msg := fmt.Sprintf("expected error=%s, but got=%s", syscall.EALREADY, err)
+		t.Error(msg)
+		launchdtest.Report(t, false, msg)
+		return
+	}
+	launchdtest.Report(t, true, "")
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix && launchd_simulator
+
+package launchd
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"runtime/trace"
+	"strings"
+	"syscall"
+)
+
+// simulatorSocketEnv is the environment variable with the path of the
+// unix socket of the simulated launchd. This must be kept in sync with
+// launchdtest.SimulatorSocketEnv.
+const simulatorSocketEnv = "LAUNCHD_SIMULATOR_SOCKET"
+
+// simulatorMaxFDs is the maximum number of file descriptors
+// accepted from the simulated launchd for a single socket.
+const simulatorMaxFDs = 64
+
+// activationBackend is the implementation of launch_activate_socket.
+const activationBackend = "simulator"
+
+// listenerFdsWithName returns file descriptors corresponding to the named socket,
+// by requesting them from the simulated launchd over a unix socket.
+//
+// The request is the name of the socket, terminated by closing the write side
+// of the connection. Response is a 4 byte big endian errno, with the file
+// descriptors sent as SCM_RIGHTS if errno is zero, mirroring the return
+// values of launch_activate_socket.
+func listenerFdsWithName(ctx context.Context, name string) ([]int32, error) {
+	if strings.IndexByte(name, 0) >= 0 {
+		return nil, fmt.Errorf("launchd: invalid socket name(%s): %w", name, syscall.EINVAL)
+	}
+
+	path := os.Getenv(simulatorSocketEnv)
+	if path == "" {
+		return nil, activationError(name, syscall.ESRCH)
+	}
+
+	region := trace.StartRegion(ctx, "launch_activate_socket")
+	defer region.End()
+
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("launchd: failed to connect to simulator: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(name)); err != nil {
+		return nil, fmt.Errorf("launchd: failed to send request to simulator: %w", err)
+	}
+	if err = conn.CloseWrite(); err != nil {
+		return nil, fmt.Errorf("launchd: failed to send request to simulator: %w", err)
+	}
+
+	buf := make([]byte, 4)
+	oob := make([]byte, syscall.CmsgSpace(simulatorMaxFDs*4))
+	n, oobn, flags, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: failed to read response from simulator: %w", err)
+	}
+
+	fds, err := simulatorFDs(oob[:oobn])
+	if err != nil {
+		return nil, err
+	}
+
+	if n != len(buf) || flags&syscall.MSG_CTRUNC != 0 {
+		closeFDs(fds)
+		return nil, fmt.Errorf("launchd: invalid response from simulator: %w", ErrProtocol)
+	}
+
+	rc := syscall.Errno(binary.BigEndian.Uint32(buf))
+	debug("launchd: simulated launch_activate_socket",
+		slog.String("name", name),
+		slog.Int("count", len(fds)),
+		slog.Int("errno", int(rc)),
+	)
+
+	if rc != 0 {
+		closeFDs(fds)
+		return nil, activationError(name, rc)
+	}
+
+	if len(fds) == 0 {
+		return nil, fmt.Errorf("launchd: no sockets found: %w", syscall.ENOENT)
+	}
+	return fds, nil
+}
+
+// simulatorFDs parses file descriptors from socket control messages.
+func simulatorFDs(oob []byte) ([]int32, error) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: invalid control message from simulator: %w", errors.Join(ErrProtocol, err))
+	}
+
+	var fds []int32
+	for i := range msgs {
+		rights, err := syscall.ParseUnixRights(&msgs[i])
+		if err != nil {
+			continue
+		}
+		for _, fd := range rights {
+			fds = append(fds, int32(fd))
+		}
+	}
+	return fds, nil
+}
+
+// listenerFdsWithNames returns file descriptors corresponding to each of the
+// named sockets, along with per socket errors.
+func listenerFdsWithNames(ctx context.Context, names []string) ([][]int32, []error) {
+	return listenerFdsEach(ctx, names)
+}
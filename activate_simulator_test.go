@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix && launchd_simulator
+
+package launchd_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/launchdtest"
+)
+
+// simulatorSockets binds the sockets of [testSockets] to ports chosen by
+// the kernel and passes them to the simulator.
+func simulatorSockets(t *testing.T) launchdtest.Option {
+	sockets := testSockets(t, func() string { return "0" })
+	return launchdtest.WithFiles(launchdtest.Listen(t, sockets))
+}
+
+func TestSimulator(t *testing.T) {
+	result := launchdtest.Simulate(t, "^TestRemote$", simulatorSockets(t))
+	checkRemoteResult(t, result)
+}
+
+func TestSimulator_Concurrent(t *testing.T) {
+	result := launchdtest.Simulate(t, "^TestRemoteConcurrent$", simulatorSockets(t))
+	checkRemoteResult(t, result)
+}
+
+func TestSimulator_Batch(t *testing.T) {
+	result := launchdtest.Simulate(t, "^TestRemoteBatch$", simulatorSockets(t))
+	checkRemoteResult(t, result)
+}
+
+func TestSimulator_ActivateRaw(t *testing.T) {
+	result := launchdtest.Simulate(t, "^TestRemoteRaw$", simulatorSockets(t))
+	checkRemoteResult(t, result)
+}
+
+func TestSimulator_NotManagedByLaunchd(t *testing.T) {
+	t.Setenv(launchdtest.SimulatorSocketEnv, "")
+	rv, err := launchd.Listeners("b39422da-351b-50ad-a7cc-9dea5ae436ea")
+	if len(rv) != 0 {
+		t.Errorf("expected no listeners when process is not manged by launchd")
+	}
+	if !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("expected error=%s, got=%s", syscall.ESRCH, err)
+	}
+}
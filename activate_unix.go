@@ -0,0 +1,371 @@
+// SPDX-FileCopyrightText: Copyright 2023 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build (darwin && !ios) || (unix && launchd_simulator)
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"runtime/trace"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// activationError returns an error for non-zero return code of launch_activate_socket.
+// Unknown return codes are returned as [ActivationError].
+func activationError(name string, rc syscall.Errno) error {
+	switch rc {
+	case syscall.ENOENT:
+		return fmt.Errorf("launchd: no such socket(%s): %w", name, syscall.ENOENT)
+	case syscall.ESRCH:
+		// Weirdly, ESRCH is returned when the socket is not present in launchd,
+		// not ENOENT as documented. This is most likely a bug in macOS or its
+		// documentation.
+		//
+		// https://developer.apple.com/documentation/xpc/1505523-launch_activate_socket
+		return fmt.Errorf("launchd: socket/process is not managed by launchd: %w", syscall.ESRCH)
+	case syscall.EALREADY:
+		return fmt.Errorf("launchd: socket(%s) has been already activated: %w", name, syscall.EALREADY)
+	default:
+		return newActivationError(name, rc, activationBackend)
+	}
+}
+
+// maxEINTRRetries is the number of times an interrupted call is retried
+// by ignoringEINTR, before giving up.
+const maxEINTRRetries = 8
+
+// ignoringEINTR calls fn until its return code is not [syscall.EINTR],
+// i.e. until it is not interrupted by a signal, at most [maxEINTRRetries]
+// times. This avoids spurious activation failures in programs which
+// receive many signals, for example when profiling or when a debugger
+// is attached. launch_activate_socket returns errors as return code,
+// thus errno is returned as is, without being checked.
+func ignoringEINTR(fn func() (uintptr, syscall.Errno)) (uintptr, syscall.Errno) {
+	rc, errno := fn()
+	for i := 0; i < maxEINTRRetries && syscall.Errno(rc) == syscall.EINTR; i++ {
+		rc, errno = fn()
+	}
+	return rc, errno
+}
+
+// maxActivatedFds is the upper bound on number of file descriptors accepted
+// from launch_activate_socket for a single socket. Larger counts are treated
+// as inconsistent responses, instead of reading beyond the returned array.
+const maxActivatedFds = 1 << 16
+
+// checkActivated checks out parameters of a successful launch_activate_socket
+// call for consistency, before fds is dereferenced.
+func checkActivated(name string, fds uintptr, count uint64) error {
+	switch {
+	case count == 0:
+		// This code is not reachable, according do docs, but here for completeness.
+		return fmt.Errorf("launchd: no sockets found: %w", syscall.ENOENT)
+	case fds == 0:
+		return fmt.Errorf("launchd: NULL fds with count=%d for socket(%s): %w", count, name, ErrProtocol)
+	case count > maxActivatedFds:
+		return fmt.Errorf("launchd: invalid count=%d for socket(%s): %w", count, name, ErrProtocol)
+	default:
+		return nil
+	}
+}
+
+// Os specific implementation of [Files].
+func files(name string) ([]*os.File, error) {
+	ctx, task := trace.NewTask(context.Background(), "launchd.Files")
+	defer task.End()
+	trace.Log(ctx, "socket", name)
+
+	fdSlice, err := listenerFdsWithName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return newFiles(name, fdSlice)
+}
+
+// Os specific implementation of [ActivateRaw].
+func activateRaw(name string) ([]int32, error) {
+	ctx, task := trace.NewTask(context.Background(), "launchd.ActivateRaw")
+	defer task.End()
+	trace.Log(ctx, "socket", name)
+	return listenerFdsWithName(ctx, name)
+}
+
+// Os specific implementation of [FilesBatch]. Returns files and errors
+// for each of the names, in the same order.
+func filesBatch(names []string) ([][]*os.File, []error) {
+	ctx, task := trace.NewTask(context.Background(), "launchd.FilesBatch")
+	defer task.End()
+	for _, name := range names {
+		trace.Log(ctx, "socket", name)
+	}
+
+	fds, errs := listenerFdsWithNames(ctx, names)
+	files := make([][]*os.File, len(names))
+	for i, name := range names {
+		if errs[i] == nil {
+			files[i], errs[i] = newFiles(name, fds[i])
+		}
+	}
+	return files, errs
+}
+
+// listenerFdsEach returns file descriptors corresponding to each of the named
+// sockets, by calling listenerFdsWithName for each of them. This is used by
+// implementations which do not have a batched implementation.
+func listenerFdsEach(ctx context.Context, names []string) ([][]int32, []error) {
+	fds := make([][]int32, len(names))
+	errs := make([]error, len(names))
+	for i, name := range names {
+		fds[i], errs[i] = listenerFdsWithName(ctx, name)
+	}
+	return fds, errs
+}
+
+// closeFDs closes file descriptors which are not returned to the caller.
+func closeFDs(fds []int32) {
+	for _, fd := range fds {
+		_ = syscall.Close(int(fd))
+	}
+}
+
+// newFiles returns slice of [*os.File] for file descriptors of the named socket.
+// File descriptors are validated with [validateFd]. Invalid file descriptors
+// are skipped and their errors are joined, along with a partial list of files.
+func newFiles(name string, fdSlice []int32) ([]*os.File, error) {
+	var err error
+	files := make([]*os.File, 0, len(fdSlice))
+	seen := make(map[int32]struct{}, len(fdSlice))
+	for _, fd := range fdSlice {
+		// Duplicate descriptors would result in multiple files owning the
+		// same descriptor, which would be closed more than once.
+		if _, ok := seen[fd]; ok {
+			err = errors.Join(err, fmt.Errorf("launchd: duplicate file descriptor(%d) for socket(%s): %w", fd, name, ErrProtocol))
+			continue
+		}
+		seen[fd] = struct{}{}
+		if ev := validateFd(fd); ev != nil {
+			debug("launchd: invalid file descriptor",
+				slog.String("name", name),
+				slog.Int("fd", int(fd)),
+				slog.Any("err", ev),
+			)
+			err = errors.Join(err, fmt.Errorf("launchd: invalid file descriptor(%d) for socket(%s): %w", fd, name, ev))
+			continue
+		}
+		path, ok := socketPath(fd)
+		if ok {
+			markActivated(path)
+		} else {
+			path = "launchd:" + name
+		}
+		files = append(files, os.NewFile(uintptr(fd), path))
+	}
+	return slices.Clip(files), err
+}
+
+// socketPath returns the filesystem path of unix sockets bound to a path,
+// as returned by getsockname. It is used as the name of the file of the
+// activated socket. Other sockets use "launchd:<name>", similar to files
+// created by other packages.
+func socketPath(fd int32) (string, bool) {
+	if sa, err := syscall.Getsockname(int(fd)); err == nil {
+		if sa, ok := sa.(*syscall.SockaddrUnix); ok && sa.Name != "" && sa.Name[0] != '@' {
+			return sa.Name, true
+		}
+	}
+	return "", false
+}
+
+// validateFd checks if fd is an open file descriptor with fcntl(F_GETFD).
+// Unlike checking for zero, this does not reject file descriptor 0, which
+// is a valid descriptor, for example with inetd compatible jobs.
+func validateFd(fd int32) error {
+	if fd < 0 {
+		return syscall.EBADF
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(fd), syscall.F_GETFD, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Os specific implementation of [Activate].
+func activate(name string) ([]Socket, error) {
+	files, err := Files(name)
+	if len(files) == 0 {
+		return nil, err
+	}
+
+	sockets, ec := classify(name, files)
+	if err = errors.Join(err, ec); err != nil {
+		return sockets, fmt.Errorf("launchd: error classifying sockets: %w", err)
+	}
+	return sockets, nil
+}
+
+// classify classifies activated files with [newSocket]. Files which
+// cannot be classified are closed, as they are not returned to the
+// caller, and their errors are joined.
+func classify(name string, files []*os.File) ([]Socket, error) {
+	var err error
+	sockets := make([]Socket, 0, len(files))
+	for i, file := range files {
+		s, es := newSocket(name, file)
+		if es != nil {
+			err = errors.Join(err, es)
+			recordListenerError(name, es)
+			_ = file.Close()
+			continue
+		}
+		s.Index = i
+		sockets = append(sockets, s)
+	}
+	return slices.Clip(sockets), err
+}
+
+// Os specific implementation of [Open].
+func open(name string, o *options) (*Result, error) {
+	files, err := retry(name, o.retry, time.Sleep, func() ([]*os.File, int, error) {
+		f, err := Files(name)
+		return f, len(f), err
+	})
+	if len(files) == 0 {
+		return nil, err
+	}
+
+	r := newResult(name, files)
+	if err != nil {
+		// Invalid file descriptors are not part of skipped sockets.
+		r.Errors = append(r.Errors, err)
+	}
+	r.reusePort(o)
+	r.fastOpen(o)
+	r.buffers(o)
+	r.packetInfo(o)
+	r.udpOptions(o)
+	r.tune(o)
+	r.limit(o)
+	return r, r.check(o)
+}
+
+// Os specific implementation of [Listeners].
+func listeners(name string) ([]net.Listener, error) {
+	files, err := Files(name)
+	if len(files) == 0 {
+		return nil, err
+	}
+
+	sockets, ec := classify(name, files)
+	err = errors.Join(err, ec)
+	listeners := make([]net.Listener, 0, len(sockets))
+	for _, s := range sockets {
+		l, el := s.Listener()
+		if el != nil {
+			debug("launchd: failed to build listener",
+				slog.String("name", name),
+				slog.Int("fd", int(s.File.Fd())),
+				slog.Any("err", el),
+			)
+			err = errors.Join(err, el)
+			recordListenerError(name, el)
+		} else {
+			debug("launchd: built listener",
+				slog.String("name", name),
+				slog.Int("fd", int(s.File.Fd())),
+				slog.String("addr", l.Addr().String()),
+			)
+			listeners = append(listeners, l)
+		}
+		// Listeners use a duplicate of the file descriptor, and the
+		// file is not returned on error, thus it is always closed.
+		_ = s.File.Close()
+	}
+
+	if err != nil {
+		return slices.Clip(listeners), fmt.Errorf("launchd: error building listeners: %w", err)
+	}
+	return slices.Clip(listeners), nil
+}
+
+// Os specific implementation of [PacketListeners].
+func packetListeners(name string) ([]net.PacketConn, error) {
+	files, err := Files(name)
+	if len(files) == 0 {
+		return nil, err
+	}
+
+	sockets, ec := classify(name, files)
+	err = errors.Join(err, ec)
+	listeners := make([]net.PacketConn, 0, len(sockets))
+	for _, s := range sockets {
+		l, el := s.PacketConn()
+		if el != nil {
+			debug("launchd: failed to build packet conn",
+				slog.String("name", name),
+				slog.Int("fd", int(s.File.Fd())),
+				slog.Any("err", el),
+			)
+			err = errors.Join(err, el)
+			recordListenerError(name, el)
+		} else {
+			debug("launchd: built packet conn",
+				slog.String("name", name),
+				slog.Int("fd", int(s.File.Fd())),
+				slog.String("addr", l.LocalAddr().String()),
+			)
+			listeners = append(listeners, l)
+		}
+		_ = s.File.Close()
+	}
+
+	if err != nil {
+		return slices.Clip(listeners), fmt.Errorf("launchd: %w", err)
+	}
+	return slices.Clip(listeners), nil
+}
+
+// Os specific implementation of [Conns].
+func conns(name string) ([]net.Conn, error) {
+	files, err := Files(name)
+	if len(files) == 0 {
+		return nil, err
+	}
+
+	sockets, ec := classify(name, files)
+	err = errors.Join(err, ec)
+	conns := make([]net.Conn, 0, len(sockets))
+	for _, s := range sockets {
+		c, el := s.Conn()
+		if el != nil {
+			debug("launchd: failed to build conn",
+				slog.String("name", name),
+				slog.Int("fd", int(s.File.Fd())),
+				slog.Any("err", el),
+			)
+			err = errors.Join(err, el)
+			recordListenerError(name, el)
+		} else {
+			debug("launchd: built conn",
+				slog.String("name", name),
+				slog.Int("fd", int(s.File.Fd())),
+				slog.String("addr", c.LocalAddr().String()),
+			)
+			conns = append(conns, c)
+		}
+		_ = s.File.Close()
+	}
+
+	if err != nil {
+		return slices.Clip(conns), fmt.Errorf("launchd: error building conns: %w", err)
+	}
+	return slices.Clip(conns), nil
+}
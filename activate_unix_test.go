@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build (darwin && !ios) || (unix && launchd_simulator)
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestValidateFd(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %s", err)
+	}
+	t.Cleanup(func() {
+		r.Close()
+	})
+	closed := int32(w.Fd())
+	w.Close()
+
+	tt := []struct {
+		name   string
+		fd     int32
+		expect error
+	}{
+		{
+			name: "Stdin",
+			fd:   0,
+		},
+		{
+			name: "Pipe",
+			fd:   int32(r.Fd()),
+		},
+		{
+			name:   "Closed",
+			fd:     closed,
+			expect: syscall.EBADF,
+		},
+		{
+			name:   "Negative",
+			fd:     -1,
+			expect: syscall.EBADF,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateFd(tc.fd)
+			if !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%v, got=%v", tc.expect, err)
+			}
+		})
+	}
+}
+
+func TestNewFiles(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %s", err)
+	}
+	t.Cleanup(func() {
+		r.Close()
+	})
+
+	// newFiles takes ownership of the file descriptor.
+	fd, err := syscall.Dup(int(r.Fd()))
+	if err != nil {
+		t.Fatalf("failed to dup: %s", err)
+	}
+	closed := int32(w.Fd())
+	w.Close()
+
+	files, err := newFiles("test", []int32{int32(fd), closed})
+	t.Cleanup(func() {
+		for _, f := range files {
+			f.Close()
+		}
+	})
+	if !errors.Is(err, syscall.EBADF) {
+		t.Errorf("expected error=%s, got=%s", syscall.EBADF, err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected files=1, got=%d", len(files))
+	}
+	if files[0].Fd() != uintptr(fd) {
+		t.Errorf("expected fd=%d, got=%d", fd, files[0].Fd())
+	}
+}
+
+func TestNewFiles_Duplicate(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %s", err)
+	}
+	t.Cleanup(func() {
+		r.Close()
+		w.Close()
+	})
+
+	// newFiles takes ownership of the file descriptor.
+	fd, err := syscall.Dup(int(r.Fd()))
+	if err != nil {
+		t.Fatalf("failed to dup: %s", err)
+	}
+
+	files, err := newFiles("test", []int32{int32(fd), int32(fd)})
+	t.Cleanup(func() {
+		for _, f := range files {
+			f.Close()
+		}
+	})
+	if !errors.Is(err, ErrProtocol) {
+		t.Errorf("expected error=%s, got=%s", ErrProtocol, err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected files=1, got=%d", len(files))
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	dir, err := os.MkdirTemp("", "launchd-")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "sock")
+	ul, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { ul.Close() })
+	tl, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { tl.Close() })
+
+	fd := func(l net.Listener) int32 {
+		f, err := l.(interface{ File() (*os.File, error) }).File()
+		if err != nil {
+			t.Fatalf("failed to get file: %s", err)
+		}
+		t.Cleanup(func() { f.Close() })
+		return int32(f.Fd())
+	}
+
+	if name, ok := socketPath(fd(ul)); !ok || name != path {
+		t.Errorf("expected path=%s, got=%s(%t)", path, name, ok)
+	}
+	if name, ok := socketPath(fd(tl)); ok {
+		t.Errorf("expected no path for tcp socket, got=%s", name)
+	}
+}
+
+func FuzzCheckActivated(f *testing.F) {
+	f.Add(uint64(0xc000), uint64(2))
+	f.Add(uint64(0), uint64(1))
+	f.Add(uint64(0xc000), uint64(0))
+	f.Add(uint64(0xc000), uint64(maxActivatedFds+1))
+	f.Fuzz(func(t *testing.T, fds, count uint64) {
+		err := checkActivated("test", uintptr(fds), count)
+		if err == nil && (uintptr(fds) == 0 || count == 0 || count > maxActivatedFds) {
+			t.Errorf("expected error for fds=%#x count=%d", fds, count)
+		}
+	})
+}
+
+func TestCheckActivated(t *testing.T) {
+	tt := []struct {
+		name   string
+		fds    uintptr
+		count  uint64
+		expect error
+	}{
+		{
+			name:  "Valid",
+			fds:   0xc000,
+			count: 2,
+		},
+		{
+			name:   "NoSockets",
+			count:  0,
+			expect: syscall.ENOENT,
+		},
+		{
+			name:   "NoSocketsNonNULL",
+			fds:    0xc000,
+			count:  0,
+			expect: syscall.ENOENT,
+		},
+		{
+			name:   "NULLWithCount",
+			count:  1,
+			expect: ErrProtocol,
+		},
+		{
+			name:   "CountTooLarge",
+			fds:    0xc000,
+			count:  maxActivatedFds + 1,
+			expect: ErrProtocol,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkActivated("test", tc.fds, tc.count)
+			if !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%v, got=%v", tc.expect, err)
+			}
+		})
+	}
+}
+
+// eintrs returns n return codes of interrupted calls.
+func eintrs(n int) []uintptr {
+	rcs := make([]uintptr, n)
+	for i := range rcs {
+		rcs[i] = uintptr(syscall.EINTR)
+	}
+	return rcs
+}
+
+func TestIgnoringEINTR(t *testing.T) {
+	tt := []struct {
+		name   string
+		rcs    []uintptr
+		errnos []syscall.Errno
+		calls  int
+		rc     uintptr
+		errno  syscall.Errno
+	}{
+		{name: "success", rcs: []uintptr{0}, errnos: []syscall.Errno{0}, calls: 1},
+		{name: "errno-eintr", rcs: []uintptr{0}, errnos: []syscall.Errno{syscall.EINTR}, calls: 1, errno: syscall.EINTR},
+		{name: "rc-eintr", rcs: []uintptr{uintptr(syscall.EINTR), uintptr(syscall.ENOENT)}, errnos: []syscall.Errno{0, 0}, calls: 2, rc: uintptr(syscall.ENOENT)},
+		{name: "errno", rcs: []uintptr{0}, errnos: []syscall.Errno{syscall.EFAULT}, calls: 1, errno: syscall.EFAULT},
+		{
+			name:   "rc-eintr-retries-exceeded",
+			rcs:    eintrs(maxEINTRRetries + 2),
+			errnos: make([]syscall.Errno, maxEINTRRetries+2),
+			calls:  maxEINTRRetries + 1,
+			rc:     uintptr(syscall.EINTR),
+		},
+		{name: "ealready", rcs: []uintptr{uintptr(syscall.EALREADY)}, errnos: []syscall.Errno{0}, calls: 1, rc: uintptr(syscall.EALREADY)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			rc, errno := ignoringEINTR(func() (uintptr, syscall.Errno) {
+				i := calls
+				calls++
+				return tc.rcs[i], tc.errnos[i]
+			})
+			if calls != tc.calls {
+				t.Errorf("expected calls=%d, got=%d", tc.calls, calls)
+			}
+			if rc != tc.rc || errno != tc.errno {
+				t.Errorf("expected rc=%d errno=%d, got rc=%d errno=%d", tc.rc, tc.errno, rc, errno)
+			}
+		})
+	}
+}
+
+func TestActivationError_Unknown(t *testing.T) {
+	err := activationError("listener", syscall.EBUSY)
+	var ae *ActivationError
+	if !errors.As(err, &ae) || ae.Backend != activationBackend {
+		t.Errorf("expected ActivationError with backend=%s, got=%v", activationBackend, err)
+	}
+	if err = activationError("listener", syscall.EALREADY); !errors.Is(err, syscall.EALREADY) || errors.As(err, &ae) {
+		t.Errorf("expected known error for EALREADY, got=%v", err)
+	}
+}
+
+func TestClassify_ClosesRejectedFiles(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %s", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	fd := r.Fd()
+
+	sockets, err := classify("test", []*os.File{r})
+	if len(sockets) != 0 {
+		t.Errorf("expected no sockets, got=%d", len(sockets))
+	}
+	if !errors.Is(err, syscall.ENOTSOCK) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSOCK, err)
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, syscall.F_GETFD, 0); errno != syscall.EBADF {
+		t.Errorf("expected fd(%d) to be closed, got=%v", fd, errno)
+	}
+}
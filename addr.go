@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+)
+
+// Addr is the address of a listener built from an activated socket, along
+// with the name of the socket in the Sockets dictionary of the job.
+// String and Network return the same values as the wrapped address, so that
+// logging the address of a named listener is unchanged.
+type Addr struct {
+	net.Addr
+
+	// Name is the name of the socket in the Sockets dictionary of the job.
+	Name string
+}
+
+// Unwrap returns the wrapped address, like [*net.TCPAddr] or [*net.UnixAddr].
+func (a *Addr) Unwrap() net.Addr {
+	return a.Addr
+}
+
+// SocketName returns the name of the activated socket the address belongs to,
+// if addr was returned by a listener created with [NameListener].
+func SocketName(addr net.Addr) (string, bool) {
+	if a, ok := addr.(*Addr); ok {
+		return a.Name, true
+	}
+	return "", false
+}
+
+// NameListener returns a listener whose Addr returns [*Addr] with the socket
+// name, so that handlers serving multiple activated sockets can tell them
+// apart. Use [Addr.Unwrap] to get the underlying address, and Unwrap method
+// of the returned listener to get the underlying listener.
+// Functions of this package which inspect listener addresses, like
+// [CleanupUnixSocket], accept named listeners.
+func NameListener(l net.Listener, name string) net.Listener {
+	return &namedListener{Listener: l, addr: &Addr{Addr: l.Addr(), Name: name}}
+}
+
+// namedListener is a [net.Listener] with a named address.
+type namedListener struct {
+	net.Listener
+	addr *Addr
+}
+
+// Addr implements [net.Listener].
+func (l *namedListener) Addr() net.Addr {
+	return l.addr
+}
+
+// Unwrap returns the underlying listener.
+func (l *namedListener) Unwrap() net.Listener {
+	return l.Listener
+}
+
+// unwrapAddr returns the address wrapped by [*Addr], or addr itself.
+func unwrapAddr(addr net.Addr) net.Addr {
+	if a, ok := addr.(*Addr); ok {
+		return a.Addr
+	}
+	return addr
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+func TestNameListener(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	named := launchd.NameListener(l, "http")
+	t.Cleanup(func() {
+		named.Close()
+	})
+
+	addr := named.Addr()
+	if addr.String() != l.Addr().String() || addr.Network() != l.Addr().Network() {
+		t.Errorf("expected addr=%s, got=%s", l.Addr(), addr)
+	}
+	if name, ok := launchd.SocketName(addr); !ok || name != "http" {
+		t.Errorf("expected name=http, got=%s(%t)", name, ok)
+	}
+	if _, ok := addr.(*launchd.Addr).Unwrap().(*net.TCPAddr); !ok {
+		t.Errorf("expected unwrapped address to be *net.TCPAddr, got=%T", addr.(*launchd.Addr).Unwrap())
+	}
+	if u, ok := named.(interface{ Unwrap() net.Listener }); !ok || u.Unwrap() != l {
+		t.Errorf("expected Unwrap to return the listener")
+	}
+	if _, ok := launchd.SocketName(l.Addr()); ok {
+		t.Errorf("expected no name for unnamed listener")
+	}
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+)
+
+// AuditToken is the audit token (audit_token_t) of a process. Unlike process ID,
+// audit token includes the PID version, which changes whenever process ID
+// is reused, thus it is suitable for making authorization decisions.
+//
+// Audit tokens of XPC connections and mach messages, for example, as returned
+// by xpc_connection_get_audit_token, have the same layout and can be
+// converted to AuditToken directly.
+type AuditToken [8]uint32
+
+// AUID returns audit user ID of the process.
+func (t AuditToken) AUID() uint32 {
+	return t[0]
+}
+
+// EUID returns effective user ID of the process.
+func (t AuditToken) EUID() uint32 {
+	return t[1]
+}
+
+// EGID returns effective group ID of the process.
+func (t AuditToken) EGID() uint32 {
+	return t[2]
+}
+
+// UID returns real user ID of the process.
+func (t AuditToken) UID() uint32 {
+	return t[3]
+}
+
+// GID returns real group ID of the process.
+func (t AuditToken) GID() uint32 {
+	return t[4]
+}
+
+// PID returns process ID of the process.
+func (t AuditToken) PID() int {
+	return int(int32(t[5]))
+}
+
+// ASID returns audit session ID of the process.
+func (t AuditToken) ASID() uint32 {
+	return t[6]
+}
+
+// PIDVersion returns version of the process ID, which is incremented
+// whenever process ID is reused.
+func (t AuditToken) PIDVersion() uint32 {
+	return t[7]
+}
+
+// SigningIdentifier returns code signing identifier of the process, like
+// "com.example.app". Code signature is not validated, thus it must not be
+// used for authorization decisions on its own. Use [VerifyPeer] instead.
+//
+//   - [ErrPeerRequirement] is returned if process is not signed.
+//   - [syscall.ESRCH] is returned if process no longer exists.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func (t AuditToken) SigningIdentifier() (string, error) {
+	return signingIdentifier(t)
+}
+
+// PeerAuditToken returns audit token of the peer process of a unix socket
+// connection using LOCAL_PEERTOKEN socket option.
+//
+//   - [syscall.EAFNOSUPPORT] is returned if conn is not a unix socket connection.
+//   - [syscall.ENOTCONN] is returned if conn is not connected.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func PeerAuditToken(conn net.Conn) (AuditToken, error) {
+	return peerAuditToken(conn)
+}
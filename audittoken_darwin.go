@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"unsafe"
+)
+
+// Os specific implementation of [PeerAuditToken].
+func peerAuditToken(conn net.Conn) (AuditToken, error) {
+	var token AuditToken
+	err := controlUnix(conn, func(fd uintptr) error {
+		err := getsockopt(fd, solLocal, localPeerToken, unsafe.Pointer(&token), uint32(unsafe.Sizeof(token)))
+		if err != nil {
+			return fmt.Errorf("launchd: error getting peer audit token: %w", os.NewSyscallError("getsockopt", err))
+		}
+		return nil
+	})
+	return token, err
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Os specific implementation of [PeerAuditToken].
+func peerAuditToken(_ net.Conn) (AuditToken, error) {
+	return AuditToken{}, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [AuditToken.SigningIdentifier].
+func signingIdentifier(_ AuditToken) (string, error) {
+	return "", fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
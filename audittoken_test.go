@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+func TestAuditToken(t *testing.T) {
+	token := launchd.AuditToken{501, 502, 20, 503, 21, 1234, 100007, 9}
+	tt := []struct {
+		name   string
+		got    uint64
+		expect uint64
+	}{
+		{name: "AUID", got: uint64(token.AUID()), expect: 501},
+		{name: "EUID", got: uint64(token.EUID()), expect: 502},
+		{name: "EGID", got: uint64(token.EGID()), expect: 20},
+		{name: "UID", got: uint64(token.UID()), expect: 503},
+		{name: "GID", got: uint64(token.GID()), expect: 21},
+		{name: "PID", got: uint64(token.PID()), expect: 1234},
+		{name: "ASID", got: uint64(token.ASID()), expect: 100007},
+		{name: "PIDVersion", got: uint64(token.PIDVersion()), expect: 9},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.expect {
+				t.Errorf("expected=%d, got=%d", tc.expect, tc.got)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package authorization
+
+import (
+	"errors"
+)
+
+// Flags are authorization options (AuthorizationFlags).
+type Flags uint32
+
+// Authorization flags from Authorization.h.
+const (
+	// InteractionAllowed allows user interaction, for example,
+	// prompting for administrator credentials.
+	InteractionAllowed Flags = 1 << 0
+
+	// ExtendRights obtains rights which are not yet granted.
+	ExtendRights Flags = 1 << 1
+
+	// PreAuthorize obtains rights ahead of time, so that they can
+	// be used later by another process, like a privileged helper.
+	PreAuthorize Flags = 1 << 4
+)
+
+var (
+	// ErrDenied is returned if rights are not granted.
+	ErrDenied = errors.New("authorization: denied")
+
+	// ErrCanceled is returned if user canceled the authorization.
+	ErrCanceled = errors.New("authorization: canceled by user")
+
+	// ErrInteractionNotAllowed is returned if user interaction is
+	// required, but not allowed.
+	ErrInteractionNotAllowed = errors.New("authorization: interaction not allowed")
+
+	// ErrInvalidExternalForm is returned if external form is invalid or
+	// the authorization it refers to no longer exists.
+	ErrInvalidExternalForm = errors.New("authorization: invalid external form")
+)
+
+// ExternalForm is the external form of an [Authorization], which can be sent
+// to another process, like a privileged helper, over a trusted channel.
+type ExternalForm [32]byte
+
+// Authorization is an authorization session (AuthorizationRef).
+// Sessions must be freed with [Authorization.Free].
+type Authorization struct {
+	ref uintptr
+}
+
+// New creates an authorization session, obtaining the given rights, like
+// "system.privilege.admin" or rights specific to the application.
+// If rights is empty, session is created without obtaining any rights.
+//
+//   - [ErrDenied] is returned if rights were not granted.
+//   - [ErrCanceled] is returned if user canceled the authorization.
+//   - [ErrInteractionNotAllowed] is returned if interaction is required but not allowed.
+//   - [syscall.EINVAL] is returned if any of the rights contain NUL bytes.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func New(rights []string, flags Flags) (*Authorization, error) {
+	return create(rights, flags)
+}
+
+// FromExternalForm returns the authorization session from its external form.
+// This is typically used by privileged helpers to use the authorization
+// session of the calling application.
+//
+//   - [ErrInvalidExternalForm] is returned if external form is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func FromExternalForm(ext ExternalForm) (*Authorization, error) {
+	return fromExternalForm(ext)
+}
+
+// Verify checks if the authorization session with the external form has
+// been granted all the rights, without user interaction. This is intended
+// to be used by privileged helpers before performing operations on behalf
+// of the calling application.
+//
+//   - [ErrDenied] is returned if any of the rights are not granted.
+//   - [ErrInvalidExternalForm] is returned if external form is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Verify(ext ExternalForm, rights ...string) error {
+	a, err := FromExternalForm(ext)
+	if err != nil {
+		return err
+	}
+	defer a.Free(false)
+	return a.CopyRights(rights, ExtendRights)
+}
+
+// CopyRights obtains the given rights for the authorization session.
+//
+//   - [ErrDenied] is returned if rights were not granted.
+//   - [ErrCanceled] is returned if user canceled the authorization.
+//   - [ErrInteractionNotAllowed] is returned if interaction is required but not allowed.
+//   - [syscall.EINVAL] is returned if rights is empty or session has been freed.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func (a *Authorization) CopyRights(rights []string, flags Flags) error {
+	return a.copyRights(rights, flags)
+}
+
+// ExternalForm returns the external form of the authorization session.
+//
+//   - [syscall.EINVAL] is returned if session has been freed.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func (a *Authorization) ExternalForm() (ExternalForm, error) {
+	return a.externalForm()
+}
+
+// Free frees the authorization session. If destroy is true, rights
+// obtained by the session are destroyed, so that they cannot be used
+// by other processes with its external form. It is safe to call
+// Free more than once.
+func (a *Authorization) Free(destroy bool) {
+	a.free(destroy)
+}
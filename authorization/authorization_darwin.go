@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// mu protects ref of all sessions, so that Free does not race with
+// other methods of the same session.
+//
+//nolint:gochecknoglobals // shared by all sessions.
+var mu sync.RWMutex
+
+// statusError returns error for OSStatus returned by Authorization Services.
+func statusError(op string, err error) error {
+	var status macos.OSStatus
+	if errors.As(err, &status) {
+		switch status {
+		case macos.ErrAuthorizationDenied:
+			return fmt.Errorf("authorization: %s: %w", op, ErrDenied)
+		case macos.ErrAuthorizationCanceled:
+			return fmt.Errorf("authorization: %s: %w", op, ErrCanceled)
+		case macos.ErrAuthorizationInteractionNotAllowed:
+			return fmt.Errorf("authorization: %s: %w", op, ErrInteractionNotAllowed)
+		case macos.ErrAuthorizationInvalidRef, macos.ErrAuthorizationInvalidPointer:
+			return fmt.Errorf("authorization: %s: %w", op, syscall.EINVAL)
+		}
+	}
+	return fmt.Errorf("authorization: %s: %w", op, err)
+}
+
+// validRights checks that rights do not contain NUL bytes.
+func validRights(rights []string) error {
+	if i := slices.IndexFunc(rights, func(s string) bool {
+		return strings.ContainsRune(s, 0)
+	}); i != -1 {
+		return fmt.Errorf("authorization: invalid right(%q): %w", rights[i], syscall.EINVAL)
+	}
+	return nil
+}
+
+// Os specific implementation of [New].
+func create(rights []string, flags Flags) (*Authorization, error) {
+	if err := validRights(rights); err != nil {
+		return nil, err
+	}
+
+	ref, err := macos.AuthorizationCreate(rights, uint32(flags))
+	if err != nil {
+		return nil, statusError("create", err)
+	}
+	return &Authorization{ref: uintptr(ref)}, nil
+}
+
+// Os specific implementation of [FromExternalForm].
+func fromExternalForm(ext ExternalForm) (*Authorization, error) {
+	ref, err := macos.AuthorizationCreateFromExternalForm(macos.AuthorizationExternalForm(ext))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidExternalForm, err)
+	}
+	return &Authorization{ref: uintptr(ref)}, nil
+}
+
+// Os specific implementation of [Authorization.CopyRights].
+func (a *Authorization) copyRights(rights []string, flags Flags) error {
+	if len(rights) == 0 {
+		return fmt.Errorf("authorization: no rights specified: %w", syscall.EINVAL)
+	}
+	if err := validRights(rights); err != nil {
+		return err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	if a == nil || a.ref == 0 {
+		return fmt.Errorf("authorization: session is nil or freed: %w", syscall.EINVAL)
+	}
+
+	err := macos.AuthorizationCopyRights(macos.AuthorizationRef(a.ref), rights, uint32(flags))
+	if err != nil {
+		return statusError("copy rights", err)
+	}
+	return nil
+}
+
+// Os specific implementation of [Authorization.ExternalForm].
+func (a *Authorization) externalForm() (ExternalForm, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if a == nil || a.ref == 0 {
+		return ExternalForm{}, fmt.Errorf("authorization: session is nil or freed: %w", syscall.EINVAL)
+	}
+
+	ext, err := macos.AuthorizationMakeExternalForm(macos.AuthorizationRef(a.ref))
+	if err != nil {
+		return ExternalForm{}, statusError("external form", err)
+	}
+	return ExternalForm(ext), nil
+}
+
+// Os specific implementation of [Authorization.Free].
+func (a *Authorization) free(destroy bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if a == nil || a.ref == 0 {
+		return
+	}
+
+	var flags uint32
+	if destroy {
+		flags = macos.AuthorizationFlagDestroyRights
+	}
+	macos.AuthorizationFree(macos.AuthorizationRef(a.ref), flags)
+	a.ref = 0
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package authorization_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/authorization"
+)
+
+func TestAuthorization(t *testing.T) {
+	a, err := authorization.New(nil, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	defer a.Free(true)
+
+	ext, err := a.ExternalForm()
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	b, err := authorization.FromExternalForm(ext)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	b.Free(false)
+	b.Free(false)
+
+	if _, err = b.ExternalForm(); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+
+	// Without user interaction, admin rights are not granted to non-root users.
+	err = authorization.Verify(ext, "system.privilege.admin")
+	if os.Geteuid() != 0 && err == nil {
+		t.Errorf("expected admin rights to not be granted without interaction")
+	}
+}
+
+func TestAuthorization_Invalid(t *testing.T) {
+	_, err := authorization.New([]string{"system.privilege\x00admin"}, 0)
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+
+	_, err = authorization.FromExternalForm(authorization.ExternalForm{})
+	if !errors.Is(err, authorization.ErrInvalidExternalForm) {
+		t.Errorf("expected error=%s, got=%s", authorization.ErrInvalidExternalForm, err)
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package authorization
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [New].
+func create(_ []string, _ Flags) (*Authorization, error) {
+	return nil, fmt.Errorf("authorization: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [FromExternalForm].
+func fromExternalForm(_ ExternalForm) (*Authorization, error) {
+	return nil, fmt.Errorf("authorization: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Authorization.CopyRights].
+func (a *Authorization) copyRights(_ []string, _ Flags) error {
+	return fmt.Errorf("authorization: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Authorization.ExternalForm].
+func (a *Authorization) externalForm() (ExternalForm, error) {
+	return ExternalForm{}, fmt.Errorf("authorization: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Authorization.Free].
+func (a *Authorization) free(_ bool) {}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package authorization_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/authorization"
+)
+
+func TestUnsupported(t *testing.T) {
+	a, err := authorization.New(nil, 0)
+	if a != nil {
+		t.Errorf("expected no authorization on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+
+	_, err = authorization.FromExternalForm(authorization.ExternalForm{})
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+
+	err = authorization.Verify(authorization.ExternalForm{}, "system.privilege.admin")
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+
+	// Must not panic.
+	a.Free(true)
+}
@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package authorization provides bindings for [Authorization Services]
+// without using cgo.
+//
+// Applications installing launch daemons, or asking privileged helpers to
+// perform operations on their behalf, obtain rights from the user with
+// [New] or [Authorization.CopyRights], and send the [ExternalForm] of the
+// authorization to the helper. Helpers verify that the caller has been
+// granted the rights with [Verify], before performing the operation.
+//
+// On non-macOS platforms (including iOS), all functions return an error
+// wrapping [syscall.ENOTSUP].
+//
+// [Authorization Services]: https://developer.apple.com/documentation/security/authorization_services
+package authorization
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"syscall"
+)
+
+// SocketType is the type of sockets expected by [Expectation].
+type SocketType int
+
+const (
+	// Stream sockets are built as [net.Listener].
+	Stream SocketType = iota + 1
+
+	// Datagram sockets are built as [net.PacketConn].
+	Datagram
+)
+
+// String implements [fmt.Stringer].
+func (t SocketType) String() string {
+	switch t {
+	case Stream:
+		return "stream"
+	case Datagram:
+		return "dgram"
+	default:
+		return fmt.Sprintf("SocketType(%d)", int(t))
+	}
+}
+
+// Expectation is the expected configuration of a socket activated by [Bind].
+type Expectation struct {
+	// Type is the expected type of all the file descriptors of the socket.
+	Type SocketType
+
+	// Min is the minimum number of file descriptors of the socket.
+	// Defaults to 1.
+	Min int
+
+	// Max is the maximum number of file descriptors of the socket.
+	// If zero, number of file descriptors is not limited.
+	Max int
+
+	// Optional allows socket to be absent from the job.
+	Optional bool
+}
+
+// Expect are expectations of sockets, keyed by their names
+// in the Sockets dictionary of the job.
+type Expect map[string]Expectation
+
+// validate checks whether expectation is valid.
+func (e Expectation) validate(name string) error {
+	switch {
+	case e.Type != Stream && e.Type != Datagram:
+		return fmt.Errorf("%s: invalid expected type(%s): %w", name, e.Type, syscall.EINVAL)
+	case e.Max > 0 && e.Max < max(e.Min, 1):
+		return fmt.Errorf("%s: invalid expected range(%d-%d): %w", name, max(e.Min, 1), e.Max, syscall.EINVAL)
+	default:
+		return nil
+	}
+}
+
+// check checks whether the result meets expectation.
+func (e Expectation) check(r *Result) error {
+	if err := e.validate(r.Name); err != nil {
+		return err
+	}
+
+	n, other := len(r.Listeners), len(r.PacketConns)+len(r.Conns)
+	if e.Type == Datagram {
+		n, other = len(r.PacketConns), len(r.Listeners)+len(r.Conns)
+	}
+
+	if other > 0 {
+		return fmt.Errorf("%s: %d sockets are not of expected type(%s): %w",
+			r.Name, other, e.Type, syscall.ESOCKTNOSUPPORT)
+	}
+
+	minimum := max(e.Min, 1)
+	switch {
+	case e.Max > 0 && (n < minimum || n > e.Max):
+		return fmt.Errorf("%s: expected %d to %d sockets, got=%d: %w", r.Name, minimum, e.Max, n, syscall.ERANGE)
+	case n < minimum:
+		return fmt.Errorf("%s: expected at least %d sockets, got=%d: %w", r.Name, minimum, n, syscall.ERANGE)
+	}
+	return nil
+}
+
+// Bound are sockets activated by [Bind].
+type Bound struct {
+	results map[string]*Result
+}
+
+// Names returns the names of activated sockets in sorted order.
+// Optional sockets which are absent are not included.
+func (b *Bound) Names() []string {
+	names := make([]string, 0, len(b.results))
+	for name := range b.results {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Result returns [Result] of the socket, or nil if socket was not activated.
+func (b *Bound) Result(name string) *Result {
+	return b.results[name]
+}
+
+// Listeners returns listeners of the stream socket.
+func (b *Bound) Listeners(name string) []net.Listener {
+	if r := b.results[name]; r != nil {
+		return r.Listeners
+	}
+	return nil
+}
+
+// PacketConns returns packet connections of the datagram socket.
+func (b *Bound) PacketConns(name string) []net.PacketConn {
+	if r := b.results[name]; r != nil {
+		return r.PacketConns
+	}
+	return nil
+}
+
+// Close closes all the activated sockets.
+func (b *Bound) Close() error {
+	var err error
+	for _, r := range b.results {
+		err = errors.Join(err, r.Close())
+	}
+	return err
+}
+
+// Bind activates all the sockets declared by expectations with [Open] and
+// validates them against expectations, so that large daemons need not
+// repeat activation and validation code for each of their sockets.
+// Options are passed to [Open] for each of the sockets.
+//
+// Either all the sockets are activated and valid, or none at all. If any of
+// the sockets cannot be activated or does not meet expectations, all the
+// sockets activated are closed and errors for each of the sockets are
+// returned joined with [errors.Join].
+//
+//   - [syscall.ENOENT] or [syscall.ESRCH] is returned if a required socket is not found.
+//   - [syscall.ESOCKTNOSUPPORT] is returned if socket is not of expected type.
+//   - [syscall.ERANGE] is returned if socket has unexpected number of file descriptors.
+//   - [syscall.EINVAL] is returned if expectation is invalid, before activating any of the sockets.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// As with [Open], sockets must be activated exactly once, thus Bind must
+// be called exactly once for the given socket names.
+func Bind(expect Expect, opts ...Option) (*Bound, error) {
+	var err error
+	names := make([]string, 0, len(expect))
+	for name, e := range expect {
+		names = append(names, name)
+		err = errors.Join(err, e.validate(name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("launchd: invalid expectations: %w", err)
+	}
+	slices.Sort(names)
+
+	o := newOptions(opts)
+	b := &Bound{results: make(map[string]*Result, len(names))}
+	for _, name := range names {
+		e := expect[name]
+		r, eo := open(name, o)
+		if eo != nil {
+			if r != nil {
+				_ = r.Close()
+			}
+			if e.Optional && (errors.Is(eo, syscall.ENOENT) || errors.Is(eo, syscall.ESRCH)) {
+				continue
+			}
+			err = errors.Join(err, eo)
+			continue
+		}
+
+		b.results[name] = r
+		if ec := e.check(r); ec != nil {
+			err = errors.Join(err, ec)
+		}
+	}
+
+	if err != nil {
+		_ = b.Close()
+		return nil, fmt.Errorf("launchd: error binding sockets: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestExpectation_Check(t *testing.T) {
+	one := &Result{Name: "test", Listeners: make([]net.Listener, 1)}
+	two := &Result{Name: "test", Listeners: make([]net.Listener, 2)}
+	packet := &Result{Name: "test", PacketConns: make([]net.PacketConn, 1)}
+	mixed := &Result{Name: "test", Listeners: make([]net.Listener, 1), PacketConns: make([]net.PacketConn, 1)}
+	empty := &Result{Name: "test"}
+
+	tt := []struct {
+		name   string
+		expect Expectation
+		result *Result
+		err    error
+	}{
+		{name: "stream", expect: Expectation{Type: Stream}, result: one},
+		{name: "stream-default-min", expect: Expectation{Type: Stream}, result: empty, err: syscall.ERANGE},
+		{name: "stream-min", expect: Expectation{Type: Stream, Min: 2}, result: one, err: syscall.ERANGE},
+		{name: "stream-max", expect: Expectation{Type: Stream, Max: 1}, result: two, err: syscall.ERANGE},
+		{name: "stream-range", expect: Expectation{Type: Stream, Min: 1, Max: 2}, result: two},
+		{name: "stream-invalid-range", expect: Expectation{Type: Stream, Min: 3, Max: 2}, result: two, err: syscall.EINVAL},
+		{name: "stream-got-dgram", expect: Expectation{Type: Stream}, result: packet, err: syscall.ESOCKTNOSUPPORT},
+		{name: "stream-mixed", expect: Expectation{Type: Stream}, result: mixed, err: syscall.ESOCKTNOSUPPORT},
+		{name: "dgram", expect: Expectation{Type: Datagram}, result: packet},
+		{name: "dgram-got-stream", expect: Expectation{Type: Datagram}, result: one, err: syscall.ESOCKTNOSUPPORT},
+		{name: "invalid-type", expect: Expectation{}, result: one, err: syscall.EINVAL},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.expect.check(tc.result)
+			if tc.err == nil && err != nil {
+				t.Errorf("expected no error, got=%s", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestSocketType_String(t *testing.T) {
+	tt := []struct {
+		t      SocketType
+		expect string
+	}{
+		{t: Stream, expect: "stream"},
+		{t: Datagram, expect: "dgram"},
+		{t: 0, expect: "SocketType(0)"},
+	}
+	for _, tc := range tt {
+		if got := tc.t.String(); got != tc.expect {
+			t.Errorf("expected=%s, got=%s", tc.expect, got)
+		}
+	}
+}
+
+func TestBind_Invalid(t *testing.T) {
+	_, err := Bind(Expect{"http": {Type: Stream, Min: 2, Max: 1}})
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
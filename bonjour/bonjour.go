@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package bonjour
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode/utf8"
+)
+
+// Limits from RFC 6763 and RFC 6335.
+const (
+	maxNameLen        = 63
+	maxServiceNameLen = 15
+	maxTXTEntryLen    = 255
+	maxTXTLen         = 65535
+)
+
+// Service is a service to be registered with Bonjour.
+type Service struct {
+	// Name is the instance name of the service, for example "Example Web".
+	// If empty, the computer name is used.
+	Name string
+
+	// Type is the service type, including protocol, for example "_http._tcp".
+	// Subtypes can be specified with commas, for example "_http._tcp,_printer".
+	Type string
+
+	// Domain is the domain of the service. If empty, default domains,
+	// typically "local", are used.
+	Domain string
+
+	// Port is the port of the service.
+	Port int
+
+	// TXT are TXT records of the service, as key value pairs.
+	TXT map[string]string
+}
+
+// validate checks if the service can be registered.
+func (s *Service) validate() error {
+	if len(s.Name) > maxNameLen || !utf8.ValidString(s.Name) || strings.IndexByte(s.Name, 0) >= 0 {
+		return fmt.Errorf("bonjour: invalid service name(%q): %w", s.Name, syscall.EINVAL)
+	}
+	if err := validateType(s.Type); err != nil {
+		return err
+	}
+	if strings.IndexByte(s.Domain, 0) >= 0 {
+		return fmt.Errorf("bonjour: invalid domain(%q): %w", s.Domain, syscall.EINVAL)
+	}
+	if s.Port < 1 || s.Port > 65535 {
+		return fmt.Errorf("bonjour: invalid port(%d): %w", s.Port, syscall.EINVAL)
+	}
+	_, err := txtEntries(s.TXT)
+	return err
+}
+
+// validateType checks if service type is of the form "_name._tcp" or
+// "_name._udp", with optional subtypes.
+func validateType(typ string) error {
+	base, _, _ := strings.Cut(typ, ",")
+	name, ok := strings.CutSuffix(base, "._tcp")
+	if !ok {
+		name, ok = strings.CutSuffix(base, "._udp")
+	}
+	if ok {
+		name, ok = strings.CutPrefix(name, "_")
+	}
+	if !ok || name == "" || len(name) > maxServiceNameLen ||
+		name[0] == '-' || name[len(name)-1] == '-' ||
+		strings.IndexFunc(name, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-')
+		}) >= 0 {
+		return fmt.Errorf("bonjour: invalid service type(%q): %w", typ, syscall.EINVAL)
+	}
+	return nil
+}
+
+// txtEntries returns TXT records as "key=value" strings sorted by key.
+func txtEntries(txt map[string]string) ([]string, error) {
+	keys := make([]string, 0, len(txt))
+	for k := range txt {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var size int
+	entries := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := txt[k]
+		invalid := strings.IndexFunc(k, func(r rune) bool {
+			return r < 0x20 || r > 0x7e || r == '='
+		}) >= 0
+		if k == "" || invalid {
+			return nil, fmt.Errorf("bonjour: invalid TXT record key(%q): %w", k, syscall.EINVAL)
+		}
+		entry := k + "=" + v
+		if len(entry) > maxTXTEntryLen || strings.IndexByte(v, 0) >= 0 {
+			return nil, fmt.Errorf("bonjour: invalid TXT record(%s): %w", k, syscall.EINVAL)
+		}
+		size += 1 + len(entry)
+		entries = append(entries, entry)
+	}
+	if size > maxTXTLen {
+		return nil, fmt.Errorf("bonjour: TXT records are too large(%d bytes): %w", size, syscall.EINVAL)
+	}
+	return entries, nil
+}
+
+// txtRecord returns TXT records as TXT record data, i.e. "key=value"
+// strings sorted by key, each prefixed with its length.
+func txtRecord(txt map[string]string) ([]byte, error) {
+	entries, err := txtEntries(txt)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	for _, entry := range entries {
+		b = append(b, byte(len(entry)))
+		b = append(b, entry...)
+	}
+	return b, nil
+}
+
+// handle is an os specific registration of a service.
+type handle interface {
+	// UpdateTXT replaces TXT records of the service with TXT record data.
+	UpdateTXT(txt []byte) error
+
+	// Close deregisters the service.
+	Close() error
+}
+
+// Registration is a service registered with Bonjour.
+type Registration struct {
+	mu      sync.Mutex
+	service Service
+	handle  handle
+}
+
+// Register registers the service with Bonjour. Service remains registered
+// until the registration is closed. Registration is requested synchronously
+// from mDNSResponder, thus only errors reported when the request is received
+// are returned. If the name is already in use, service is renamed automatically.
+//
+//   - [syscall.EINVAL] is returned if service is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Register(ctx context.Context, s Service) (*Registration, error) {
+	s.TXT = maps.Clone(s.TXT)
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	h, err := start(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return &Registration{service: s, handle: h}, nil
+}
+
+// RegisterListener is like [Register], but uses the port of the listener,
+// typically built from an activated socket.
+//
+//   - [syscall.EAFNOSUPPORT] is returned if listener is not a TCP listener.
+func RegisterListener(ctx context.Context, l net.Listener, s Service) (*Registration, error) {
+	port, err := portOf(l.Addr())
+	if err != nil {
+		return nil, err
+	}
+	s.Port = port
+	return Register(ctx, s)
+}
+
+// portOf returns port of the TCP or UDP address. Addresses wrapping other
+// addresses, like those returned by launchd.NameListener, are unwrapped.
+func portOf(addr net.Addr) (int, error) {
+	if u, ok := addr.(interface{ Unwrap() net.Addr }); ok {
+		addr = u.Unwrap()
+	}
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		return addr.Port, nil
+	case *net.UDPAddr:
+		return addr.Port, nil
+	default:
+		return 0, fmt.Errorf("bonjour: address(%s) has no port: %w", addr, syscall.EAFNOSUPPORT)
+	}
+}
+
+// Service returns the registered service.
+func (r *Registration) Service() Service {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.service
+	s.TXT = maps.Clone(s.TXT)
+	return s
+}
+
+// UpdateTXT replaces TXT records of the registered service, without
+// registering it again, thus service remains available during the update.
+// If updating fails, service remains registered with previous TXT records.
+//
+//   - [syscall.EINVAL] is returned if TXT records are invalid.
+//   - [net.ErrClosed] is returned if registration is closed.
+func (r *Registration) UpdateTXT(_ context.Context, txt map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.handle == nil {
+		return fmt.Errorf("bonjour: registration is closed: %w", net.ErrClosed)
+	}
+	s := r.service
+	s.TXT = maps.Clone(txt)
+	if err := s.validate(); err != nil {
+		return err
+	}
+	record, err := txtRecord(s.TXT)
+	if err != nil {
+		return err
+	}
+	if err = r.handle.UpdateTXT(record); err != nil {
+		return err
+	}
+	r.service = s
+	return nil
+}
+
+// Close deregisters the service. It is safe to call Close multiple times.
+func (r *Registration) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handle == nil {
+		return nil
+	}
+	err := r.handle.Close()
+	r.handle = nil
+	return err
+}
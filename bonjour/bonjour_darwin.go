@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package bonjour
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// dnssdHandle is a service registered with DNSServiceRegister.
+type dnssdHandle struct {
+	ref macos.DNSServiceRef
+}
+
+// UpdateTXT replaces TXT records of the service with DNSServiceUpdateRecord.
+func (h *dnssdHandle) UpdateTXT(txt []byte) error {
+	if err := macos.DNSServiceUpdateTXT(h.ref, txt); err != nil {
+		return fmt.Errorf("bonjour: failed to update TXT records: %w", dnssdError(err))
+	}
+	return nil
+}
+
+// Close deregisters the service.
+func (h *dnssdHandle) Close() error {
+	macos.DNSServiceRefDeallocate(h.ref)
+	h.ref = 0
+	return nil
+}
+
+// Os specific implementation of [Register]. Returns a handle which
+// updates and deregisters the service.
+func start(ctx context.Context, s Service) (handle, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("bonjour: %w", err)
+	}
+	txt, err := txtRecord(s.TXT)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := macos.DNSServiceRegister(s.Name, s.Type, s.Domain, uint16(s.Port), txt)
+	if err != nil {
+		return nil, fmt.Errorf("bonjour: failed to register service: %w", dnssdError(err))
+	}
+	return &dnssdHandle{ref: ref}, nil
+}
+
+// dnssdError wraps errors returned by DNS Service Discovery API
+// with corresponding [syscall.Errno] where possible.
+func dnssdError(err error) error {
+	var status macos.DNSServiceError
+	if !errors.As(err, &status) {
+		return err
+	}
+	switch status {
+	case macos.DNSServiceErrBadParam:
+		return fmt.Errorf("%w: %w", err, syscall.EINVAL)
+	case macos.DNSServiceErrServiceNotRunning:
+		return fmt.Errorf("%w: %w", err, syscall.ECONNREFUSED)
+	case macos.DNSServiceErrPolicyDenied:
+		return fmt.Errorf("%w: %w", err, syscall.EPERM)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package bonjour
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [Register]. Returns a handle which
+// updates and deregisters the service.
+func start(_ context.Context, _ Service) (handle, error) {
+	return nil, fmt.Errorf("bonjour: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package bonjour_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/bonjour"
+)
+
+func TestUnsupported(t *testing.T) {
+	s := bonjour.Service{Type: "_http._tcp", Port: 8080}
+	if _, err := bonjour.Register(context.Background(), s); !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+
+	// Invalid services are rejected on all platforms.
+	s.Port = 0
+	if _, err := bonjour.Register(context.Background(), s); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package bonjour
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestService_Validate(t *testing.T) {
+	valid := Service{Name: "Example Web", Type: "_http._tcp", Port: 8080, TXT: map[string]string{"path": "/"}}
+	tt := []struct {
+		name   string
+		modify func(s *Service)
+		expect error
+	}{
+		{name: "Valid", modify: func(*Service) {}},
+		{name: "DefaultName", modify: func(s *Service) { s.Name = "" }},
+		{name: "UDP", modify: func(s *Service) { s.Type = "_statsd._udp" }},
+		{name: "Subtype", modify: func(s *Service) { s.Type = "_http._tcp,_printer" }},
+		{name: "EmptyTXTValue", modify: func(s *Service) { s.TXT = map[string]string{"flag": ""} }},
+		{name: "NameTooLong", modify: func(s *Service) { s.Name = strings.Repeat("a", 64) }, expect: syscall.EINVAL},
+		{name: "TypeNoProtocol", modify: func(s *Service) { s.Type = "_http" }, expect: syscall.EINVAL},
+		{name: "TypeNoUnderscore", modify: func(s *Service) { s.Type = "http._tcp" }, expect: syscall.EINVAL},
+		{name: "TypeTooLong", modify: func(s *Service) { s.Type = "_abcdefghijklmnop._tcp" }, expect: syscall.EINVAL},
+		{name: "TypeInvalidChar", modify: func(s *Service) { s.Type = "_ht.tp._tcp" }, expect: syscall.EINVAL},
+		{name: "TypeHyphen", modify: func(s *Service) { s.Type = "_http-._tcp" }, expect: syscall.EINVAL},
+		{name: "PortZero", modify: func(s *Service) { s.Port = 0 }, expect: syscall.EINVAL},
+		{name: "PortTooLarge", modify: func(s *Service) { s.Port = 65536 }, expect: syscall.EINVAL},
+		{name: "TXTEmptyKey", modify: func(s *Service) { s.TXT = map[string]string{"": "v"} }, expect: syscall.EINVAL},
+		{name: "TXTKeyEquals", modify: func(s *Service) { s.TXT = map[string]string{"a=b": "v"} }, expect: syscall.EINVAL},
+		{name: "TXTTooLong", modify: func(s *Service) { s.TXT = map[string]string{"k": strings.Repeat("v", 254)} }, expect: syscall.EINVAL},
+		{name: "TXTNUL", modify: func(s *Service) { s.TXT = map[string]string{"k": "v\x00"} }, expect: syscall.EINVAL},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid
+			tc.modify(&s)
+			err := s.validate()
+			if !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%v, got=%v", tc.expect, err)
+			}
+		})
+	}
+}
+
+func TestTXTRecord(t *testing.T) {
+	record, err := txtRecord(map[string]string{"version": "1.2", "path": "/"})
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	expect := []byte("\x06path=/\x0bversion=1.2")
+	if !bytes.Equal(record, expect) {
+		t.Errorf("expected record=%q, got=%q", expect, record)
+	}
+
+	record, err = txtRecord(nil)
+	if err != nil || len(record) != 0 {
+		t.Errorf("expected empty record, got=%q, err=%v", record, err)
+	}
+
+	if _, err = txtRecord(map[string]string{"": "v"}); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%v", syscall.EINVAL, err)
+	}
+}
+
+// fakeHandle records TXT record updates.
+type fakeHandle struct {
+	txt    []byte
+	err    error
+	closed bool
+}
+
+func (h *fakeHandle) UpdateTXT(txt []byte) error {
+	if h.err != nil {
+		return h.err
+	}
+	h.txt = txt
+	return nil
+}
+
+func (h *fakeHandle) Close() error {
+	h.closed = true
+	return nil
+}
+
+func TestRegistration_UpdateTXT(t *testing.T) {
+	errUpdate := errors.New("update failed")
+	s := Service{Type: "_http._tcp", Port: 8080, TXT: map[string]string{"version": "1"}}
+
+	t.Run("Updated", func(t *testing.T) {
+		h := &fakeHandle{}
+		r := &Registration{service: s, handle: h}
+		if err := r.UpdateTXT(context.Background(), map[string]string{"version": "2"}); err != nil {
+			t.Fatalf("expected no error, got=%s", err)
+		}
+		if v := r.Service().TXT["version"]; v != "2" {
+			t.Errorf("expected version=2, got=%s", v)
+		}
+		if expect := []byte("\x09version=2"); !bytes.Equal(h.txt, expect) {
+			t.Errorf("expected record=%q, got=%q", expect, h.txt)
+		}
+		if h.closed {
+			t.Errorf("expected service to remain registered")
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		h := &fakeHandle{err: errUpdate}
+		r := &Registration{service: s, handle: h}
+		if err := r.UpdateTXT(context.Background(), map[string]string{"version": "2"}); !errors.Is(err, errUpdate) {
+			t.Errorf("expected error=%s, got=%v", errUpdate, err)
+		}
+		if v := r.Service().TXT["version"]; v != "1" {
+			t.Errorf("expected previous version=1, got=%s", v)
+		}
+		if h.closed {
+			t.Errorf("expected service to remain registered")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		r := &Registration{service: s, handle: &fakeHandle{}}
+		if err := r.UpdateTXT(context.Background(), map[string]string{"": "v"}); !errors.Is(err, syscall.EINVAL) {
+			t.Errorf("expected error=%s, got=%v", syscall.EINVAL, err)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		h := &fakeHandle{}
+		r := &Registration{service: s, handle: h}
+		if err := r.Close(); err != nil {
+			t.Fatalf("expected no error, got=%s", err)
+		}
+		if !h.closed {
+			t.Errorf("expected service to be deregistered")
+		}
+		if err := r.UpdateTXT(context.Background(), nil); !errors.Is(err, net.ErrClosed) {
+			t.Errorf("expected error=%s, got=%v", net.ErrClosed, err)
+		}
+	})
+}
+
+// namedAddr wraps an address like launchd.Addr.
+type namedAddr struct {
+	net.Addr
+}
+
+func (a namedAddr) Unwrap() net.Addr {
+	return a.Addr
+}
+
+func TestPortOf(t *testing.T) {
+	tt := []struct {
+		name   string
+		addr   net.Addr
+		port   int
+		expect error
+	}{
+		{name: "TCP", addr: &net.TCPAddr{Port: 8080}, port: 8080},
+		{name: "UDP", addr: &net.UDPAddr{Port: 8125}, port: 8125},
+		{name: "Wrapped", addr: namedAddr{&net.TCPAddr{Port: 443}}, port: 443},
+		{name: "Unix", addr: &net.UnixAddr{Name: "/tmp/svc.sock"}, expect: syscall.EAFNOSUPPORT},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			port, err := portOf(tc.addr)
+			if !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%v, got=%v", tc.expect, err)
+			}
+			if port != tc.port {
+				t.Errorf("expected port=%d, got=%d", tc.port, port)
+			}
+		})
+	}
+}
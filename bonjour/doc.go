@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package bonjour announces services over Bonjour (DNS-SD over multicast DNS)
+// with mDNSResponder, without using cgo.
+//
+// launchd can register activated sockets with Bonjour via the Bonjour key
+// of the socket, but such registrations cannot carry TXT records which are
+// only known at runtime, like versions or capabilities of the service.
+// This package registers services, typically on the port of an activated
+// socket, with TXT records which can be updated while the service is running.
+//
+// Services are registered with DNSServiceRegister, and TXT records are updated
+// in place with DNSServiceUpdateRecord, thus services remain available while
+// TXT records are updated. As DNSServiceRegister takes more arguments than
+// can be passed to C functions via the runtime, it is called via a small
+// assembly shim which passes the remaining arguments on the stack. Services
+// are deregistered when the registration is closed, or when the job exits,
+// as mDNSResponder deregisters services of processes which exit.
+//
+// On non-macOS platforms (including iOS), all functions return an error
+// wrapping [syscall.ENOTSUP].
+package bonjour
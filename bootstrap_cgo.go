@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && cgo && launchd_cgo
+
+package launchd
+
+/*
+#include <stdlib.h>
+#include <mach/mach.h>
+#include <servers/bootstrap.h>
+
+// launchd_bootstrap_look_up looks up the mach service in the bootstrap
+// namespace of the process and releases the returned port.
+static kern_return_t launchd_bootstrap_look_up(const char *name) {
+	mach_port_t port = MACH_PORT_NULL;
+	kern_return_t kr = bootstrap_look_up(bootstrap_port, name, &port);
+	if (kr == KERN_SUCCESS) {
+		mach_port_deallocate(mach_task_self(), port);
+	}
+	return kr;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// machServiceRegistered reports whether the mach service is registered
+// in the bootstrap namespace of the process.
+//
+// Unlike the default implementation, this calls bootstrap_look_up via cgo.
+// This does not start the job providing the service.
+func machServiceRegistered(name string) (bool, error) {
+	if len(name) >= C.BOOTSTRAP_MAX_NAME_LEN || strings.IndexByte(name, 0) >= 0 {
+		return false, fmt.Errorf("launchd: invalid mach service name(%q): %w", name, syscall.EINVAL)
+	}
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	switch kr := C.launchd_bootstrap_look_up(cname); kr {
+	case C.KERN_SUCCESS:
+		return true, nil
+	case C.BOOTSTRAP_UNKNOWN_SERVICE:
+		return false, nil
+	default:
+		return false, fmt.Errorf("launchd: bootstrap_look_up(%s) failed: kern_return(%d)", name, int32(kr))
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !(cgo && launchd_cgo)
+
+package launchd
+
+import (
+	"fmt"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// machServiceRegistered reports whether the mach service is registered
+// in the bootstrap namespace of the process.
+func machServiceRegistered(name string) (bool, error) {
+	ok, err := macos.MachServiceRegistered(name)
+	if err != nil {
+		return false, fmt.Errorf("launchd: %w", err)
+	}
+	return ok, nil
+}
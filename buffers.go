@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// WithUDPBuffers sets the size of receive and send buffers (SO_RCVBUF and
+// SO_SNDBUF) of datagram sockets returned by [Open] as packet connections.
+// High throughput services, like syslog, statsd or QUIC servers, typically
+// need larger buffers than macOS defaults. Buffer sizes which are not
+// positive are left unchanged.
+//
+// Sizes are limited by kern.ipc.maxsockbuf sysctl on macOS. Failing to set
+// buffer sizes is handled like any other socket which cannot be used,
+// i.e. [Open] returns an error unless [WithPartial] is used.
+func WithUDPBuffers(rcv, snd int) Option {
+	return func(o *options) {
+		o.rcvBuf = rcv
+		o.sndBuf = snd
+	}
+}
+
+// bufferSetter is implemented by [*net.UDPConn] and [*net.UnixConn].
+type bufferSetter interface {
+	SetReadBuffer(bytes int) error
+	SetWriteBuffer(bytes int) error
+}
+
+// buffers sets buffer sizes of packet connections of the result, if configured.
+// Packet connections which fail are closed and reported as errors,
+// like sockets which could not be used.
+func (r *Result) buffers(o *options) {
+	if o.rcvBuf <= 0 && o.sndBuf <= 0 {
+		return
+	}
+
+	var err error
+	packetConns := r.PacketConns[:0]
+	for _, p := range r.PacketConns {
+		bs, ok := p.(bufferSetter)
+		if !ok {
+			packetConns = append(packetConns, p)
+			continue
+		}
+
+		var e error
+		if o.rcvBuf > 0 {
+			e = bs.SetReadBuffer(o.rcvBuf)
+		}
+		if e == nil && o.sndBuf > 0 {
+			e = bs.SetWriteBuffer(o.sndBuf)
+		}
+		debug("launchd: setting socket buffer sizes",
+			slog.String("name", r.Name),
+			slog.String("addr", p.LocalAddr().String()),
+			slog.Int("rcvbuf", o.rcvBuf),
+			slog.Int("sndbuf", o.sndBuf),
+			slog.Any("err", e),
+		)
+		if e != nil {
+			err = errors.Join(err, fmt.Errorf("launchd: failed to set buffer sizes(%s): %w", p.LocalAddr(), e))
+			_ = p.Close()
+			continue
+		}
+		packetConns = append(packetConns, p)
+	}
+	r.PacketConns = packetConns
+	if err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestResult_Buffers(t *testing.T) {
+	p, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	r := &Result{Name: "test", PacketConns: []net.PacketConn{p}}
+	defer r.Close()
+
+	const size = 64 << 10
+	r.buffers(newOptions([]Option{WithUDPBuffers(size, size)}))
+	if len(r.PacketConns) != 1 || r.Partial() {
+		t.Fatalf("expected no error, got=%v", r.Err())
+	}
+
+	rc, err := p.(*net.UDPConn).SyscallConn()
+	if err != nil {
+		t.Fatalf("failed to get raw conn: %s", err)
+	}
+	for _, opt := range []int{syscall.SO_RCVBUF, syscall.SO_SNDBUF} {
+		var v int
+		_ = rc.Control(func(fd uintptr) {
+			v, err = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, opt)
+		})
+		if err != nil {
+			t.Fatalf("failed to get socket option: %s", err)
+		}
+		// Linux doubles the requested size for bookkeeping overhead.
+		if v < size {
+			t.Errorf("expected buffer size >= %d, got=%d", size, v)
+		}
+	}
+}
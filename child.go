@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Environment variables used to pass sockets to child processes.
+// These follow the conventions of systemd's sd_listen_fds.
+const (
+	listenFDsEnv     = "LISTEN_FDS"
+	listenFDNamesEnv = "LISTEN_FDNAMES"
+	listenPIDEnv     = "LISTEN_PID"
+)
+
+// listenFDsStart is the first file descriptor passed to child processes.
+const listenFDsStart = 3
+
+// PassToChild configures cmd to inherit listeners, so that worker processes
+// of prefork style servers can accept connections on sockets activated
+// by launchd. It can be called multiple times with different names,
+// but must be called before cmd is started.
+//
+// Listeners are passed via [exec.Cmd.ExtraFiles], starting at file
+// descriptor 3, and are described by environment variables LISTEN_FDS
+// and LISTEN_FDNAMES. Child processes can use [InheritedListeners] to get
+// them. As pid of the child is not known before it is started, LISTEN_PID
+// is not set, thus libraries which require it (like sd_listen_fds) cannot
+// be used by the child. If cmd.Env is nil, it is initialized with
+// environment of the current process, excluding the variables above.
+//
+// Listeners remain usable by the caller. Files added to cmd.ExtraFiles
+// are duplicates of the listeners and should be closed by the caller once
+// cmd has started.
+//
+//   - [syscall.EINVAL] is returned if name is invalid, if a listener
+//     does not have an underlying file, or if cmd.ExtraFiles has files
+//     not added by PassToChild.
+func PassToChild(cmd *exec.Cmd, name string, listeners []net.Listener) error {
+	if cmd == nil {
+		return fmt.Errorf("launchd: cmd is nil: %w", syscall.EINVAL)
+	}
+	if err := ValidateSocketName(name); err != nil {
+		return err
+	}
+
+	// Sockets passed to the current process, if any, are not inherited.
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+		for _, key := range []string{listenFDsEnv, listenFDNamesEnv, listenPIDEnv} {
+			cmd.Env = unsetEnv(cmd.Env, key)
+		}
+	}
+
+	// Files not described by LISTEN_FDS would shift file descriptors
+	// of passed listeners.
+	count, names := childFDs(cmd.Env)
+	if count != len(cmd.ExtraFiles) {
+		return fmt.Errorf("launchd: cmd has extra files not passed by PassToChild: %w", syscall.EINVAL)
+	}
+
+	files := make([]*os.File, 0, len(listeners))
+	for _, l := range listeners {
+		fl, ok := l.(interface{ File() (*os.File, error) })
+		if !ok {
+			closeFiles(files)
+			return fmt.Errorf("launchd: listener(%s) does not support File: %w", l.Addr(), syscall.EINVAL)
+		}
+		f, err := fl.File()
+		if err != nil {
+			closeFiles(files)
+			return fmt.Errorf("launchd: failed to get file for listener(%s): %w", l.Addr(), err)
+		}
+		files = append(files, f)
+		names = append(names, name)
+	}
+
+	cmd.ExtraFiles = append(cmd.ExtraFiles, files...)
+	cmd.Env = setEnv(cmd.Env, listenFDsEnv, strconv.Itoa(len(cmd.ExtraFiles)))
+	cmd.Env = setEnv(cmd.Env, listenFDNamesEnv, strings.Join(names, ":"))
+	return nil
+}
+
+// InheritedListeners returns listeners with the given name passed to the
+// current process by its parent with [PassToChild]. Listeners are matched
+// by name, using environment variables LISTEN_FDS and LISTEN_FDNAMES.
+// If LISTEN_PID is set, it must match pid of the current process.
+//
+// Closing returned listeners does not close underlying file descriptors.
+// This must be called exactly once for given socket name. Subsequent calls
+// will return an error.
+//
+//   - [syscall.ENOENT] is returned if no listeners with the name were passed.
+func InheritedListeners(name string) ([]net.Listener, error) {
+	if pid := os.Getenv(listenPIDEnv); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, fmt.Errorf("launchd: sockets were not passed to this process: %w", syscall.ENOENT)
+	}
+
+	count, names := childFDs(os.Environ())
+	var err error
+	var listeners []net.Listener
+	for i := 0; i < count; i++ {
+		if i >= len(names) || names[i] != name {
+			continue
+		}
+
+		f := os.NewFile(uintptr(listenFDsStart+i), name)
+		l, lErr := net.FileListener(f)
+		if lErr != nil {
+			err = errors.Join(err, fmt.Errorf("launchd: socket(%s): %w", name, lErr))
+		} else {
+			listeners = append(listeners, l)
+		}
+		_ = f.Close()
+	}
+
+	if len(listeners) == 0 && err == nil {
+		return nil, fmt.Errorf("launchd: socket(%s) was not passed: %w", name, syscall.ENOENT)
+	}
+	return listeners, err
+}
+
+// childFDs returns number and names of file descriptors
+// described by LISTEN_FDS and LISTEN_FDNAMES in env.
+func childFDs(env []string) (int, []string) {
+	var count int
+	var names []string
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		switch k {
+		case listenFDsEnv:
+			count, _ = strconv.Atoi(v)
+		case listenFDNamesEnv:
+			if v != "" {
+				names = strings.Split(v, ":")
+			}
+		}
+	}
+	return max(count, 0), names
+}
+
+// unsetEnv returns a copy of env without key.
+func unsetEnv(env []string, key string) []string {
+	out := make([]string, 0, len(env))
+	for _, kv := range env {
+		if k, _, _ := strings.Cut(kv, "="); k != key {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
+
+// setEnv returns a copy of env with key set to value.
+func setEnv(env []string, key, value string) []string {
+	return append(unsetEnv(env, key), key+"="+value)
+}
+
+// closeFiles closes files.
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+func TestPassToChild_Invalid(t *testing.T) {
+	tt := []struct {
+		name   string
+		cmd    *exec.Cmd
+		socket string
+	}{
+		{name: "nil-cmd", socket: "Listeners"},
+		{name: "empty-name", cmd: exec.Command("true"), socket: ""},
+		{name: "name-with-colon", cmd: exec.Command("true"), socket: "a:b"},
+		{
+			name:   "foreign-extra-files",
+			cmd:    &exec.Cmd{Path: "true", ExtraFiles: []*os.File{os.Stdin}},
+			socket: "Listeners",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := launchd.PassToChild(tc.cmd, tc.socket, nil)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
+
+func TestInheritedListeners_NotPassed(t *testing.T) {
+	t.Setenv("LISTEN_FDS", "")
+	t.Setenv("LISTEN_FDNAMES", "")
+	_, err := launchd.InheritedListeners("Listeners")
+	if !errors.Is(err, syscall.ENOENT) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOENT, err)
+	}
+}
+
+func TestPassToChild_Env(t *testing.T) {
+	l1, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l1.Close()
+
+	l2, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l2.Close()
+
+	cmd := &exec.Cmd{Path: "true", Env: []string{"HOME=/"}}
+	if err = launchd.PassToChild(cmd, "http", []net.Listener{l1}); err != nil {
+		t.Skipf("listener files are not supported: %s", err)
+	}
+	if err = launchd.PassToChild(cmd, "admin", []net.Listener{l2}); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	defer func() {
+		for _, f := range cmd.ExtraFiles {
+			f.Close()
+		}
+	}()
+
+	if len(cmd.ExtraFiles) != 2 {
+		t.Errorf("expected 2 extra files, got=%d", len(cmd.ExtraFiles))
+	}
+
+	for _, expect := range []string{"HOME=/", "LISTEN_FDS=2", "LISTEN_FDNAMES=http:admin"} {
+		if !slices.Contains(cmd.Env, expect) {
+			t.Errorf("expected env to contain=%s, got=%v", expect, cmd.Env)
+		}
+	}
+}
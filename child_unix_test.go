@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd_test
+
+import (
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+// TestInheritedListeners_Child is run as a child process
+// by TestPassToChild.
+func TestInheritedListeners_Child(t *testing.T) {
+	if os.Getenv("GO_LAUNCHD_TEST_CHILD") != "1" {
+		t.Skip("only run as child process")
+	}
+
+	listeners, err := launchd.InheritedListeners("http")
+	if err != nil || len(listeners) != 1 {
+		t.Fatalf("expected 1 listener, got=%d, err=%s", len(listeners), err)
+	}
+	defer listeners[0].Close()
+
+	c, err := listeners[0].Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+	defer c.Close()
+	_, _ = c.Write([]byte("child"))
+}
+
+func TestPassToChild(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInheritedListeners_Child$", "-test.count=1")
+	cmd.Env = append(os.Environ(), "GO_LAUNCHD_TEST_CHILD=1")
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err = launchd.PassToChild(cmd, "http", []net.Listener{l}); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if err = cmd.Start(); err != nil {
+		t.Fatalf("failed to start child: %s", err)
+	}
+	for _, f := range cmd.ExtraFiles {
+		f.Close()
+	}
+
+	// Parent does not accept, so connection is accepted by the child.
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer c.Close()
+
+	b, err := io.ReadAll(c)
+	if err != nil || string(b) != "child" {
+		t.Errorf("expected=child, got=%s, err=%s", b, err)
+	}
+
+	if err = cmd.Wait(); err != nil {
+		t.Errorf("child failed: %s", err)
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// activatedPaths maps socket paths of unix sockets activated by launchd
+// to [fs.FileInfo] of the socket file, as it was when activated.
+//
+//nolint:gochecknoglobals // registry of activated sockets.
+var activatedPaths sync.Map
+
+// markActivated records that socket file at path is owned by launchd.
+// Socket file is identified by its device and inode, thus a socket
+// created later at the same path is not considered to be activated.
+func markActivated(path string) {
+	if fi, err := os.Lstat(path); err == nil {
+		activatedPaths.Store(path, fi)
+	}
+}
+
+// isActivated reports whether fi is a socket file activated by launchd.
+func isActivated(path string, fi fs.FileInfo) bool {
+	v, ok := activatedPaths.Load(path)
+	if !ok {
+		return false
+	}
+	activated, ok := v.(fs.FileInfo)
+	return ok && os.SameFile(activated, fi)
+}
+
+// CleanupUnixSocket removes the socket path of the unix listener, which
+// was bound by the process itself, but is not unlinked when closed.
+// For example, listeners built with [net.FileListener] from sockets
+// inherited from a previous instance, or with SetUnlinkOnClose(false).
+// It is a no-op if listener is not a unix listener, is not bound to a
+// filesystem path or if path has already been removed. Path is only
+// removed if it is still a socket.
+//
+// This must not be used with sockets activated by launchd. launchd owns
+// them and keeps listening on them after the job exits, to start the job
+// again on demand. Removing their paths would prevent clients from
+// connecting, and thus the job from being started.
+//
+//   - [syscall.EPERM] is returned if listener is a socket activated by launchd.
+func CleanupUnixSocket(l net.Listener) error {
+	if l == nil {
+		return fmt.Errorf("launchd: listener is nil: %w", syscall.EINVAL)
+	}
+
+	addr, ok := unwrapAddr(l.Addr()).(*net.UnixAddr)
+	if !ok || addr.Name == "" || addr.Name[0] == '@' {
+		return nil
+	}
+
+	fi, err := os.Lstat(addr.Name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("launchd: %w", err)
+	}
+	if fi.Mode().Type() != fs.ModeSocket {
+		return nil
+	}
+
+	if isActivated(addr.Name, fi) {
+		return fmt.Errorf("launchd: socket(%s) is owned by launchd: %w", addr.Name, syscall.EPERM)
+	}
+
+	if err = os.Remove(addr.Name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("launchd: failed to remove socket path: %w", err)
+	}
+	return nil
+}
+
+// RemoveStaleUnixSocket removes the socket at path if it is stale,
+// i.e. no process is accepting connections on it, typically because a
+// previous instance exited without removing it. This should be called
+// before listening on the path. It reports whether path was removed.
+// It is not an error if path does not exist. Stream, datagram and
+// seqpacket sockets are supported.
+//
+//   - [syscall.EADDRINUSE] is returned if socket is in use.
+//   - [syscall.ENOTSOCK] is returned if path exists, but is not a socket.
+func RemoveStaleUnixSocket(path string) (bool, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("launchd: %w", err)
+	}
+	if fi.Mode().Type() != fs.ModeSocket {
+		return false, fmt.Errorf("launchd: %s: %w", path, syscall.ENOTSOCK)
+	}
+
+	// Connecting with a different socket type fails with EPROTOTYPE.
+	for _, network := range []string{"unix", "unixgram", "unixpacket"} {
+		var conn net.Conn
+		conn, err = net.DialTimeout(network, path, time.Second)
+		if err == nil {
+			conn.Close()
+			return false, fmt.Errorf("launchd: %s: %w", path, syscall.EADDRINUSE)
+		}
+		if !errors.Is(err, syscall.EPROTOTYPE) {
+			break
+		}
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return false, fmt.Errorf("launchd: failed to probe socket %s: %w", path, err)
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("launchd: failed to remove stale socket: %w", err)
+	}
+	return true, nil
+}
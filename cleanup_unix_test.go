@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// staleSocket creates a unix socket at path, which is not removed
+// when its listener is closed.
+func staleSocket(t *testing.T, path string) {
+	t.Helper()
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	l.SetUnlinkOnClose(false)
+	l.Close()
+}
+
+func TestCleanupUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "launchd-")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	t.Run("nil", func(t *testing.T) {
+		err := CleanupUnixSocket(nil)
+		if !errors.Is(err, syscall.EINVAL) {
+			t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+		}
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		l, err := net.Listen("tcp4", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		defer l.Close()
+		if err = CleanupUnixSocket(l); err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+	})
+
+	t.Run("unix", func(t *testing.T) {
+		path := filepath.Join(dir, "cleanup.sock")
+		l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		// Like listeners built from activated sockets.
+		l.SetUnlinkOnClose(false)
+		l.Close()
+
+		if err = CleanupUnixSocket(l); err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+		if _, err = os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected socket path to be removed, got=%s", err)
+		}
+
+		// Already removed.
+		if err = CleanupUnixSocket(l); err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+	})
+
+	t.Run("activated", func(t *testing.T) {
+		path := filepath.Join(dir, "activated.sock")
+		l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		l.SetUnlinkOnClose(false)
+		l.Close()
+		markActivated(path)
+
+		if err = CleanupUnixSocket(l); !errors.Is(err, syscall.EPERM) {
+			t.Errorf("expected error=%s, got=%s", syscall.EPERM, err)
+		}
+		if _, err = os.Lstat(path); err != nil {
+			t.Errorf("expected socket path to be kept, got=%s", err)
+		}
+	})
+
+	t.Run("replaced", func(t *testing.T) {
+		path := filepath.Join(dir, "replaced.sock")
+		staleSocket(t, path)
+		markActivated(path)
+		// Rename keeps the activated socket file, so that its inode
+		// is not reused by the new socket.
+		if err := os.Rename(path, path+".old"); err != nil {
+			t.Fatalf("failed to rename socket: %s", err)
+		}
+
+		// Socket at the same path, which is not the activated socket.
+		l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		l.SetUnlinkOnClose(false)
+		l.Close()
+
+		if err = CleanupUnixSocket(l); err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+		if _, err = os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected socket path to be removed, got=%s", err)
+		}
+	})
+
+	t.Run("named", func(t *testing.T) {
+		path := filepath.Join(dir, "named.sock")
+		l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		l.SetUnlinkOnClose(false)
+		l.Close()
+
+		if err = CleanupUnixSocket(NameListener(l, "named")); err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+		if _, err = os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected socket path to be removed, got=%s", err)
+		}
+	})
+}
+
+func TestRemoveStaleUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "launchd-")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	active := filepath.Join(dir, "active.sock")
+	l, err := net.Listen("unix", active)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	stale := filepath.Join(dir, "stale.sock")
+	staleSocket(t, stale)
+
+	activeDgram := filepath.Join(dir, "active-dgram.sock")
+	dgram, err := net.ListenPacket("unixgram", activeDgram)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { dgram.Close() })
+
+	staleDgram := filepath.Join(dir, "stale-dgram.sock")
+	c, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: staleDgram, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	c.Close()
+	if _, err = os.Lstat(staleDgram); err != nil {
+		t.Fatalf("expected stale socket to exist: %s", err)
+	}
+
+	regular := filepath.Join(dir, "regular")
+	if err = os.WriteFile(regular, nil, 0o600); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+
+	tt := []struct {
+		name    string
+		path    string
+		removed bool
+		err     error
+	}{
+		{name: "not-exist", path: filepath.Join(dir, "x.sock")},
+		{name: "active", path: active, err: syscall.EADDRINUSE},
+		{name: "active-dgram", path: activeDgram, err: syscall.EADDRINUSE},
+		{name: "stale-dgram", path: staleDgram, removed: true},
+		{name: "regular-file", path: regular, err: syscall.ENOTSOCK},
+		{name: "stale", path: stale, removed: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			removed, err := RemoveStaleUnixSocket(tc.path)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+			if removed != tc.removed {
+				t.Errorf("expected removed=%t, got=%t", tc.removed, removed)
+			}
+		})
+	}
+}
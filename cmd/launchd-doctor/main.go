@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Command launchd-doctor diagnoses why socket activation of a job fails.
+//
+//	launchd-doctor -socket Listeners com.example.svc
+//
+// It checks whether the plist file of the job exists and is valid, whether
+// the job is loaded, configuration of the socket, whether program of the
+// job is executable and permissions of the plist file and socket path,
+// and prints findings along with hints to fix them. It exits with status 1
+// if any of the checks failed. See [launchd.DiagnoseJob].
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/internal/cli"
+)
+
+// errFindings is returned when any of the checks failed.
+var errFindings = errors.New("one or more checks failed")
+
+// hints are hints for fixing failed checks, keyed by check name.
+// Checks of sockets are named socket(<name>) and use "socket" hint.
+var hints = map[string]string{
+	"loaded": "Load the job with 'launchctl bootstrap <domain> <plist>', " +
+		"for example 'launchctl bootstrap gui/$(id -u) ~/Library/LaunchAgents/<label>.plist'.",
+	"plist": "Check that plist file is valid with 'plutil -lint <plist>', " +
+		"that it is not writable by group or others and that LaunchDaemons are owned by root.",
+	"socket": "Socket name passed to launchd.Listeners must match a key in the Sockets dictionary " +
+		"of the plist. Reload the job after changing its plist file.",
+	"program": "Program must be an absolute path to an executable. Socket activation " +
+		"only works in the process started by launchd, not when running it from a shell.",
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errFindings):
+		os.Exit(1)
+	case errors.Is(err, cli.ErrUsage):
+		fmt.Fprintf(os.Stderr, "launchd-doctor: %s\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "launchd-doctor: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run diagnoses the job specified by args.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	var socket string
+	fs := flag.NewFlagSet("launchd-doctor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: launchd-doctor [flags] <label>")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&socket, "socket", "", "name of the socket in the Sockets dictionary of the job")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || fs.Arg(0) == "" {
+		return fmt.Errorf("%w: exactly one label must be specified", cli.ErrUsage)
+	}
+
+	findings, err := launchd.DiagnoseJob(ctx, fs.Arg(0), socket)
+	if err != nil {
+		return err
+	}
+	return report(stdout, findings)
+}
+
+// report prints findings and hints for failed checks. [errFindings] is
+// returned if any of the checks failed.
+func report(w io.Writer, findings []launchd.Finding) error {
+	var failed int
+	hinted := make(map[string]bool)
+	for _, f := range findings {
+		fmt.Fprintln(w, f)
+		if f.Severity != launchd.SeverityError {
+			continue
+		}
+		failed++
+
+		key := f.Check
+		if strings.HasPrefix(key, "socket(") {
+			key = "socket"
+		}
+		if hint, ok := hints[key]; ok && !hinted[key] {
+			hinted[key] = true
+			fmt.Fprintf(w, "  hint: %s\n", hint)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(w, "\n%d of %d checks failed\n", failed, len(findings))
+		return errFindings
+	}
+	fmt.Fprintf(w, "\nall %d checks passed\n", len(findings))
+	return nil
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/internal/cli"
+)
+
+func TestRun_Usage(t *testing.T) {
+	tt := []struct {
+		name string
+		args []string
+	}{
+		{name: "no-label"},
+		{name: "extra-args", args: []string{"a", "b"}},
+		{name: "empty-label", args: []string{"-socket", "Listeners", ""}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := run(context.Background(), tc.args, io.Discard, io.Discard)
+			if !errors.Is(err, cli.ErrUsage) {
+				t.Errorf("expected error=%s, got=%s", cli.ErrUsage, err)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := report(&buf, []launchd.Finding{
+			{Check: "loaded", Severity: launchd.SeverityOK, Message: "job is loaded"},
+			{Check: "plist", Severity: launchd.SeverityWarning, Message: "plist warning"},
+		})
+		if err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+		if strings.Contains(buf.String(), "hint:") {
+			t.Errorf("expected no hints, got=%s", buf.String())
+		}
+		if !strings.Contains(buf.String(), "all 2 checks passed") {
+			t.Errorf("expected summary, got=%s", buf.String())
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := report(&buf, []launchd.Finding{
+			{Check: "loaded", Severity: launchd.SeverityError, Message: "job is not loaded"},
+			{Check: "socket(a)", Severity: launchd.SeverityError, Message: "bad socket"},
+			{Check: "socket(b)", Severity: launchd.SeverityError, Message: "bad socket"},
+		})
+		if !errors.Is(err, errFindings) {
+			t.Errorf("expected error=%s, got=%s", errFindings, err)
+		}
+		out := buf.String()
+		if n := strings.Count(out, "hint:"); n != 2 {
+			t.Errorf("expected 2 hints, got=%d\n%s", n, out)
+		}
+		if !strings.Contains(out, "3 of 3 checks failed") {
+			t.Errorf("expected summary, got=%s", out)
+		}
+	})
+}
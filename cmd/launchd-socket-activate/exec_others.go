@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// execProgram is only supported on unix platforms.
+func execProgram(_ []string) error {
+	return fmt.Errorf("only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/cli"
+)
+
+// execProgram replaces the current process with the program, setting
+// LISTEN_PID to pid of the current process. Sockets are already at
+// file descriptors starting at 3, as passed by activate.
+func execProgram(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: program is not specified", cli.ErrUsage)
+	}
+
+	program, err := exec.LookPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	env := make([]string, 0, len(os.Environ())+1)
+	for _, kv := range os.Environ() {
+		if k, _, _ := strings.Cut(kv, "="); k != execEnv && k != listenPIDEnv {
+			env = append(env, kv)
+		}
+	}
+	env = append(env, listenPIDEnv+"="+strconv.Itoa(os.Getpid()))
+	return syscall.Exec(program, args, env)
+}
@@ -0,0 +1,294 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Command launchd-socket-activate is an analog of systemd-socket-activate
+// for macOS, to test socket activated servers manually.
+//
+//	launchd-socket-activate -l tcp:8080 -l api=unix:/tmp/api.sock -- /path/to/server --flag
+//
+// It defines a temporary launchd job with the requested sockets in the
+// domain of the calling user and waits until interrupted, after which the
+// job is removed. When a connection is received on any of the sockets,
+// launchd starts the job, which activates the sockets and runs the program
+// with them passed as file descriptors starting at 3, described by
+// LISTEN_FDS, LISTEN_FDNAMES and LISTEN_PID environment variables, like
+// systemd does. Thus, servers which were not written for launchd, but
+// support systemd socket activation, can be tested.
+//
+// Output of the program is written to the log file specified by -log.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/internal/cli"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Environment variables used between stages.
+const (
+	// socketsEnv is set in the job and lists socket names to activate.
+	socketsEnv = "LAUNCHD_SOCKET_ACTIVATE_SOCKETS"
+
+	// execEnv is set by the job for the process which execs the program.
+	execEnv = "LAUNCHD_SOCKET_ACTIVATE_EXEC"
+)
+
+// Environment variables describing passed sockets, as used by systemd.
+const (
+	listenFDsEnv     = "LISTEN_FDS"
+	listenFDNamesEnv = "LISTEN_FDNAMES"
+	listenPIDEnv     = "LISTEN_PID"
+)
+
+// defaultSocketName is the socket name used when -l does not specify one.
+const defaultSocketName = launchd.SocketListeners
+
+func main() {
+	var err error
+	switch {
+	case os.Getenv(execEnv) != "":
+		err = execProgram(os.Args[1:])
+	case os.Getenv(socketsEnv) != "":
+		err = activate(os.Args[1:])
+	default:
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err = run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+		stop()
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.As(err, &exitErr):
+		os.Exit(exitErr.ExitCode())
+	case errors.Is(err, cli.ErrUsage):
+		fmt.Fprintf(os.Stderr, "launchd-socket-activate: %s\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "launchd-socket-activate: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run installs the job and waits until ctx is cancelled.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get path of the executable: %w", err)
+	}
+
+	job, err := parseArgs(self, args, stderr)
+	if err != nil {
+		return err
+	}
+
+	if err = launchd.Install(ctx, job); err != nil {
+		return err
+	}
+	defer func() {
+		// Job must be removed even if ctx is cancelled.
+		_ = launchd.Uninstall(context.WithoutCancel(ctx), job.Label)
+	}()
+
+	fmt.Fprintf(stdout, "Installed job %s, logs are written to %s\n", job.Label, job.StandardOutPath)
+	for name, s := range job.Sockets {
+		addr := s.SockPathName
+		if addr == "" {
+			addr = strings.TrimPrefix(s.SockNodeName+":"+s.SockServiceName, ":")
+		}
+		fmt.Fprintf(stdout, "Listening on %s (%s, %s)\n", addr, name, s.SockType)
+	}
+	fmt.Fprintln(stdout, "Press Ctrl+C to stop")
+
+	<-ctx.Done()
+	return nil
+}
+
+// parseArgs parses command line arguments and returns the job
+// which runs self with the program.
+func parseArgs(self string, args []string, stderr io.Writer) (plist.Job, error) {
+	var (
+		label, logPath string
+		sockets, env   cli.ListFlag
+	)
+	fs := flag.NewFlagSet("launchd-socket-activate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: launchd-socket-activate [flags] -- program [args...]")
+		fs.PrintDefaults()
+	}
+	fs.Var(&sockets, "l", "socket as [name=]network:address, "+
+		`for example "tcp:8080" or "api=unix:/tmp/api.sock" (repeatable)`)
+	fs.Var(&env, "E", "environment variable as KEY=VALUE, or KEY to pass its current value (repeatable)")
+	fs.StringVar(&label, "label", "", "label of the temporary job (default local.launchd-socket-activate.<pid>)")
+	fs.StringVar(&logPath, "log", "", "log file for output of the program (default <tmpdir>/<label>.log)")
+	if err := fs.Parse(args); err != nil {
+		return plist.Job{}, err
+	}
+
+	if fs.NArg() == 0 {
+		return plist.Job{}, fmt.Errorf("%w: program is not specified", cli.ErrUsage)
+	}
+	if len(sockets) == 0 {
+		return plist.Job{}, fmt.Errorf("%w: at least one socket must be specified with -l", cli.ErrUsage)
+	}
+
+	if label == "" {
+		label = "local.launchd-socket-activate." + strconv.Itoa(os.Getpid())
+	}
+	if logPath == "" {
+		logPath = filepath.Join(os.TempDir(), label+".log")
+	}
+	logPath, err := filepath.Abs(logPath)
+	if err != nil {
+		return plist.Job{}, fmt.Errorf("invalid log path: %w", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return plist.Job{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	// Program is resolved now, as job does not inherit PATH.
+	program, err := exec.LookPath(fs.Arg(0))
+	if err != nil {
+		return plist.Job{}, fmt.Errorf("%w: %w", cli.ErrUsage, err)
+	}
+	program, err = filepath.Abs(program)
+	if err != nil {
+		return plist.Job{}, fmt.Errorf("%w: %w", cli.ErrUsage, err)
+	}
+
+	job := plist.Job{
+		Label:                label,
+		ProgramArguments:     append([]string{self, program}, fs.Args()[1:]...),
+		EnvironmentVariables: make(map[string]string),
+		WorkingDirectory:     wd,
+		StandardOutPath:      logPath,
+		StandardErrorPath:    logPath,
+		Sockets:              make(map[string]plist.Socket),
+	}
+
+	for _, kv := range env {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			v = os.Getenv(k)
+		}
+		if k == "" {
+			return plist.Job{}, fmt.Errorf("%w: invalid -E %q", cli.ErrUsage, kv)
+		}
+		job.EnvironmentVariables[k] = v
+	}
+
+	// Order of sockets is preserved, so that file descriptors are
+	// passed to the program in the order they were specified.
+	names := make([]string, 0, len(sockets))
+	for _, spec := range sockets {
+		name, socket, err := cli.ParseSocket(spec)
+		if err != nil {
+			return plist.Job{}, err
+		}
+		if name == "" {
+			name = defaultSocketName
+		}
+		if err = launchd.ValidateSocketName(name); err != nil {
+			return plist.Job{}, fmt.Errorf("%w: %w", cli.ErrUsage, err)
+		}
+		if _, ok := job.Sockets[name]; ok {
+			return plist.Job{}, fmt.Errorf("%w: duplicate socket name %q, "+
+				"use name=network:address to name sockets", cli.ErrUsage, name)
+		}
+		job.Sockets[name] = socket
+		names = append(names, name)
+	}
+	job.EnvironmentVariables[socketsEnv] = strings.Join(names, ":")
+	return job, nil
+}
+
+// activate runs in the job started by launchd. It activates the sockets
+// and runs itself with the sockets as extra files, which then execs
+// the program, so that LISTEN_PID is the pid of the program.
+// Signals are forwarded to the program.
+func activate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: program is not specified", cli.ErrUsage)
+	}
+
+	var files []*os.File
+	var names []string
+	for _, name := range strings.Split(os.Getenv(socketsEnv), ":") {
+		f, err := launchd.Files(name)
+		if err != nil {
+			return err
+		}
+		files = append(files, f...)
+		for range f {
+			names = append(names, name)
+		}
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get path of the executable: %w", err)
+	}
+
+	cmd := exec.Command(self, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = listenEnv(os.Environ(), len(files), names)
+	cmd.Env = append(cmd.Env, execEnv+"=1")
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start program: %w", err)
+	}
+	for _, f := range files {
+		_ = f.Close()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	for {
+		select {
+		case sig := <-signals:
+			_ = cmd.Process.Signal(sig)
+		case err = <-done:
+			return err
+		}
+	}
+}
+
+// listenEnv returns a copy of env describing count sockets with names,
+// without variables used by launchd-socket-activate.
+func listenEnv(env []string, count int, names []string) []string {
+	out := make([]string, 0, len(env)+2)
+	for _, kv := range env {
+		switch k, _, _ := strings.Cut(kv, "="); k {
+		case socketsEnv, execEnv, listenFDsEnv, listenFDNamesEnv, listenPIDEnv:
+		default:
+			out = append(out, kv)
+		}
+	}
+	return append(out,
+		listenFDsEnv+"="+strconv.Itoa(count),
+		listenFDNamesEnv+"="+strings.Join(names, ":"),
+	)
+}
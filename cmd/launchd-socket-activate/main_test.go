@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/internal/cli"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestParseArgs(t *testing.T) {
+	program, err := exec.LookPath("go")
+	if err != nil {
+		t.Skipf("go not found in PATH: %s", err)
+	}
+	program, _ = filepath.Abs(program)
+
+	job, err := parseArgs("/usr/local/bin/launchd-socket-activate", []string{
+		"-l", "tcp:8080",
+		"-l", "api=unix:/tmp/api.sock",
+		"-E", "LOG_LEVEL=debug",
+		"-label", "com.example.test",
+		"-log", "/tmp/test.log",
+		"--", "go", "version",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expectArgs := []string{"/usr/local/bin/launchd-socket-activate", program, "version"}
+	if !reflect.DeepEqual(job.ProgramArguments, expectArgs) {
+		t.Errorf("expected args=%q, got=%q", expectArgs, job.ProgramArguments)
+	}
+	if job.Label != "com.example.test" {
+		t.Errorf("expected label=com.example.test, got=%s", job.Label)
+	}
+	if job.StandardOutPath != "/tmp/test.log" || job.StandardErrorPath != "/tmp/test.log" {
+		t.Errorf("expected logs=/tmp/test.log, got=%s, %s", job.StandardOutPath, job.StandardErrorPath)
+	}
+
+	expectEnv := map[string]string{
+		"LOG_LEVEL": "debug",
+		socketsEnv:  defaultSocketName + ":api",
+	}
+	if !reflect.DeepEqual(job.EnvironmentVariables, expectEnv) {
+		t.Errorf("expected env=%v, got=%v", expectEnv, job.EnvironmentVariables)
+	}
+
+	expectSockets := map[string]plist.Socket{
+		defaultSocketName: {SockType: "stream", SockServiceName: "8080"},
+		"api":             {SockType: "stream", SockPathName: "/tmp/api.sock"},
+	}
+	if !reflect.DeepEqual(job.Sockets, expectSockets) {
+		t.Errorf("expected sockets=%+v, got=%+v", expectSockets, job.Sockets)
+	}
+}
+
+func TestParseArgs_Usage(t *testing.T) {
+	tt := []struct {
+		name string
+		args []string
+	}{
+		{name: "no-program", args: []string{"-l", "tcp:8080"}},
+		{name: "no-sockets", args: []string{"--", "go"}},
+		{name: "invalid-socket", args: []string{"-l", "tcp", "--", "go"}},
+		{name: "duplicate-socket", args: []string{"-l", "tcp:80", "-l", "tcp:81", "--", "go"}},
+		{name: "invalid-env", args: []string{"-l", "tcp:80", "-E", "=x", "--", "go"}},
+		{name: "program-not-found", args: []string{"-l", "tcp:80", "--", "launchd-socket-activate-not-found"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseArgs("/bin/self", tc.args, io.Discard)
+			if !errors.Is(err, cli.ErrUsage) {
+				t.Errorf("expected error=%s, got=%s", cli.ErrUsage, err)
+			}
+		})
+	}
+}
+
+func TestListenEnv(t *testing.T) {
+	env := []string{
+		"HOME=/Users/test",
+		socketsEnv + "=a:b",
+		listenFDsEnv + "=5",
+		listenPIDEnv + "=1",
+	}
+	expect := []string{
+		"HOME=/Users/test",
+		listenFDsEnv + "=3",
+		listenFDNamesEnv + "=a:b:b",
+	}
+	got := listenEnv(env, 3, []string{"a", "b", "b"})
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected env=%q, got=%q", expect, got)
+	}
+}
@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Command launchdctl inspects, installs and uninstalls launchd jobs.
+//
+//	launchdctl inspect --label com.example.svc --exec /usr/local/bin/svc --socket tcp:8080
+//	launchdctl install --label com.example.svc --exec /usr/local/bin/svc --socket tcp:8080
+//	launchdctl status com.example.svc
+//	launchdctl uninstall com.example.svc
+//
+// inspect prints the plist which would be installed by install with
+// the same flags, without installing it. Jobs are installed into the
+// domain appropriate for the calling user, unless --domain is specified.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/internal/cli"
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+const usage = `Usage: launchdctl <command> [flags]
+
+Commands:
+  inspect    Print plist of the job, without installing it.
+  install    Install and load the job.
+  status     Print status of the job.
+  uninstall  Unload the job and remove its plist file.
+
+Run 'launchdctl <command> -h' for flags of the command.
+`
+
+// defaultSocketName is the socket name used when --socket does not specify one.
+const defaultSocketName = launchd.SocketListeners
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, cli.ErrUsage):
+		fmt.Fprintf(os.Stderr, "launchdctl: %s\n", err)
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "launchdctl: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command specified by args.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("%w: command not specified", cli.ErrUsage)
+	}
+
+	switch args[0] {
+	case "inspect":
+		job, _, err := parseJob(args[0], args[1:], stderr)
+		if err != nil {
+			return err
+		}
+		return plist.NewEncoder(stdout).Encode(job)
+	case "install":
+		job, domain, err := parseJob(args[0], args[1:], stderr)
+		if err != nil {
+			return err
+		}
+		return launchd.Install(ctx, job, domainOptions(domain)...)
+	case "status":
+		label, domain, err := parseLabel(args[0], args[1:], stderr)
+		if err != nil {
+			return err
+		}
+		return status(ctx, stdout, label, domain)
+	case "uninstall":
+		label, domain, err := parseLabel(args[0], args[1:], stderr)
+		if err != nil {
+			return err
+		}
+		return launchd.Uninstall(ctx, label, domainOptions(domain)...)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	default:
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("%w: unknown command %q", cli.ErrUsage, args[0])
+	}
+}
+
+// newFlagSet returns a flag set for the command with --domain flag.
+func newFlagSet(cmd string, stderr io.Writer, domain *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("launchdctl "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(domain, "domain", "",
+		`launchd domain, "system", "gui", "user" or a domain target like "gui/501"`)
+	return fs
+}
+
+// parseJob parses flags of inspect and install commands.
+func parseJob(cmd string, args []string, stderr io.Writer) (plist.Job, launchctl.Domain, error) {
+	var (
+		domain, label, exec string
+		arg, env, sockets   cli.ListFlag
+		runAtLoad           bool
+		keepAlive           bool
+	)
+	fs := newFlagSet(cmd, stderr, &domain)
+	fs.StringVar(&label, "label", "", "label of the job (required)")
+	fs.StringVar(&exec, "exec", "", "absolute path of the executable (required)")
+	fs.Var(&arg, "arg", "argument passed to the executable (repeatable)")
+	fs.Var(&env, "env", "environment variable as KEY=VALUE (repeatable)")
+	fs.Var(&sockets, "socket", "socket as [name=]network:address, "+
+		`for example "tcp:8080" or "api=unix:/var/run/api.sock" (repeatable)`)
+	fs.BoolVar(&runAtLoad, "run-at-load", false, "start the job as soon as it is loaded")
+	fs.BoolVar(&keepAlive, "keep-alive", false, "keep the job running regardless of demand")
+	err := fs.Parse(args)
+	if err != nil {
+		return plist.Job{}, "", err
+	}
+
+	switch {
+	case fs.NArg() > 0:
+		return plist.Job{}, "", fmt.Errorf("%w: unexpected arguments %q", cli.ErrUsage, fs.Args())
+	case label == "":
+		return plist.Job{}, "", fmt.Errorf("%w: --label is required", cli.ErrUsage)
+	case exec == "":
+		return plist.Job{}, "", fmt.Errorf("%w: --exec is required", cli.ErrUsage)
+	case !strings.HasPrefix(exec, "/"):
+		return plist.Job{}, "", fmt.Errorf("%w: --exec must be an absolute path", cli.ErrUsage)
+	}
+
+	d, err := parseDomain(domain)
+	if err != nil {
+		return plist.Job{}, "", err
+	}
+
+	job := plist.Job{
+		Label:            label,
+		ProgramArguments: append([]string{exec}, arg...),
+		RunAtLoad:        runAtLoad,
+		KeepAlive:        plist.KeepAlive{Always: keepAlive},
+	}
+
+	for _, kv := range env {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return plist.Job{}, "", fmt.Errorf("%w: invalid --env %q", cli.ErrUsage, kv)
+		}
+		if job.EnvironmentVariables == nil {
+			job.EnvironmentVariables = make(map[string]string)
+		}
+		job.EnvironmentVariables[k] = v
+	}
+
+	for _, spec := range sockets {
+		name, socket, err := cli.ParseSocket(spec)
+		if err != nil {
+			return plist.Job{}, "", err
+		}
+		if name == "" {
+			name = defaultSocketName
+		}
+		if err = launchd.ValidateSocketName(name); err != nil {
+			return plist.Job{}, "", fmt.Errorf("%w: %w", cli.ErrUsage, err)
+		}
+		if _, ok := job.Sockets[name]; ok {
+			return plist.Job{}, "", fmt.Errorf("%w: duplicate socket name %q", cli.ErrUsage, name)
+		}
+		if job.Sockets == nil {
+			job.Sockets = make(map[string]plist.Socket)
+		}
+		job.Sockets[name] = socket
+	}
+	return job, d, nil
+}
+
+// parseLabel parses flags of status and uninstall commands.
+func parseLabel(cmd string, args []string, stderr io.Writer) (string, launchctl.Domain, error) {
+	var domain string
+	fs := newFlagSet(cmd, stderr, &domain)
+	if err := fs.Parse(args); err != nil {
+		return "", "", err
+	}
+	if fs.NArg() != 1 || fs.Arg(0) == "" {
+		return "", "", fmt.Errorf("%w: %s requires exactly one label", cli.ErrUsage, cmd)
+	}
+	d, err := parseDomain(domain)
+	if err != nil {
+		return "", "", err
+	}
+	return fs.Arg(0), d, nil
+}
+
+// parseDomain parses value of --domain flag. Empty value is returned as is,
+// "gui" and "user" are domains of the current user.
+func parseDomain(s string) (launchctl.Domain, error) {
+	switch s {
+	case "":
+		return "", nil
+	case "system":
+		return launchctl.System, nil
+	case "gui":
+		return launchctl.GUI(os.Getuid()), nil
+	case "user":
+		return launchctl.User(os.Getuid()), nil
+	}
+
+	d := launchctl.Domain(s)
+	if _, ok := d.UID(); ok {
+		return d, nil
+	}
+	return "", fmt.Errorf("%w: invalid --domain %q", cli.ErrUsage, s)
+}
+
+// domainOptions returns install options for the domain.
+// If domain is empty, no options are returned.
+func domainOptions(d launchctl.Domain) []launchd.InstallOption {
+	if d == "" {
+		return nil
+	}
+	return []launchd.InstallOption{launchd.WithDomain(d)}
+}
+
+// status prints status of the job.
+func status(ctx context.Context, w io.Writer, label string, d launchctl.Domain) error {
+	var err error
+	if d == "" {
+		d, err = launchctl.CurrentDomain(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	svc, err := launchctl.Print(ctx, d, label)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "label:     %s\n", label)
+	fmt.Fprintf(w, "domain:    %s\n", d)
+	fmt.Fprintf(w, "path:      %s\n", svc.Path)
+	fmt.Fprintf(w, "state:     %s\n", svc.State)
+	if svc.PID > 0 {
+		fmt.Fprintf(w, "pid:       %d\n", svc.PID)
+	}
+	fmt.Fprintf(w, "runs:      %d\n", svc.Runs)
+	if svc.LastExitReason != "" {
+		fmt.Fprintf(w, "last exit: %s\n", svc.LastExitReason)
+	} else {
+		fmt.Fprintf(w, "last exit: %d\n", svc.LastExitCode)
+	}
+	for _, s := range svc.Sockets {
+		fmt.Fprintf(w, "socket:    %s (%s, active=%t)\n", s.Name, s.Type, s.Active)
+	}
+	return nil
+}
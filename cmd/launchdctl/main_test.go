@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/internal/cli"
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestParseDomain(t *testing.T) {
+	tt := []struct {
+		input  string
+		domain launchctl.Domain
+		err    error
+	}{
+		{input: ""},
+		{input: "system", domain: launchctl.System},
+		{input: "gui/501", domain: launchctl.GUI(501)},
+		{input: "user/501", domain: launchctl.User(501)},
+		{input: "pid/1", err: cli.ErrUsage},
+		{input: "invalid", err: cli.ErrUsage},
+	}
+	for _, tc := range tt {
+		t.Run(tc.input, func(t *testing.T) {
+			domain, err := parseDomain(tc.input)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+			if domain != tc.domain {
+				t.Errorf("expected domain=%s, got=%s", tc.domain, domain)
+			}
+		})
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	tt := []struct {
+		name string
+		args []string
+	}{
+		{name: "no-command"},
+		{name: "unknown-command", args: []string{"start"}},
+		{name: "install-no-label", args: []string{"install", "--exec", "/bin/true"}},
+		{name: "install-no-exec", args: []string{"install", "--label", "com.example.svc"}},
+		{name: "install-relative-exec", args: []string{"install", "--label", "a", "--exec", "true"}},
+		{name: "install-invalid-env", args: []string{"install", "--label", "a", "--exec", "/bin/true", "--env", "X"}},
+		{name: "install-duplicate-socket", args: []string{
+			"install", "--label", "a", "--exec", "/bin/true", "--socket", "tcp:80", "--socket", "tcp:81",
+		}},
+		{name: "status-no-label", args: []string{"status"}},
+		{name: "uninstall-extra-args", args: []string{"uninstall", "a", "b"}},
+		{name: "status-invalid-domain", args: []string{"status", "--domain", "x", "a"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := run(context.Background(), tc.args, io.Discard, io.Discard)
+			if !errors.Is(err, cli.ErrUsage) {
+				t.Errorf("expected error=%s, got=%s", cli.ErrUsage, err)
+			}
+		})
+	}
+}
+
+func TestRun_Inspect(t *testing.T) {
+	var stdout bytes.Buffer
+	err := run(context.Background(), []string{
+		"inspect",
+		"--label", "com.example.svc",
+		"--exec", "/usr/local/bin/svc",
+		"--arg", "serve",
+		"--env", "LOG_LEVEL=debug",
+		"--socket", "tcp:8080",
+		"--run-at-load",
+	}, &stdout, io.Discard)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	var job plist.Job
+	if err = plist.Unmarshal(stdout.Bytes(), &job); err != nil {
+		t.Fatalf("failed to decode plist: %s\n%s", err, stdout.String())
+	}
+
+	expect := plist.Job{
+		Label:                "com.example.svc",
+		ProgramArguments:     []string{"/usr/local/bin/svc", "serve"},
+		EnvironmentVariables: map[string]string{"LOG_LEVEL": "debug"},
+		RunAtLoad:            true,
+		Sockets: map[string]plist.Socket{
+			defaultSocketName: {SockType: "stream", SockServiceName: "8080"},
+		},
+	}
+	if !reflect.DeepEqual(job, expect) {
+		t.Errorf("expected job=%+v, got=%+v", expect, job)
+	}
+	if !strings.Contains(stdout.String(), "<key>Label</key>") {
+		t.Errorf("expected plist output, got=%s", stdout.String())
+	}
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+// TestConcurrent checks that concurrent activation is safe, when process
+// is not managed by launchd. Run with -race to detect data races.
+func TestConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan error, 96)
+	for i := 0; i < cap(errs)/3; i++ {
+		name := fmt.Sprintf("b39422da-351b-50ad-a7cc-%012d", i%4)
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_, err := launchd.Files(name)
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := launchd.Listeners(name)
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := launchd.PacketListeners(name)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if !errors.Is(err, syscall.ESRCH) && !errors.Is(err, syscall.ENOTSUP) {
+			t.Errorf("expected error=%s or %s, got=%s", syscall.ESRCH, syscall.ENOTSUP, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"syscall"
+	"time"
+)
+
+// Dependency is another job which must be ready before the job can serve,
+// see [WaitFor]. Dependencies should also be declared in the plist file of
+// the job with [github.com/tprasadtp/go-launchd/plist.Job.DependsOn], so that
+// launchd starts the job once they are loaded.
+type Dependency struct {
+	// Label of the job. If SocketName and MachService are empty,
+	// dependency is ready once the job is loaded.
+	Label string
+
+	// SocketName is the name of the unix socket in the Sockets dictionary
+	// of the job, see [DialContext]. If specified, dependency is ready once
+	// a connection to the socket succeeds. Requires Label.
+	SocketName string
+
+	// MachService is the name of the mach service provided by the job.
+	// If specified, dependency is ready once the service can be looked up
+	// in the bootstrap namespace of the process.
+	MachService string
+}
+
+// String returns a human readable name of the dependency.
+func (d Dependency) String() string {
+	switch {
+	case d.MachService != "":
+		return "mach-service:" + d.MachService
+	case d.SocketName != "":
+		return d.Label + ":" + d.SocketName
+	default:
+		return d.Label
+	}
+}
+
+// validate checks if the dependency is well formed.
+func (d Dependency) validate() error {
+	switch {
+	case d.Label == "" && d.MachService == "":
+		return fmt.Errorf("launchd: dependency requires a label or a mach service: %w", syscall.EINVAL)
+	case d.SocketName != "" && d.Label == "":
+		return fmt.Errorf("launchd: dependency socket(%s) requires a label: %w", d.SocketName, syscall.EINVAL)
+	case d.SocketName != "":
+		return ValidateSocketName(d.SocketName)
+	}
+	return nil
+}
+
+// WaitFor waits until all the dependencies are ready, polling them with
+// exponential backoff up to 1s. This is useful for products made of multiple
+// daemons, as launchd has no ordering between jobs other than KeepAlive
+// OtherJobEnabled, which only ensures that dependencies are loaded.
+// Use [context.WithTimeout] to limit the time spent waiting.
+//
+//   - [syscall.EINVAL] is returned if any of the dependencies is invalid.
+//   - [syscall.EAFNOSUPPORT] is returned if a dependency socket is not a unix socket.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//   - Error wrapping ctx.Err() and the last probe error is returned if ctx
+//     is done before all dependencies are ready.
+func WaitFor(ctx context.Context, deps ...Dependency) error {
+	for _, d := range deps {
+		if err := d.validate(); err != nil {
+			return err
+		}
+	}
+	return waitFor(ctx, deps, probe)
+}
+
+// permanent reports whether probe error will not go away by retrying.
+func permanent(err error) bool {
+	return errors.Is(err, syscall.EINVAL) ||
+		errors.Is(err, syscall.EAFNOSUPPORT) ||
+		errors.Is(err, syscall.ENOTSUP)
+}
+
+// waitFor waits until probe succeeds for each of the dependencies in order.
+func waitFor(ctx context.Context, deps []Dependency, probe func(context.Context, Dependency) error) error {
+	for _, d := range deps {
+		delay := 50 * time.Millisecond
+		for {
+			err := probe(ctx, d)
+			if err == nil {
+				break
+			}
+			if permanent(err) {
+				return err
+			}
+
+			debug("launchd: waiting for dependency",
+				slog.String("dependency", d.String()),
+				slog.Duration("delay", delay),
+				slog.Any("err", err),
+			)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("launchd: dependency(%s) is not ready: %w: %w", d, ctx.Err(), err)
+			case <-timer.C:
+			}
+			delay = min(2*delay, time.Second)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// probe checks if the dependency is ready.
+func probe(ctx context.Context, d Dependency) error {
+	if d.MachService != "" {
+		ok, err := machServiceRegistered(d.MachService)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("launchd: mach service(%s) is not registered: %w", d.MachService, syscall.ENOENT)
+		}
+	}
+
+	if d.SocketName != "" {
+		conn, err := dial(ctx, d.Label, d.SocketName)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	if d.Label != "" && d.MachService == "" {
+		_, svc, err := findService(ctx, d.Label)
+		if err != nil {
+			return err
+		}
+		if svc == nil {
+			return fmt.Errorf("launchd: job(%s) is not loaded: %w", d.Label, syscall.ENOENT)
+		}
+	}
+	return nil
+}
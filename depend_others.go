@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// probe checks if the dependency is ready.
+func probe(_ context.Context, _ Dependency) error {
+	return fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDependency_Validate(t *testing.T) {
+	tt := []struct {
+		name string
+		dep  Dependency
+		err  error
+	}{
+		{name: "label", dep: Dependency{Label: "com.example.db"}},
+		{name: "socket", dep: Dependency{Label: "com.example.db", SocketName: "Listeners"}},
+		{name: "mach-service", dep: Dependency{MachService: "com.example.db.xpc"}},
+		{name: "empty", dep: Dependency{}, err: syscall.EINVAL},
+		{name: "socket-without-label", dep: Dependency{SocketName: "Listeners"}, err: syscall.EINVAL},
+		{name: "invalid-socket", dep: Dependency{Label: "com.example.db", SocketName: " "}, err: syscall.EINVAL},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dep.validate()
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%v, got=%v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestWaitFor_Invalid(t *testing.T) {
+	err := WaitFor(context.Background(), Dependency{Label: "com.example.db"}, Dependency{})
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
+
+func TestWaitFor_Unsupported(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("only applicable on non-macOS platforms")
+	}
+	err := WaitFor(context.Background(), Dependency{Label: "com.example.db"})
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestWaitForProbe(t *testing.T) {
+	deps := []Dependency{
+		{Label: "com.example.db", SocketName: "Listeners"},
+		{MachService: "com.example.cache.xpc"},
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		attempts := map[string]int{}
+		err := waitFor(context.Background(), deps, func(_ context.Context, d Dependency) error {
+			attempts[d.String()]++
+			if attempts[d.String()] < 3 {
+				return syscall.ECONNREFUSED
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+		for _, d := range deps {
+			if attempts[d.String()] != 3 {
+				t.Errorf("expected 3 attempts for %s, got=%d", d, attempts[d.String()])
+			}
+		}
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		var attempts int
+		err := waitFor(context.Background(), deps, func(context.Context, Dependency) error {
+			attempts++
+			return syscall.EAFNOSUPPORT
+		})
+		if !errors.Is(err, syscall.EAFNOSUPPORT) {
+			t.Errorf("expected error=%s, got=%s", syscall.EAFNOSUPPORT, err)
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got=%d", attempts)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		err := waitFor(ctx, deps, func(context.Context, Dependency) error {
+			return syscall.ENOENT
+		})
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, syscall.ENOENT) {
+			t.Errorf("expected error=%s and %s, got=%s", context.DeadlineExceeded, syscall.ENOENT, err)
+		}
+	})
+}
@@ -0,0 +1,367 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Severity is the severity of a [Finding].
+type Severity int
+
+const (
+	// SeverityOK indicates that check passed.
+	SeverityOK Severity = iota
+
+	// SeverityWarning indicates a possible problem.
+	SeverityWarning
+
+	// SeverityError indicates a problem which prevents socket activation.
+	SeverityError
+)
+
+// String implements [fmt.Stringer].
+func (s Severity) String() string {
+	switch s {
+	case SeverityOK:
+		return "ok"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Finding is the result of a single check performed by [Diagnose].
+type Finding struct {
+	// Check is the name of the check, for example "plist" or "socket".
+	Check string
+
+	// Severity is the severity of the finding.
+	Severity Severity
+
+	// Message is a human readable description of the finding.
+	Message string
+}
+
+// String implements [fmt.Stringer].
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", f.Severity, f.Check, f.Message)
+}
+
+// Diagnose diagnoses why socket activation of the socket with the given
+// name fails for the job with the given label, typically with
+// [syscall.ESRCH] or [syscall.ENOENT]. It automates the usual checklist:
+//
+//   - Whether the job is loaded and its plist file can be found and parsed.
+//   - Whether the plist defines the socket.
+//   - Whether the program of the job matches the calling process.
+//   - Whether the calling process was started by launchd.
+//   - Whether unix socket paths exist and have appropriate permissions.
+//
+// Job is looked up in the domain of the calling process and then in the
+// system domain. If socketName is empty, socket checks are skipped.
+// Findings are returned in the order checks are performed. Only errors
+// which prevent performing the checks at all are returned as errors.
+//
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Diagnose(ctx context.Context, label, socketName string) ([]Finding, error) {
+	return diagnose(ctx, label, socketName, false)
+}
+
+// DiagnoseJob is like [Diagnose], but diagnoses the job from outside,
+// for example from a command line tool run by the user. Instead of checking
+// whether the calling process is the program of the job started by launchd,
+// it checks whether the program exists and is executable. It also checks
+// ownership and permissions of the plist file and configuration of the socket.
+//
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func DiagnoseJob(ctx context.Context, label, socketName string) ([]Finding, error) {
+	return diagnose(ctx, label, socketName, true)
+}
+
+// checkSocket checks whether the job defines socket with the given name.
+func checkSocket(job *plist.Job, name string) Finding {
+	if _, ok := job.Sockets[name]; ok {
+		return Finding{
+			Check:    "socket",
+			Severity: SeverityOK,
+			Message:  fmt.Sprintf("socket(%s) is defined", name),
+		}
+	}
+
+	names := make([]string, 0, len(job.Sockets))
+	for k := range job.Sockets {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	msg := fmt.Sprintf("socket(%s) is not defined in Sockets", name)
+	if len(names) > 0 {
+		msg += fmt.Sprintf(", defined sockets are: %s", strings.Join(names, ", "))
+	}
+	return Finding{Check: "socket", Severity: SeverityError, Message: msg}
+}
+
+// checkSocketConfig checks the configuration of the socket.
+func checkSocketConfig(name string, sock plist.Socket) Finding {
+	check := fmt.Sprintf("socket(%s)", name)
+	var problems []string
+
+	switch sock.SockType {
+	case "", "stream", "dgram", "seqpacket":
+	default:
+		problems = append(problems, fmt.Sprintf("invalid SockType(%s)", sock.SockType))
+	}
+
+	switch sock.SockFamily {
+	case "", "IPv4", "IPv6", "IPv4v6", "Unix":
+	default:
+		problems = append(problems, fmt.Sprintf("invalid SockFamily(%s)", sock.SockFamily))
+	}
+
+	switch sock.SockProtocol {
+	case "", "TCP", "UDP":
+	default:
+		problems = append(problems, fmt.Sprintf("unsupported SockProtocol(%s)", sock.SockProtocol))
+	}
+
+	switch {
+	case sock.SockPathName != "" && sock.SockServiceName != "":
+		problems = append(problems, "both SockPathName and SockServiceName are specified")
+	case sock.SockPathName != "" && !filepath.IsAbs(sock.SockPathName):
+		problems = append(problems, fmt.Sprintf("SockPathName(%s) is not an absolute path", sock.SockPathName))
+	case sock.SockPathName == "" && sock.SockServiceName == "":
+		problems = append(problems, "neither SockPathName nor SockServiceName is specified")
+	}
+
+	if len(problems) > 0 {
+		return Finding{Check: check, Severity: SeverityError, Message: strings.Join(problems, ", ")}
+	}
+	return Finding{Check: check, Severity: SeverityOK, Message: "socket configuration is valid"}
+}
+
+// checkPlistFile checks ownership and permissions of the plist file.
+// launchd refuses to load plist files which are writable by group or
+// others, and plist files of LaunchDaemons must be owned by root.
+func checkPlistFile(path string, mode fs.FileMode, uid int, daemon bool) Finding {
+	switch {
+	case mode.Perm()&0o022 != 0:
+		return Finding{
+			Check:    "plist",
+			Severity: SeverityError,
+			Message: fmt.Sprintf("plist file(%s) has mode %#o, it must not be writable by group or others",
+				path, mode.Perm()),
+		}
+	case daemon && uid != 0:
+		return Finding{
+			Check:    "plist",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("plist file(%s) is owned by uid=%d, it must be owned by root", path, uid),
+		}
+	default:
+		return Finding{
+			Check:    "plist",
+			Severity: SeverityOK,
+			Message:  fmt.Sprintf("plist file(%s) has appropriate ownership and mode %#o", path, mode.Perm()),
+		}
+	}
+}
+
+// checkExecutable checks whether program of the job exists and is executable.
+func checkExecutable(job *plist.Job) Finding {
+	program := job.Program
+	if program == "" && len(job.ProgramArguments) > 0 {
+		program = job.ProgramArguments[0]
+	}
+
+	if program == "" {
+		return Finding{
+			Check:    "program",
+			Severity: SeverityError,
+			Message:  "neither Program nor ProgramArguments is specified",
+		}
+	}
+	if !filepath.IsAbs(program) {
+		return Finding{
+			Check:    "program",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("program(%s) is not an absolute path, launchd does not search PATH", program),
+		}
+	}
+
+	fi, err := os.Stat(program)
+	switch {
+	case err != nil:
+		return Finding{
+			Check:    "program",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("program(%s) cannot be found: %s", program, err),
+		}
+	case !fi.Mode().IsRegular():
+		return Finding{
+			Check:    "program",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("program(%s) is not a regular file", program),
+		}
+	case fi.Mode().Perm()&0o111 == 0:
+		return Finding{
+			Check:    "program",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("program(%s) is not executable (mode %#o)", program, fi.Mode().Perm()),
+		}
+	default:
+		return Finding{
+			Check:    "program",
+			Severity: SeverityOK,
+			Message:  fmt.Sprintf("program(%s) exists and is executable", program),
+		}
+	}
+}
+
+// sameFile reports whether paths a and b refer to the same file,
+// resolving symlinks.
+func sameFile(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
+// checkProgram checks whether program of the job is the executable.
+func checkProgram(job *plist.Job, executable string) Finding {
+	program := job.Program
+	if program == "" && len(job.ProgramArguments) > 0 {
+		program = job.ProgramArguments[0]
+	}
+
+	switch {
+	case program == "":
+		return Finding{
+			Check:    "program",
+			Severity: SeverityError,
+			Message:  "neither Program nor ProgramArguments is specified",
+		}
+	case !filepath.IsAbs(program):
+		return Finding{
+			Check:    "program",
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("program(%s) is not an absolute path", program),
+		}
+	case !sameFile(program, executable):
+		return Finding{
+			Check:    "program",
+			Severity: SeverityError,
+			Message: fmt.Sprintf("program(%s) does not match executable(%s), "+
+				"sockets are only available to the process started by launchd", program, executable),
+		}
+	default:
+		return Finding{
+			Check:    "program",
+			Severity: SeverityOK,
+			Message:  fmt.Sprintf("program(%s) matches executable", program),
+		}
+	}
+}
+
+// checkParent checks whether the process was started by launchd.
+func checkParent(ppid int) Finding {
+	if ppid == 1 {
+		return Finding{
+			Check:    "parent",
+			Severity: SeverityOK,
+			Message:  "process was started by launchd",
+		}
+	}
+	return Finding{
+		Check:    "parent",
+		Severity: SeverityError,
+		Message: fmt.Sprintf("process was not started by launchd (ppid=%d), "+
+			"socket activation fails with ESRCH", ppid),
+	}
+}
+
+// checkSocketPath checks the unix socket path of the socket.
+func checkSocketPath(name string, sock plist.Socket) []Finding {
+	if sock.SockPathName == "" {
+		return nil
+	}
+
+	check := fmt.Sprintf("socket(%s)", name)
+	path := sock.SockPathName
+	var findings []Finding
+
+	dir := filepath.Dir(path)
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		findings = append(findings, Finding{
+			Check:    check,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("parent directory of socket path(%s) does not exist", path),
+		})
+		return findings
+	}
+
+	fi, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return append(findings, Finding{
+			Check:    check,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("socket path(%s) does not exist, job may not be loaded", path),
+		})
+	case err != nil:
+		return append(findings, Finding{
+			Check:    check,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("failed to stat socket path(%s): %s", path, err),
+		})
+	case fi.Mode().Type() != fs.ModeSocket:
+		return append(findings, Finding{
+			Check:    check,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("socket path(%s) exists but is not a socket (%s)", path, fi.Mode().Type()),
+		})
+	}
+
+	perm := fi.Mode().Perm()
+	if sock.SockPathMode != 0 && perm != fs.FileMode(sock.SockPathMode).Perm() {
+		findings = append(findings, Finding{
+			Check:    check,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("socket path(%s) has mode %#o, expected %#o",
+				path, perm, sock.SockPathMode),
+		})
+	}
+
+	if perm&0o022 == 0 {
+		findings = append(findings, Finding{
+			Check:    check,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("socket path(%s) has mode %#o, only its owner can connect to it",
+				path, perm),
+		})
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, Finding{
+			Check:    check,
+			Severity: SeverityOK,
+			Message:  fmt.Sprintf("socket path(%s) exists with mode %#o", path, perm),
+		})
+	}
+	return findings
+}
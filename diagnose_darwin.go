@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// findService looks up the service in the current domain and then
+// in the system domain. If service is not found, nil is returned.
+func findService(ctx context.Context, label string) (launchctl.Domain, *launchctl.Service, error) {
+	current, err := launchctl.CurrentDomain(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("launchd: failed to get current domain: %w", err)
+	}
+
+	domains := []launchctl.Domain{current}
+	if current != launchctl.System {
+		domains = append(domains, launchctl.System)
+	}
+
+	for _, domain := range domains {
+		svc, err := launchctl.Print(ctx, domain, label)
+		if err != nil {
+			if errors.Is(err, launchctl.ErrNotFound) {
+				continue
+			}
+			return "", nil, fmt.Errorf("launchd: failed to get job status: %w", err)
+		}
+		return domain, svc, nil
+	}
+	return current, nil, nil
+}
+
+// findPlist returns path of the plist file of the job, searching
+// standard directories if service is not loaded.
+func findPlist(domain launchctl.Domain, svc *launchctl.Service, label string) string {
+	if svc != nil && svc.Path != "" {
+		return svc.Path
+	}
+
+	var dirs []string
+	if dir, err := plistDir(domain); err == nil {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, "/Library/LaunchAgents", "/Library/LaunchDaemons")
+	dirs = slices.Compact(dirs)
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, label+".plist")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Os specific implementation of [Diagnose] and [DiagnoseJob]. If external
+// is true, job is diagnosed from outside, instead of from its own process.
+func diagnose(ctx context.Context, label, socketName string, external bool) ([]Finding, error) {
+	domain, svc, err := findService(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	if svc != nil {
+		findings = append(findings, Finding{
+			Check:    "loaded",
+			Severity: SeverityOK,
+			Message:  fmt.Sprintf("job is loaded in %s domain (state=%s)", domain, svc.State),
+		})
+	} else {
+		findings = append(findings, Finding{
+			Check:    "loaded",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("job(%s) is not loaded in %s or system domain", label, domain),
+		})
+	}
+
+	path := findPlist(domain, svc, label)
+	if path == "" {
+		findings = append(findings, Finding{
+			Check:    "plist",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("plist file for job(%s) not found", label),
+		})
+		return appendProcessChecks(findings, nil, external), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		findings = append(findings, Finding{
+			Check:    "plist",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("failed to read plist file(%s): %s", path, err),
+		})
+		return appendProcessChecks(findings, nil, external), nil
+	}
+
+	var job plist.Job
+	if err = plist.Unmarshal(data, &job); err != nil {
+		findings = append(findings, Finding{
+			Check:    "plist",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("failed to parse plist file(%s): %s", path, err),
+		})
+		return appendProcessChecks(findings, nil, external), nil
+	}
+
+	findings = append(findings, Finding{
+		Check:    "plist",
+		Severity: SeverityOK,
+		Message:  fmt.Sprintf("plist file is %s", path),
+	})
+
+	if external {
+		if fi, err := os.Stat(path); err == nil {
+			uid := -1
+			if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+				uid = int(st.Uid)
+			}
+			daemon := strings.HasPrefix(path, "/Library/LaunchDaemons/") ||
+				strings.HasPrefix(path, "/System/Library/LaunchDaemons/")
+			findings = append(findings, checkPlistFile(path, fi.Mode(), uid, daemon))
+		}
+	}
+
+	if job.Label != label {
+		findings = append(findings, Finding{
+			Check:    "plist",
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("label in plist file(%s) does not match %s", job.Label, label),
+		})
+	}
+
+	if socketName != "" {
+		findings = append(findings, checkSocket(&job, socketName))
+		if sock, ok := job.Sockets[socketName]; ok {
+			if external {
+				findings = append(findings, checkSocketConfig(socketName, sock))
+			}
+			findings = append(findings, checkSocketPath(socketName, sock)...)
+		}
+	}
+	return appendProcessChecks(findings, &job, external), nil
+}
+
+// appendProcessChecks appends checks of the program of the job. If external
+// is true, program is checked to be executable. Otherwise, calling process
+// is checked to be the program of the job started by launchd. Job may be
+// nil if plist file could not be parsed.
+func appendProcessChecks(findings []Finding, job *plist.Job, external bool) []Finding {
+	switch {
+	case external && job != nil:
+		return append(findings, checkExecutable(job))
+	case external:
+		return findings
+	}
+
+	if job != nil {
+		if executable, err := os.Executable(); err == nil {
+			findings = append(findings, checkProgram(job, executable))
+		}
+	}
+	return append(findings, checkParent(os.Getppid()))
+}
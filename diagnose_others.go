@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [Diagnose] and [DiagnoseJob].
+func diagnose(_ context.Context, _, _ string, _ bool) ([]Finding, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
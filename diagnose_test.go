@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestCheckSocket(t *testing.T) {
+	job := &plist.Job{
+		Label: "com.example.svc",
+		Sockets: map[string]plist.Socket{
+			"b": {},
+			"a": {},
+		},
+	}
+
+	f := checkSocket(job, "a")
+	if f.Severity != SeverityOK {
+		t.Errorf("expected severity=%s, got=%s", SeverityOK, f.Severity)
+	}
+
+	f = checkSocket(job, "c")
+	if f.Severity != SeverityError {
+		t.Errorf("expected severity=%s, got=%s", SeverityError, f.Severity)
+	}
+	if expect := "socket(c) is not defined in Sockets, defined sockets are: a, b"; f.Message != expect {
+		t.Errorf("expected message=%s, got=%s", expect, f.Message)
+	}
+}
+
+func TestCheckProgram(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "svc")
+	if err := os.WriteFile(exe, nil, 0o755); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(exe, link); err != nil {
+		t.Fatalf("failed to create symlink: %s", err)
+	}
+
+	tt := []struct {
+		name   string
+		job    plist.Job
+		expect Severity
+	}{
+		{name: "program", job: plist.Job{Program: exe}, expect: SeverityOK},
+		{name: "program-arguments", job: plist.Job{ProgramArguments: []string{exe, "-v"}}, expect: SeverityOK},
+		{name: "symlink", job: plist.Job{Program: link}, expect: SeverityOK},
+		{name: "mismatch", job: plist.Job{Program: "/usr/local/bin/other"}, expect: SeverityError},
+		{name: "relative", job: plist.Job{ProgramArguments: []string{"svc"}}, expect: SeverityWarning},
+		{name: "empty", job: plist.Job{}, expect: SeverityError},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f := checkProgram(&tc.job, exe)
+			if f.Severity != tc.expect {
+				t.Errorf("expected severity=%s, got=%s (%s)", tc.expect, f.Severity, f.Message)
+			}
+		})
+	}
+}
+
+func TestCheckParent(t *testing.T) {
+	if f := checkParent(1); f.Severity != SeverityOK {
+		t.Errorf("expected severity=%s, got=%s", SeverityOK, f.Severity)
+	}
+	if f := checkParent(1024); f.Severity != SeverityError {
+		t.Errorf("expected severity=%s, got=%s", SeverityError, f.Severity)
+	}
+}
+
+func TestCheckSocketPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket file modes are not supported on windows")
+	}
+
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "svc.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	if err = os.Chmod(sock, 0o666); err != nil {
+		t.Fatalf("failed to chmod: %s", err)
+	}
+
+	regular := filepath.Join(dir, "regular")
+	if err = os.WriteFile(regular, nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+
+	tt := []struct {
+		name   string
+		sock   plist.Socket
+		expect []Severity
+	}{
+		{name: "tcp", sock: plist.Socket{SockServiceName: "8080"}},
+		{name: "ok", sock: plist.Socket{SockPathName: sock}, expect: []Severity{SeverityOK}},
+		{
+			name:   "mode-mismatch",
+			sock:   plist.Socket{SockPathName: sock, SockPathMode: 0o600},
+			expect: []Severity{SeverityWarning},
+		},
+		{
+			name:   "not-exist",
+			sock:   plist.Socket{SockPathName: filepath.Join(dir, "missing.sock")},
+			expect: []Severity{SeverityWarning},
+		},
+		{
+			name:   "no-parent",
+			sock:   plist.Socket{SockPathName: filepath.Join(dir, "missing", "svc.sock")},
+			expect: []Severity{SeverityError},
+		},
+		{
+			name:   "not-socket",
+			sock:   plist.Socket{SockPathName: regular},
+			expect: []Severity{SeverityError},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := checkSocketPath("listener", tc.sock)
+			if len(findings) != len(tc.expect) {
+				t.Fatalf("expected %d findings, got=%v", len(tc.expect), findings)
+			}
+			for i := range findings {
+				if findings[i].Severity != tc.expect[i] {
+					t.Errorf("expected severity=%s, got=%s", tc.expect[i], findings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSocketConfig(t *testing.T) {
+	tt := []struct {
+		name   string
+		sock   plist.Socket
+		expect Severity
+	}{
+		{name: "tcp", sock: plist.Socket{SockServiceName: "8080"}, expect: SeverityOK},
+		{name: "unix", sock: plist.Socket{SockType: "dgram", SockPathName: "/var/run/svc.sock"}, expect: SeverityOK},
+		{name: "invalid-type", sock: plist.Socket{SockType: "raw", SockServiceName: "80"}, expect: SeverityError},
+		{name: "invalid-family", sock: plist.Socket{SockFamily: "IPv5", SockServiceName: "80"}, expect: SeverityError},
+		{name: "invalid-protocol", sock: plist.Socket{SockProtocol: "SCTP", SockServiceName: "80"}, expect: SeverityError},
+		{name: "relative-path", sock: plist.Socket{SockPathName: "svc.sock"}, expect: SeverityError},
+		{name: "path-and-service", sock: plist.Socket{SockPathName: "/a.sock", SockServiceName: "80"}, expect: SeverityError},
+		{name: "empty", expect: SeverityError},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f := checkSocketConfig("listener", tc.sock)
+			if f.Severity != tc.expect {
+				t.Errorf("expected severity=%s, got=%s (%s)", tc.expect, f.Severity, f.Message)
+			}
+		})
+	}
+}
+
+func TestCheckPlistFile(t *testing.T) {
+	tt := []struct {
+		name   string
+		mode   os.FileMode
+		uid    int
+		daemon bool
+		expect Severity
+	}{
+		{name: "agent", mode: 0o644, uid: 501, expect: SeverityOK},
+		{name: "daemon", mode: 0o644, uid: 0, daemon: true, expect: SeverityOK},
+		{name: "group-writable", mode: 0o664, uid: 501, expect: SeverityError},
+		{name: "world-writable", mode: 0o646, uid: 0, daemon: true, expect: SeverityError},
+		{name: "daemon-not-root", mode: 0o644, uid: 501, daemon: true, expect: SeverityError},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f := checkPlistFile("/Library/LaunchDaemons/com.example.svc.plist", tc.mode, tc.uid, tc.daemon)
+			if f.Severity != tc.expect {
+				t.Errorf("expected severity=%s, got=%s (%s)", tc.expect, f.Severity, f.Message)
+			}
+		})
+	}
+}
+
+func TestCheckExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "svc")
+	if err := os.WriteFile(exe, nil, 0o755); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+	notExe := filepath.Join(dir, "config")
+	if err := os.WriteFile(notExe, nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+
+	tt := []struct {
+		name   string
+		job    plist.Job
+		expect Severity
+	}{
+		{name: "executable", job: plist.Job{ProgramArguments: []string{exe}}, expect: SeverityOK},
+		{name: "not-executable", job: plist.Job{Program: notExe}, expect: SeverityError},
+		{name: "not-exist", job: plist.Job{Program: filepath.Join(dir, "x")}, expect: SeverityError},
+		{name: "directory", job: plist.Job{Program: dir}, expect: SeverityError},
+		{name: "relative", job: plist.Job{ProgramArguments: []string{"svc"}}, expect: SeverityWarning},
+		{name: "empty", job: plist.Job{}, expect: SeverityError},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f := checkExecutable(&tc.job)
+			if f.Severity != tc.expect {
+				t.Errorf("expected severity=%s, got=%s (%s)", tc.expect, f.Severity, f.Message)
+			}
+		})
+	}
+}
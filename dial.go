@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Dial connects to the unix socket with the given name of another job with
+// the given label. It is a shorthand for [DialContext] with background context.
+func Dial(label, socketName string) (net.Conn, error) {
+	return DialContext(context.Background(), label, socketName)
+}
+
+// DialContext connects to the unix socket with the given name of another job
+// with the given label, for example a sibling service which is socket
+// activated by launchd. Path of the socket is determined from SockPathName
+// of the socket in the plist file of the job, which is found via
+// launchctl print or by searching standard directories, like [Diagnose].
+// Network is "unix", "unixgram" or "unixpacket" depending on SockType.
+//
+// Job is looked up in the domain of the calling process and then in the
+// system domain.
+//
+//   - [syscall.ENOENT] is returned if job, its plist file or the socket is not found.
+//   - [syscall.EAFNOSUPPORT] is returned if socket is not a unix socket.
+//   - [syscall.EINVAL] is returned if socket is not passive.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func DialContext(ctx context.Context, label, socketName string) (net.Conn, error) {
+	return dial(ctx, label, socketName)
+}
+
+// unixSocketOf returns network and path of the unix socket with the given
+// name in the Sockets dictionary of the job.
+func unixSocketOf(job *plist.Job, name string) (string, string, error) {
+	sock, ok := job.Sockets[name]
+	if !ok {
+		return "", "", fmt.Errorf("launchd: socket(%s) not found in job(%s): %w", name, job.Label, syscall.ENOENT)
+	}
+
+	if sock.SockPathName == "" {
+		return "", "", fmt.Errorf("launchd: socket(%s) of job(%s) is not a unix socket: %w",
+			name, job.Label, syscall.EAFNOSUPPORT)
+	}
+
+	if sock.SockPassive != nil && !*sock.SockPassive {
+		return "", "", fmt.Errorf("launchd: socket(%s) of job(%s) is not passive: %w",
+			name, job.Label, syscall.EINVAL)
+	}
+
+	switch sock.SockType {
+	case "", "stream":
+		return "unix", sock.SockPathName, nil
+	case "dgram":
+		return "unixgram", sock.SockPathName, nil
+	case "seqpacket":
+		return "unixpacket", sock.SockPathName, nil
+	default:
+		return "", "", fmt.Errorf("launchd: socket(%s) of job(%s) has invalid SockType(%s): %w",
+			name, job.Label, sock.SockType, syscall.EINVAL)
+	}
+}
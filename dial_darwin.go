@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Os specific implementation of [DialContext].
+func dial(ctx context.Context, label, socketName string) (net.Conn, error) {
+	domain, svc, err := findService(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+
+	path := findPlist(domain, svc, label)
+	if path == "" {
+		return nil, fmt.Errorf("launchd: plist file for job(%s) not found: %w", label, syscall.ENOENT)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: failed to read plist file(%s): %w", path, err)
+	}
+
+	var job plist.Job
+	if err = plist.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("launchd: failed to parse plist file(%s): %w", path, err)
+	}
+
+	network, addr, err := unixSocketOf(&job, socketName)
+	if err != nil {
+		return nil, err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: failed to connect to socket(%s) of job(%s): %w", socketName, label, err)
+	}
+	return conn, nil
+}
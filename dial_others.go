@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Os specific implementation of [DialContext].
+func dial(_ context.Context, _, _ string) (net.Conn, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestUnixSocketOf(t *testing.T) {
+	active := false
+	job := &plist.Job{
+		Label: "com.example.test",
+		Sockets: map[string]plist.Socket{
+			"stream":    {SockPathName: "/var/run/stream.sock"},
+			"dgram":     {SockType: "dgram", SockPathName: "/var/run/dgram.sock"},
+			"seqpacket": {SockType: "seqpacket", SockPathName: "/var/run/seqpacket.sock"},
+			"tcp":       {SockServiceName: "8080"},
+			"active":    {SockPassive: &active, SockPathName: "/var/run/active.sock"},
+			"invalid":   {SockType: "raw", SockPathName: "/var/run/raw.sock"},
+		},
+	}
+	tt := []struct {
+		name    string
+		network string
+		path    string
+		err     error
+	}{
+		{name: "stream", network: "unix", path: "/var/run/stream.sock"},
+		{name: "dgram", network: "unixgram", path: "/var/run/dgram.sock"},
+		{name: "seqpacket", network: "unixpacket", path: "/var/run/seqpacket.sock"},
+		{name: "tcp", err: syscall.EAFNOSUPPORT},
+		{name: "active", err: syscall.EINVAL},
+		{name: "invalid", err: syscall.EINVAL},
+		{name: "missing", err: syscall.ENOENT},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			network, path, err := unixSocketOf(job, tc.name)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%v, got=%v", tc.err, err)
+			}
+			if network != tc.network || path != tc.path {
+				t.Errorf("expected=%s:%s, got=%s:%s", tc.network, tc.path, network, path)
+			}
+		})
+	}
+}
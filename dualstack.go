@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"syscall"
+)
+
+// WithEnsureDualStack makes [DualStack] listen on the missing address family
+// itself, when launchd only created a socket for one of IPv4 or IPv6, for
+// example for plists with SockFamily written before IPv6 was considered.
+// Missing listener is bound on the same port, to the unspecified address
+// if the socket listens on unspecified address, or to the loopback address
+// if the socket listens on loopback address. Listening is best-effort,
+// and failures are only logged, as other addresses cannot be mapped
+// between address families.
+func WithEnsureDualStack() Option {
+	return func(o *options) {
+		o.ensureDualStack = true
+	}
+}
+
+// DualStackListeners are listeners of a socket which listens on both IPv4
+// and IPv6, for example a socket without SockFamily, for which launchd
+// creates a file descriptor for each of the address families.
+type DualStackListeners struct {
+	// V4 is the listener of the IPv4 socket. This is nil if launchd
+	// did not create an IPv4 socket.
+	V4 net.Listener
+
+	// V6 is the listener of the IPv6 socket. This is nil if launchd
+	// did not create an IPv6 socket.
+	V6 net.Listener
+}
+
+// Listeners returns non-nil listeners, IPv4 listener first.
+func (d *DualStackListeners) Listeners() []net.Listener {
+	listeners := make([]net.Listener, 0, 2)
+	for _, l := range []net.Listener{d.V4, d.V6} {
+		if l != nil {
+			listeners = append(listeners, l)
+		}
+	}
+	return listeners
+}
+
+// Close closes non-nil listeners.
+func (d *DualStackListeners) Close() error {
+	var err error
+	for _, l := range d.Listeners() {
+		err = errors.Join(err, l.Close())
+	}
+	return err
+}
+
+// DualStack is like [Open], but returns listeners of the TCP socket by their
+// address family, so that servers can register them distinctly, for example
+// for metrics and policies.
+//
+// Use [WithEnsureDualStack] to listen on the missing address family, if
+// launchd only created a socket for one of them.
+//
+// By default, if activated socket has file descriptors which are not TCP
+// listeners, or more than one listener for an address family, all listeners
+// are closed and an error is returned. With [WithPartial], such file
+// descriptors are closed and usable listeners are returned.
+//
+//   - [syscall.EALREADY] is returned if socket is already activated.
+//   - [syscall.ENOENT] or [syscall.ESRCH] is returned if socket is not found.
+//   - [syscall.ESOCKTNOSUPPORT] is returned if socket is not a stream socket.
+//   - [syscall.EAFNOSUPPORT] is returned if socket is not a TCP socket.
+//   - [syscall.EADDRINUSE] is returned if there are multiple listeners for an address family.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// This must be called exactly once for a given socket name. Subsequent calls
+// with the same socket name will return [syscall.EALREADY].
+func DualStack(name string, opts ...Option) (*DualStackListeners, error) {
+	o := newOptions(opts)
+	r, err := open(name, o)
+	if err != nil {
+		if r != nil {
+			_ = r.Close()
+		}
+		return nil, err
+	}
+	return newDualStack(r, o)
+}
+
+// newDualStack builds [DualStackListeners] from the result. Anything other
+// than a single TCP listener per address family is closed, and reported as
+// an error unless partial results are allowed.
+func newDualStack(r *Result, o *options) (*DualStackListeners, error) {
+	d := &DualStackListeners{}
+	err := r.Err()
+	for _, l := range r.Listeners {
+		addr, ok := l.Addr().(*net.TCPAddr)
+		switch {
+		case !ok:
+			err = errors.Join(err, fmt.Errorf("%s: not a TCP listener(%s): %w", r.Name, l.Addr(), syscall.EAFNOSUPPORT))
+			_ = l.Close()
+		case len(addr.IP) == net.IPv4len && d.V4 == nil:
+			d.V4 = l
+		case len(addr.IP) == net.IPv6len && d.V6 == nil:
+			d.V6 = l
+		default:
+			err = errors.Join(err, fmt.Errorf("%s: duplicate listener(%s): %w", r.Name, addr, syscall.EADDRINUSE))
+			_ = l.Close()
+		}
+	}
+	for _, l := range r.PacketConns {
+		err = errors.Join(err, fmt.Errorf("%s: not a stream socket(%s): %w", r.Name, l.LocalAddr(), syscall.ESOCKTNOSUPPORT))
+	}
+	for _, c := range r.Conns {
+		err = errors.Join(err, fmt.Errorf("%s: not a listening socket(%s): %w", r.Name, c.LocalAddr(), syscall.EISCONN))
+	}
+
+	// Listeners are owned by d, and everything else is closed.
+	r.Listeners = nil
+	_ = r.Close()
+
+	if err != nil && !o.partial {
+		_ = d.Close()
+		return nil, fmt.Errorf("launchd: error building dual stack listeners: %w", err)
+	}
+	if o.ensureDualStack {
+		d.ensure(r.Name, o)
+	}
+	return d, nil
+}
+
+// ensure listens on the missing address family, if only one of
+// the listeners is present. Errors are logged and ignored.
+func (d *DualStackListeners) ensure(name string, o *options) {
+	var have net.Listener
+	switch {
+	case d.V4 != nil && d.V6 == nil:
+		have = d.V4
+	case d.V6 != nil && d.V4 == nil:
+		have = d.V6
+	default:
+		return
+	}
+
+	network, address, ok := counterpart(have.Addr().(*net.TCPAddr))
+	if !ok {
+		debug("launchd: cannot map address to other address family",
+			slog.String("name", name),
+			slog.String("addr", have.Addr().String()),
+		)
+		return
+	}
+
+	l, err := net.Listen(network, address)
+	if err != nil {
+		debug("launchd: failed to listen on missing address family",
+			slog.String("name", name),
+			slog.String("addr", address),
+			slog.Any("err", err),
+		)
+		return
+	}
+
+	// Apply the same options as activated listeners, and share
+	// the token bucket, if listener is rate limited.
+	l = tuneListener(l, o)
+	if rl, ok := have.(*rateLimitListener); ok {
+		l = &rateLimitListener{Listener: l, bucket: rl.bucket}
+	}
+
+	debug("launchd: listening on missing address family",
+		slog.String("name", name),
+		slog.String("addr", l.Addr().String()),
+	)
+	if network == "tcp4" {
+		d.V4 = l
+	} else {
+		d.V6 = l
+	}
+}
+
+// counterpart returns network and address of the other address family
+// for unspecified and loopback addresses, with the same port.
+func counterpart(addr *net.TCPAddr) (string, string, bool) {
+	port := strconv.Itoa(addr.Port)
+	switch {
+	case len(addr.IP) == net.IPv4len && addr.IP.IsUnspecified():
+		return "tcp6", net.JoinHostPort("::", port), true
+	case len(addr.IP) == net.IPv4len && addr.IP.IsLoopback():
+		return "tcp6", net.JoinHostPort("::1", port), true
+	case len(addr.IP) == net.IPv6len && addr.IP.IsUnspecified():
+		return "tcp4", net.JoinHostPort("0.0.0.0", port), true
+	case len(addr.IP) == net.IPv6len && addr.IP.Equal(net.IPv6loopback):
+		return "tcp4", net.JoinHostPort("127.0.0.1", port), true
+	default:
+		return "", "", false
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+)
+
+// listen listens on the network and closes the listener on cleanup.
+func listen(t *testing.T, network, address string) net.Listener {
+	t.Helper()
+	l, err := net.Listen(network, address)
+	if err != nil {
+		t.Skipf("failed to listen on %s(%s): %s", address, network, err)
+	}
+	t.Cleanup(func() {
+		l.Close()
+	})
+	return l
+}
+
+func TestNewDualStack(t *testing.T) {
+	t.Run("DualStack", func(t *testing.T) {
+		v4, v6 := listen(t, "tcp4", "127.0.0.1:0"), listen(t, "tcp6", "[::1]:0")
+		d, err := newDualStack(&Result{Name: "test", Listeners: []net.Listener{v6, v4}}, &options{})
+		if err != nil {
+			t.Fatalf("expected no error, got=%s", err)
+		}
+		if d.V4 != v4 || d.V6 != v6 {
+			t.Errorf("expected listeners by family, got=%+v", d)
+		}
+		if got := d.Listeners(); len(got) != 2 || got[0] != v4 {
+			t.Errorf("expected IPv4 listener first, got=%v", got)
+		}
+	})
+
+	t.Run("V4Only", func(t *testing.T) {
+		v4 := listen(t, "tcp4", "127.0.0.1:0")
+		d, err := newDualStack(&Result{Name: "test", Listeners: []net.Listener{v4}}, &options{})
+		if err != nil {
+			t.Fatalf("expected no error, got=%s", err)
+		}
+		if d.V4 != v4 || d.V6 != nil {
+			t.Errorf("expected only IPv4 listener, got=%+v", d)
+		}
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		a, b := listen(t, "tcp4", "127.0.0.1:0"), listen(t, "tcp4", "127.0.0.1:0")
+		_, err := newDualStack(&Result{Name: "test", Listeners: []net.Listener{a, b}}, &options{})
+		if !errors.Is(err, syscall.EADDRINUSE) {
+			t.Errorf("expected error=%s, got=%s", syscall.EADDRINUSE, err)
+		}
+		if _, err = a.Accept(); !errors.Is(err, net.ErrClosed) {
+			t.Errorf("expected listeners to be closed, got=%s", err)
+		}
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		a, b := listen(t, "tcp4", "127.0.0.1:0"), listen(t, "tcp4", "127.0.0.1:0")
+		p, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		d, err := newDualStack(&Result{
+			Name:        "test",
+			Listeners:   []net.Listener{a, b},
+			PacketConns: []net.PacketConn{p},
+		}, &options{partial: true})
+		if err != nil {
+			t.Fatalf("expected no error, got=%s", err)
+		}
+		if d.V4 != a || d.V6 != nil {
+			t.Errorf("expected first IPv4 listener, got=%+v", d)
+		}
+		if _, err = b.Accept(); !errors.Is(err, net.ErrClosed) {
+			t.Errorf("expected duplicate listener to be closed, got=%s", err)
+		}
+		if _, _, err = p.ReadFrom(make([]byte, 1)); !errors.Is(err, net.ErrClosed) {
+			t.Errorf("expected packet conn to be closed, got=%s", err)
+		}
+	})
+}
+
+func TestCounterpart(t *testing.T) {
+	tt := []struct {
+		name    string
+		addr    *net.TCPAddr
+		network string
+		address string
+		ok      bool
+	}{
+		{name: "v4-unspecified", addr: &net.TCPAddr{IP: net.IPv4zero.To4(), Port: 80}, network: "tcp6", address: "[::]:80", ok: true},
+		{name: "v4-loopback", addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1).To4(), Port: 80}, network: "tcp6", address: "[::1]:80", ok: true},
+		{name: "v6-unspecified", addr: &net.TCPAddr{IP: net.IPv6unspecified, Port: 443}, network: "tcp4", address: "0.0.0.0:443", ok: true},
+		{name: "v6-loopback", addr: &net.TCPAddr{IP: net.IPv6loopback, Port: 443}, network: "tcp4", address: "127.0.0.1:443", ok: true},
+		{name: "v4-other", addr: &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1).To4(), Port: 80}},
+		{name: "v6-other", addr: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 80}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			network, address, ok := counterpart(tc.addr)
+			if network != tc.network || address != tc.address || ok != tc.ok {
+				t.Errorf("expected=%s:%s(%t), got=%s:%s(%t)", tc.network, tc.address, tc.ok, network, address, ok)
+			}
+		})
+	}
+}
+
+func TestNewDualStack_Ensure(t *testing.T) {
+	v4 := listen(t, "tcp4", "127.0.0.1:0")
+	d, err := newDualStack(&Result{Name: "test", Listeners: []net.Listener{v4}}, newOptions([]Option{WithEnsureDualStack()}))
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	if d.V4 != v4 {
+		t.Errorf("expected IPv4 listener to be returned as is")
+	}
+	if d.V6 == nil {
+		t.Skipf("failed to listen on IPv6 loopback address")
+	}
+	defer d.V6.Close()
+
+	addr := d.V6.Addr().(*net.TCPAddr)
+	if !addr.IP.Equal(net.IPv6loopback) || addr.Port != v4.Addr().(*net.TCPAddr).Port {
+		t.Errorf("expected IPv6 listener on [::1]:%d, got=%s", v4.Addr().(*net.TCPAddr).Port, addr)
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ECN is the Explicit Congestion Notification codepoint of an IP packet,
+// i.e. the two least significant bits of IPv4 TOS or IPv6 traffic class,
+// as defined by RFC 3168.
+type ECN uint8
+
+// ECN codepoints.
+const (
+	ECNNotECT ECN = 0x0 // Not ECN-Capable Transport
+	ECNECT1   ECN = 0x1 // ECN Capable Transport, ECT(1)
+	ECNECT0   ECN = 0x2 // ECN Capable Transport, ECT(0)
+	ECNCE     ECN = 0x3 // Congestion Experienced
+)
+
+// ecnMask is the mask of ECN bits of TOS or traffic class.
+const ecnMask = 0x3
+
+// String returns the name of the codepoint, as used by RFC 3168.
+func (e ECN) String() string {
+	switch e {
+	case ECNNotECT:
+		return "Not-ECT"
+	case ECNECT1:
+		return "ECT(1)"
+	case ECNECT0:
+		return "ECT(0)"
+	case ECNCE:
+		return "CE"
+	default:
+		return fmt.Sprintf("ECN(%d)", uint8(e))
+	}
+}
+
+// WithECN enables IP_RECVTOS or IPV6_RECVTCLASS on UDP sockets returned by
+// [Open] as packet connections, so that [ReadFromWithDst] returns the
+// ECN codepoint of received packets, as required by QUIC (RFC 9000)
+// for ECN validation. Use [WithTrafficClass] to mark sent packets as
+// ECN capable.
+//
+// Failing to set socket options is handled like any other socket which
+// cannot be used, i.e. [Open] returns an error unless [WithPartial] is used.
+// This also applies to [WithTrafficClass] and [WithDontFragment].
+func WithECN() Option {
+	return func(o *options) {
+		o.ecn = true
+	}
+}
+
+// WithTrafficClass sets IP_TOS or IPV6_TCLASS of UDP sockets returned by
+// [Open] as packet connections, i.e. DSCP and ECN codepoint of sent packets.
+// For example, QUIC stacks which support ECN would use [ECNECT0].
+// Traffic class is left unchanged if tc is negative or zero.
+func WithTrafficClass(tc int) Option {
+	return func(o *options) {
+		o.trafficClass = tc
+	}
+}
+
+// WithDontFragment sets don't fragment (DF) bit on packets sent by UDP sockets
+// returned by [Open] as packet connections, as required by QUIC (RFC 9000)
+// and by path MTU discovery of datagram protocols. This uses IP_DONTFRAG
+// or IPV6_DONTFRAG on macOS, and path MTU discovery options on Linux.
+func WithDontFragment() Option {
+	return func(o *options) {
+		o.dontFragment = true
+	}
+}
+
+// udpOptions sets ECN, traffic class and don't fragment options on UDP
+// packet connections of the result, if configured. Packet connections which
+// fail are closed and reported as errors, like sockets which could not be used.
+func (r *Result) udpOptions(o *options) {
+	if !o.ecn && o.trafficClass <= 0 && !o.dontFragment {
+		return
+	}
+
+	var err error
+	packetConns := r.PacketConns[:0]
+	for _, p := range r.PacketConns {
+		if c, ok := p.(*net.UDPConn); ok {
+			if e := setUDPOptions(c, o); e != nil {
+				err = errors.Join(err, fmt.Errorf("launchd: failed to set socket options(%s): %w", p.LocalAddr(), e))
+				_ = p.Close()
+				continue
+			}
+		}
+		packetConns = append(packetConns, p)
+	}
+	r.PacketConns = packetConns
+	if err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+}
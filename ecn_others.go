@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin && !linux
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// setUDPOptions sets ECN, traffic class and don't fragment options
+// of the UDP connection for its address family.
+func setUDPOptions(_ *net.UDPConn, _ *options) error {
+	return fmt.Errorf("launchd: UDP socket options are only supported on macOS and Linux: %w", syscall.ENOTSUP)
+}
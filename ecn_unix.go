@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin || linux
+
+package launchd
+
+import (
+	"net"
+	"syscall"
+)
+
+// setUDPOptions sets ECN, traffic class and don't fragment options
+// of the UDP connection for its address family.
+func setUDPOptions(c *net.UDPConn, o *options) error {
+	level, recvTOS, tos, df, dfValue := syscall.IPPROTO_IPV6, ipv6RecvTClass, ipv6TClass, ipv6DontFrag, ipv6DontFragValue
+	if isIPv4Conn(c) {
+		level, recvTOS, tos, df, dfValue = syscall.IPPROTO_IP, ipRecvTOS, ipTOS, ipDontFrag, ipDontFragValue
+	}
+
+	if o.ecn {
+		if err := setSockopt(c, level, recvTOS, 1); err != nil {
+			return err
+		}
+	}
+	if o.trafficClass > 0 {
+		if err := setSockopt(c, level, tos, o.trafficClass); err != nil {
+			return err
+		}
+	}
+	if o.dontFragment {
+		if err := setSockopt(c, level, df, dfValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
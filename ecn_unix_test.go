@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin || linux
+
+package launchd
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestUDPOptions(t *testing.T) {
+	tt := []struct {
+		name     string
+		network  string
+		loopback net.IP
+		level    int
+		df       int
+	}{
+		{name: "IPv4", network: "udp4", loopback: net.IPv4(127, 0, 0, 1), level: syscall.IPPROTO_IP, df: ipDontFrag},
+		{name: "IPv6", network: "udp6", loopback: net.IPv6loopback, level: syscall.IPPROTO_IPV6, df: ipv6DontFrag},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := net.ListenPacket(tc.network, net.JoinHostPort(tc.loopback.String(), "0"))
+			if err != nil {
+				t.Skipf("failed to listen: %s", err)
+			}
+			r := &Result{Name: "test", PacketConns: []net.PacketConn{p}}
+			defer r.Close()
+
+			r.udpOptions(newOptions([]Option{WithECN(), WithTrafficClass(int(ECNECT0)), WithDontFragment()}))
+			if len(r.PacketConns) != 1 || r.Partial() {
+				t.Fatalf("expected no error, got=%v", r.Err())
+			}
+			server := p.(*net.UDPConn)
+
+			rc, err := server.SyscallConn()
+			if err != nil {
+				t.Fatalf("failed to get raw conn: %s", err)
+			}
+			var df int
+			_ = rc.Control(func(fd uintptr) {
+				df, err = syscall.GetsockoptInt(int(fd), tc.level, tc.df)
+			})
+			if err != nil || df == 0 {
+				t.Errorf("expected don't fragment to be set, got=%d(%v)", df, err)
+			}
+
+			client, err := net.DialUDP(tc.network, nil, server.LocalAddr().(*net.UDPAddr))
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err)
+			}
+			defer client.Close()
+			if err = setUDPOptions(client, &options{trafficClass: int(ECNCE)}); err != nil {
+				t.Fatalf("failed to set traffic class: %s", err)
+			}
+			_ = server.SetDeadline(time.Now().Add(5 * time.Second))
+			if _, err = client.Write([]byte("ping")); err != nil {
+				t.Fatalf("failed to write: %s", err)
+			}
+
+			b := make([]byte, 16)
+			_, _, info, err := ReadFromWithDst(server, b)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			if info == nil || info.ECN != ECNCE {
+				t.Errorf("expected ECN=%s, got=%+v", ECNCE, info)
+			}
+		})
+	}
+}
+
+func TestECN_String(t *testing.T) {
+	for ecn, expect := range map[ECN]string{
+		ECNNotECT: "Not-ECT",
+		ECNECT1:   "ECT(1)",
+		ECNECT0:   "ECT(0)",
+		ECNCE:     "CE",
+		ECN(4):    "ECN(4)",
+	} {
+		if ecn.String() != expect {
+			t.Errorf("expected %s, got=%s", expect, ecn)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && cgo && launchd_cgo
+
+package launchd
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework Security
+#include <stdbool.h>
+#include <stdlib.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <Security/Security.h>
+
+// launchd_has_entitlement reports whether the calling process has the
+// entitlement, and whether its value is true. For entitlements with
+// non-boolean values, like arrays of strings, it is true if present.
+static bool launchd_has_entitlement(const char *name) {
+	CFStringRef key = CFStringCreateWithCString(NULL, name, kCFStringEncodingUTF8);
+	if (key == NULL) {
+		return false;
+	}
+	SecTaskRef task = SecTaskCreateFromSelf(NULL);
+	if (task == NULL) {
+		CFRelease(key);
+		return false;
+	}
+	CFTypeRef value = SecTaskCopyValueForEntitlement(task, key, NULL);
+	CFRelease(task);
+	CFRelease(key);
+	if (value == NULL) {
+		return false;
+	}
+	bool rv = true;
+	if (CFGetTypeID(value) == CFBooleanGetTypeID()) {
+		rv = CFBooleanGetValue((CFBooleanRef)value);
+	}
+	CFRelease(value);
+	return rv;
+}
+*/
+import "C"
+
+import (
+	"strings"
+	"unsafe"
+)
+
+// Os specific implementation of [HasEntitlement].
+func hasEntitlement(name string) bool {
+	if name == "" || strings.ContainsRune(name, 0) {
+		return false
+	}
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return bool(C.launchd_has_entitlement(cname))
+}
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !(cgo && launchd_cgo)
+
+package launchd
+
+import (
+	"strings"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// Os specific implementation of [HasEntitlement].
+func hasEntitlement(name string) bool {
+	if name == "" || strings.ContainsRune(name, 0) {
+		return false
+	}
+	_, value := macos.Entitlement(name)
+	return value
+}
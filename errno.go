@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"fmt"
+	"maps"
+	buildinfo "runtime/debug"
+	"sync"
+	"syscall"
+)
+
+// modulePath is the module path of this library.
+const modulePath = "github.com/tprasadtp/go-launchd"
+
+// activationErrnos are documented or observed return codes
+// of launch_activate_socket.
+//
+//nolint:gochecknoglobals // read only.
+var activationErrnos = map[syscall.Errno]string{
+	syscall.ENOENT:   "no socket of the specified name is owned by the caller",
+	syscall.ESRCH:    "caller is not managed by launchd, or socket is not present in launchd",
+	syscall.EALREADY: "socket has already been activated by the caller",
+	syscall.EINTR:    "call was interrupted by a signal",
+	syscall.EAGAIN:   "resource is temporarily unavailable",
+	syscall.EINVAL:   "socket name is invalid",
+	syscall.EPERM:    "operation is not permitted",
+}
+
+// ActivationErrnos returns a copy of the table of known return codes of
+// launch_activate_socket and their descriptions, which is used by
+// [DecodeErrno].
+func ActivationErrnos() map[syscall.Errno]string {
+	return maps.Clone(activationErrnos)
+}
+
+// DecodeErrno decodes raw return code of launch_activate_socket, as logged
+// in "rc" attribute of debug logs, into errno and its description.
+// This is useful for log processors. Description of unknown return
+// codes is the description of errno from the operating system.
+func DecodeErrno(rc uintptr) (syscall.Errno, string) {
+	errno := syscall.Errno(rc)
+	if desc, ok := activationErrnos[errno]; ok {
+		return errno, desc
+	}
+	return errno, fmt.Sprintf("unknown return code(%d): %s", rc, errno.Error())
+}
+
+// ActivationError is returned when launch_activate_socket returns
+// an unknown return code, along with details useful for diagnostics.
+type ActivationError struct {
+	// Name is the name of the socket.
+	Name string
+
+	// Code is the raw return code of launch_activate_socket.
+	Code uintptr
+
+	// Backend is the implementation used to call launch_activate_socket,
+	// one of "libc", "dlsym", "cgo" or "simulator".
+	Backend string
+
+	// LibraryVersion is the version of this library,
+	// as recorded in build info of the binary.
+	LibraryVersion string
+
+	// OSVersion is the product version of macOS, like "14.4.1".
+	// This is empty if it cannot be determined.
+	OSVersion string
+}
+
+// Error implements error interface.
+func (e *ActivationError) Error() string {
+	_, desc := DecodeErrno(e.Code)
+	return fmt.Sprintf("launchd: socket(%s): %s (backend=%s, go-launchd=%s, macOS=%s)",
+		e.Name, desc, e.Backend, e.LibraryVersion, e.OSVersion)
+}
+
+// Unwrap returns the errno of the return code.
+func (e *ActivationError) Unwrap() error {
+	return syscall.Errno(e.Code)
+}
+
+// newActivationError returns [ActivationError] for the unknown return code.
+func newActivationError(name string, rc syscall.Errno, backend string) *ActivationError {
+	return &ActivationError{
+		Name:           name,
+		Code:           uintptr(rc),
+		Backend:        backend,
+		LibraryVersion: libraryVersion(),
+		OSVersion:      osVersion(),
+	}
+}
+
+// libraryVersion returns version of this library from build info.
+//
+//nolint:gochecknoglobals // computed once.
+var libraryVersion = sync.OnceValue(func() string {
+	info, ok := buildinfo.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+})
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestDecodeErrno(t *testing.T) {
+	tt := []struct {
+		name  string
+		rc    uintptr
+		errno syscall.Errno
+		desc  string
+	}{
+		{name: "ENOENT", rc: uintptr(syscall.ENOENT), errno: syscall.ENOENT, desc: activationErrnos[syscall.ENOENT]},
+		{name: "EALREADY", rc: uintptr(syscall.EALREADY), errno: syscall.EALREADY, desc: activationErrnos[syscall.EALREADY]},
+		{name: "unknown", rc: uintptr(syscall.EBUSY), errno: syscall.EBUSY, desc: "unknown return code"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			errno, desc := DecodeErrno(tc.rc)
+			if errno != tc.errno {
+				t.Errorf("expected errno=%s, got=%s", tc.errno, errno)
+			}
+			if !strings.HasPrefix(desc, tc.desc) {
+				t.Errorf("expected description=%q, got=%q", tc.desc, desc)
+			}
+		})
+	}
+}
+
+func TestActivationErrnos(t *testing.T) {
+	table := ActivationErrnos()
+	if _, ok := table[syscall.ESRCH]; !ok {
+		t.Errorf("expected ESRCH to be in table")
+	}
+
+	delete(table, syscall.ESRCH)
+	if _, ok := activationErrnos[syscall.ESRCH]; !ok {
+		t.Errorf("expected a copy of the table to be returned")
+	}
+}
+
+func TestActivationError(t *testing.T) {
+	var err error = newActivationError("listener", syscall.EBUSY, "libc")
+	if !errors.Is(err, syscall.EBUSY) {
+		t.Errorf("expected error=%s, got=%s", syscall.EBUSY, err)
+	}
+
+	var ae *ActivationError
+	if !errors.As(err, &ae) {
+		t.Fatalf("expected ActivationError, got=%T", err)
+	}
+	if ae.Code != uintptr(syscall.EBUSY) || ae.Name != "listener" || ae.LibraryVersion == "" {
+		t.Errorf("unexpected error details: %+v", ae)
+	}
+	for _, s := range []string{"listener", "backend=libc", "go-launchd=", "macOS="} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error message to contain %q, got=%s", s, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Escalator runs commands with root privileges.
+//
+// It is used by [Install], [Uninstall] and [Upgrade] to perform privileged
+// operations, like writing plist files to /Library/LaunchDaemons and
+// bootstrapping jobs into the system domain, when the calling process
+// is not running as root. Implementations may prompt the user for
+// credentials, or delegate the command to a pre-installed privileged
+// helper.
+type Escalator interface {
+	// Escalate runs the command specified by argv with root privileges
+	// and waits for it to complete. argv[0] is always an absolute path.
+	Escalate(ctx context.Context, argv []string) error
+}
+
+// EscalatorFunc is an adapter to allow use of ordinary functions
+// as [Escalator].
+type EscalatorFunc func(ctx context.Context, argv []string) error
+
+// Escalate implements [Escalator].
+func (fn EscalatorFunc) Escalate(ctx context.Context, argv []string) error {
+	return fn(ctx, argv)
+}
+
+// SudoEscalator is an [Escalator] which uses sudo(8).
+type SudoEscalator struct {
+	// NonInteractive prevents sudo from prompting for password.
+	// If enabled, command fails if password is required.
+	// Otherwise, sudo prompts on the controlling terminal.
+	NonInteractive bool
+}
+
+// Escalate implements [Escalator].
+func (s SudoEscalator) Escalate(ctx context.Context, argv []string) error {
+	args := make([]string, 0, len(argv)+2)
+	if s.NonInteractive {
+		args = append(args, "-n")
+	}
+	args = append(args, "--")
+	args = append(args, argv...)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "/usr/bin/sudo", args...)
+	if !s.NonInteractive {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("launchd: sudo failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// OsascriptEscalator is an [Escalator] which uses osascript(1) to run
+// commands with administrator privileges. User is prompted for credentials
+// with a standard macOS authorization dialog, thus it is suitable
+// for GUI applications.
+type OsascriptEscalator struct {
+	// Prompt is shown in the authorization dialog.
+	// If empty, a default prompt is shown.
+	Prompt string
+}
+
+// appleScriptQuote returns s as an AppleScript string literal.
+func appleScriptQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// Escalate implements [Escalator].
+func (s OsascriptEscalator) Escalate(ctx context.Context, argv []string) error {
+	script := fmt.Sprintf("do shell script %s with administrator privileges",
+		appleScriptQuote(shellJoin(argv)))
+	if s.Prompt != "" {
+		script += " with prompt " + appleScriptQuote(s.Prompt)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "/usr/bin/osascript", "-e", script)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("launchd: osascript failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// WithEscalator sets the [Escalator] used for privileged operations
+// when the calling process is not running as root, i.e. for jobs in
+// the system domain or in per-user domains of other users. Jobs in
+// per-user domains of the caller are installed without escalation.
+//
+// If not specified, privileged operations are performed directly
+// and fail with permission errors when not running as root.
+func WithEscalator(e Escalator) InstallOption {
+	return func(o *installOptions) {
+		o.escalator = e
+	}
+}
+
+// shellQuote returns s quoted for use in POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin returns argv as a POSIX shell command.
+func shellJoin(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i := range argv {
+		quoted[i] = shellQuote(argv[i])
+	}
+	return strings.Join(quoted, " ")
+}
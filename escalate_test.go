@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"testing"
+)
+
+func TestShellJoin(t *testing.T) {
+	tt := []struct {
+		name   string
+		argv   []string
+		expect string
+	}{
+		{
+			name:   "simple",
+			argv:   []string{"/bin/launchctl", "bootstrap", "system"},
+			expect: `'/bin/launchctl' 'bootstrap' 'system'`,
+		},
+		{
+			name:   "quotes",
+			argv:   []string{"/bin/sh", "-c", "echo 'hello world'"},
+			expect: `'/bin/sh' '-c' 'echo '\''hello world'\'''`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := shellJoin(tc.argv); v != tc.expect {
+				t.Errorf("expected=%s, got=%s", tc.expect, v)
+			}
+		})
+	}
+}
+
+func TestAppleScriptQuote(t *testing.T) {
+	v := appleScriptQuote(`'/bin/sh' '-c' "a\b"`)
+	expect := `"'/bin/sh' '-c' \"a\\b\""`
+	if v != expect {
+		t.Errorf("expected=%s, got=%s", expect, v)
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"log/slog"
+	"net"
+)
+
+// WithTCPFastOpen enables TCP Fast Open (RFC 7413) on TCP listeners returned
+// by [Open], which allows clients to send data in the SYN of repeated
+// connections, saving a round trip for latency sensitive daemons.
+//
+// On macOS, length of the queue of pending Fast Open requests is system wide,
+// set by net.inet.tcp.fastopen_backlog sysctl, thus qlen only enables
+// TCP Fast Open when positive. TCP Fast Open must also be enabled for servers
+// by net.inet.tcp.fastopen sysctl, which it is by default.
+//
+// As TCP Fast Open is an optimization, failing to enable it, for example on
+// macOS versions or platforms without support for it, is logged and ignored.
+func WithTCPFastOpen(qlen int) Option {
+	return func(o *options) {
+		o.fastOpen = qlen
+	}
+}
+
+// fastOpen enables TCP Fast Open on TCP listeners of the result, if configured.
+func (r *Result) fastOpen(o *options) {
+	if o.fastOpen <= 0 {
+		return
+	}
+	for _, l := range r.Listeners {
+		tl, ok := l.(*net.TCPListener)
+		if !ok {
+			continue
+		}
+		rc, err := tl.SyscallConn()
+		if err == nil {
+			err = setFastOpen(rc, o.fastOpen)
+		}
+		debug("launchd: enabling TCP fast open",
+			slog.String("name", r.Name),
+			slog.String("addr", l.Addr().String()),
+			slog.Int("qlen", o.fastOpen),
+			slog.Any("err", err),
+		)
+	}
+}
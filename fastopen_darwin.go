@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin
+
+package launchd
+
+import (
+	"os"
+	"syscall"
+)
+
+// tcpFastOpen is TCP_FASTOPEN socket option from <netinet/tcp.h>,
+// which is not defined by [syscall].
+const tcpFastOpen = 0x105
+
+// setFastOpen enables TCP Fast Open on the listening socket. Queue length
+// is system wide on macOS, thus qlen is not used.
+func setFastOpen(c syscall.RawConn, _ int) error {
+	var serr error
+	err := c.Control(func(fd uintptr) {
+		serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, tcpFastOpen, 1)
+	})
+	if err != nil {
+		return err
+	}
+	if serr != nil {
+		return os.NewSyscallError("setsockopt", serr)
+	}
+	return nil
+}
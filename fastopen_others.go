@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin
+
+package launchd
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// setFastOpen enables TCP Fast Open on the listening socket.
+func setFastOpen(_ syscall.RawConn, _ int) error {
+	return fmt.Errorf("launchd: TCP fast open is only supported on macOS: %w", syscall.ENOTSUP)
+}
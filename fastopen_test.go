@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+	"testing"
+)
+
+func TestResult_FastOpen(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	r := &Result{Name: "test", Listeners: []net.Listener{l}}
+	defer r.Close()
+
+	// Failing to enable TCP fast open is not an error.
+	r.fastOpen(newOptions([]Option{WithTCPFastOpen(16)}))
+	if len(r.Listeners) != 1 || r.Partial() {
+		t.Errorf("expected listener to be usable, got=%v", r.Err())
+	}
+
+	conn, err := net.Dial("tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	conn.Close()
+}
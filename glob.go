@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"slices"
+	"syscall"
+)
+
+// ListenersGlob is like [Listeners], but activates all the sockets of the job
+// whose names match the pattern, with the syntax of [path.Match]. This is
+// useful for sharded services, whose plist defines sockets like "worker-0"
+// to "worker-7", which can be activated with ListenersGlob("worker-*").
+//
+// Socket names of the job are determined from launchctl print output of the
+// job, or its plist file if the job is not loaded, like [Diagnose]. Label of
+// the job is determined from XPC_SERVICE_NAME environment variable.
+//
+// Returned map contains listeners for each of the matching sockets, including
+// partial list of listeners for sockets with errors. Errors are joined with
+// [errors.Join]. It is the responsibility of the caller to close the returned
+// listeners whenever required.
+//
+//   - [syscall.EINVAL] is returned if pattern is malformed.
+//   - [syscall.ENOENT] is returned if no socket matches the pattern.
+//   - [syscall.ESRCH] is returned if calling process is not manged by launchd.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// As with [Listeners], this must be called exactly once for the matching
+// sockets. Subsequent calls will return [syscall.EALREADY].
+func ListenersGlob(pattern string) (map[string][]net.Listener, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("launchd: invalid pattern(%s): %w", pattern, errors.Join(syscall.EINVAL, err))
+	}
+
+	names, err := socketNames(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return listenersMatching(pattern, names, Listeners)
+}
+
+// listenersMatching builds listeners for each of the names matching the pattern
+// with listen. Pattern must be validated beforehand.
+func listenersMatching(
+	pattern string, names []string, listen func(string) ([]net.Listener, error),
+) (map[string][]net.Listener, error) {
+	var matched []string
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			matched = append(matched, name)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("launchd: no sockets matching pattern(%s): %w", pattern, syscall.ENOENT)
+	}
+	slices.Sort(matched)
+	matched = slices.Compact(matched)
+
+	var err error
+	rv := make(map[string][]net.Listener, len(matched))
+	for _, name := range matched {
+		l, el := listen(name)
+		if len(l) > 0 {
+			rv[name] = l
+		}
+		err = errors.Join(err, el)
+	}
+	return rv, err
+}
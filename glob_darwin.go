@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// socketNames returns names of sockets of the job of the calling process.
+func socketNames(ctx context.Context) ([]string, error) {
+	// Processes started from Terminal have XPC_SERVICE_NAME set to "0".
+	label := os.Getenv("XPC_SERVICE_NAME")
+	if label == "" || label == "0" {
+		return nil, fmt.Errorf("launchd: process is not managed by launchd: %w", syscall.ESRCH)
+	}
+
+	domain, svc, err := findService(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+	if svc != nil && len(svc.Sockets) > 0 {
+		names := make([]string, 0, len(svc.Sockets))
+		for _, s := range svc.Sockets {
+			names = append(names, s.Name)
+		}
+		return names, nil
+	}
+
+	path := findPlist(domain, svc, label)
+	if path == "" {
+		return nil, fmt.Errorf("launchd: plist file for job(%s) not found: %w", label, syscall.ENOENT)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: failed to read plist file(%s): %w", path, err)
+	}
+
+	var job plist.Job
+	if err = plist.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("launchd: failed to parse plist file(%s): %w", path, err)
+	}
+	names := make([]string, 0, len(job.Sockets))
+	for name := range job.Sockets {
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// socketNames returns names of sockets of the job of the calling process.
+func socketNames(_ context.Context) ([]string, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestListenersMatching(t *testing.T) {
+	names := []string{"worker-2", "http", "worker-0", "worker-1", "worker-0"}
+	var calls []string
+	listen := func(name string) ([]net.Listener, error) {
+		calls = append(calls, name)
+		if name == "worker-1" {
+			return nil, syscall.EALREADY
+		}
+		return []net.Listener{nil}, nil
+	}
+
+	rv, err := listenersMatching("worker-*", names, listen)
+	if !errors.Is(err, syscall.EALREADY) {
+		t.Errorf("expected error=%s, got=%s", syscall.EALREADY, err)
+	}
+	if expect := []string{"worker-0", "worker-1", "worker-2"}; !slices.Equal(calls, expect) {
+		t.Errorf("expected calls=%v, got=%v", expect, calls)
+	}
+	if len(rv) != 2 || rv["worker-0"] == nil || rv["worker-2"] == nil {
+		t.Errorf("expected listeners for worker-0 and worker-2, got=%v", rv)
+	}
+
+	_, err = listenersMatching("db-*", names, listen)
+	if !errors.Is(err, syscall.ENOENT) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOENT, err)
+	}
+}
+
+func TestListenersGlob_InvalidPattern(t *testing.T) {
+	_, err := ListenersGlob("worker-[")
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
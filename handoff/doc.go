@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package handoff passes activated sockets from a running process to its
+// replacement, allowing in-place upgrades of long running launchd daemons
+// without dropping connections or losing the kernel accept queue.
+//
+// Old process calls [Offer] with its sockets and an optional state blob,
+// which waits for the new process on a unix socket. New process calls
+// [Receive] on the same path, which returns the same sockets, passed via
+// SCM_RIGHTS, and the state. Once the new process has acknowledged the
+// handoff, old process should stop accepting new connections, drain
+// existing ones and exit.
+//
+// As launchd only runs a single instance of a job, the new process is
+// typically started by the old process itself. Such jobs should set
+// AbandonProcessGroup, so that launchd does not kill the new process
+// when the old process exits.
+//
+// For daemons which may crash, [Hold] runs an fd holder, typically as a
+// separate job generated by plist.FDHolder. Daemon stores duplicates of
+// its sockets in the holder with [Store], and on restart re-acquires
+// them with [Fetch], instead of creating new sockets.
+package handoff
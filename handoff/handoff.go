@@ -0,0 +1,396 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package handoff
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/internal/unixsock"
+)
+
+// SocketEnv is the environment variable which can be used by the old
+// process to pass the path of the handoff socket to the new process.
+const SocketEnv = "LAUNCHD_HANDOFF_SOCKET"
+
+// MaxFiles is the maximum number of files which can be handed off.
+const MaxFiles = 128
+
+// MaxStateSize is the maximum size of the state blob in bytes.
+const MaxStateSize = 32 << 10
+
+// ack is sent by the new process once it has received the payload.
+const ack = "ok"
+
+// maxMessageSize is the maximum size of the header. State is base64
+// encoded in the header, thus it can be larger than [MaxStateSize].
+const maxMessageSize = 2*MaxStateSize + MaxFiles*256
+
+// Payload is handed off from the old process to the new process.
+type Payload struct {
+	// Files are the sockets keyed by their names, for example names of
+	// the sockets in the Sockets dictionary of the job.
+	Files map[string][]*os.File
+
+	// State is an opaque blob passed to the new process.
+	State []byte
+}
+
+// header is the data part of the handoff message. Names has the name
+// of each passed file descriptor, in order. Op is only used by the
+// holder protocol.
+type header struct {
+	Op    string   `json:"op,omitempty"`
+	Names []string `json:"names"`
+	State []byte   `json:"state,omitempty"`
+}
+
+// Listeners returns [net.Listener] for each file of the stream socket.
+//
+// In case of error, a partial list of listeners is returned,
+// along with an error. Caller must close the returned listeners.
+//
+//   - [syscall.ENOENT] is returned if socket was not handed off.
+func (p *Payload) Listeners(name string) ([]net.Listener, error) {
+	files, ok := p.Files[name]
+	if !ok {
+		return nil, fmt.Errorf("handoff: socket(%s) was not handed off: %w", name, syscall.ENOENT)
+	}
+
+	var err error
+	listeners := make([]net.Listener, 0, len(files))
+	for _, f := range files {
+		l, lErr := net.FileListener(f)
+		if lErr != nil {
+			err = errors.Join(err, fmt.Errorf("handoff: socket(%s): %w", name, lErr))
+			continue
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, err
+}
+
+// PacketConns returns [net.PacketConn] for each file of the datagram socket.
+//
+// In case of error, a partial list of connections is returned,
+// along with an error. Caller must close the returned connections.
+//
+//   - [syscall.ENOENT] is returned if socket was not handed off.
+func (p *Payload) PacketConns(name string) ([]net.PacketConn, error) {
+	files, ok := p.Files[name]
+	if !ok {
+		return nil, fmt.Errorf("handoff: socket(%s) was not handed off: %w", name, syscall.ENOENT)
+	}
+
+	var err error
+	conns := make([]net.PacketConn, 0, len(files))
+	for _, f := range files {
+		c, cErr := net.FilePacketConn(f)
+		if cErr != nil {
+			err = errors.Join(err, fmt.Errorf("handoff: socket(%s): %w", name, cErr))
+			continue
+		}
+		conns = append(conns, c)
+	}
+	return conns, err
+}
+
+// Close closes all files of the payload. Listeners and connections
+// built from them are not affected.
+func (p *Payload) Close() error {
+	var err error
+	for _, files := range p.Files {
+		for _, f := range files {
+			err = errors.Join(err, f.Close())
+		}
+	}
+	return err
+}
+
+// Offer listens on unix socket at path and hands off the payload to the
+// first process which connects to it with [Receive]. It returns once
+// the new process has acknowledged the handoff or ctx is cancelled.
+// Files of the payload are not closed and remain usable by the caller.
+//
+// Socket is only accessible by the owner and is removed when Offer returns.
+// Connections from processes running as a different user, other than root,
+// are rejected.
+//
+//   - [syscall.EINVAL] is returned if payload exceeds [MaxFiles]
+//     or [MaxStateSize].
+//   - [syscall.ENOTSUP] is returned on non-unix platforms.
+func Offer(ctx context.Context, path string, p *Payload) error {
+	if err := supported(); err != nil {
+		return err
+	}
+
+	data, oob, err := encode("", p)
+	if err != nil {
+		return err
+	}
+
+	l, err := unixsock.Listen(path)
+	if err != nil {
+		return fmt.Errorf("handoff: %w", err)
+	}
+	defer l.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = l.Close()
+	})
+	defer stop()
+
+	var conn *net.UnixConn
+	for conn == nil {
+		c, err := l.AcceptUnix()
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("handoff: no process connected: %w", ctx.Err())
+			}
+			return fmt.Errorf("handoff: failed to accept: %w", err)
+		}
+		if err = unixsock.CheckPeer(c); err != nil {
+			_ = c.Close()
+			continue
+		}
+		conn = c
+	}
+	defer conn.Close()
+
+	setDeadline(ctx, conn)
+	if err = writeMsg(conn, data, oob); err != nil {
+		return fmt.Errorf("handoff: failed to send payload: %w", err)
+	}
+	return readAck(conn)
+}
+
+// Receive connects to unix socket at path, on which old process is waiting
+// with [Offer], and returns the payload handed off by it. Caller owns
+// the files of the returned payload.
+//
+// Like [Offer], payload is only accepted from processes running as the
+// same user or as root.
+//
+//   - [syscall.EPERM] is returned if process listening on path is not
+//     running as the same user or as root.
+//   - [syscall.EPROTO] is returned if message is malformed or truncated.
+//   - [syscall.ENOTSUP] is returned on non-unix platforms.
+func Receive(ctx context.Context, path string) (*Payload, error) {
+	if err := supported(); err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	_, p, err := decode(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = writeAck(conn); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// dial connects to unix socket at path, rejecting listeners which are
+// neither running as the same user as the current process, nor as root.
+func dial(ctx context.Context, path string) (*net.UnixConn, error) {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("handoff: failed to connect to %s: %w", path, err)
+	}
+	conn := c.(*net.UnixConn)
+	if err = unixsock.CheckPeer(conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("handoff: %w", err)
+	}
+	setDeadline(ctx, conn)
+	return conn, nil
+}
+
+// encode encodes the payload as a message with the given operation.
+// Data part of the message is the header prefixed with its length as
+// 32-bit big endian integer, and file descriptors are passed as
+// SCM_RIGHTS control message.
+func encode(op string, p *Payload) ([]byte, []byte, error) {
+	names, fds, err := flatten(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hdr, err := json.Marshal(header{Op: op, Names: names, State: p.State})
+	if err != nil {
+		return nil, nil, fmt.Errorf("handoff: failed to encode header: %w", err)
+	}
+	data := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(hdr)), uint32(len(hdr)))
+	return append(data, hdr...), unixRights(fds), nil
+}
+
+// writeMsg writes the message encoded by [encode]. Control message is sent
+// along with the first chunk of data, and the rest is written as is, as
+// stream sockets may accept only part of the data.
+func writeMsg(conn *net.UnixConn, data, oob []byte) error {
+	n, _, err := conn.WriteMsgUnix(data, oob, nil)
+	if err != nil {
+		return err
+	}
+	if n < len(data) {
+		_, err = conn.Write(data[n:])
+	}
+	return err
+}
+
+// decode reads a message encoded by [encode] from the connection,
+// and returns its operation and payload. Data may arrive in multiple
+// chunks, but control message is always received with the first one.
+func decode(conn *net.UnixConn) (string, *Payload, error) {
+	var prefix [4]byte
+	oob := make([]byte, rightsSpace(MaxFiles))
+	n, oobn, flags, _, err := conn.ReadMsgUnix(prefix[:], oob)
+	if err != nil {
+		return "", nil, fmt.Errorf("handoff: failed to receive payload: %w", err)
+	}
+
+	fds, err := parseUnixRights(oob[:oobn])
+	if err != nil {
+		return "", nil, err
+	}
+
+	var h header
+	var data []byte
+	switch {
+	case n == 0:
+		err = fmt.Errorf("handoff: connection closed by peer: %w", syscall.EPROTO)
+	case flags&msgTruncated != 0:
+		err = fmt.Errorf("handoff: control message is truncated: %w", syscall.EPROTO)
+	}
+	if err == nil {
+		data, err = readHeader(conn, prefix[:], n)
+	}
+	switch {
+	case err != nil:
+	case json.Unmarshal(data, &h) != nil:
+		err = fmt.Errorf("handoff: malformed header: %w", syscall.EPROTO)
+	case len(h.Names) != len(fds):
+		err = fmt.Errorf("handoff: expected %d files, got %d: %w", len(h.Names), len(fds), syscall.EPROTO)
+	}
+	if err != nil {
+		closeFDs(fds)
+		return "", nil, err
+	}
+
+	p := &Payload{
+		Files: make(map[string][]*os.File),
+		State: h.State,
+	}
+	for i, fd := range fds {
+		p.Files[h.Names[i]] = append(p.Files[h.Names[i]],
+			os.NewFile(uintptr(fd), "handoff:"+h.Names[i]))
+	}
+	return h.Op, p, nil
+}
+
+// readHeader reads rest of the length prefix, of which n bytes are already
+// read, and the header following it.
+func readHeader(conn net.Conn, prefix []byte, n int) ([]byte, error) {
+	if _, err := io.ReadFull(conn, prefix[n:]); err != nil {
+		return nil, fmt.Errorf("handoff: payload is truncated: %w", syscall.EPROTO)
+	}
+	size := binary.BigEndian.Uint32(prefix)
+	if size > maxMessageSize {
+		return nil, fmt.Errorf("handoff: header exceeds %d bytes: %w", maxMessageSize, syscall.EPROTO)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return nil, fmt.Errorf("handoff: payload is truncated: %w", syscall.EPROTO)
+	}
+	return data, nil
+}
+
+// writeAck acknowledges a received message.
+func writeAck(conn net.Conn) error {
+	if _, err := conn.Write([]byte(ack)); err != nil {
+		return fmt.Errorf("handoff: failed to send acknowledgement: %w", err)
+	}
+	return nil
+}
+
+// readAck waits for acknowledgement of a sent message.
+func readAck(conn net.Conn) error {
+	buf := make([]byte, len(ack))
+	n, err := io.ReadFull(conn, buf)
+	if err != nil {
+		return fmt.Errorf("handoff: failed to read acknowledgement: %w", err)
+	}
+	if string(buf[:n]) != ack {
+		return fmt.Errorf("handoff: invalid acknowledgement: %w", syscall.EPROTO)
+	}
+	return nil
+}
+
+// flatten returns name and file descriptor of each file in the payload,
+// in deterministic order. File descriptors are obtained without
+// changing blocking mode of the files.
+func flatten(p *Payload) ([]string, []int, error) {
+	if p == nil {
+		return nil, nil, fmt.Errorf("handoff: payload is nil: %w", syscall.EINVAL)
+	}
+	if len(p.State) > MaxStateSize {
+		return nil, nil, fmt.Errorf("handoff: state exceeds %d bytes: %w", MaxStateSize, syscall.EINVAL)
+	}
+
+	keys := make([]string, 0, len(p.Files))
+	for name := range p.Files {
+		keys = append(keys, name)
+	}
+	slices.Sort(keys)
+
+	var names []string
+	var fds []int
+	for _, name := range keys {
+		for _, f := range p.Files[name] {
+			rc, err := f.SyscallConn()
+			if err != nil {
+				return nil, nil, fmt.Errorf("handoff: socket(%s): %w", name, err)
+			}
+			err = rc.Control(func(fd uintptr) {
+				fds = append(fds, int(fd))
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("handoff: socket(%s): %w", name, err)
+			}
+			names = append(names, name)
+		}
+	}
+
+	if len(fds) > MaxFiles {
+		return nil, nil, fmt.Errorf("handoff: more than %d files: %w", MaxFiles, syscall.EINVAL)
+	}
+	return names, fds, nil
+}
+
+// setDeadline sets deadline of the connection to that of ctx, if any.
+func setDeadline(ctx context.Context, conn net.Conn) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Time{})
+	}
+}
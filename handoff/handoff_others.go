@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package handoff
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const msgTruncated = 0
+
+func supported() error {
+	return fmt.Errorf("handoff: only supported on unix: %w", syscall.ENOTSUP)
+}
+
+func rightsSpace(_ int) int {
+	return 0
+}
+
+func unixRights(_ []int) []byte {
+	return nil
+}
+
+func parseUnixRights(_ []byte) ([]int, error) {
+	return nil, supported()
+}
+
+func closeFDs(_ []int) {}
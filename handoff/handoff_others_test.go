@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package handoff_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/handoff"
+)
+
+func TestUnsupported(t *testing.T) {
+	ctx := context.Background()
+	err := handoff.Offer(ctx, "handoff.sock", &handoff.Payload{})
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+	_, err = handoff.Receive(ctx, "handoff.sock")
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+	err = handoff.Hold(ctx, "holder.sock")
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+	err = handoff.Store(ctx, "holder.sock", &handoff.Payload{})
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+	_, err = handoff.Fetch(ctx, "holder.sock")
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
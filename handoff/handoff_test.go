@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package handoff_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/handoff"
+)
+
+func TestPayload_Listeners_NotFound(t *testing.T) {
+	p := &handoff.Payload{Files: map[string][]*os.File{}}
+	_, err := p.Listeners("Listeners")
+	if !errors.Is(err, syscall.ENOENT) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOENT, err)
+	}
+	_, err = p.PacketConns("Listeners")
+	if !errors.Is(err, syscall.ENOENT) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOENT, err)
+	}
+}
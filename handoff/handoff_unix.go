@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package handoff
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// msgTruncated is set in flags of received message if data
+// or control message was truncated.
+const msgTruncated = syscall.MSG_TRUNC | syscall.MSG_CTRUNC
+
+// supported returns nil, as handoff is supported on all unix platforms.
+func supported() error {
+	return nil
+}
+
+// rightsSpace returns size of control message buffer required
+// to receive n file descriptors.
+func rightsSpace(n int) int {
+	return syscall.CmsgSpace(n * 4)
+}
+
+// unixRights encodes file descriptors as SCM_RIGHTS control message.
+func unixRights(fds []int) []byte {
+	if len(fds) == 0 {
+		return nil
+	}
+	return syscall.UnixRights(fds...)
+}
+
+// parseUnixRights returns file descriptors received via SCM_RIGHTS.
+// Received file descriptors are closed in case of error.
+func parseUnixRights(oob []byte) ([]int, error) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, fmt.Errorf("handoff: malformed control message: %w", syscall.EPROTO)
+	}
+
+	var fds []int
+	for i := range msgs {
+		if msgs[i].Header.Level != syscall.SOL_SOCKET || msgs[i].Header.Type != syscall.SCM_RIGHTS {
+			continue
+		}
+		v, err := syscall.ParseUnixRights(&msgs[i])
+		if err != nil {
+			closeFDs(fds)
+			return nil, fmt.Errorf("handoff: malformed control message: %w", syscall.EPROTO)
+		}
+		fds = append(fds, v...)
+	}
+	return fds, nil
+}
+
+// closeFDs closes file descriptors.
+func closeFDs(fds []int) {
+	for _, fd := range fds {
+		_ = syscall.Close(fd)
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package handoff_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/handoff"
+)
+
+func TestOfferReceive(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("failed to get file: %s", err)
+	}
+	defer f.Close()
+
+	// Path of unix sockets is limited to 104 bytes on macOS.
+	dir, err := os.MkdirTemp("", "handoff")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "handoff.sock")
+
+	offered := make(chan error, 1)
+	go func() {
+		offered <- handoff.Offer(ctx, path, &handoff.Payload{
+			Files: map[string][]*os.File{"Listeners": {f}},
+			State: []byte("generation=1"),
+		})
+	}()
+
+	var p *handoff.Payload
+	for {
+		p, err = handoff.Receive(ctx, path)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("failed to receive: %s", err)
+	}
+	defer p.Close()
+
+	if err = <-offered; err != nil {
+		t.Fatalf("failed to offer: %s", err)
+	}
+
+	if string(p.State) != "generation=1" {
+		t.Errorf("expected state=generation=1, got=%s", p.State)
+	}
+
+	listeners, err := p.Listeners("Listeners")
+	if err != nil || len(listeners) != 1 {
+		t.Fatalf("expected 1 listener, got=%d, err=%s", len(listeners), err)
+	}
+	defer listeners[0].Close()
+
+	if listeners[0].Addr().String() != l.Addr().String() {
+		t.Errorf("expected addr=%s, got=%s", l.Addr(), listeners[0].Addr())
+	}
+
+	go func() {
+		c, err := net.Dial("tcp", l.Addr().String())
+		if err == nil {
+			_, _ = c.Write([]byte("ping"))
+			c.Close()
+		}
+	}()
+
+	c, err := listeners[0].Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+	defer c.Close()
+
+	b, err := io.ReadAll(c)
+	if err != nil || string(b) != "ping" {
+		t.Errorf("expected=ping, got=%s, err=%s", b, err)
+	}
+
+	if _, err = os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected socket to be removed, got=%s", err)
+	}
+}
+
+func TestOffer_Cancelled(t *testing.T) {
+	dir, err := os.MkdirTemp("", "handoff")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = handoff.Offer(ctx, filepath.Join(dir, "handoff.sock"), &handoff.Payload{})
+	if err == nil {
+		t.Errorf("expected error when no process connects")
+	}
+}
+
+func TestOfferReceive_LargeState(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "handoff")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "handoff.sock")
+
+	// Larger than default buffer size of unix stream sockets on macOS.
+	state := bytes.Repeat([]byte("0123456789abcdef"), handoff.MaxStateSize/16)
+
+	offered := make(chan error, 1)
+	go func() {
+		offered <- handoff.Offer(ctx, path, &handoff.Payload{State: state})
+	}()
+
+	var p *handoff.Payload
+	for {
+		if fi, sErr := os.Stat(path); sErr == nil && fi.Mode().Perm() != 0o600 {
+			t.Errorf("expected socket mode=0600, got=%s", fi.Mode().Perm())
+		}
+		p, err = handoff.Receive(ctx, path)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("failed to receive: %s", err)
+	}
+	defer p.Close()
+
+	if err = <-offered; err != nil {
+		t.Fatalf("failed to offer: %s", err)
+	}
+	if !bytes.Equal(p.State, state) {
+		t.Errorf("expected state of %d bytes, got %d bytes", len(state), len(p.State))
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected temporary files to be removed, got=%v", entries)
+	}
+}
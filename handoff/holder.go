@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package handoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/internal/unixsock"
+)
+
+// HolderSocketEnv is the path of the socket of the fd holder.
+// This is set by plist.FDHolder for both the job and its holder.
+const HolderSocketEnv = "LAUNCHD_FD_HOLDER_SOCKET"
+
+// holderTimeout is the time budget for serving a single request,
+// so that a stuck client cannot block the holder.
+const holderTimeout = 10 * time.Second
+
+// Operations of the holder protocol.
+const (
+	opStore = "store"
+	opFetch = "fetch"
+	opEmpty = "empty"
+)
+
+// HolderSocket returns the path of the socket of the fd holder,
+// if the job is configured to use one.
+func HolderSocket() (string, bool) {
+	path := os.Getenv(HolderSocketEnv)
+	return path, path != ""
+}
+
+// Hold runs an fd holder on unix socket at path, until ctx is cancelled.
+//
+// Holder retains duplicates of the files sent to it with [Store] and
+// returns them to [Fetch], so that a daemon which crashes can re-acquire
+// its listeners on restart without losing connections waiting in the
+// kernel accept queue. This emulates the fd store of systemd, and is
+// typically run by a separate job generated by plist.FDHolder.
+//
+// Stale socket at path, if any, is removed. Socket is only accessible
+// by the owner and is removed when Hold returns. Connections from processes
+// running as a different user, other than root, are rejected.
+//
+//   - [syscall.ENOTSUP] is returned on non-unix platforms.
+func Hold(ctx context.Context, path string) error {
+	if err := supported(); err != nil {
+		return err
+	}
+
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(path)
+	}
+
+	l, err := unixsock.Listen(path)
+	if err != nil {
+		return fmt.Errorf("handoff: %w", err)
+	}
+	defer l.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = l.Close()
+	})
+	defer stop()
+
+	held := &Payload{}
+	defer held.Close()
+
+	for {
+		conn, err := l.AcceptUnix()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return fmt.Errorf("handoff: failed to accept: %w", err)
+			}
+			continue
+		}
+
+		// Errors are specific to the client and are not fatal.
+		_ = serveHolder(conn, &held)
+		_ = conn.Close()
+	}
+}
+
+// serveHolder serves a single request to the holder.
+func serveHolder(conn *net.UnixConn, held **Payload) error {
+	_ = conn.SetDeadline(time.Now().Add(holderTimeout))
+
+	if err := unixsock.CheckPeer(conn); err != nil {
+		return fmt.Errorf("handoff: %w", err)
+	}
+
+	op, p, err := decode(conn)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case opStore:
+		_ = (*held).Close()
+		*held = p
+		return writeAck(conn)
+	case opFetch:
+		_ = p.Close()
+		reply := opFetch
+		if len((*held).Files) == 0 && len((*held).State) == 0 {
+			reply = opEmpty
+		}
+		data, oob, err := encode(reply, *held)
+		if err != nil {
+			return err
+		}
+		if err = writeMsg(conn, data, oob); err != nil {
+			return fmt.Errorf("handoff: failed to send payload: %w", err)
+		}
+		return nil
+	default:
+		_ = p.Close()
+		return fmt.Errorf("handoff: unknown operation(%s): %w", op, syscall.EPROTO)
+	}
+}
+
+// Store sends the payload to the fd holder listening on unix socket at
+// path, replacing files previously stored in it. Files of the payload
+// are not closed and remain usable by the caller.
+//
+//   - [syscall.EPERM] is returned if holder is not running as the same
+//     user or as root.
+//   - [syscall.EINVAL] is returned if payload exceeds [MaxFiles]
+//     or [MaxStateSize].
+//   - [syscall.ENOTSUP] is returned on non-unix platforms.
+func Store(ctx context.Context, path string, p *Payload) error {
+	if err := supported(); err != nil {
+		return err
+	}
+
+	data, oob, err := encode(opStore, p)
+	if err != nil {
+		return err
+	}
+
+	conn, err := dial(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err = writeMsg(conn, data, oob); err != nil {
+		return fmt.Errorf("handoff: failed to send payload: %w", err)
+	}
+	return readAck(conn)
+}
+
+// Fetch returns duplicates of the files stored in the fd holder listening
+// on unix socket at path. Caller owns the files of the returned payload.
+// Files remain stored in the holder.
+//
+//   - [syscall.ENOENT] is returned if nothing is stored in the holder,
+//     or if socket does not exist, i.e. holder is not running.
+//   - [syscall.EPERM] is returned if holder is not running as the same
+//     user or as root.
+//   - [syscall.EPROTO] is returned if message is malformed or truncated.
+//   - [syscall.ENOTSUP] is returned on non-unix platforms.
+func Fetch(ctx context.Context, path string) (*Payload, error) {
+	if err := supported(); err != nil {
+		return nil, err
+	}
+
+	data, oob, err := encode(opFetch, &Payload{})
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dial(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = writeMsg(conn, data, oob); err != nil {
+		return nil, fmt.Errorf("handoff: failed to send request: %w", err)
+	}
+
+	op, p, err := decode(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case opFetch:
+		return p, nil
+	case opEmpty:
+		_ = p.Close()
+		return nil, fmt.Errorf("handoff: nothing stored in holder: %w", syscall.ENOENT)
+	default:
+		_ = p.Close()
+		return nil, fmt.Errorf("handoff: unexpected reply(%s): %w", op, syscall.EPROTO)
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package handoff_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/handoff"
+)
+
+func TestHold(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "handoff")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "holder.sock")
+
+	holdCtx, stop := context.WithCancel(ctx)
+	held := make(chan error, 1)
+	go func() {
+		held <- handoff.Hold(holdCtx, path)
+	}()
+
+	// Wait for the holder to start listening.
+	for {
+		if _, err = os.Stat(path); err == nil || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, err = handoff.Fetch(ctx, path)
+	if !errors.Is(err, syscall.ENOENT) {
+		t.Fatalf("expected error=%s, got=%s", syscall.ENOENT, err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	f, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("failed to get file: %s", err)
+	}
+
+	err = handoff.Store(ctx, path, &handoff.Payload{
+		Files: map[string][]*os.File{"Listeners": {f}},
+	})
+	if err != nil {
+		t.Fatalf("failed to store: %s", err)
+	}
+
+	// Simulate a crash of the daemon.
+	addr := l.Addr().String()
+	f.Close()
+	l.Close()
+
+	for i := 0; i < 2; i++ {
+		p, err := handoff.Fetch(ctx, path)
+		if err != nil {
+			t.Fatalf("failed to fetch: %s", err)
+		}
+		listeners, err := p.Listeners("Listeners")
+		if err != nil || len(listeners) != 1 {
+			t.Fatalf("expected 1 listener, got=%d, err=%s", len(listeners), err)
+		}
+		if listeners[0].Addr().String() != addr {
+			t.Errorf("expected addr=%s, got=%s", addr, listeners[0].Addr())
+		}
+		listeners[0].Close()
+		p.Close()
+	}
+
+	stop()
+	if err = <-held; err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// BrewService returns the name of the Homebrew formula, if the current
+// process was started by launchd as a job managed by "brew services".
+//
+// launchd sets XPC_SERVICE_NAME environment variable to the label of
+// the job, which "brew services" derives from the formula name.
+// See [plist.Homebrew].
+func BrewService() (formula string, ok bool) {
+	formula, ok = strings.CutPrefix(os.Getenv("XPC_SERVICE_NAME"), plist.HomebrewLabelPrefix)
+	if !ok || formula == "" {
+		return "", false
+	}
+	return formula, true
+}
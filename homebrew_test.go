@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+func TestBrewService(t *testing.T) {
+	tt := []struct {
+		name    string
+		env     string
+		formula string
+		ok      bool
+	}{
+		{name: "homebrew", env: "homebrew.mxcl.svc", formula: "svc", ok: true},
+		{name: "other-job", env: "com.example.svc"},
+		{name: "prefix-only", env: "homebrew.mxcl."},
+		{name: "terminal", env: "0"},
+		{name: "empty"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("XPC_SERVICE_NAME", tc.env)
+			formula, ok := launchd.BrewService()
+			if ok != tc.ok {
+				t.Errorf("expected ok=%t, got=%t", tc.ok, ok)
+			}
+			if formula != tc.formula {
+				t.Errorf("expected formula=%s, got=%s", tc.formula, formula)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+)
+
+// InetdConn returns a [net.Conn] for the connection passed via stdin
+// and stdout to jobs with inetdCompatibility Wait set to false.
+//
+// In this mode, launchd accepts the connection on behalf of the job
+// and spawns a new instance of the job for each connection.
+//
+//   - [syscall.ENOTSOCK] is returned if stdin is not a socket.
+//   - [syscall.EINVAL] is returned if stdin is a listening socket.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// Closing returned connection does not close [os.Stdin] or [os.Stdout].
+// However, it is recommended to not read or write to them directly.
+func InetdConn() (net.Conn, error) {
+	return inetdConn()
+}
+
+// InetdListener returns a [net.Listener] for the listening socket passed
+// via stdin to jobs with inetdCompatibility Wait set to true.
+//
+//   - [syscall.ENOTSOCK] is returned if stdin is not a socket.
+//   - [syscall.EINVAL] is returned if stdin is not a listening socket.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func InetdListener() (net.Listener, error) {
+	return inetdListener()
+}
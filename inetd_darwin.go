@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// inetdSocket returns a duplicate of stdin, if it is a stream socket
+// whose listening state matches the given value.
+func inetdSocket(listening bool) (*os.File, error) {
+	fd := int(os.Stdin.Fd())
+	stype, err := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_TYPE)
+	if err != nil {
+		if errors.Is(err, syscall.ENOTSOCK) {
+			return nil, fmt.Errorf("launchd: stdin is not a socket: %w", syscall.ENOTSOCK)
+		}
+		return nil, fmt.Errorf("launchd: %w", os.NewSyscallError("getsockopt", err))
+	}
+
+	if stype != syscall.SOCK_STREAM {
+		return nil, fmt.Errorf("launchd: stdin is not a stream socket: %w", syscall.ESOCKTNOSUPPORT)
+	}
+
+	accept, err := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_ACCEPTCONN)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: %w", os.NewSyscallError("getsockopt", err))
+	}
+
+	if (accept != 0) != listening {
+		if listening {
+			return nil, fmt.Errorf("launchd: stdin is not a listening socket: %w", syscall.EINVAL)
+		}
+		return nil, fmt.Errorf("launchd: stdin is a listening socket: %w", syscall.EINVAL)
+	}
+
+	return os.Stdin, nil
+}
+
+// Os specific implementation of [InetdConn].
+func inetdConn() (net.Conn, error) {
+	file, err := inetdSocket(false)
+	if err != nil {
+		return nil, err
+	}
+
+	// net.FileConn duplicates the file descriptor.
+	conn, err := net.FileConn(file)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: error building connection: %w", err)
+	}
+	return conn, nil
+}
+
+// Os specific implementation of [InetdListener].
+func inetdListener() (net.Listener, error) {
+	file, err := inetdSocket(true)
+	if err != nil {
+		return nil, err
+	}
+
+	// net.FileListener duplicates the file descriptor.
+	l, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: error building listener: %w", err)
+	}
+	return l, nil
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Os specific implementation of [InetdConn].
+func inetdConn() (net.Conn, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [InetdListener].
+func inetdListener() (net.Listener, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
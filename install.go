@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// InstallOption configures [Install] and [Uninstall].
+type InstallOption func(*installOptions)
+
+type installOptions struct {
+	domain    launchctl.Domain
+	restart   bool
+	escalator Escalator
+}
+
+// WithDomain sets the domain to install the job into.
+//
+// If not specified, [launchctl.CurrentDomain] is used, i.e. jobs are
+// installed as LaunchDaemons when running as root and as LaunchAgents
+// of the current user otherwise.
+func WithDomain(domain launchctl.Domain) InstallOption {
+	return func(o *installOptions) {
+		o.domain = domain
+	}
+}
+
+// WithRestart restarts the job with [Upgrade], even if its plist file
+// is unchanged. This is useful for auto-updaters which replace binary
+// of the job, but not its plist file.
+func WithRestart() InstallOption {
+	return func(o *installOptions) {
+		o.restart = true
+	}
+}
+
+func newInstallOptions(opts []InstallOption) installOptions {
+	var o installOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// Install installs and loads the job.
+//
+// Job is rendered to /Library/LaunchDaemons for system domain and to
+// ~/Library/LaunchAgents for per-user domains, with appropriate
+// ownership and permissions. If a job with the same label is already
+// loaded, it is unloaded first. Job is then bootstrapped into the domain
+// and verified to be loaded. Only whether the job is loaded is checked,
+// not whether it is running, as jobs which are started on demand, for
+// example by socket activation, do not run until they are activated.
+//
+//   - [*RestrictedError] is returned if loading the job fails due to
+//     System Integrity Protection or App Sandbox, or if calling process
+//     is sandboxed, in which case plist file is not written.
+//   - [*launchctl.Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned if domain is not supported.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Install(ctx context.Context, job plist.Job, opts ...InstallOption) error {
+	return install(ctx, job, newInstallOptions(opts))
+}
+
+// Uninstall unloads the job with the given label and removes its plist file.
+//
+// It is not an error if the job is not loaded or its plist file does
+// not exist.
+//
+//   - [*launchctl.Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned if label is invalid, for example if it
+//     contains path separators, or if domain is not supported.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Uninstall(ctx context.Context, label string, opts ...InstallOption) error {
+	if err := launchctl.ValidateLabel(label); err != nil {
+		return err
+	}
+	return uninstall(ctx, label, newInstallOptions(opts))
+}
+
+// Upgrade installs the job if its plist file differs from the installed one.
+//
+// If plist file is unchanged and job is loaded, job is left as is, unless
+// [WithRestart] is specified, in which case it is restarted. If plist
+// file has changed, job is unloaded, plist file is updated and job is
+// loaded again. If job is not installed, this is same as [Install].
+// Thus, it is safe to call this on every start of the installer or
+// auto-updater.
+//
+// Returned boolean reports whether plist file was changed.
+//
+//   - [*RestrictedError] is returned if loading the job fails due to
+//     System Integrity Protection or App Sandbox, or if calling process
+//     is sandboxed, in which case plist file is not written.
+//   - [*launchctl.Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned if domain is not supported.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Upgrade(ctx context.Context, job plist.Job, opts ...InstallOption) (bool, error) {
+	return upgrade(ctx, job, newInstallOptions(opts))
+}
@@ -0,0 +1,477 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// resolveDomain returns the domain specified in options or
+// the domain appropriate for the current process.
+func (o *installOptions) resolveDomain(ctx context.Context) (launchctl.Domain, error) {
+	if o.domain != "" {
+		return o.domain, nil
+	}
+	domain, err := launchctl.CurrentDomain(ctx)
+	if err != nil {
+		return "", fmt.Errorf("launchd: failed to get current domain: %w", err)
+	}
+	return domain, nil
+}
+
+// domainOwner returns the uid and gid which should own plist files
+// of the domain, and home directory of the user for per-user domains.
+//
+// For per-user domains of users other than the current user, for example
+// when installing agents for other users from a root context, user
+// database is used to resolve home directory and primary group.
+func domainOwner(domain launchctl.Domain) (uid, gid int, home string, err error) {
+	if domain == launchctl.System {
+		return 0, 0, "", nil
+	}
+
+	uid, ok := domain.UID()
+	if !ok {
+		return 0, 0, "", fmt.Errorf("launchd: unsupported domain(%s): %w", domain, syscall.EINVAL)
+	}
+
+	if uid == os.Getuid() {
+		home, err = os.UserHomeDir()
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("launchd: failed to get home directory: %w", err)
+		}
+		return uid, os.Getgid(), home, nil
+	}
+
+	u, err := user.LookupId(strconv.Itoa(uid))
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("launchd: failed to lookup user(%d): %w", uid, err)
+	}
+
+	gid, err = strconv.Atoi(u.Gid)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("launchd: invalid gid(%s) for user(%d): %w", u.Gid, uid, err)
+	}
+
+	if u.HomeDir == "" {
+		return 0, 0, "", fmt.Errorf("launchd: user(%d) has no home directory: %w", uid, syscall.ENOENT)
+	}
+	return uid, gid, u.HomeDir, nil
+}
+
+// plistDir returns the directory where plist files for the domain are stored.
+func plistDir(domain launchctl.Domain) (string, error) {
+	if domain == launchctl.System {
+		return "/Library/LaunchDaemons", nil
+	}
+
+	_, _, home, err := domainOwner(domain)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents"), nil
+}
+
+// writePlist atomically writes the plist file with appropriate
+// ownership and permissions.
+func writePlist(path string, data []byte, domain launchctl.Domain) error {
+	uid, gid, _, err := domainOwner(domain)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if _, err = os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		if err = os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("launchd: failed to create directory: %w", err)
+		}
+
+		// LaunchAgents directory of other users must be owned by them.
+		if os.Getuid() == 0 {
+			if err = os.Chown(dir, uid, gid); err != nil {
+				return fmt.Errorf("launchd: failed to set directory owner: %w", err)
+			}
+		}
+	}
+
+	f, err := os.CreateTemp(dir, fmt.Sprintf(".%s.*", filepath.Base(path)))
+	if err != nil {
+		return fmt.Errorf("launchd: failed to create plist file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err = f.Write(data); err != nil {
+		return fmt.Errorf("launchd: failed to write plist file: %w", err)
+	}
+
+	// launchd refuses to load plist files which are writable by
+	// group or others, or LaunchDaemons not owned by root.
+	if err = f.Chmod(0o644); err != nil {
+		return fmt.Errorf("launchd: failed to set plist file mode: %w", err)
+	}
+
+	if os.Getuid() == 0 {
+		if err = f.Chown(uid, gid); err != nil {
+			return fmt.Errorf("launchd: failed to set plist file owner: %w", err)
+		}
+	}
+
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("launchd: failed to sync plist file: %w", err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("launchd: failed to close plist file: %w", err)
+	}
+
+	if err = os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("launchd: failed to rename plist file: %w", err)
+	}
+	return nil
+}
+
+// bootout unloads the job if it is loaded.
+func bootout(ctx context.Context, domain launchctl.Domain, label string) error {
+	loaded, err := launchctl.Loaded(ctx, domain, label)
+	if err != nil {
+		return fmt.Errorf("launchd: failed to get job status: %w", err)
+	}
+
+	if loaded {
+		if err = launchctl.Bootout(ctx, domain, label); err != nil {
+			return fmt.Errorf("launchd: failed to unload job: %w", err)
+		}
+	}
+	return nil
+}
+
+// verify checks that the job is loaded. It does not check whether the
+// job is running, as jobs started on demand may not run until activated.
+func verify(ctx context.Context, domain launchctl.Domain, label string) error {
+	loaded, err := launchctl.Loaded(ctx, domain, label)
+	if err != nil {
+		return fmt.Errorf("launchd: failed to get job status: %w", err)
+	}
+
+	if !loaded {
+		return fmt.Errorf("launchd: job(%s) is not loaded after bootstrap: %w",
+			label, syscall.ESRCH)
+	}
+	return nil
+}
+
+// installer performs privileged steps of installing a job, either
+// directly or via an [Escalator].
+type installer struct {
+	domain    launchctl.Domain
+	escalator Escalator
+}
+
+// newInstaller returns an installer for the domain. Escalator is only
+// used if the domain requires privileges of the calling process.
+func newInstaller(domain launchctl.Domain, opts installOptions) installer {
+	i := installer{domain: domain}
+	if needsEscalation(domain, os.Getuid()) {
+		i.escalator = opts.escalator
+	}
+	return i
+}
+
+// needsEscalation reports whether installing jobs in the domain requires
+// root, i.e. if uid is not root and domain is not a per-user domain of
+// the uid. Jobs can be installed in caller's own domains without root.
+func needsEscalation(domain launchctl.Domain, uid int) bool {
+	if uid == 0 {
+		return false
+	}
+	duid, ok := domain.UID()
+	return !ok || duid != uid
+}
+
+// escalate runs the shell script via escalator.
+func (i installer) escalate(ctx context.Context, script string) error {
+	err := i.escalator.Escalate(ctx, []string{"/bin/sh", "-c", "set -e\n" + script})
+	if err != nil {
+		return fmt.Errorf("launchd: privileged operation failed: %w", err)
+	}
+	return nil
+}
+
+// install unloads the job if loaded, writes the plist file and loads the job.
+func (i installer) install(ctx context.Context, label, path string, data []byte) error {
+	if i.escalator == nil {
+		if err := bootout(ctx, i.domain, label); err != nil {
+			return err
+		}
+		if err := writePlist(path, data, i.domain); err != nil {
+			return err
+		}
+		return i.bootstrap(ctx, label, path)
+	}
+
+	// Render plist to a temporary file, which is then installed
+	// with appropriate ownership and permissions.
+	tmp, err := os.CreateTemp("", fmt.Sprintf("%s.*.plist", label))
+	if err != nil {
+		return fmt.Errorf("launchd: failed to create temporary plist file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err = tmp.Write(data); err != nil {
+		return fmt.Errorf("launchd: failed to write temporary plist file: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("launchd: failed to close temporary plist file: %w", err)
+	}
+
+	uid, gid, _, err := domainOwner(i.domain)
+	if err != nil {
+		return err
+	}
+
+	script := strings.Join([]string{
+		shellJoin([]string{"/bin/launchctl", "bootout", i.domain.Service(label)}) + " 2>/dev/null || true",
+		shellJoin([]string{"/bin/mkdir", "-p", filepath.Dir(path)}),
+		shellJoin([]string{
+			"/usr/bin/install", "-m", "0644",
+			"-o", strconv.Itoa(uid), "-g", strconv.Itoa(gid),
+			tmp.Name(), path,
+		}),
+		shellJoin([]string{"/bin/launchctl", "bootstrap", i.domain.String(), path}),
+	}, "\n")
+
+	if err = i.escalate(ctx, script); err != nil {
+		return err
+	}
+	return verify(ctx, i.domain, label)
+}
+
+// bootstrap loads the job from plist file and verifies that it is loaded.
+func (i installer) bootstrap(ctx context.Context, label, path string) error {
+	if i.escalator == nil {
+		if err := launchctl.Bootstrap(ctx, i.domain, path); err != nil {
+			return fmt.Errorf("launchd: failed to load job: %w", err)
+		}
+	} else {
+		script := shellJoin([]string{"/bin/launchctl", "bootstrap", i.domain.String(), path})
+		if err := i.escalate(ctx, script); err != nil {
+			return err
+		}
+	}
+	return verify(ctx, i.domain, label)
+}
+
+// restart kills the running instance of the job and starts it again.
+func (i installer) restart(ctx context.Context, label string) error {
+	if i.escalator == nil {
+		err := launchctl.Kickstart(ctx, i.domain, label, launchctl.KickstartOptions{Kill: true})
+		if err != nil {
+			return fmt.Errorf("launchd: failed to restart job: %w", err)
+		}
+		return nil
+	}
+	return i.escalate(ctx, shellJoin([]string{"/bin/launchctl", "kickstart", "-k", i.domain.Service(label)}))
+}
+
+// remove unloads the job if loaded and removes its plist file.
+func (i installer) remove(ctx context.Context, label, path string) error {
+	if i.escalator == nil {
+		if err := bootout(ctx, i.domain, label); err != nil {
+			return err
+		}
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("launchd: failed to remove plist file: %w", err)
+		}
+		return nil
+	}
+
+	script := strings.Join([]string{
+		shellJoin([]string{"/bin/launchctl", "bootout", i.domain.Service(label)}) + " 2>/dev/null || true",
+		shellJoin([]string{"/bin/rm", "-f", path}),
+	}, "\n")
+	return i.escalate(ctx, script)
+}
+
+// prepare validates and renders the job and returns its domain
+// and plist file path.
+func prepare(ctx context.Context, job plist.Job, opts installOptions) (launchctl.Domain, string, []byte, error) {
+	if err := job.Validate(); err != nil {
+		return "", "", nil, fmt.Errorf("launchd: invalid job: %w", err)
+	}
+
+	domain, err := opts.resolveDomain(ctx)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	dir, err := plistDir(domain)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	path := filepath.Join(dir, job.Label+".plist")
+	if isSandboxed() {
+		return "", "", nil, &RestrictedError{
+			Restriction: RestrictionSandbox,
+			Path:        path,
+			Hint:        sandboxHint,
+			Err:         syscall.EPERM,
+		}
+	}
+
+	data, err := plist.Marshal(job)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("launchd: failed to render plist: %w", err)
+	}
+	return domain, path, data, nil
+}
+
+// Os specific implementation of [Install].
+func install(ctx context.Context, job plist.Job, opts installOptions) error {
+	domain, path, data, err := prepare(ctx, job, opts)
+	if err != nil {
+		return err
+	}
+	err = newInstaller(domain, opts).install(ctx, job.Label, path, data)
+	return diagnoseRestriction(ctx, err, append(jobPaths(job), path)...)
+}
+
+// jobPaths returns paths used by the job, which are checked
+// when diagnosing bootstrap failures.
+func jobPaths(job plist.Job) []string {
+	var paths []string
+	if job.Program != "" {
+		paths = append(paths, job.Program)
+	} else if len(job.ProgramArguments) > 0 {
+		paths = append(paths, job.ProgramArguments[0])
+	}
+	if job.WorkingDirectory != "" {
+		paths = append(paths, job.WorkingDirectory)
+	}
+	return paths
+}
+
+// Os specific implementation of [Upgrade].
+func upgrade(ctx context.Context, job plist.Job, opts installOptions) (bool, error) {
+	changed, err := upgradeJob(ctx, job, opts)
+	return changed, diagnoseRestriction(ctx, err, jobPaths(job)...)
+}
+
+// upgradeJob upgrades the job if required.
+func upgradeJob(ctx context.Context, job plist.Job, opts installOptions) (bool, error) {
+	domain, path, data, err := prepare(ctx, job, opts)
+	if err != nil {
+		return false, err
+	}
+
+	i := newInstaller(domain, opts)
+	installed, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return true, i.install(ctx, job.Label, path, data)
+		}
+		return false, fmt.Errorf("launchd: failed to read plist file: %w", err)
+	}
+
+	// Installed plist file may be invalid or may have been modified
+	// manually. Any error decoding it is treated as a change.
+	var current any
+	changed := true
+	if plist.Unmarshal(installed, &current) == nil {
+		changes, diffErr := plist.Diff(current, job)
+		changed = diffErr != nil || len(changes) > 0
+	}
+
+	if changed {
+		return true, i.install(ctx, job.Label, path, data)
+	}
+
+	loaded, err := launchctl.Loaded(ctx, domain, job.Label)
+	if err != nil {
+		return false, fmt.Errorf("launchd: failed to get job status: %w", err)
+	}
+
+	if !loaded {
+		// Re-install to fix ownership and permissions of plist file,
+		// which might be the reason job is not loaded.
+		return false, i.install(ctx, job.Label, path, data)
+	}
+
+	if opts.restart {
+		return false, i.restart(ctx, job.Label)
+	}
+	return false, nil
+}
+
+// Os specific implementation of [Uninstall].
+func uninstall(ctx context.Context, label string, opts installOptions) error {
+	domain, err := opts.resolveDomain(ctx)
+	if err != nil {
+		return err
+	}
+
+	dir, err := plistDir(domain)
+	if err != nil {
+		return err
+	}
+	return newInstaller(domain, opts).remove(ctx, label, filepath.Join(dir, label+".plist"))
+}
+
+// Os specific implementation of [RemoveOrphans].
+func removeOrphans(ctx context.Context, orphans []Orphan, opts installOptions) error {
+	var agents launchctl.Domain
+	var err error
+	for _, o := range orphans {
+		domain := launchctl.System
+		if !o.Daemon {
+			if agents == "" {
+				d, dErr := opts.resolveDomain(ctx)
+				if dErr != nil {
+					return errors.Join(err, dErr)
+				}
+				agents = d
+			}
+			domain = agents
+		}
+
+		if vErr := validateOrphan(o); vErr != nil {
+			err = errors.Join(err, vErr)
+			continue
+		}
+		if rErr := newInstaller(domain, opts).remove(ctx, o.Label, o.Path); rErr != nil {
+			err = errors.Join(err, fmt.Errorf("launchd: failed to remove orphan(%s): %w", o.Label, rErr))
+		}
+	}
+	return err
+}
+
+// validateOrphan checks that orphan has a valid label and its path is an
+// absolute path of a plist file, so that removing it does not remove
+// arbitrary files, possibly with root privileges.
+func validateOrphan(o Orphan) error {
+	if err := launchctl.ValidateLabel(o.Label); err != nil {
+		return err
+	}
+	if !filepath.IsAbs(o.Path) || filepath.Clean(o.Path) != o.Path || filepath.Ext(o.Path) != ".plist" {
+		return fmt.Errorf("launchd: invalid plist path(%q) of orphan(%s): %w", o.Path, o.Label, syscall.EINVAL)
+	}
+	return nil
+}
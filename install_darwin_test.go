@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+func TestNeedsEscalation(t *testing.T) {
+	tt := []struct {
+		name   string
+		domain launchctl.Domain
+		uid    int
+		expect bool
+	}{
+		{name: "root-system", domain: launchctl.System, uid: 0},
+		{name: "root-gui", domain: launchctl.GUI(501), uid: 0},
+		{name: "system", domain: launchctl.System, uid: 501, expect: true},
+		{name: "own-gui", domain: launchctl.GUI(501), uid: 501},
+		{name: "own-user", domain: launchctl.User(501), uid: 501},
+		{name: "other-gui", domain: launchctl.GUI(502), uid: 501, expect: true},
+		{name: "other-user", domain: launchctl.User(502), uid: 501, expect: true},
+		{name: "pid", domain: launchctl.PID(1), uid: 501, expect: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := needsEscalation(tc.domain, tc.uid); v != tc.expect {
+				t.Errorf("expected=%t, got=%t", tc.expect, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Os specific implementation of [Install].
+func install(_ context.Context, _ plist.Job, _ installOptions) error {
+	return fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Uninstall].
+func uninstall(_ context.Context, _ string, _ installOptions) error {
+	return fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Upgrade].
+func upgrade(_ context.Context, _ plist.Job, _ installOptions) (bool, error) {
+	return false, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [RemoveOrphans].
+func removeOrphans(_ context.Context, _ []Orphan, _ installOptions) error {
+	return fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestInstall(t *testing.T) {
+	job := plist.Job{
+		Label:   "com.example.svc",
+		Program: "/usr/local/bin/svc",
+	}
+	err := launchd.Install(context.Background(), job, launchd.WithDomain(launchctl.System))
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestUninstall(t *testing.T) {
+	err := launchd.Uninstall(context.Background(), "com.example.svc")
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	job := plist.Job{
+		Label:   "com.example.svc",
+		Program: "/usr/local/bin/svc",
+	}
+	changed, err := launchd.Upgrade(context.Background(), job, launchd.WithRestart())
+	if changed {
+		t.Errorf("expected changed=false on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	findings, err := launchd.Diagnose(context.Background(), "com.example.svc", "listener")
+	if len(findings) != 0 {
+		t.Errorf("expected no findings on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestDiagnoseJob(t *testing.T) {
+	findings, err := launchd.DiagnoseJob(context.Background(), "com.example.svc", "listener")
+	if len(findings) != 0 {
+		t.Errorf("expected no findings on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestContext(t *testing.T) {
+	_, err := launchd.Context(context.Background())
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestRemoveOrphans(t *testing.T) {
+	err := launchd.RemoveOrphans(context.Background(), []launchd.Orphan{
+		{Path: "/Library/LaunchDaemons/com.example.svc.plist", Label: "com.example.svc", Daemon: true},
+	})
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestLegacyItems(t *testing.T) {
+	items, err := launchd.LegacyItems(context.Background(), "/Applications/Example.app")
+	if len(items) != 0 {
+		t.Errorf("expected no items on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestDial(t *testing.T) {
+	conn, err := launchd.Dial("com.example.svc", "listener")
+	if conn != nil {
+		t.Errorf("expected no conn on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestListenersGlob(t *testing.T) {
+	listeners, err := launchd.ListenersGlob("worker-*")
+	if len(listeners) != 0 {
+		t.Errorf("expected no listeners on non-darwin platform")
+	}
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
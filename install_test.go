@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestUninstall_InvalidLabel(t *testing.T) {
+	err := Uninstall(context.Background(), "../../etc/x")
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%v", syscall.EINVAL, err)
+	}
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package installer generates preinstall and postinstall scripts for
+// macOS installer packages built with pkgbuild and productbuild,
+// which ship a launchd job.
+//
+// Scripts generated by this package:
+//
+//   - preinstall stops and unloads the existing job, if any, before
+//     files of the package are replaced.
+//   - postinstall writes the plist file with appropriate ownership and
+//     permissions, loads (bootstraps) the job into the appropriate domain,
+//     and optionally restarts it with kickstart.
+//
+// LaunchDaemons are loaded into the system domain. LaunchAgents are
+// installed for all users in /Library/LaunchAgents and are loaded into
+// the GUI domain of the user logged in at the console, if any. Other users
+// get the agent when they log in next. Jobs are only loaded when
+// package is installed on the boot volume.
+//
+// Scripts only depend on tools shipped with macOS and are placed in the
+// directory passed to pkgbuild with --scripts, see [WriteScripts].
+package installer
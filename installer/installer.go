@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package installer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"text/template"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Names of the scripts as expected by pkgbuild.
+const (
+	PreinstallName  = "preinstall"
+	PostinstallName = "postinstall"
+)
+
+// heredocDelimiter is the delimiter of the here-document which
+// embeds the plist in the postinstall script.
+const heredocDelimiter = "GO_LAUNCHD_PLIST_EOF"
+
+// Option configures scripts.
+type Option func(*options)
+
+type options struct {
+	agent     bool
+	kickstart bool
+}
+
+// WithAgent generates scripts for a LaunchAgent instead of a LaunchDaemon.
+func WithAgent() Option {
+	return func(o *options) {
+		o.agent = true
+	}
+}
+
+// WithKickstart restarts the job after it is loaded, with
+// "launchctl kickstart -k". This is useful for jobs which are
+// not started when loaded, i.e. do not specify RunAtLoad or KeepAlive,
+// but should be started right after installation.
+func WithKickstart() Option {
+	return func(o *options) {
+		o.kickstart = true
+	}
+}
+
+// scriptData is the data passed to script templates.
+type scriptData struct {
+	Label     string
+	Dir       string
+	Plist     string
+	Delimiter string
+	Agent     bool
+	Kickstart bool
+}
+
+const header = `#!/bin/sh
+# Code generated by github.com/tprasadtp/go-launchd/installer. DO NOT EDIT.
+#
+# Arguments passed by installer are: $1 path of the package, $2 install
+# location, $3 target volume and $4 root directory of the target volume.
+set -eu
+
+LABEL='{{ .Label }}'
+TARGET_VOLUME="${3:-/}"
+
+# Jobs are only loaded when installing on the boot volume.
+if [ "$TARGET_VOLUME" != "/" ]; then
+	exit 0
+fi
+{{- if .Agent }}
+
+# Agents are loaded into GUI domain of the user logged in at the console.
+CONSOLE_UID="$(/usr/bin/stat -f %u /dev/console)"
+{{- end }}
+`
+
+var preinstallTemplate = template.Must(template.New(PreinstallName).Parse(header + `
+# Unload the existing job, so that its files can be replaced.
+# bootout takes service target, i.e. <domain>/<label>.
+{{- if .Agent }}
+if [ "$CONSOLE_UID" != "0" ]; then
+	/bin/launchctl bootout "gui/$CONSOLE_UID/$LABEL" 2>/dev/null || true
+fi
+{{- else }}
+/bin/launchctl bootout "system/$LABEL" 2>/dev/null || true
+{{- end }}
+exit 0
+`))
+
+var postinstallTemplate = template.Must(template.New(PostinstallName).Parse(header + `
+PLIST='{{ .Dir }}/{{ .Label }}.plist'
+
+# Write plist file atomically. launchd refuses to load plist files
+# which are not owned by root or are writable by group or others.
+/bin/mkdir -p '{{ .Dir }}'
+/bin/cat > "$PLIST.tmp" <<'{{ .Delimiter }}'
+{{ .Plist }}{{ .Delimiter }}
+/usr/sbin/chown root:wheel "$PLIST.tmp"
+/bin/chmod 0644 "$PLIST.tmp"
+/bin/mv -f "$PLIST.tmp" "$PLIST"
+
+# bootout and enable take service target, i.e. <domain>/<label>, while
+# bootstrap takes domain target and path of the plist file.
+{{- if .Agent }}
+if [ "$CONSOLE_UID" = "0" ]; then
+	# No user is logged in, agent is loaded on next login.
+	exit 0
+fi
+DOMAIN="gui/$CONSOLE_UID"
+{{- else }}
+DOMAIN="system"
+{{- end }}
+/bin/launchctl bootout "$DOMAIN/$LABEL" 2>/dev/null || true
+/bin/launchctl enable "$DOMAIN/$LABEL"
+/bin/launchctl bootstrap "$DOMAIN" "$PLIST"
+{{- if .Kickstart }}
+/bin/launchctl kickstart -k "$DOMAIN/$LABEL"
+{{- end }}
+exit 0
+`))
+
+// newScriptData validates the job and returns data for script templates.
+func newScriptData(job plist.Job, opts []Option) (*scriptData, error) {
+	if err := job.Validate(); err != nil {
+		return nil, fmt.Errorf("installer: invalid job: %w: %w", err, syscall.EINVAL)
+	}
+
+	// Label is used in shell scripts and file names.
+	for _, c := range job.Label {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '.' || c == '-' || c == '_') {
+			return nil, fmt.Errorf("installer: label(%s) contains invalid character %q: %w",
+				job.Label, c, syscall.EINVAL)
+		}
+	}
+
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	data, err := plist.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("installer: failed to render plist: %w", err)
+	}
+	if bytes.Contains(data, []byte(heredocDelimiter)) {
+		return nil, fmt.Errorf("installer: plist contains %s: %w", heredocDelimiter, syscall.EINVAL)
+	}
+
+	dir := "/Library/LaunchDaemons"
+	if o.agent {
+		dir = "/Library/LaunchAgents"
+	}
+	return &scriptData{
+		Label:     job.Label,
+		Dir:       dir,
+		Plist:     string(data),
+		Delimiter: heredocDelimiter,
+		Agent:     o.agent,
+		Kickstart: o.kickstart,
+	}, nil
+}
+
+// render renders the template with data.
+func render(t *template.Template, data *scriptData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("installer: failed to render %s: %w", t.Name(), err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Preinstall returns the preinstall script for the job.
+//
+//   - [syscall.EINVAL] is returned if job is invalid.
+func Preinstall(job plist.Job, opts ...Option) ([]byte, error) {
+	data, err := newScriptData(job, opts)
+	if err != nil {
+		return nil, err
+	}
+	return render(preinstallTemplate, data)
+}
+
+// Postinstall returns the postinstall script for the job.
+//
+//   - [syscall.EINVAL] is returned if job is invalid.
+func Postinstall(job plist.Job, opts ...Option) ([]byte, error) {
+	data, err := newScriptData(job, opts)
+	if err != nil {
+		return nil, err
+	}
+	return render(postinstallTemplate, data)
+}
+
+// WriteScripts writes preinstall and postinstall scripts for the job to
+// the directory, which can then be passed to pkgbuild with --scripts.
+// Directory is created if it does not exist.
+//
+//   - [syscall.EINVAL] is returned if job is invalid.
+func WriteScripts(dir string, job plist.Job, opts ...Option) error {
+	data, err := newScriptData(job, opts)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("installer: failed to create directory: %w", err)
+	}
+
+	for _, t := range []*template.Template{preinstallTemplate, postinstallTemplate} {
+		script, err := render(t, data)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, t.Name())
+		if err = os.WriteFile(path, script, 0o755); err != nil {
+			return fmt.Errorf("installer: failed to write %s: %w", t.Name(), err)
+		}
+		// Mode is subject to umask.
+		if err = os.Chmod(path, 0o755); err != nil {
+			return fmt.Errorf("installer: failed to set mode of %s: %w", t.Name(), err)
+		}
+	}
+	return nil
+}
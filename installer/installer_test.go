@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package installer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+var testJob = plist.Job{
+	Label:            "com.example.svc",
+	ProgramArguments: []string{"/usr/local/bin/svc"},
+	RunAtLoad:        true,
+}
+
+// checkSyntax checks syntax of the shell script with "sh -n".
+func checkSyntax(t *testing.T, script []byte) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		return
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		return
+	}
+	cmd := exec.Command(sh, "-n")
+	cmd.Stdin = bytes.NewReader(script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("invalid shell script: %s: %s\n%s", err, out, script)
+	}
+}
+
+func TestScripts(t *testing.T) {
+	tt := []struct {
+		name        string
+		opts        []Option
+		preinstall  []string
+		postinstall []string
+		absent      []string
+	}{
+		{
+			name:       "daemon",
+			preinstall: []string{`/bin/launchctl bootout "system/$LABEL"`},
+			postinstall: []string{
+				"PLIST='/Library/LaunchDaemons/com.example.svc.plist'",
+				`DOMAIN="system"`,
+				`/bin/launchctl bootstrap "$DOMAIN" "$PLIST"`,
+				`/bin/launchctl enable "$DOMAIN/$LABEL"`,
+				"<string>com.example.svc</string>",
+			},
+			absent: []string{"kickstart", "CONSOLE_UID"},
+		},
+		{
+			name:       "agent",
+			opts:       []Option{WithAgent(), WithKickstart()},
+			preinstall: []string{`/bin/launchctl bootout "gui/$CONSOLE_UID/$LABEL"`},
+			postinstall: []string{
+				"PLIST='/Library/LaunchAgents/com.example.svc.plist'",
+				`DOMAIN="gui/$CONSOLE_UID"`,
+				`/bin/launchctl kickstart -k "$DOMAIN/$LABEL"`,
+			},
+			absent: []string{`"system/`},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			pre, err := Preinstall(testJob, tc.opts...)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			post, err := Postinstall(testJob, tc.opts...)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			checkSyntax(t, pre)
+			checkSyntax(t, post)
+
+			for _, s := range tc.preinstall {
+				if !bytes.Contains(pre, []byte(s)) {
+					t.Errorf("expected preinstall to contain %s\n%s", s, pre)
+				}
+			}
+			for _, s := range tc.postinstall {
+				if !bytes.Contains(post, []byte(s)) {
+					t.Errorf("expected postinstall to contain %s\n%s", s, post)
+				}
+			}
+			for _, s := range tc.absent {
+				if bytes.Contains(pre, []byte(s)) || bytes.Contains(post, []byte(s)) {
+					t.Errorf("expected scripts not to contain %s", s)
+				}
+			}
+		})
+	}
+}
+
+func TestScripts_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		job  plist.Job
+	}{
+		{name: "no-program", job: plist.Job{Label: "com.example.svc"}},
+		{name: "quote-in-label", job: plist.Job{Label: "com.example'svc", Program: "/a"}},
+		{name: "delimiter", job: plist.Job{
+			Label: "com.example.svc", Program: "/a",
+			EnvironmentVariables: map[string]string{"X": heredocDelimiter},
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Preinstall(tc.job); !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+			if _, err := Postinstall(tc.job); !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
+
+func TestWriteScripts(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "scripts")
+	if err := WriteScripts(dir, testJob); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	for _, name := range []string{PreinstallName, PostinstallName} {
+		fi, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("expected %s to exist: %s", name, err)
+			continue
+		}
+		if runtime.GOOS != "windows" && fi.Mode().Perm() != 0o755 {
+			t.Errorf("expected %s mode=0755, got=%#o", name, fi.Mode().Perm())
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		if !strings.HasPrefix(string(data), "#!/bin/sh\n") {
+			t.Errorf("expected %s to have shebang", name)
+		}
+	}
+}
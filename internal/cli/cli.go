@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package cli implements flag parsing shared by commands in cmd.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// ErrUsage is returned when command line arguments are invalid.
+// Commands exit with status 2 when this is returned.
+var ErrUsage = errors.New("invalid usage")
+
+// ListFlag is a repeatable string flag.
+type ListFlag []string
+
+// String implements [flag.Value].
+func (l *ListFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implements [flag.Value].
+func (l *ListFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// ParseSocket parses socket specification of the form [name=]network:address.
+// Returned name is empty if specification does not include one.
+//
+// Network is one of tcp, tcp4, tcp6, udp, udp4, udp6, unix or unixgram.
+// For inet networks, address is a port or host:port. For unix networks,
+// address is the absolute path of the socket.
+func ParseSocket(spec string) (string, plist.Socket, error) {
+	var name string
+	network, address, ok := strings.Cut(spec, ":")
+	if n, rest, found := strings.Cut(network, "="); found {
+		if n == "" {
+			return "", plist.Socket{}, fmt.Errorf("%w: empty socket name in %q", ErrUsage, spec)
+		}
+		name, network = n, rest
+	}
+	if !ok || address == "" {
+		return "", plist.Socket{}, fmt.Errorf("%w: invalid socket %q", ErrUsage, spec)
+	}
+
+	var socket plist.Socket
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		socket.SockType = "stream"
+	case "udp", "udp4", "udp6", "unixgram":
+		socket.SockType = "dgram"
+	default:
+		return "", plist.Socket{}, fmt.Errorf("%w: unsupported network %q in socket %q",
+			ErrUsage, network, spec)
+	}
+
+	if strings.HasPrefix(network, "unix") {
+		if !strings.HasPrefix(address, "/") {
+			return "", plist.Socket{}, fmt.Errorf("%w: socket path must be absolute in %q",
+				ErrUsage, spec)
+		}
+		socket.SockPathName = address
+		return name, socket, nil
+	}
+
+	switch network[len(network)-1] {
+	case '4':
+		socket.SockFamily = "IPv4"
+	case '6':
+		socket.SockFamily = "IPv6"
+	}
+
+	host, port := "", address
+	if strings.Contains(address, ":") {
+		var err error
+		host, port, err = net.SplitHostPort(address)
+		if err != nil {
+			return "", plist.Socket{}, fmt.Errorf("%w: invalid address in socket %q: %w",
+				ErrUsage, spec, err)
+		}
+	}
+	if port == "" {
+		return "", plist.Socket{}, fmt.Errorf("%w: port is required in socket %q", ErrUsage, spec)
+	}
+	socket.SockNodeName = host
+	socket.SockServiceName = port
+	return name, socket, nil
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestParseSocket(t *testing.T) {
+	tt := []struct {
+		spec   string
+		name   string
+		socket plist.Socket
+		err    error
+	}{
+		{
+			spec:   "tcp:8080",
+			socket: plist.Socket{SockType: "stream", SockServiceName: "8080"},
+		},
+		{
+			spec:   "web=tcp4:127.0.0.1:8080",
+			name:   "web",
+			socket: plist.Socket{SockType: "stream", SockFamily: "IPv4", SockNodeName: "127.0.0.1", SockServiceName: "8080"},
+		},
+		{
+			spec:   "dns=udp6:[::1]:53",
+			name:   "dns",
+			socket: plist.Socket{SockType: "dgram", SockFamily: "IPv6", SockNodeName: "::1", SockServiceName: "53"},
+		},
+		{
+			spec:   "api=unix:/var/run/api.sock",
+			name:   "api",
+			socket: plist.Socket{SockType: "stream", SockPathName: "/var/run/api.sock"},
+		},
+		{
+			spec:   "unixgram:/var/run/log.sock",
+			socket: plist.Socket{SockType: "dgram", SockPathName: "/var/run/log.sock"},
+		},
+		{spec: "tcp", err: ErrUsage},
+		{spec: "tcp:", err: ErrUsage},
+		{spec: "=tcp:8080", err: ErrUsage},
+		{spec: "sctp:8080", err: ErrUsage},
+		{spec: "tcp:127.0.0.1:", err: ErrUsage},
+		{spec: "unix:relative.sock", err: ErrUsage},
+	}
+	for _, tc := range tt {
+		t.Run(tc.spec, func(t *testing.T) {
+			name, socket, err := ParseSocket(tc.spec)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error=%s, got=%s", tc.err, err)
+			}
+			if name != tc.name {
+				t.Errorf("expected name=%s, got=%s", tc.name, name)
+			}
+			if !reflect.DeepEqual(socket, tc.socket) {
+				t.Errorf("expected socket=%+v, got=%+v", tc.socket, socket)
+			}
+		})
+	}
+}
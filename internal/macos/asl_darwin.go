@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// ASL functions are resolved at runtime with dlsym instead of being
+// imported, as Apple System Log is deprecated and binaries must not
+// fail to load if it is removed in future versions of macOS.
+//
+//nolint:gochecknoglobals // resolved once.
+var asl struct {
+	once    sync.Once
+	new     uintptr
+	set     uintptr
+	send    uintptr
+	release uintptr
+}
+
+// aslTypeMsg is ASL_TYPE_MSG from asl.h.
+const aslTypeMsg = 0
+
+// ASL message keys from asl.h.
+const (
+	ASLKeyMessage  = "Message"
+	ASLKeyLevel    = "Level"
+	ASLKeyFacility = "Facility"
+)
+
+// ASLAvailable reports whether Apple System Log is available.
+func ASLAvailable() bool {
+	asl.once.Do(func() {
+		asl.new = Symbol("asl_new")
+		asl.set = Symbol("asl_set")
+		asl.send = Symbol("asl_send")
+		asl.release = Symbol("asl_release")
+	})
+	return asl.new != 0 && asl.set != 0 && asl.send != 0 && asl.release != 0
+}
+
+// ASLSend sends a message with the given keys and values to Apple System
+// Log. Keys and values containing NUL bytes are truncated at the first
+// NUL byte. It is a no-op if ASL is not available.
+func ASLSend(kv map[string]string) {
+	if !ASLAvailable() {
+		return
+	}
+
+	msg, _ := Call(asl.new, aslTypeMsg)
+	if msg == 0 {
+		return
+	}
+	defer Call(asl.release, msg) //nolint:errcheck // returns void.
+
+	for k, v := range kv {
+		key := cstringTruncate(k)
+		value := cstringTruncate(v)
+		Call(asl.set, msg, //nolint:errcheck // best effort.
+			uintptr(unsafe.Pointer(&key[0])), uintptr(unsafe.Pointer(&value[0])))
+		runtime.KeepAlive(key)
+		runtime.KeepAlive(value)
+	}
+
+	// Default client is used if client is NULL.
+	Call(asl.send, 0, msg) //nolint:errcheck // best effort.
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_task_self_trap task_self_trap "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_task_self_trap_addr uintptr
+
+//go:cgo_import_dynamic libc_task_get_special_port task_get_special_port "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_task_get_special_port_addr uintptr
+
+//go:cgo_import_dynamic libc_bootstrap_look_up bootstrap_look_up "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_bootstrap_look_up_addr uintptr
+
+//go:cgo_import_dynamic libc_mach_port_deallocate mach_port_deallocate "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_mach_port_deallocate_addr uintptr
+
+const (
+	// taskBootstrapPort is TASK_BOOTSTRAP_PORT from mach/task_special_ports.h.
+	taskBootstrapPort = 4
+
+	// bootstrapUnknownService is BOOTSTRAP_UNKNOWN_SERVICE from bootstrap.h.
+	bootstrapUnknownService = 1102
+
+	// bootstrapMaxNameLen is the size of name_t including NUL terminator.
+	bootstrapMaxNameLen = 128
+)
+
+// MachServiceRegistered reports whether the mach service is registered in
+// the bootstrap namespace of the process, i.e. whether bootstrap_look_up
+// succeeds. This does not start the job providing the service.
+func MachServiceRegistered(name string) (bool, error) {
+	if len(name) >= bootstrapMaxNameLen {
+		return false, fmt.Errorf("macos: mach service name(%s) is too long: %w", name, syscall.EINVAL)
+	}
+	p, err := CString(name)
+	if err != nil {
+		return false, fmt.Errorf("macos: invalid mach service name(%q): %w", name, syscall.EINVAL)
+	}
+
+	task, _ := Call(libc_trampoline_task_self_trap_addr)
+	defer Call(libc_trampoline_mach_port_deallocate_addr, task, task)
+
+	var bootstrap, port uint32
+	var pinner runtime.Pinner
+	pinner.Pin(&bootstrap)
+	pinner.Pin(&port)
+	defer pinner.Unpin()
+
+	kr, _ := Call(libc_trampoline_task_get_special_port_addr,
+		task, taskBootstrapPort, uintptr(unsafe.Pointer(&bootstrap)))
+	if int32(kr) != 0 {
+		return false, fmt.Errorf("macos: failed to get bootstrap port: kern_return(%d)", int32(kr))
+	}
+	defer Call(libc_trampoline_mach_port_deallocate_addr, task, uintptr(bootstrap))
+
+	kr, _ = Call(libc_trampoline_bootstrap_look_up_addr,
+		uintptr(bootstrap), uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&port)))
+	runtime.KeepAlive(p)
+	switch int32(kr) {
+	case 0:
+		Call(libc_trampoline_mach_port_deallocate_addr, task, uintptr(port))
+		return true, nil
+	case bootstrapUnknownService:
+		return false, nil
+	default:
+		return false, fmt.Errorf("macos: bootstrap_look_up(%s) failed: kern_return(%d)", name, int32(kr))
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+#include "textflag.h"
+
+GLOBL	·call12_trampoline_addr(SB), RODATA, $8
+DATA	·call12_trampoline_addr(SB)/8, $call12_trampoline<>(SB)
+
+// call12_trampoline is called with C calling convention via Call, with
+// pointer to call12Args in DI. It calls call12Args.fn with twelve arguments,
+// of which the last six are passed on the stack, and saves its return value.
+//
+// Assembler saves frame pointer on entry, thus stack is 16 byte aligned.
+TEXT call12_trampoline<>(SB),NOSPLIT,$0
+	SUBQ	$64, SP		// stack arguments and structure pointer, 16 byte aligned.
+	MOVQ	DI, (6*8)(SP)	// save structure pointer
+
+	MOVQ	(7*8)(DI), AX	// a7
+	MOVQ	AX, (0*8)(SP)
+	MOVQ	(8*8)(DI), AX	// a8
+	MOVQ	AX, (1*8)(SP)
+	MOVQ	(9*8)(DI), AX	// a9
+	MOVQ	AX, (2*8)(SP)
+	MOVQ	(10*8)(DI), AX	// a10
+	MOVQ	AX, (3*8)(SP)
+	MOVQ	(11*8)(DI), AX	// a11
+	MOVQ	AX, (4*8)(SP)
+	MOVQ	(12*8)(DI), AX	// a12
+	MOVQ	AX, (5*8)(SP)
+
+	MOVQ	(0*8)(DI), R10	// fn
+	MOVQ	(2*8)(DI), SI	// a2
+	MOVQ	(3*8)(DI), DX	// a3
+	MOVQ	(4*8)(DI), CX	// a4
+	MOVQ	(5*8)(DI), R8	// a5
+	MOVQ	(6*8)(DI), R9	// a6
+	MOVQ	(1*8)(DI), DI	// a1
+	XORL	AX, AX		// no vector registers for variadic functions
+	CALL	R10
+
+	MOVQ	(6*8)(SP), DI	// restore structure pointer
+	MOVQ	AX, (13*8)(DI)	// r1
+	ADDQ	$64, SP
+	RET
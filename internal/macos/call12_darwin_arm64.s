@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+#include "textflag.h"
+
+GLOBL	·call12_trampoline_addr(SB), RODATA, $8
+DATA	·call12_trampoline_addr(SB)/8, $call12_trampoline<>(SB)
+
+// call12_trampoline is called with C calling convention via Call, with
+// pointer to call12Args in R0. It calls call12Args.fn with twelve arguments,
+// of which the last four are passed on the stack, and saves its return value.
+//
+// Assembler saves link register and frame pointer on entry.
+TEXT call12_trampoline<>(SB),NOSPLIT,$0
+	SUB	$48, RSP		// stack arguments and structure pointer, 16 byte aligned.
+	MOVD	R0, (4*8)(RSP)	// save structure pointer
+
+	MOVD	(9*8)(R0), R1	// a9
+	MOVD	R1, (0*8)(RSP)
+	MOVD	(10*8)(R0), R1	// a10
+	MOVD	R1, (1*8)(RSP)
+	MOVD	(11*8)(R0), R1	// a11
+	MOVD	R1, (2*8)(RSP)
+	MOVD	(12*8)(R0), R1	// a12
+	MOVD	R1, (3*8)(RSP)
+
+	MOVD	(0*8)(R0), R12	// fn
+	MOVD	(2*8)(R0), R1	// a2
+	MOVD	(3*8)(R0), R2	// a3
+	MOVD	(4*8)(R0), R3	// a4
+	MOVD	(5*8)(R0), R4	// a5
+	MOVD	(6*8)(R0), R5	// a6
+	MOVD	(7*8)(R0), R6	// a7
+	MOVD	(8*8)(R0), R7	// a8
+	MOVD	(1*8)(R0), R0	// a1
+	BL	(R12)
+
+	MOVD	(4*8)(RSP), R2	// restore structure pointer
+	MOVD	R0, (13*8)(R2)	// r1
+	ADD	$48, RSP
+	RET
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libcf_CFDataCreate CFDataCreate "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFDataCreate_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFDictionaryCreate CFDictionaryCreate "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFDictionaryCreate_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFDictionaryGetValue CFDictionaryGetValue "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFDictionaryGetValue_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecCodeCopySigningInformation SecCodeCopySigningInformation "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecCodeCopySigningInformation_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecCodeCopyGuestWithAttributes SecCodeCopyGuestWithAttributes "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecCodeCopyGuestWithAttributes_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecRequirementCreateWithString SecRequirementCreateWithString "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecRequirementCreateWithString_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecCodeCheckValidity SecCodeCheckValidity "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecCodeCheckValidity_addr uintptr
+
+// Code signing errors from CSCommon.h.
+const (
+	ErrSecCSReqFailed       OSStatus = -67050
+	ErrSecCSReqInvalid      OSStatus = -67052
+	ErrSecCSSignatureFailed OSStatus = -67061
+	ErrSecCSUnsigned        OSStatus = -67062
+	ErrSecCSGuestInvalid    OSStatus = -67063
+	ErrSecCSNoSuchCode      OSStatus = -67065
+)
+
+// kSecCSSigningInformation from SecCode.h.
+const kSecCSSigningInformation = 1 << 1
+
+// AuditToken is audit_token_t from bsm/audit.h.
+type AuditToken [8]uint32
+
+// CFData returns CFDataRef with a copy of b.
+// Caller must release returned data with [Release].
+func CFData(b []byte) ID {
+	var p unsafe.Pointer
+	if len(b) > 0 {
+		p = unsafe.Pointer(&b[0])
+	}
+	r1, _ := Call(libcf_trampoline_CFDataCreate_addr, 0, uintptr(p), uintptr(len(b)))
+	runtime.KeepAlive(b)
+	return ID(r1)
+}
+
+// CFDictionary returns CFDictionaryRef with given keys and values, which
+// must be CoreFoundation types of equal length. Keys and values are retained
+// by the dictionary. Caller must release returned dictionary with [Release].
+func CFDictionary(keys, values []ID) ID {
+	if len(keys) != len(values) || len(keys) == 0 {
+		return 0
+	}
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	pinner.Pin(&keys[0])
+	pinner.Pin(&values[0])
+
+	r1, _ := Call(libcf_trampoline_CFDictionaryCreate_addr,
+		0,
+		uintptr(unsafe.Pointer(&keys[0])),
+		uintptr(unsafe.Pointer(&values[0])),
+		uintptr(len(keys)),
+		Symbol("kCFTypeDictionaryKeyCallBacks"),
+		Symbol("kCFTypeDictionaryValueCallBacks"),
+	)
+	return ID(r1)
+}
+
+// guestCode returns SecCodeRef of the process identified by the audit token.
+// Caller must release returned code with [Release].
+func guestCode(token AuditToken) (ID, error) {
+	attr := Pointer(Symbol("kSecGuestAttributeAudit"))
+	if attr == 0 {
+		return 0, fmt.Errorf("macos: kSecGuestAttributeAudit is not available")
+	}
+
+	data := CFData(unsafe.Slice((*byte)(unsafe.Pointer(&token[0])), unsafe.Sizeof(token)))
+	if data == 0 {
+		return 0, fmt.Errorf("macos: failed to create audit token data")
+	}
+	defer Release(data)
+
+	attrs := CFDictionary([]ID{ID(attr)}, []ID{data})
+	if attrs == 0 {
+		return 0, fmt.Errorf("macos: failed to create guest attributes")
+	}
+	defer Release(attrs)
+
+	var code ID
+	var pinner runtime.Pinner
+	pinner.Pin(&code)
+	defer pinner.Unpin()
+
+	// SecCodeCopyGuestWithAttributes(SecCodeRef host, CFDictionaryRef attrs, SecCSFlags flags, SecCodeRef *guest)
+	r1, _ := Call(libsecurity_trampoline_SecCodeCopyGuestWithAttributes_addr,
+		0, uintptr(attrs), 0, uintptr(unsafe.Pointer(&code)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return 0, status
+	}
+	return code, nil
+}
+
+// CheckCodeRequirement checks if code signature of the process identified by
+// the audit token is valid and satisfies the requirement in text form.
+// [ErrSecCSReqFailed] is returned if requirement is not satisfied.
+func CheckCodeRequirement(token AuditToken, requirement string) error {
+	text := NSString(requirement)
+	if text == 0 {
+		return fmt.Errorf("macos: invalid requirement(%q): %w", requirement, ErrSecCSReqInvalid)
+	}
+	defer Release(text)
+
+	var req ID
+	var pinner runtime.Pinner
+	pinner.Pin(&req)
+	defer pinner.Unpin()
+
+	// SecRequirementCreateWithString(CFStringRef text, SecCSFlags flags, SecRequirementRef *requirement)
+	r1, _ := Call(libsecurity_trampoline_SecRequirementCreateWithString_addr,
+		uintptr(text), 0, uintptr(unsafe.Pointer(&req)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return status
+	}
+	defer Release(req)
+
+	code, err := guestCode(token)
+	if err != nil {
+		return err
+	}
+	defer Release(code)
+
+	// SecCodeCheckValidity(SecCodeRef code, SecCSFlags flags, SecRequirementRef requirement)
+	r1, _ = Call(libsecurity_trampoline_SecCodeCheckValidity_addr,
+		uintptr(code), 0, uintptr(req))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return status
+	}
+	return nil
+}
+
+// SigningIdentifier returns code signing identifier of the process identified
+// by the audit token. Signature is not validated, thus callers must use
+// [CheckCodeRequirement] before making authorization decisions based on it.
+// [ErrSecCSUnsigned] is returned if process is not signed.
+func SigningIdentifier(token AuditToken) (string, error) {
+	key := Pointer(Symbol("kSecCodeInfoIdentifier"))
+	if key == 0 {
+		return "", fmt.Errorf("macos: kSecCodeInfoIdentifier is not available")
+	}
+
+	code, err := guestCode(token)
+	if err != nil {
+		return "", err
+	}
+	defer Release(code)
+
+	var info ID
+	var pinner runtime.Pinner
+	pinner.Pin(&info)
+	defer pinner.Unpin()
+
+	// SecCodeCopySigningInformation(SecStaticCodeRef code, SecCSFlags flags, CFDictionaryRef *information)
+	r1, _ := Call(libsecurity_trampoline_SecCodeCopySigningInformation_addr,
+		uintptr(code), kSecCSSigningInformation, uintptr(unsafe.Pointer(&info)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return "", status
+	}
+	defer Release(info)
+
+	// Value is owned by the dictionary, thus must not be released.
+	r1, _ = Call(libcf_trampoline_CFDictionaryGetValue_addr, uintptr(info), key)
+	if r1 == 0 {
+		return "", ErrSecCSUnsigned
+	}
+
+	var id string
+	WithAutoreleasePool(func() {
+		id = GoStringFromNSString(ID(r1))
+	})
+	return id, nil
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_dlsym dlsym "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_dlsym_addr uintptr
+
+//go:cgo_import_dynamic libc_dlopen dlopen "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_dlopen_addr uintptr
+
+// rtldDefault is RTLD_DEFAULT from dlfcn.h.
+const rtldDefault = ^uintptr(1) // (void *)-2
+
+// Flags for dlopen from dlfcn.h.
+const (
+	rtldLazy  = 0x1
+	rtldLocal = 0x4
+)
+
+// Symbol returns address of the symbol in the loaded images,
+// or zero if symbol is not found.
+func Symbol(name string) uintptr {
+	return Lookup(rtldDefault, name)
+}
+
+// Open loads the dynamic library at path with dlopen and returns its handle.
+// Libraries are never unloaded, thus handle remains valid for the lifetime
+// of the process. Libraries in the dyld shared cache, like libSystem,
+// can be opened even though they do not exist on disk.
+//
+//   - [syscall.ENOENT] is returned if library cannot be loaded.
+//   - [syscall.EINVAL] is returned if path contains NUL bytes.
+func Open(path string) (uintptr, error) {
+	p, err := CString(path)
+	if err != nil {
+		return 0, fmt.Errorf("macos: invalid library path(%s): %w", path, syscall.EINVAL)
+	}
+	r1, _ := Call(libc_trampoline_dlopen_addr, uintptr(unsafe.Pointer(p)), rtldLazy|rtldLocal)
+	runtime.KeepAlive(p)
+	if r1 == 0 {
+		return 0, fmt.Errorf("macos: failed to load library(%s): %w", path, syscall.ENOENT)
+	}
+	return r1, nil
+}
+
+// Lookup returns address of the symbol in the library with handle
+// returned by [Open], or zero if symbol is not found.
+func Lookup(handle uintptr, name string) uintptr {
+	p, err := CString(name)
+	if err != nil {
+		return 0
+	}
+	r1, _ := Call(libc_trampoline_dlsym_addr, handle, uintptr(unsafe.Pointer(p)))
+	runtime.KeepAlive(p)
+	return r1
+}
+
+// Pointer returns the pointer value stored at addr. This is useful for reading
+// exported constants like CFStringRef values, whose address is returned
+// by [Symbol]. Zero is returned if addr is zero.
+func Pointer(addr uintptr) uintptr {
+	if addr == 0 {
+		return 0
+	}
+	// Unsafe trick is used to silence govet, as addr points to
+	// memory not managed by go runtime.
+	return **(**uintptr)(unsafe.Pointer(&addr))
+}
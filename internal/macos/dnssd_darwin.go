@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_DNSServiceRegister DNSServiceRegister "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_DNSServiceRegister_addr uintptr
+
+//go:cgo_import_dynamic libc_DNSServiceUpdateRecord DNSServiceUpdateRecord "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_DNSServiceUpdateRecord_addr uintptr
+
+//go:cgo_import_dynamic libc_DNSServiceRefDeallocate DNSServiceRefDeallocate "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_DNSServiceRefDeallocate_addr uintptr
+
+// call12_trampoline_addr is the address of call12_trampoline,
+// which is defined in call12_darwin_$GOARCH.s.
+//
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var call12_trampoline_addr uintptr
+
+// call12Args are the function and arguments passed to call12_trampoline,
+// along with its return value. Layout must match call12_darwin_$GOARCH.s.
+type call12Args struct {
+	fn   uintptr
+	args [12]uintptr
+	r1   uintptr
+}
+
+// call12 is like [Call], but calls C function at address fn with up to twelve
+// arguments, some of which are passed on the stack. Arguments are passed on
+// the stack in pointer sized slots. As arm64 ABI of macOS packs arguments on
+// the stack by their natural alignment, arguments after the eighth, which are
+// smaller than a pointer, must be followed by a pointer sized argument.
+//
+// Callers must ensure that go pointers passed as arguments are pinned.
+func call12(fn uintptr, args ...uintptr) uintptr {
+	c := &call12Args{fn: fn}
+	if len(args) > len(c.args) {
+		panic("macos: too many arguments")
+	}
+	copy(c.args[:], args)
+
+	var pinner runtime.Pinner
+	pinner.Pin(c)
+	defer pinner.Unpin()
+
+	Call(call12_trampoline_addr, uintptr(unsafe.Pointer(c))) //nolint:errcheck // returns void.
+	return c.r1
+}
+
+// DNSServiceError is an error code returned by DNS Service Discovery API.
+type DNSServiceError int32
+
+// Error codes from dns_sd.h.
+const (
+	DNSServiceErrUnknown           DNSServiceError = -65537
+	DNSServiceErrNoMemory          DNSServiceError = -65539
+	DNSServiceErrBadParam          DNSServiceError = -65540
+	DNSServiceErrBadReference      DNSServiceError = -65541
+	DNSServiceErrUnsupported       DNSServiceError = -65544
+	DNSServiceErrNameConflict      DNSServiceError = -65548
+	DNSServiceErrServiceNotRunning DNSServiceError = -65563
+	DNSServiceErrPolicyDenied      DNSServiceError = -65570
+)
+
+// Error implements error interface.
+func (e DNSServiceError) Error() string {
+	switch e {
+	case DNSServiceErrNoMemory:
+		return "dnssd: no memory"
+	case DNSServiceErrBadParam:
+		return "dnssd: bad parameter"
+	case DNSServiceErrBadReference:
+		return "dnssd: bad reference"
+	case DNSServiceErrUnsupported:
+		return "dnssd: unsupported"
+	case DNSServiceErrNameConflict:
+		return "dnssd: name conflict"
+	case DNSServiceErrServiceNotRunning:
+		return "dnssd: mDNSResponder is not running"
+	case DNSServiceErrPolicyDenied:
+		return "dnssd: denied by policy"
+	default:
+		return fmt.Sprintf("dnssd: error(%d)", int32(e))
+	}
+}
+
+// DNSServiceRef is a reference to a service registered with [DNSServiceRegister].
+type DNSServiceRef uintptr
+
+// DNSServiceRegister registers the service with mDNSResponder, and returns
+// a reference which keeps it registered until [DNSServiceRefDeallocate].
+// Empty name and domain use the computer name and default domains. txt
+// must be TXT record data, i.e. length prefixed strings, or empty.
+//
+// Callback is not used, thus only errors returned by mDNSResponder when
+// the request is received are reported, and services are renamed
+// automatically on name conflicts.
+func DNSServiceRegister(name, regtype, domain string, port uint16, txt []byte) (DNSServiceRef, error) {
+	if len(txt) > 0xffff {
+		return 0, DNSServiceErrBadParam
+	}
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+
+	// cstring returns pinned C string for s, or NULL if s is empty.
+	cstring := func(s string) (uintptr, error) {
+		if s == "" {
+			return 0, nil
+		}
+		p, err := CString(s)
+		if err != nil {
+			return 0, DNSServiceErrBadParam
+		}
+		pinner.Pin(p)
+		return uintptr(unsafe.Pointer(p)), nil
+	}
+
+	cname, err := cstring(name)
+	if err != nil {
+		return 0, err
+	}
+	ctype, err := cstring(regtype)
+	if err != nil || ctype == 0 {
+		return 0, DNSServiceErrBadParam
+	}
+	cdomain, err := cstring(domain)
+	if err != nil {
+		return 0, err
+	}
+
+	var ctxt uintptr
+	if len(txt) > 0 {
+		pinner.Pin(&txt[0])
+		ctxt = uintptr(unsafe.Pointer(&txt[0]))
+	}
+
+	var ref DNSServiceRef
+	pinner.Pin(&ref)
+
+	// DNSServiceRegister(DNSServiceRef *sdRef, DNSServiceFlags flags, uint32_t interfaceIndex,
+	//     const char *name, const char *regtype, const char *domain, const char *host,
+	//     uint16_t port, uint16_t txtLen, const void *txtRecord,
+	//     DNSServiceRegisterReply callBack, void *context)
+	//
+	// Port is in network byte order. Both amd64 and arm64 are little endian.
+	r1 := call12(libc_trampoline_DNSServiceRegister_addr,
+		uintptr(unsafe.Pointer(&ref)),
+		0, // flags
+		0, // all interfaces
+		cname,
+		ctype,
+		cdomain,
+		0, // host name of the computer
+		uintptr(port>>8|port<<8),
+		uintptr(len(txt)),
+		ctxt,
+		0, // no callback
+		0, // no context
+	)
+	if status := DNSServiceError(int32(r1)); status != 0 {
+		return 0, status
+	}
+	return ref, nil
+}
+
+// DNSServiceUpdateTXT replaces TXT record of the service registered
+// with [DNSServiceRegister], without registering it again.
+func DNSServiceUpdateTXT(ref DNSServiceRef, txt []byte) error {
+	if len(txt) > 0xffff {
+		return DNSServiceErrBadParam
+	}
+
+	var ctxt uintptr
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	if len(txt) > 0 {
+		pinner.Pin(&txt[0])
+		ctxt = uintptr(unsafe.Pointer(&txt[0]))
+	}
+
+	// DNSServiceUpdateRecord(DNSServiceRef sdRef, DNSRecordRef RecordRef, DNSServiceFlags flags,
+	//     uint16_t rdlen, const void *rdata, uint32_t ttl)
+	//
+	// NULL RecordRef updates the primary TXT record of the registered service.
+	r1, _ := Call(libc_trampoline_DNSServiceUpdateRecord_addr,
+		uintptr(ref), 0, 0, uintptr(len(txt)), ctxt, 0)
+	if status := DNSServiceError(int32(r1)); status != 0 {
+		return status
+	}
+	return nil
+}
+
+// DNSServiceRefDeallocate deregisters the service and releases the reference.
+func DNSServiceRefDeallocate(ref DNSServiceRef) {
+	if ref != 0 {
+		Call(libc_trampoline_DNSServiceRefDeallocate_addr, uintptr(ref)) //nolint:errcheck // returns void.
+	}
+}
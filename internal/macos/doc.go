@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package macos provides helpers for calling functions in macOS system
+// libraries and frameworks without using cgo.
+//
+// This uses the same approach as the root package, i.e. functions are
+// imported with go:cgo_import_dynamic and called via trampolines using
+// syscall.syscall from the runtime. All exported functions are only
+// available on macOS.
+package macos
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+//go:cgo_import_dynamic libsecurity_SecTaskCreateFromSelf SecTaskCreateFromSelf "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecTaskCreateFromSelf_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecTaskCopyValueForEntitlement SecTaskCopyValueForEntitlement "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecTaskCopyValueForEntitlement_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFGetTypeID CFGetTypeID "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFGetTypeID_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFBooleanGetTypeID CFBooleanGetTypeID "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFBooleanGetTypeID_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFBooleanGetValue CFBooleanGetValue "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFBooleanGetValue_addr uintptr
+
+// Entitlement reports whether the calling process has the entitlement, and
+// whether its value is true. For entitlements with non-boolean values, like
+// arrays of strings, value is true if entitlement is present.
+func Entitlement(name string) (present, value bool) {
+	key := NSString(name)
+	if key == 0 {
+		return false, false
+	}
+	defer Release(key)
+
+	// SecTaskCreateFromSelf(CFAllocatorRef allocator)
+	task, _ := Call(libsecurity_trampoline_SecTaskCreateFromSelf_addr, 0)
+	if task == 0 {
+		return false, false
+	}
+	defer Release(ID(task))
+
+	// SecTaskCopyValueForEntitlement(SecTaskRef task, CFStringRef entitlement, CFErrorRef *error)
+	v, _ := Call(libsecurity_trampoline_SecTaskCopyValueForEntitlement_addr, task, uintptr(key), 0)
+	if v == 0 {
+		return false, false
+	}
+	defer Release(ID(v))
+
+	typ, _ := Call(libcf_trampoline_CFGetTypeID_addr, v)
+	boolean, _ := Call(libcf_trampoline_CFBooleanGetTypeID_addr)
+	if typ != boolean {
+		return true, true
+	}
+
+	// Boolean is unsigned char.
+	r1, _ := Call(libcf_trampoline_CFBooleanGetValue_addr, v)
+	return true, r1&0xff != 0
+}
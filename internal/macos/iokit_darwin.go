@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libiokit_IOPMAssertionCreateWithName IOPMAssertionCreateWithName "/System/Library/Frameworks/IOKit.framework/Versions/A/IOKit"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libiokit_trampoline_IOPMAssertionCreateWithName_addr uintptr
+
+//go:cgo_import_dynamic libiokit_IOPMAssertionRelease IOPMAssertionRelease "/System/Library/Frameworks/IOKit.framework/Versions/A/IOKit"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libiokit_trampoline_IOPMAssertionRelease_addr uintptr
+
+// Power management assertion types.
+const (
+	// AssertionPreventUserIdleSystemSleep is
+	// kIOPMAssertionTypePreventUserIdleSystemSleep.
+	AssertionPreventUserIdleSystemSleep = "PreventUserIdleSystemSleep"
+
+	// AssertionPreventUserIdleDisplaySleep is
+	// kIOPMAssertionTypePreventUserIdleDisplaySleep.
+	AssertionPreventUserIdleDisplaySleep = "PreventUserIdleDisplaySleep"
+)
+
+// kIOPMAssertionLevelOn.
+const assertionLevelOn = 255
+
+// IOReturn is an error code returned by IOKit.
+type IOReturn int32
+
+// Error implements error interface.
+func (r IOReturn) Error() string {
+	return fmt.Sprintf("IOReturn(%#x)", uint32(r))
+}
+
+// AssertionID is IOPMAssertionID.
+type AssertionID uint32
+
+// IOPMAssertionCreateWithName creates a power management assertion
+// of the given type with name as reason.
+func IOPMAssertionCreateWithName(kind, name string) (AssertionID, error) {
+	t := NSString(kind)
+	if t == 0 {
+		return 0, fmt.Errorf("macos: invalid assertion type(%q)", kind)
+	}
+	defer Release(t)
+
+	n := NSString(name)
+	if n == 0 {
+		return 0, fmt.Errorf("macos: invalid assertion name(%q)", name)
+	}
+	defer Release(n)
+
+	var id AssertionID
+	var pinner runtime.Pinner
+	pinner.Pin(&id)
+	defer pinner.Unpin()
+
+	r1, _ := Call(libiokit_trampoline_IOPMAssertionCreateWithName_addr,
+		uintptr(t),
+		assertionLevelOn,
+		uintptr(n),
+		uintptr(unsafe.Pointer(&id)),
+	)
+
+	// IOReturn is a 32-bit kern_return_t, kIOReturnSuccess is zero.
+	if rv := IOReturn(int32(uint32(r1))); rv != 0 {
+		return 0, rv
+	}
+	return id, nil
+}
+
+// IOPMAssertionRelease releases the power management assertion.
+func IOPMAssertionRelease(id AssertionID) error {
+	r1, _ := Call(libiokit_trampoline_IOPMAssertionRelease_addr, uintptr(id))
+	if rv := IOReturn(int32(uint32(r1))); rv != 0 {
+		return rv
+	}
+	return nil
+}
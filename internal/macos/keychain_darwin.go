@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libcf_CFDataGetBytePtr CFDataGetBytePtr "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFDataGetBytePtr_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFDataGetLength CFDataGetLength "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFDataGetLength_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecItemCopyMatching SecItemCopyMatching "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecItemCopyMatching_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecIdentityCopyCertificate SecIdentityCopyCertificate "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecIdentityCopyCertificate_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecIdentityCopyPrivateKey SecIdentityCopyPrivateKey "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecIdentityCopyPrivateKey_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecCertificateCopyData SecCertificateCopyData "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecCertificateCopyData_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_SecKeyCreateSignature SecKeyCreateSignature "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_SecKeyCreateSignature_addr uintptr
+
+// Keychain errors from SecBase.h.
+const (
+	ErrSecAuthFailed            OSStatus = -25293
+	ErrSecItemNotFound          OSStatus = -25300
+	ErrSecInteractionNotAllowed OSStatus = -25308
+)
+
+// CFDataBytes returns a copy of contents of CFDataRef.
+func CFDataBytes(data ID) []byte {
+	if data == 0 {
+		return nil
+	}
+	p, _ := Call(libcf_trampoline_CFDataGetBytePtr_addr, uintptr(data))
+	n, _ := Call(libcf_trampoline_CFDataGetLength_addr, uintptr(data))
+	if p == 0 || int(n) <= 0 {
+		return nil
+	}
+
+	// Unsafe trick is used to silence govet, as p points to
+	// memory not managed by go runtime.
+	ptr := *(*unsafe.Pointer)(unsafe.Pointer(&p))
+	return append([]byte(nil), unsafe.Slice((*byte)(ptr), int(n))...)
+}
+
+// CopyIdentity returns SecIdentityRef with the given label from the
+// keychain search list, which includes login and System keychains.
+// Caller must release returned identity with [Release].
+// [ErrSecItemNotFound] is returned if identity is not found.
+func CopyIdentity(label string) (ID, error) {
+	keys := []string{"kSecClass", "kSecAttrLabel", "kSecReturnRef", "kSecMatchLimit"}
+	values := []string{"kSecClassIdentity", "", "kCFBooleanTrue", "kSecMatchLimitOne"}
+
+	name := NSString(label)
+	if name == 0 {
+		return 0, fmt.Errorf("macos: invalid label(%q)", label)
+	}
+	defer Release(name)
+
+	k := make([]ID, len(keys))
+	v := make([]ID, len(values))
+	for i := range keys {
+		k[i] = ID(Pointer(Symbol(keys[i])))
+		if values[i] == "" {
+			v[i] = name
+		} else {
+			v[i] = ID(Pointer(Symbol(values[i])))
+		}
+		if k[i] == 0 || v[i] == 0 {
+			return 0, fmt.Errorf("macos: keychain constants are not available")
+		}
+	}
+
+	query := CFDictionary(k, v)
+	if query == 0 {
+		return 0, fmt.Errorf("macos: failed to create keychain query")
+	}
+	defer Release(query)
+
+	var identity ID
+	var pinner runtime.Pinner
+	pinner.Pin(&identity)
+	defer pinner.Unpin()
+
+	// SecItemCopyMatching(CFDictionaryRef query, CFTypeRef *result)
+	r1, _ := Call(libsecurity_trampoline_SecItemCopyMatching_addr,
+		uintptr(query), uintptr(unsafe.Pointer(&identity)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return 0, status
+	}
+	return identity, nil
+}
+
+// IdentityCertificate returns DER encoded certificate of SecIdentityRef.
+func IdentityCertificate(identity ID) ([]byte, error) {
+	var cert ID
+	var pinner runtime.Pinner
+	pinner.Pin(&cert)
+	defer pinner.Unpin()
+
+	// SecIdentityCopyCertificate(SecIdentityRef identityRef, SecCertificateRef *certificateRef)
+	r1, _ := Call(libsecurity_trampoline_SecIdentityCopyCertificate_addr,
+		uintptr(identity), uintptr(unsafe.Pointer(&cert)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return nil, status
+	}
+	defer Release(cert)
+
+	// SecCertificateCopyData(SecCertificateRef certificate)
+	data, _ := Call(libsecurity_trampoline_SecCertificateCopyData_addr, uintptr(cert))
+	if data == 0 {
+		return nil, fmt.Errorf("macos: failed to copy certificate data")
+	}
+	defer Release(ID(data))
+	return CFDataBytes(ID(data)), nil
+}
+
+// IdentityPrivateKey returns SecKeyRef of the private key of SecIdentityRef.
+// Caller must release returned key with [Release].
+func IdentityPrivateKey(identity ID) (ID, error) {
+	var key ID
+	var pinner runtime.Pinner
+	pinner.Pin(&key)
+	defer pinner.Unpin()
+
+	// SecIdentityCopyPrivateKey(SecIdentityRef identityRef, SecKeyRef *privateKeyRef)
+	r1, _ := Call(libsecurity_trampoline_SecIdentityCopyPrivateKey_addr,
+		uintptr(identity), uintptr(unsafe.Pointer(&key)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return 0, status
+	}
+	return key, nil
+}
+
+// KeySign signs digest with SecKeyRef using algorithm, which is name of
+// the SecKeyAlgorithm constant, like "kSecKeyAlgorithmECDSASignatureDigestX962SHA256".
+// Private key never leaves the keychain.
+func KeySign(key ID, algorithm string, digest []byte) ([]byte, error) {
+	alg := Pointer(Symbol(algorithm))
+	if alg == 0 {
+		return nil, fmt.Errorf("macos: algorithm(%s) is not available", algorithm)
+	}
+
+	data := CFData(digest)
+	if data == 0 {
+		return nil, fmt.Errorf("macos: failed to create digest data")
+	}
+	defer Release(data)
+
+	var cferr ID
+	var pinner runtime.Pinner
+	pinner.Pin(&cferr)
+	defer pinner.Unpin()
+
+	// SecKeyCreateSignature(SecKeyRef key, SecKeyAlgorithm algorithm, CFDataRef dataToSign, CFErrorRef *error)
+	sig, _ := Call(libsecurity_trampoline_SecKeyCreateSignature_addr,
+		uintptr(key), alg, uintptr(data), uintptr(unsafe.Pointer(&cferr)))
+	if sig == 0 {
+		return nil, newCFError(cferr)
+	}
+	defer Release(ID(sig))
+	return CFDataBytes(ID(sig)), nil
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_notify_post notify_post "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_notify_post_addr uintptr
+
+//go:cgo_import_dynamic libc_notify_register_file_descriptor notify_register_file_descriptor "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_notify_register_file_descriptor_addr uintptr
+
+//go:cgo_import_dynamic libc_notify_cancel notify_cancel "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_notify_cancel_addr uintptr
+
+// NotifyStatus is a status code returned by libnotify.
+type NotifyStatus uint32
+
+// Status codes from notify.h.
+const (
+	NotifyStatusOK             NotifyStatus = 0
+	NotifyStatusInvalidName    NotifyStatus = 1
+	NotifyStatusInvalidToken   NotifyStatus = 2
+	NotifyStatusInvalidFile    NotifyStatus = 4
+	NotifyStatusNotAuthorized  NotifyStatus = 7
+	NotifyStatusServerNotFound NotifyStatus = 9
+	NotifyStatusFailed         NotifyStatus = 1000000
+)
+
+// Error implements error interface.
+func (s NotifyStatus) Error() string {
+	switch s {
+	case NotifyStatusInvalidName:
+		return "notify: invalid name"
+	case NotifyStatusInvalidToken:
+		return "notify: invalid token"
+	case NotifyStatusInvalidFile:
+		return "notify: invalid file"
+	case NotifyStatusNotAuthorized:
+		return "notify: not authorized"
+	case NotifyStatusServerNotFound:
+		return "notify: server not found"
+	case NotifyStatusFailed:
+		return "notify: failed"
+	default:
+		return fmt.Sprintf("notify: status(%d)", uint32(s))
+	}
+}
+
+// NotifyPost posts a notification with the given name.
+func NotifyPost(name string) error {
+	p, err := CString(name)
+	if err != nil {
+		return NotifyStatusInvalidName
+	}
+	r1, _ := Call(libc_trampoline_notify_post_addr, uintptr(unsafe.Pointer(p)))
+	runtime.KeepAlive(p)
+	if s := NotifyStatus(uint32(r1)); s != NotifyStatusOK {
+		return s
+	}
+	return nil
+}
+
+// NotifyRegisterFileDescriptor registers for notifications with the given
+// name. Each notification is delivered by writing the registration token
+// as a 4 byte integer in network byte order to the returned file descriptor.
+// Caller must close the file descriptor and cancel the registration.
+func NotifyRegisterFileDescriptor(name string) (fd int, token int, err error) {
+	p, err := CString(name)
+	if err != nil {
+		return -1, 0, NotifyStatusInvalidName
+	}
+
+	var cfd, ctoken int32
+	var pinner runtime.Pinner
+	pinner.Pin(p)
+	pinner.Pin(&cfd)
+	pinner.Pin(&ctoken)
+	defer pinner.Unpin()
+
+	// uint32_t notify_register_file_descriptor(const char *name,
+	//     int *notify_fd, int flags, int *out_token);
+	r1, _ := Call(libc_trampoline_notify_register_file_descriptor_addr,
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&cfd)),
+		0,
+		uintptr(unsafe.Pointer(&ctoken)),
+	)
+	if s := NotifyStatus(uint32(r1)); s != NotifyStatusOK {
+		return -1, 0, s
+	}
+	return int(cfd), int(ctoken), nil
+}
+
+// NotifyCancel cancels the registration.
+func NotifyCancel(token int) error {
+	r1, _ := Call(libc_trampoline_notify_cancel_addr, uintptr(token))
+	if s := NotifyStatus(uint32(r1)); s != NotifyStatusOK {
+		return s
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libobjc_objc_getClass objc_getClass "/usr/lib/libobjc.A.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libobjc_trampoline_objc_getClass_addr uintptr
+
+//go:cgo_import_dynamic libobjc_sel_registerName sel_registerName "/usr/lib/libobjc.A.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libobjc_trampoline_sel_registerName_addr uintptr
+
+//go:cgo_import_dynamic libobjc_objc_msgSend objc_msgSend "/usr/lib/libobjc.A.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libobjc_trampoline_objc_msgSend_addr uintptr
+
+//go:cgo_import_dynamic libobjc_objc_autoreleasePoolPush objc_autoreleasePoolPush "/usr/lib/libobjc.A.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libobjc_trampoline_objc_autoreleasePoolPush_addr uintptr
+
+//go:cgo_import_dynamic libobjc_objc_autoreleasePoolPop objc_autoreleasePoolPop "/usr/lib/libobjc.A.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libobjc_trampoline_objc_autoreleasePoolPop_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFStringCreateWithCString CFStringCreateWithCString "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFStringCreateWithCString_addr uintptr
+
+//go:cgo_import_dynamic libcf_CFRelease CFRelease "/System/Library/Frameworks/CoreFoundation.framework/Versions/A/CoreFoundation"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libcf_trampoline_CFRelease_addr uintptr
+
+// kCFStringEncodingUTF8 from CFString.h.
+const kCFStringEncodingUTF8 = 0x08000100
+
+// ID is an Objective-C object pointer.
+type ID uintptr
+
+// Class returns the Objective-C class with the given name.
+// Zero is returned if class is not registered, for example,
+// if it is not available on the running macOS version.
+func Class(name string) ID {
+	p, err := CString(name)
+	if err != nil {
+		return 0
+	}
+	r1, _ := Call(libobjc_trampoline_objc_getClass_addr, uintptr(unsafe.Pointer(p)))
+	runtime.KeepAlive(p)
+	return ID(r1)
+}
+
+// Selector returns the Objective-C selector with the given name.
+func Selector(name string) uintptr {
+	p, err := CString(name)
+	if err != nil {
+		return 0
+	}
+	r1, _ := Call(libobjc_trampoline_sel_registerName_addr, uintptr(unsafe.Pointer(p)))
+	runtime.KeepAlive(p)
+	return r1
+}
+
+// Send sends message with given selector and up to four
+// arguments to the receiver, i.e. calls objc_msgSend.
+//
+// Only messages whose arguments and return values are integers or
+// pointers are supported.
+func (id ID) Send(selector string, args ...uintptr) uintptr {
+	r1, _ := Call(libobjc_trampoline_objc_msgSend_addr,
+		append([]uintptr{uintptr(id), Selector(selector)}, args...)...)
+	return r1
+}
+
+// WithAutoreleasePool runs fn within an autorelease pool on a locked
+// OS thread, so that autoreleased objects are released when it returns.
+func WithAutoreleasePool(fn func()) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	pool, _ := Call(libobjc_trampoline_objc_autoreleasePoolPush_addr)
+	defer Call(libobjc_trampoline_objc_autoreleasePoolPop_addr, pool) //nolint:errcheck // returns void.
+	fn()
+}
+
+// NSString returns an NSString (CFStringRef) with the contents of s.
+// Caller must release returned string with [Release].
+// Zero is returned if s contains NUL bytes.
+func NSString(s string) ID {
+	p, err := CString(s)
+	if err != nil {
+		return 0
+	}
+	r1, _ := Call(libcf_trampoline_CFStringCreateWithCString_addr,
+		0, uintptr(unsafe.Pointer(p)), kCFStringEncodingUTF8)
+	runtime.KeepAlive(p)
+	return ID(r1)
+}
+
+// GoStringFromNSString returns go string from NSString.
+func GoStringFromNSString(s ID) string {
+	if s == 0 {
+		return ""
+	}
+	return GoString(s.Send("UTF8String"))
+}
+
+// Release releases a CoreFoundation or Objective-C object.
+// It is a no-op if id is zero.
+func Release(id ID) {
+	if id != 0 {
+		Call(libcf_trampoline_CFRelease_addr, uintptr(id)) //nolint:errcheck // returns void.
+	}
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"encoding/binary"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_os_log_create os_log_create "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_os_log_create_addr uintptr
+
+//go:cgo_import_dynamic libc_os_log_type_enabled os_log_type_enabled "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_os_log_type_enabled_addr uintptr
+
+//go:cgo_import_dynamic libc__os_log_impl _os_log_impl "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline__os_log_impl_addr uintptr
+
+//go:cgo_import_dynamic libc__dyld_get_image_header _dyld_get_image_header "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline__dyld_get_image_header_addr uintptr
+
+// OSLogType is os_log_type_t.
+type OSLogType uint8
+
+// Log types from os/log.h.
+const (
+	OSLogTypeDefault OSLogType = 0x00
+	OSLogTypeInfo    OSLogType = 0x01
+	OSLogTypeDebug   OSLogType = 0x02
+	OSLogTypeError   OSLogType = 0x10
+	OSLogTypeFault   OSLogType = 0x11
+)
+
+// Format strings passed to _os_log_impl. These must be NUL terminated
+// and must reside in the main executable, as unified logging stores
+// offset of the format string relative to the image passed as dso.
+const (
+	oslogFormatPublic  = "%{public}s\x00"
+	oslogFormatPrivate = "%{public}s %{private}s\x00"
+)
+
+// Descriptors of os_log buffer. Summary byte indicates whether buffer
+// has private and non-scalar arguments. Argument descriptor has type
+// of the argument in upper nibble and its privacy in lower nibble.
+const (
+	oslogSummaryPrivate   = 0x01
+	oslogSummaryNonScalar = 0x02
+	oslogArgString        = 0x20
+	oslogArgPrivate       = 0x01
+	oslogArgPublic        = 0x02
+)
+
+// OSLog is os_log_t. Logs are never released, thus
+// callers should cache them.
+type OSLog uintptr
+
+// OSLogCreate creates a log for the subsystem and category.
+// Zero is returned if subsystem or category contain NUL bytes.
+func OSLogCreate(subsystem, category string) OSLog {
+	s, err := CString(subsystem)
+	if err != nil {
+		return 0
+	}
+	c, err := CString(category)
+	if err != nil {
+		return 0
+	}
+	r1, _ := Call(libc_trampoline_os_log_create_addr,
+		uintptr(unsafe.Pointer(s)), uintptr(unsafe.Pointer(c)))
+	runtime.KeepAlive(s)
+	runtime.KeepAlive(c)
+	return OSLog(r1)
+}
+
+// Enabled reports whether messages of the given type are enabled for the log.
+func (l OSLog) Enabled(t OSLogType) bool {
+	r1, _ := Call(libc_trampoline_os_log_type_enabled_addr, uintptr(l), uintptr(t))
+	return uint8(r1) != 0
+}
+
+// Log writes message to the log, with public and optional private parts.
+// Private part is redacted unless private data logging is enabled.
+// Messages containing NUL bytes are truncated at the first NUL byte.
+func (l OSLog) Log(t OSLogType, public, private string) {
+	pub := cstringTruncate(public)
+	var priv []byte
+	if private != "" {
+		priv = cstringTruncate(private)
+	}
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	pinner.Pin(&pub[0])
+
+	format := oslogFormatPublic
+	buf := []byte{oslogSummaryNonScalar, 1}
+	buf = appendOSLogArg(buf, oslogArgString|oslogArgPublic, &pub[0])
+	if priv != nil {
+		pinner.Pin(&priv[0])
+		format = oslogFormatPrivate
+		buf[0] |= oslogSummaryPrivate
+		buf[1] = 2
+		buf = appendOSLogArg(buf, oslogArgString|oslogArgPrivate, &priv[0])
+	}
+	pinner.Pin(&buf[0])
+
+	dso, _ := Call(libc_trampoline__dyld_get_image_header_addr, 0)
+	Call(libc_trampoline__os_log_impl_addr, //nolint:errcheck // returns void.
+		dso,
+		uintptr(l),
+		uintptr(t),
+		uintptr(unsafe.Pointer(unsafe.StringData(format))),
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(len(buf)),
+	)
+	runtime.KeepAlive(pub)
+	runtime.KeepAlive(priv)
+	runtime.KeepAlive(buf)
+}
+
+// appendOSLogArg appends a pointer argument with the descriptor to buf.
+func appendOSLogArg(buf []byte, desc byte, p *byte) []byte {
+	buf = append(buf, desc, 8)
+	return binary.LittleEndian.AppendUint64(buf, uint64(uintptr(unsafe.Pointer(p))))
+}
+
+// cstringTruncate returns NUL terminated copy of s,
+// truncated at the first NUL byte in s, if any.
+func cstringTruncate(s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 {
+			s = s[:i]
+			break
+		}
+	}
+	b := make([]byte, len(s)+1)
+	copy(b, s)
+	return b
+}
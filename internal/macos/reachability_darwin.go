@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libsc_SCNetworkReachabilityCreateWithName SCNetworkReachabilityCreateWithName "/System/Library/Frameworks/SystemConfiguration.framework/Versions/A/SystemConfiguration"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsc_trampoline_SCNetworkReachabilityCreateWithName_addr uintptr
+
+//go:cgo_import_dynamic libsc_SCNetworkReachabilityCreateWithAddress SCNetworkReachabilityCreateWithAddress "/System/Library/Frameworks/SystemConfiguration.framework/Versions/A/SystemConfiguration"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsc_trampoline_SCNetworkReachabilityCreateWithAddress_addr uintptr
+
+//go:cgo_import_dynamic libsc_SCNetworkReachabilityGetFlags SCNetworkReachabilityGetFlags "/System/Library/Frameworks/SystemConfiguration.framework/Versions/A/SystemConfiguration"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsc_trampoline_SCNetworkReachabilityGetFlags_addr uintptr
+
+// NotifyNetworkChange is kNotifySCNetworkChange from notify_keys.h, which
+// is posted by configd whenever network configuration changes.
+const NotifyNetworkChange = "com.apple.system.config.network_change"
+
+// ReachabilityFlags returns SCNetworkReachabilityFlags of the host.
+// If host is empty, flags of the default route, i.e. of the
+// address 0.0.0.0, are returned.
+func ReachabilityFlags(host string) (uint32, error) {
+	var ref uintptr
+	if host == "" {
+		// struct sockaddr_in with sin_len and sin_family set.
+		var sa [syscall.SizeofSockaddrInet4]byte
+		sa[0] = syscall.SizeofSockaddrInet4
+		sa[1] = syscall.AF_INET
+		ref, _ = Call(libsc_trampoline_SCNetworkReachabilityCreateWithAddress_addr,
+			0, uintptr(unsafe.Pointer(&sa[0])))
+		runtime.KeepAlive(&sa)
+	} else {
+		p, err := CString(host)
+		if err != nil {
+			return 0, fmt.Errorf("macos: invalid host(%q): %w", host, syscall.EINVAL)
+		}
+		ref, _ = Call(libsc_trampoline_SCNetworkReachabilityCreateWithName_addr,
+			0, uintptr(unsafe.Pointer(p)))
+		runtime.KeepAlive(p)
+	}
+	if ref == 0 {
+		return 0, fmt.Errorf("macos: failed to create reachability reference for host(%q)", host)
+	}
+	defer Release(ID(ref))
+
+	var flags uint32
+	var pinner runtime.Pinner
+	pinner.Pin(&flags)
+	defer pinner.Unpin()
+
+	// Boolean SCNetworkReachabilityGetFlags(SCNetworkReachabilityRef target,
+	//     SCNetworkReachabilityFlags *flags);
+	r1, _ := Call(libsc_trampoline_SCNetworkReachabilityGetFlags_addr,
+		ref, uintptr(unsafe.Pointer(&flags)))
+	if uint8(r1) == 0 {
+		return 0, fmt.Errorf("macos: failed to get reachability flags for host(%q)", host)
+	}
+	return flags, nil
+}
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libsecurity_AuthorizationCreate AuthorizationCreate "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_AuthorizationCreate_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_AuthorizationCopyRights AuthorizationCopyRights "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_AuthorizationCopyRights_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_AuthorizationMakeExternalForm AuthorizationMakeExternalForm "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_AuthorizationMakeExternalForm_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_AuthorizationCreateFromExternalForm AuthorizationCreateFromExternalForm "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_AuthorizationCreateFromExternalForm_addr uintptr
+
+//go:cgo_import_dynamic libsecurity_AuthorizationFree AuthorizationFree "/System/Library/Frameworks/Security.framework/Versions/A/Security"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsecurity_trampoline_AuthorizationFree_addr uintptr
+
+// OSStatus is an error code returned by macOS frameworks.
+type OSStatus int32
+
+// Error implements error interface.
+func (s OSStatus) Error() string {
+	return fmt.Sprintf("OSStatus(%d)", int32(s))
+}
+
+// Authorization flags from Authorization.h.
+const (
+	AuthorizationFlagInteractionAllowed = 1 << 0
+	AuthorizationFlagExtendRights       = 1 << 1
+	AuthorizationFlagPartialRights      = 1 << 2
+	AuthorizationFlagDestroyRights      = 1 << 3
+	AuthorizationFlagPreAuthorize       = 1 << 4
+)
+
+// Authorization errors from Authorization.h.
+const (
+	ErrAuthorizationInvalidSet            OSStatus = -60001
+	ErrAuthorizationInvalidRef            OSStatus = -60002
+	ErrAuthorizationInvalidTag            OSStatus = -60003
+	ErrAuthorizationInvalidPointer        OSStatus = -60004
+	ErrAuthorizationDenied                OSStatus = -60005
+	ErrAuthorizationCanceled              OSStatus = -60006
+	ErrAuthorizationInteractionNotAllowed OSStatus = -60007
+	ErrAuthorizationInternal              OSStatus = -60008
+	ErrAuthorizationExternalizeNotAllowed OSStatus = -60009
+	ErrAuthorizationInternalizeNotAllowed OSStatus = -60010
+	ErrAuthorizationInvalidFlags          OSStatus = -60011
+	ErrAuthorizationToolExecuteFailure    OSStatus = -60031
+	ErrAuthorizationToolEnvironmentError  OSStatus = -60032
+	ErrAuthorizationBadAddress            OSStatus = -60033
+)
+
+// AuthorizationRef is a reference to an authorization session.
+type AuthorizationRef uintptr
+
+// authorizationItem is AuthorizationItem from Authorization.h.
+type authorizationItem struct {
+	name        *byte
+	valueLength uintptr
+	value       uintptr
+	flags       uint32
+}
+
+// authorizationRights is AuthorizationRights from Authorization.h.
+type authorizationRights struct {
+	count uint32
+	items *authorizationItem
+}
+
+// authorizationRightsSet returns AuthorizationRights for rights, pinned with pinner.
+// Nil is returned if rights is empty.
+func authorizationRightsSet(pinner *runtime.Pinner, rights []string) (*authorizationRights, error) {
+	if len(rights) == 0 {
+		return nil, nil
+	}
+
+	items := make([]authorizationItem, len(rights))
+	for i, right := range rights {
+		name, err := CString(right)
+		if err != nil {
+			return nil, fmt.Errorf("macos: invalid right(%q): %w", right, err)
+		}
+		pinner.Pin(name)
+		items[i].name = name
+	}
+	pinner.Pin(&items[0])
+	set := &authorizationRights{count: uint32(len(items)), items: &items[0]}
+	pinner.Pin(set)
+	return set, nil
+}
+
+// AuthorizationCreate creates an authorization session, optionally
+// obtaining the given rights.
+func AuthorizationCreate(rights []string, flags uint32) (AuthorizationRef, error) {
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+
+	set, err := authorizationRightsSet(&pinner, rights)
+	if err != nil {
+		return 0, err
+	}
+
+	var ref AuthorizationRef
+	pinner.Pin(&ref)
+
+	r1, _ := Call(libsecurity_trampoline_AuthorizationCreate_addr,
+		uintptr(unsafe.Pointer(set)),
+		0,
+		uintptr(flags),
+		uintptr(unsafe.Pointer(&ref)),
+	)
+	if status := OSStatus(int32(r1)); status != 0 {
+		return 0, status
+	}
+	return ref, nil
+}
+
+// AuthorizationCopyRights obtains the given rights for the authorization
+// session. Granted rights are not returned, thus callers must not use
+// [AuthorizationFlagPartialRights].
+func AuthorizationCopyRights(ref AuthorizationRef, rights []string, flags uint32) error {
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+
+	set, err := authorizationRightsSet(&pinner, rights)
+	if err != nil {
+		return err
+	}
+	if set == nil {
+		return ErrAuthorizationInvalidSet
+	}
+
+	// AuthorizationCopyRights(AuthorizationRef authorization, const AuthorizationRights *rights,
+	//   const AuthorizationEnvironment *environment, AuthorizationFlags flags,
+	//   AuthorizationRights **authorizedRights)
+	r1, _ := Call(libsecurity_trampoline_AuthorizationCopyRights_addr,
+		uintptr(ref),
+		uintptr(unsafe.Pointer(set)),
+		0,
+		uintptr(flags),
+		0,
+	)
+	if status := OSStatus(int32(r1)); status != 0 {
+		return status
+	}
+	return nil
+}
+
+// AuthorizationExternalForm is AuthorizationExternalForm from Authorization.h.
+type AuthorizationExternalForm [32]byte
+
+// AuthorizationMakeExternalForm returns external form of the authorization
+// session, which can be sent to another process.
+func AuthorizationMakeExternalForm(ref AuthorizationRef) (AuthorizationExternalForm, error) {
+	var ext AuthorizationExternalForm
+	var pinner runtime.Pinner
+	pinner.Pin(&ext)
+	defer pinner.Unpin()
+
+	r1, _ := Call(libsecurity_trampoline_AuthorizationMakeExternalForm_addr,
+		uintptr(ref), uintptr(unsafe.Pointer(&ext)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return AuthorizationExternalForm{}, status
+	}
+	return ext, nil
+}
+
+// AuthorizationCreateFromExternalForm returns authorization session
+// from its external form.
+func AuthorizationCreateFromExternalForm(ext AuthorizationExternalForm) (AuthorizationRef, error) {
+	var ref AuthorizationRef
+	var pinner runtime.Pinner
+	pinner.Pin(&ext)
+	pinner.Pin(&ref)
+	defer pinner.Unpin()
+
+	r1, _ := Call(libsecurity_trampoline_AuthorizationCreateFromExternalForm_addr,
+		uintptr(unsafe.Pointer(&ext)), uintptr(unsafe.Pointer(&ref)))
+	if status := OSStatus(int32(r1)); status != 0 {
+		return 0, status
+	}
+	return ref, nil
+}
+
+// AuthorizationFree frees the authorization session.
+func AuthorizationFree(ref AuthorizationRef, flags uint32) {
+	if ref != 0 {
+		Call(libsecurity_trampoline_AuthorizationFree_addr, uintptr(ref), uintptr(flags)) //nolint:errcheck // ignore
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libsm_SMJobBless SMJobBless "/System/Library/Frameworks/ServiceManagement.framework/Versions/A/ServiceManagement"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libsm_trampoline_SMJobBless_addr uintptr
+
+// RightBlessPrivilegedHelper is kSMRightBlessPrivilegedHelper.
+const RightBlessPrivilegedHelper = "com.apple.ServiceManagement.blesshelper"
+
+// CFError is an error returned as CFErrorRef.
+type CFError struct {
+	Code    int
+	Message string
+}
+
+// Error implements error interface.
+func (e *CFError) Error() string {
+	return fmt.Sprintf("%s(code=%d)", e.Message, e.Code)
+}
+
+// newCFError builds [*CFError] from CFErrorRef and releases it.
+func newCFError(ref ID) *CFError {
+	if ref == 0 {
+		return &CFError{Message: "unknown error"}
+	}
+	defer Release(ref)
+
+	var e CFError
+	WithAutoreleasePool(func() {
+		e.Code = int(ref.Send("code"))
+		e.Message = GoStringFromNSString(ID(ref.Send("localizedDescription")))
+	})
+	return &e
+}
+
+// SMJobBless installs the privileged helper tool with the given
+// label, which must be bundled within the main application bundle.
+func SMJobBless(label string, auth AuthorizationRef) error {
+	domain := Pointer(Symbol("kSMDomainSystemLaunchd"))
+	if domain == 0 {
+		return fmt.Errorf("macos: kSMDomainSystemLaunchd is not available")
+	}
+
+	name := NSString(label)
+	if name == 0 {
+		return fmt.Errorf("macos: invalid label(%q)", label)
+	}
+	defer Release(name)
+
+	var cferr ID
+	var pinner runtime.Pinner
+	pinner.Pin(&cferr)
+	defer pinner.Unpin()
+
+	r1, _ := Call(libsm_trampoline_SMJobBless_addr,
+		domain,
+		uintptr(name),
+		uintptr(auth),
+		uintptr(unsafe.Pointer(&cferr)),
+	)
+
+	// Boolean is unsigned char.
+	if r1&0xff == 0 {
+		return newCFError(cferr)
+	}
+	return nil
+}
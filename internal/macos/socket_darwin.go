@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_getsockopt getsockopt "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_getsockopt_addr uintptr
+
+// Socket options for unix domain sockets from sys/un.h.
+const (
+	SolLocal       = 0
+	LocalPeerCred  = 1
+	LocalPeerPID   = 2
+	LocalPeerEPID  = 3
+	LocalPeerToken = 6
+)
+
+// Version and number of groups of struct xucred from sys/ucred.h.
+const (
+	XucredVersion = 0
+	XucredNGroups = 16
+)
+
+// Xucred is struct xucred from sys/ucred.h.
+type Xucred struct {
+	Version uint32
+	UID     uint32
+	NGroups int16
+	Groups  [XucredNGroups]uint32
+}
+
+// Getsockopt calls getsockopt(2) on socket fd, with value of size n at val.
+// It returns the size of the value set by the kernel.
+func Getsockopt(fd uintptr, level, name int, val unsafe.Pointer, n uint32) (uint32, error) {
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	pinner.Pin(val)
+	pinner.Pin(&n)
+
+	r1, errno := Call(
+		libc_trampoline_getsockopt_addr,
+		fd,
+		uintptr(level),
+		uintptr(name),
+		uintptr(val),
+		uintptr(unsafe.Pointer(&n)),
+	)
+	if int32(r1) == -1 {
+		return 0, errno
+	}
+	return n, nil
+}
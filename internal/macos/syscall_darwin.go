@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package macos
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// syscall_syscall is implemented in package [runtime] and pushed to [syscall].
+// See activate_darwin.go in the root package for details.
+//
+//go:linkname syscall_syscall syscall.syscall
+//nolint:revive // for linkname
+func syscall_syscall(fn, a1, a2, a3 uintptr) (r1, r2 uintptr, err syscall.Errno)
+
+// syscall_syscall6 is implemented in package [runtime] and pushed to [syscall].
+// Like syscall_syscall, it is used by [golang.org/x/sys/unix] and is
+// exempted from linkname restrictions.
+//
+//go:linkname syscall_syscall6 syscall.syscall6
+//nolint:revive // for linkname
+func syscall_syscall6(fn, a1, a2, a3, a4, a5, a6 uintptr) (r1, r2 uintptr, err syscall.Errno)
+
+// Call calls C function at address fn with up to six arguments and
+// returns its return value. Errno is only meaningful if function
+// returns -1 and sets errno.
+//
+// Callers must ensure that go pointers passed as arguments are pinned
+// or kept alive until the call returns.
+func Call(fn uintptr, args ...uintptr) (uintptr, syscall.Errno) {
+	var a [6]uintptr
+	if len(args) > len(a) {
+		panic("macos: too many arguments")
+	}
+	copy(a[:], args)
+
+	if len(args) <= 3 {
+		r1, _, e1 := syscall_syscall(fn, a[0], a[1], a[2])
+		return r1, e1
+	}
+	r1, _, e1 := syscall_syscall6(fn, a[0], a[1], a[2], a[3], a[4], a[5])
+	return r1, e1
+}
+
+// CString returns a pointer to NUL terminated copy of s.
+// It returns an error if s contains NUL bytes.
+func CString(s string) (*byte, error) {
+	return syscall.BytePtrFromString(s)
+}
+
+// GoString returns go string from NUL terminated C string at p.
+// Empty string is returned if p is zero.
+func GoString(p uintptr) string {
+	if p == 0 {
+		return ""
+	}
+
+	// Unsafe trick is used to silence govet, as p points to
+	// memory not managed by go runtime.
+	ptr := *(*unsafe.Pointer)(unsafe.Pointer(&p))
+	var n int
+	for *(*byte)(unsafe.Add(ptr, n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice((*byte)(ptr), n))
+}
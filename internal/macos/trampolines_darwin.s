@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+#include "textflag.h"
+
+GLOBL	·libobjc_trampoline_objc_getClass_addr(SB), RODATA, $8
+DATA	·libobjc_trampoline_objc_getClass_addr(SB)/8, $libobjc_trampoline_objc_getClass<>(SB)
+TEXT    libobjc_trampoline_objc_getClass<>(SB),NOSPLIT,$0-0
+            JMP	libobjc_objc_getClass(SB)
+
+GLOBL	·libobjc_trampoline_sel_registerName_addr(SB), RODATA, $8
+DATA	·libobjc_trampoline_sel_registerName_addr(SB)/8, $libobjc_trampoline_sel_registerName<>(SB)
+TEXT    libobjc_trampoline_sel_registerName<>(SB),NOSPLIT,$0-0
+            JMP	libobjc_sel_registerName(SB)
+
+GLOBL	·libobjc_trampoline_objc_msgSend_addr(SB), RODATA, $8
+DATA	·libobjc_trampoline_objc_msgSend_addr(SB)/8, $libobjc_trampoline_objc_msgSend<>(SB)
+TEXT    libobjc_trampoline_objc_msgSend<>(SB),NOSPLIT,$0-0
+            JMP	libobjc_objc_msgSend(SB)
+
+GLOBL	·libobjc_trampoline_objc_autoreleasePoolPush_addr(SB), RODATA, $8
+DATA	·libobjc_trampoline_objc_autoreleasePoolPush_addr(SB)/8, $libobjc_trampoline_objc_autoreleasePoolPush<>(SB)
+TEXT    libobjc_trampoline_objc_autoreleasePoolPush<>(SB),NOSPLIT,$0-0
+            JMP	libobjc_objc_autoreleasePoolPush(SB)
+
+GLOBL	·libobjc_trampoline_objc_autoreleasePoolPop_addr(SB), RODATA, $8
+DATA	·libobjc_trampoline_objc_autoreleasePoolPop_addr(SB)/8, $libobjc_trampoline_objc_autoreleasePoolPop<>(SB)
+TEXT    libobjc_trampoline_objc_autoreleasePoolPop<>(SB),NOSPLIT,$0-0
+            JMP	libobjc_objc_autoreleasePoolPop(SB)
+
+GLOBL	·libcf_trampoline_CFStringCreateWithCString_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFStringCreateWithCString_addr(SB)/8, $libcf_trampoline_CFStringCreateWithCString<>(SB)
+TEXT    libcf_trampoline_CFStringCreateWithCString<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFStringCreateWithCString(SB)
+
+GLOBL	·libcf_trampoline_CFRelease_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFRelease_addr(SB)/8, $libcf_trampoline_CFRelease<>(SB)
+TEXT    libcf_trampoline_CFRelease<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFRelease(SB)
+
+GLOBL	·libc_trampoline_dlsym_addr(SB), RODATA, $8
+DATA	·libc_trampoline_dlsym_addr(SB)/8, $libc_trampoline_dlsym<>(SB)
+TEXT    libc_trampoline_dlsym<>(SB),NOSPLIT,$0-0
+            JMP	libc_dlsym(SB)
+
+GLOBL	·libc_trampoline_dlopen_addr(SB), RODATA, $8
+DATA	·libc_trampoline_dlopen_addr(SB)/8, $libc_trampoline_dlopen<>(SB)
+TEXT    libc_trampoline_dlopen<>(SB),NOSPLIT,$0-0
+            JMP	libc_dlopen(SB)
+
+GLOBL	·libsecurity_trampoline_AuthorizationCreate_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_AuthorizationCreate_addr(SB)/8, $libsecurity_trampoline_AuthorizationCreate<>(SB)
+TEXT    libsecurity_trampoline_AuthorizationCreate<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_AuthorizationCreate(SB)
+
+GLOBL	·libsecurity_trampoline_AuthorizationFree_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_AuthorizationFree_addr(SB)/8, $libsecurity_trampoline_AuthorizationFree<>(SB)
+TEXT    libsecurity_trampoline_AuthorizationFree<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_AuthorizationFree(SB)
+
+GLOBL	·libsm_trampoline_SMJobBless_addr(SB), RODATA, $8
+DATA	·libsm_trampoline_SMJobBless_addr(SB)/8, $libsm_trampoline_SMJobBless<>(SB)
+TEXT    libsm_trampoline_SMJobBless<>(SB),NOSPLIT,$0-0
+            JMP	libsm_SMJobBless(SB)
+
+GLOBL	·libiokit_trampoline_IOPMAssertionCreateWithName_addr(SB), RODATA, $8
+DATA	·libiokit_trampoline_IOPMAssertionCreateWithName_addr(SB)/8, $libiokit_trampoline_IOPMAssertionCreateWithName<>(SB)
+TEXT    libiokit_trampoline_IOPMAssertionCreateWithName<>(SB),NOSPLIT,$0-0
+            JMP	libiokit_IOPMAssertionCreateWithName(SB)
+
+GLOBL	·libiokit_trampoline_IOPMAssertionRelease_addr(SB), RODATA, $8
+DATA	·libiokit_trampoline_IOPMAssertionRelease_addr(SB)/8, $libiokit_trampoline_IOPMAssertionRelease<>(SB)
+TEXT    libiokit_trampoline_IOPMAssertionRelease<>(SB),NOSPLIT,$0-0
+            JMP	libiokit_IOPMAssertionRelease(SB)
+
+GLOBL	·libc_trampoline_os_log_create_addr(SB), RODATA, $8
+DATA	·libc_trampoline_os_log_create_addr(SB)/8, $libc_trampoline_os_log_create<>(SB)
+TEXT    libc_trampoline_os_log_create<>(SB),NOSPLIT,$0-0
+            JMP	libc_os_log_create(SB)
+
+GLOBL	·libc_trampoline_os_log_type_enabled_addr(SB), RODATA, $8
+DATA	·libc_trampoline_os_log_type_enabled_addr(SB)/8, $libc_trampoline_os_log_type_enabled<>(SB)
+TEXT    libc_trampoline_os_log_type_enabled<>(SB),NOSPLIT,$0-0
+            JMP	libc_os_log_type_enabled(SB)
+
+GLOBL	·libc_trampoline__os_log_impl_addr(SB), RODATA, $8
+DATA	·libc_trampoline__os_log_impl_addr(SB)/8, $libc_trampoline__os_log_impl<>(SB)
+TEXT    libc_trampoline__os_log_impl<>(SB),NOSPLIT,$0-0
+            JMP	libc__os_log_impl(SB)
+
+GLOBL	·libc_trampoline__dyld_get_image_header_addr(SB), RODATA, $8
+DATA	·libc_trampoline__dyld_get_image_header_addr(SB)/8, $libc_trampoline__dyld_get_image_header<>(SB)
+TEXT    libc_trampoline__dyld_get_image_header<>(SB),NOSPLIT,$0-0
+            JMP	libc__dyld_get_image_header(SB)
+
+GLOBL	·libc_trampoline_notify_post_addr(SB), RODATA, $8
+DATA	·libc_trampoline_notify_post_addr(SB)/8, $libc_trampoline_notify_post<>(SB)
+TEXT    libc_trampoline_notify_post<>(SB),NOSPLIT,$0-0
+            JMP	libc_notify_post(SB)
+
+GLOBL	·libc_trampoline_notify_register_file_descriptor_addr(SB), RODATA, $8
+DATA	·libc_trampoline_notify_register_file_descriptor_addr(SB)/8, $libc_trampoline_notify_register_file_descriptor<>(SB)
+TEXT    libc_trampoline_notify_register_file_descriptor<>(SB),NOSPLIT,$0-0
+            JMP	libc_notify_register_file_descriptor(SB)
+
+GLOBL	·libc_trampoline_notify_cancel_addr(SB), RODATA, $8
+DATA	·libc_trampoline_notify_cancel_addr(SB)/8, $libc_trampoline_notify_cancel<>(SB)
+TEXT    libc_trampoline_notify_cancel<>(SB),NOSPLIT,$0-0
+            JMP	libc_notify_cancel(SB)
+
+GLOBL	·libc_trampoline_getsockopt_addr(SB), RODATA, $8
+DATA	·libc_trampoline_getsockopt_addr(SB)/8, $libc_trampoline_getsockopt<>(SB)
+TEXT    libc_trampoline_getsockopt<>(SB),NOSPLIT,$0-0
+            JMP	libc_getsockopt(SB)
+
+GLOBL	·libcf_trampoline_CFDataCreate_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFDataCreate_addr(SB)/8, $libcf_trampoline_CFDataCreate<>(SB)
+TEXT    libcf_trampoline_CFDataCreate<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFDataCreate(SB)
+
+GLOBL	·libcf_trampoline_CFDictionaryCreate_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFDictionaryCreate_addr(SB)/8, $libcf_trampoline_CFDictionaryCreate<>(SB)
+TEXT    libcf_trampoline_CFDictionaryCreate<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFDictionaryCreate(SB)
+
+GLOBL	·libsecurity_trampoline_SecCodeCopyGuestWithAttributes_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecCodeCopyGuestWithAttributes_addr(SB)/8, $libsecurity_trampoline_SecCodeCopyGuestWithAttributes<>(SB)
+TEXT    libsecurity_trampoline_SecCodeCopyGuestWithAttributes<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecCodeCopyGuestWithAttributes(SB)
+
+GLOBL	·libsecurity_trampoline_SecRequirementCreateWithString_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecRequirementCreateWithString_addr(SB)/8, $libsecurity_trampoline_SecRequirementCreateWithString<>(SB)
+TEXT    libsecurity_trampoline_SecRequirementCreateWithString<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecRequirementCreateWithString(SB)
+
+GLOBL	·libsecurity_trampoline_SecCodeCheckValidity_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecCodeCheckValidity_addr(SB)/8, $libsecurity_trampoline_SecCodeCheckValidity<>(SB)
+TEXT    libsecurity_trampoline_SecCodeCheckValidity<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecCodeCheckValidity(SB)
+
+GLOBL	·libcf_trampoline_CFDictionaryGetValue_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFDictionaryGetValue_addr(SB)/8, $libcf_trampoline_CFDictionaryGetValue<>(SB)
+TEXT    libcf_trampoline_CFDictionaryGetValue<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFDictionaryGetValue(SB)
+
+GLOBL	·libsecurity_trampoline_SecCodeCopySigningInformation_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecCodeCopySigningInformation_addr(SB)/8, $libsecurity_trampoline_SecCodeCopySigningInformation<>(SB)
+TEXT    libsecurity_trampoline_SecCodeCopySigningInformation<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecCodeCopySigningInformation(SB)
+
+GLOBL	·libcf_trampoline_CFDataGetBytePtr_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFDataGetBytePtr_addr(SB)/8, $libcf_trampoline_CFDataGetBytePtr<>(SB)
+TEXT    libcf_trampoline_CFDataGetBytePtr<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFDataGetBytePtr(SB)
+
+GLOBL	·libcf_trampoline_CFDataGetLength_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFDataGetLength_addr(SB)/8, $libcf_trampoline_CFDataGetLength<>(SB)
+TEXT    libcf_trampoline_CFDataGetLength<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFDataGetLength(SB)
+
+GLOBL	·libsecurity_trampoline_SecItemCopyMatching_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecItemCopyMatching_addr(SB)/8, $libsecurity_trampoline_SecItemCopyMatching<>(SB)
+TEXT    libsecurity_trampoline_SecItemCopyMatching<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecItemCopyMatching(SB)
+
+GLOBL	·libsecurity_trampoline_SecIdentityCopyCertificate_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecIdentityCopyCertificate_addr(SB)/8, $libsecurity_trampoline_SecIdentityCopyCertificate<>(SB)
+TEXT    libsecurity_trampoline_SecIdentityCopyCertificate<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecIdentityCopyCertificate(SB)
+
+GLOBL	·libsecurity_trampoline_SecIdentityCopyPrivateKey_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecIdentityCopyPrivateKey_addr(SB)/8, $libsecurity_trampoline_SecIdentityCopyPrivateKey<>(SB)
+TEXT    libsecurity_trampoline_SecIdentityCopyPrivateKey<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecIdentityCopyPrivateKey(SB)
+
+GLOBL	·libsecurity_trampoline_SecCertificateCopyData_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecCertificateCopyData_addr(SB)/8, $libsecurity_trampoline_SecCertificateCopyData<>(SB)
+TEXT    libsecurity_trampoline_SecCertificateCopyData<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecCertificateCopyData(SB)
+
+GLOBL	·libsecurity_trampoline_SecKeyCreateSignature_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecKeyCreateSignature_addr(SB)/8, $libsecurity_trampoline_SecKeyCreateSignature<>(SB)
+TEXT    libsecurity_trampoline_SecKeyCreateSignature<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecKeyCreateSignature(SB)
+
+GLOBL	·libsecurity_trampoline_AuthorizationCopyRights_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_AuthorizationCopyRights_addr(SB)/8, $libsecurity_trampoline_AuthorizationCopyRights<>(SB)
+TEXT    libsecurity_trampoline_AuthorizationCopyRights<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_AuthorizationCopyRights(SB)
+
+GLOBL	·libsecurity_trampoline_AuthorizationMakeExternalForm_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_AuthorizationMakeExternalForm_addr(SB)/8, $libsecurity_trampoline_AuthorizationMakeExternalForm<>(SB)
+TEXT    libsecurity_trampoline_AuthorizationMakeExternalForm<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_AuthorizationMakeExternalForm(SB)
+
+GLOBL	·libsecurity_trampoline_AuthorizationCreateFromExternalForm_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_AuthorizationCreateFromExternalForm_addr(SB)/8, $libsecurity_trampoline_AuthorizationCreateFromExternalForm<>(SB)
+TEXT    libsecurity_trampoline_AuthorizationCreateFromExternalForm<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_AuthorizationCreateFromExternalForm(SB)
+
+GLOBL	·libsecurity_trampoline_SecTaskCreateFromSelf_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecTaskCreateFromSelf_addr(SB)/8, $libsecurity_trampoline_SecTaskCreateFromSelf<>(SB)
+TEXT    libsecurity_trampoline_SecTaskCreateFromSelf<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecTaskCreateFromSelf(SB)
+
+GLOBL	·libsecurity_trampoline_SecTaskCopyValueForEntitlement_addr(SB), RODATA, $8
+DATA	·libsecurity_trampoline_SecTaskCopyValueForEntitlement_addr(SB)/8, $libsecurity_trampoline_SecTaskCopyValueForEntitlement<>(SB)
+TEXT    libsecurity_trampoline_SecTaskCopyValueForEntitlement<>(SB),NOSPLIT,$0-0
+            JMP	libsecurity_SecTaskCopyValueForEntitlement(SB)
+
+GLOBL	·libcf_trampoline_CFGetTypeID_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFGetTypeID_addr(SB)/8, $libcf_trampoline_CFGetTypeID<>(SB)
+TEXT    libcf_trampoline_CFGetTypeID<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFGetTypeID(SB)
+
+GLOBL	·libcf_trampoline_CFBooleanGetTypeID_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFBooleanGetTypeID_addr(SB)/8, $libcf_trampoline_CFBooleanGetTypeID<>(SB)
+TEXT    libcf_trampoline_CFBooleanGetTypeID<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFBooleanGetTypeID(SB)
+
+GLOBL	·libcf_trampoline_CFBooleanGetValue_addr(SB), RODATA, $8
+DATA	·libcf_trampoline_CFBooleanGetValue_addr(SB)/8, $libcf_trampoline_CFBooleanGetValue<>(SB)
+TEXT    libcf_trampoline_CFBooleanGetValue<>(SB),NOSPLIT,$0-0
+            JMP	libcf_CFBooleanGetValue(SB)
+
+GLOBL	·libsc_trampoline_SCNetworkReachabilityCreateWithName_addr(SB), RODATA, $8
+DATA	·libsc_trampoline_SCNetworkReachabilityCreateWithName_addr(SB)/8, $libsc_trampoline_SCNetworkReachabilityCreateWithName<>(SB)
+TEXT    libsc_trampoline_SCNetworkReachabilityCreateWithName<>(SB),NOSPLIT,$0-0
+            JMP	libsc_SCNetworkReachabilityCreateWithName(SB)
+
+GLOBL	·libsc_trampoline_SCNetworkReachabilityCreateWithAddress_addr(SB), RODATA, $8
+DATA	·libsc_trampoline_SCNetworkReachabilityCreateWithAddress_addr(SB)/8, $libsc_trampoline_SCNetworkReachabilityCreateWithAddress<>(SB)
+TEXT    libsc_trampoline_SCNetworkReachabilityCreateWithAddress<>(SB),NOSPLIT,$0-0
+            JMP	libsc_SCNetworkReachabilityCreateWithAddress(SB)
+
+GLOBL	·libsc_trampoline_SCNetworkReachabilityGetFlags_addr(SB), RODATA, $8
+DATA	·libsc_trampoline_SCNetworkReachabilityGetFlags_addr(SB)/8, $libsc_trampoline_SCNetworkReachabilityGetFlags<>(SB)
+TEXT    libsc_trampoline_SCNetworkReachabilityGetFlags<>(SB),NOSPLIT,$0-0
+            JMP	libsc_SCNetworkReachabilityGetFlags(SB)
+
+GLOBL	·libc_trampoline_task_self_trap_addr(SB), RODATA, $8
+DATA	·libc_trampoline_task_self_trap_addr(SB)/8, $libc_trampoline_task_self_trap<>(SB)
+TEXT    libc_trampoline_task_self_trap<>(SB),NOSPLIT,$0-0
+            JMP	libc_task_self_trap(SB)
+
+GLOBL	·libc_trampoline_task_get_special_port_addr(SB), RODATA, $8
+DATA	·libc_trampoline_task_get_special_port_addr(SB)/8, $libc_trampoline_task_get_special_port<>(SB)
+TEXT    libc_trampoline_task_get_special_port<>(SB),NOSPLIT,$0-0
+            JMP	libc_task_get_special_port(SB)
+
+GLOBL	·libc_trampoline_bootstrap_look_up_addr(SB), RODATA, $8
+DATA	·libc_trampoline_bootstrap_look_up_addr(SB)/8, $libc_trampoline_bootstrap_look_up<>(SB)
+TEXT    libc_trampoline_bootstrap_look_up<>(SB),NOSPLIT,$0-0
+            JMP	libc_bootstrap_look_up(SB)
+
+GLOBL	·libc_trampoline_mach_port_deallocate_addr(SB), RODATA, $8
+DATA	·libc_trampoline_mach_port_deallocate_addr(SB)/8, $libc_trampoline_mach_port_deallocate<>(SB)
+TEXT    libc_trampoline_mach_port_deallocate<>(SB),NOSPLIT,$0-0
+            JMP	libc_mach_port_deallocate(SB)
+
+GLOBL	·libc_trampoline_DNSServiceRegister_addr(SB), RODATA, $8
+DATA	·libc_trampoline_DNSServiceRegister_addr(SB)/8, $libc_trampoline_DNSServiceRegister<>(SB)
+TEXT    libc_trampoline_DNSServiceRegister<>(SB),NOSPLIT,$0-0
+            JMP	libc_DNSServiceRegister(SB)
+
+GLOBL	·libc_trampoline_DNSServiceUpdateRecord_addr(SB), RODATA, $8
+DATA	·libc_trampoline_DNSServiceUpdateRecord_addr(SB)/8, $libc_trampoline_DNSServiceUpdateRecord<>(SB)
+TEXT    libc_trampoline_DNSServiceUpdateRecord<>(SB),NOSPLIT,$0-0
+            JMP	libc_DNSServiceUpdateRecord(SB)
+
+GLOBL	·libc_trampoline_DNSServiceRefDeallocate_addr(SB), RODATA, $8
+DATA	·libc_trampoline_DNSServiceRefDeallocate_addr(SB)/8, $libc_trampoline_DNSServiceRefDeallocate<>(SB)
+TEXT    libc_trampoline_DNSServiceRefDeallocate<>(SB),NOSPLIT,$0-0
+            JMP	libc_DNSServiceRefDeallocate(SB)
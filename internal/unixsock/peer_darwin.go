@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package unixsock
+
+import (
+	"net"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+// PeerUID returns effective user ID of the peer, using LOCAL_PEERCRED.
+// It reports whether peer credentials are supported.
+func PeerUID(conn net.Conn) (int, bool, error) {
+	creds, err := launchd.PeerCredentials(conn)
+	if err != nil {
+		return 0, false, err
+	}
+	return int(creds.EUID), true, nil
+}
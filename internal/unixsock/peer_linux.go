@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package unixsock
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// PeerUID returns effective user ID of the peer, using SO_PEERCRED.
+// It reports whether peer credentials are supported.
+func PeerUID(conn net.Conn) (int, bool, error) {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return 0, false, fmt.Errorf("connection(%T) is not a socket: %w", conn, syscall.EAFNOSUPPORT)
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return 0, false, err
+	}
+
+	var cred *syscall.Ucred
+	var ep error
+	err = rc.Control(func(fd uintptr) {
+		cred, ep = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	switch {
+	case err != nil:
+		return 0, false, err
+	case ep != nil:
+		return 0, false, fmt.Errorf("error getting peer credentials: %w", os.NewSyscallError("getsockopt", ep))
+	}
+	return int(cred.Uid), true, nil
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !linux && !(darwin && !ios)
+
+package unixsock
+
+import (
+	"net"
+)
+
+// PeerUID is not implemented, thus peers are only restricted by
+// permissions of the socket.
+func PeerUID(_ net.Conn) (int, bool, error) {
+	return 0, false, nil
+}
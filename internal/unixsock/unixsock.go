@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package unixsock implements private unix socket listeners and peer checks
+// shared by the svc control socket and handoff.
+package unixsock
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Listener is a [net.UnixListener] which removes its socket when closed,
+// as it is not bound to its final path.
+type Listener struct {
+	*net.UnixListener
+	path string
+}
+
+// Close closes the listener and removes the socket.
+func (l *Listener) Close() error {
+	err := l.UnixListener.Close()
+	_ = os.Remove(l.path)
+	return err
+}
+
+// Listen listens on unix socket at path, which is only accessible by the
+// owner. Socket is bound in a private temporary directory next to path,
+// made accessible by the owner only and then linked to path, so that
+// there is no window in which other users can connect to it.
+func Listen(path string) (*Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".sock-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "s")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmp, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	l.SetUnlinkOnClose(false)
+
+	if err = os.Chmod(tmp, 0o600); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if err = os.Link(tmp, path); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	return &Listener{UnixListener: l, path: path}, nil
+}
+
+// CheckPeer rejects peers which are neither running as the same user as
+// the current process, nor as root. On platforms where peer credentials
+// are not supported, peers are only restricted by permissions of the socket.
+//
+//   - [syscall.EPERM] is returned if peer is not permitted.
+func CheckPeer(conn net.Conn) error {
+	uid, ok, err := PeerUID(conn)
+	if err != nil {
+		return err
+	}
+	if ok && uid != 0 && uid != os.Geteuid() {
+		return fmt.Errorf("peer uid(%d) is not permitted: %w", uid, syscall.EPERM)
+	}
+	return nil
+}
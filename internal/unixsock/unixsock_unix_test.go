@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package unixsock
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.sock")
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		t.Fatalf("expected socket to exist, got=%s", err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		t.Errorf("expected socket, got mode=%s", fi.Mode())
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected permissions=0600, got=%#o", perm)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read directory: %s", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected temporary directory to be removed, got=%v", entries)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("failed to connect: %s", err)
+	}
+	defer conn.Close()
+
+	peer, err := l.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+	defer peer.Close()
+
+	if err = CheckPeer(peer); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+	if err = CheckPeer(conn); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+
+	if err = l.Close(); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+	if _, err = os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected socket to be removed, got=%v", err)
+	}
+}
+
+func TestListen_Exists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.sock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+	if _, err := Listen(path); !errors.Is(err, os.ErrExist) {
+		t.Errorf("expected error=%s, got=%v", os.ErrExist, err)
+	}
+}
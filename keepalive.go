@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+	"time"
+)
+
+// WithTCPKeepAlive sets the keep-alive period of connections accepted by TCP
+// listeners returned by [Open], so that operators can enforce kernel
+// keep-alive behavior without changing every accept site. Keep-alives are
+// disabled if d is negative. If d is zero, which is the default, Go's
+// default keep-alive period is used.
+func WithTCPKeepAlive(d time.Duration) Option {
+	return func(o *options) {
+		o.tcpKeepAlive = d
+	}
+}
+
+// WithAcceptDeadline limits the time each Accept call of TCP listeners
+// returned by [Open] waits for a connection. Accept returns an error
+// wrapping [os.ErrDeadlineExceeded] if no connection is accepted within d.
+// This is useful for on-demand jobs which exit when idle, as launchd
+// starts them again on the next connection. Deadline is disabled if d is
+// not positive, which is the default.
+func WithAcceptDeadline(d time.Duration) Option {
+	return func(o *options) {
+		o.acceptDeadline = d
+	}
+}
+
+// tcpListener is a [*net.TCPListener] with keep-alive and accept deadline.
+type tcpListener struct {
+	*net.TCPListener
+	keepAlive time.Duration
+	deadline  time.Duration
+}
+
+// Accept implements [net.Listener].
+func (l *tcpListener) Accept() (net.Conn, error) {
+	if l.deadline > 0 {
+		if err := l.SetDeadline(time.Now().Add(l.deadline)); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := l.AcceptTCP()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case l.keepAlive < 0:
+		_ = conn.SetKeepAlive(false)
+	case l.keepAlive > 0:
+		_ = conn.SetKeepAlive(true)
+		_ = conn.SetKeepAlivePeriod(l.keepAlive)
+	}
+	return conn, nil
+}
+
+// tune wraps TCP listeners of the result with [tuneListener].
+func (r *Result) tune(o *options) {
+	for i, l := range r.Listeners {
+		r.Listeners[i] = tuneListener(l, o)
+	}
+}
+
+// tuneListener wraps the TCP listener with keep-alive and accept deadline,
+// if configured. Other listeners are returned as is.
+func tuneListener(l net.Listener, o *options) net.Listener {
+	tl, ok := l.(*net.TCPListener)
+	if !ok || (o.tcpKeepAlive == 0 && o.acceptDeadline <= 0) {
+		return l
+	}
+	return &tcpListener{
+		TCPListener: tl,
+		keepAlive:   o.tcpKeepAlive,
+		deadline:    o.acceptDeadline,
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestResult_Tune(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	u, err := net.Listen("unix", t.TempDir()+"/tune.sock")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	r := &Result{Listeners: []net.Listener{l, u}}
+	defer r.Close()
+
+	r.tune(&options{})
+	if r.Listeners[0] != l {
+		t.Errorf("expected listener not to be wrapped without options")
+	}
+
+	r.tune(newOptions([]Option{WithTCPKeepAlive(time.Minute), WithAcceptDeadline(time.Second)}))
+	if _, ok := r.Listeners[0].(*tcpListener); !ok {
+		t.Errorf("expected listener to be wrapped, got=%T", r.Listeners[0])
+	}
+	if r.Listeners[1] != u {
+		t.Errorf("expected unix listener not to be wrapped, got=%T", r.Listeners[1])
+	}
+}
+
+func TestTCPListener_AcceptDeadline(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	tl := tuneListener(l, &options{acceptDeadline: 20 * time.Millisecond})
+	defer tl.Close()
+
+	_, err = tl.Accept()
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("expected error=%s, got=%s", os.ErrDeadlineExceeded, err)
+	}
+
+	// Deadline is reset for each Accept call.
+	done := make(chan error, 1)
+	go func() {
+		conn, err := tl.Accept()
+		if err == nil {
+			conn.Close()
+		}
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	conn, err := net.Dial("tcp", tl.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer conn.Close()
+	if err = <-done; err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
+
+func TestTCPListener_KeepAlive(t *testing.T) {
+	for _, d := range []time.Duration{-1, time.Minute} {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err)
+		}
+		tl := tuneListener(l, &options{tcpKeepAlive: d})
+
+		client, err := net.Dial("tcp", tl.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %s", err)
+		}
+		conn, err := tl.Accept()
+		if err != nil {
+			t.Errorf("expected no error with keep-alive=%s, got=%s", d, err)
+		} else {
+			conn.Close()
+		}
+		client.Close()
+		tl.Close()
+	}
+}
@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package keychain loads TLS identities from macOS keychains without using cgo.
+//
+// Socket activated HTTPS daemons can use certificates provisioned by
+// administrators or MDM into the System or login keychain, instead of
+// PEM encoded certificates and private keys on disk. Private keys are
+// never exported from the keychain. Instead, signatures are created by
+// the keychain, thus non-extractable keys are supported.
+//
+// On non-macOS platforms (including iOS), all functions return an error
+// wrapping [syscall.ENOTSUP].
+package keychain
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package keychain
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"fmt"
+	"syscall"
+)
+
+// TLSCertificate returns [tls.Certificate] for the identity (certificate and
+// its private key) with the given label, from the keychain search list,
+// which includes System and login keychains. Private key of the returned
+// certificate is a [crypto.Signer], which signs using the keychain.
+// Only ECDSA and RSA keys are supported.
+//
+// Accessing the private key may require user consent, depending on access
+// control settings of the key. Daemons must use identities whose keys are
+// accessible without user interaction.
+//
+//   - [syscall.ENOENT] is returned if identity with the label is not found.
+//   - [syscall.EACCES] is returned if keychain denied access to the identity.
+//   - [syscall.EINVAL] is returned if label is empty or contains NUL bytes.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func TLSCertificate(label string) (tls.Certificate, error) {
+	return tlsCertificate(label)
+}
+
+// algorithm returns name of the SecKeyAlgorithm constant for signing
+// digests with a private key with public key pub.
+func algorithm(pub crypto.PublicKey, opts crypto.SignerOpts) (string, error) {
+	var hash string
+	switch opts.HashFunc() {
+	case crypto.SHA1:
+		hash = "SHA1"
+	case crypto.SHA256:
+		hash = "SHA256"
+	case crypto.SHA384:
+		hash = "SHA384"
+	case crypto.SHA512:
+		hash = "SHA512"
+	default:
+		return "", fmt.Errorf("keychain: unsupported hash(%s): %w", opts.HashFunc(), syscall.ENOTSUP)
+	}
+
+	switch pub.(type) {
+	case *ecdsa.PublicKey:
+		return "kSecKeyAlgorithmECDSASignatureDigestX962" + hash, nil
+	case *rsa.PublicKey:
+		if pss, ok := opts.(*rsa.PSSOptions); ok {
+			// Keychain always uses salt of the same length as the hash.
+			if pss.SaltLength != rsa.PSSSaltLengthEqualsHash && pss.SaltLength != opts.HashFunc().Size() {
+				return "", fmt.Errorf("keychain: unsupported PSS salt length(%d): %w", pss.SaltLength, syscall.ENOTSUP)
+			}
+			return "kSecKeyAlgorithmRSASignatureDigestPSS" + hash, nil
+		}
+		return "kSecKeyAlgorithmRSASignatureDigestPKCS1v15" + hash, nil
+	default:
+		return "", fmt.Errorf("keychain: unsupported key type(%T): %w", pub, syscall.ENOTSUP)
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package keychain
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// signer is a [crypto.Signer] backed by a SecKeyRef.
+type signer struct {
+	key macos.ID
+	pub crypto.PublicKey
+}
+
+// Public implements [crypto.Signer].
+func (s *signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign implements [crypto.Signer].
+func (s *signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	alg, err := algorithm(s.pub, opts)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := macos.KeySign(s.key, alg, digest)
+	runtime.KeepAlive(s)
+	if err != nil {
+		return nil, fmt.Errorf("keychain: failed to sign: %w", err)
+	}
+	return sig, nil
+}
+
+// keychainError returns error for OSStatus returned by keychain functions.
+func keychainError(label string, err error) error {
+	var status macos.OSStatus
+	if errors.As(err, &status) {
+		switch status {
+		case macos.ErrSecItemNotFound:
+			return fmt.Errorf("keychain: identity(%s) not found: %w", label, syscall.ENOENT)
+		case macos.ErrSecAuthFailed, macos.ErrSecInteractionNotAllowed:
+			return fmt.Errorf("keychain: access to identity(%s) denied: %w", label, syscall.EACCES)
+		}
+	}
+	return fmt.Errorf("keychain: identity(%s): %w", label, err)
+}
+
+// Os specific implementation of [TLSCertificate].
+func tlsCertificate(label string) (tls.Certificate, error) {
+	if label == "" || strings.ContainsRune(label, 0) {
+		return tls.Certificate{}, fmt.Errorf("keychain: invalid label(%q): %w", label, syscall.EINVAL)
+	}
+
+	identity, err := macos.CopyIdentity(label)
+	if err != nil {
+		return tls.Certificate{}, keychainError(label, err)
+	}
+	defer macos.Release(identity)
+
+	der, err := macos.IdentityCertificate(identity)
+	if err != nil {
+		return tls.Certificate{}, keychainError(label, err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("keychain: invalid certificate for identity(%s): %w", label, err)
+	}
+
+	key, err := macos.IdentityPrivateKey(identity)
+	if err != nil {
+		return tls.Certificate{}, keychainError(label, err)
+	}
+
+	s := &signer{key: key, pub: leaf.PublicKey}
+	runtime.SetFinalizer(s, func(s *signer) {
+		macos.Release(s.key)
+	})
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  s,
+		Leaf:        leaf,
+	}, nil
+}
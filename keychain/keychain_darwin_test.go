@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package keychain_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/keychain"
+)
+
+func TestTLSCertificate(t *testing.T) {
+	tt := []struct {
+		name  string
+		label string
+		err   error
+	}{
+		{
+			name:  "NotFound",
+			label: "b39422da-351b-50ad-a7cc-9dea5ae436ea",
+			err:   syscall.ENOENT,
+		},
+		{
+			name: "Empty",
+			err:  syscall.EINVAL,
+		},
+		{
+			name:  "NUL",
+			label: "com.example\x00svc",
+			err:   syscall.EINVAL,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := keychain.TLSCertificate(tc.label)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package keychain
+
+import (
+	"crypto/tls"
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [TLSCertificate].
+func tlsCertificate(_ string) (tls.Certificate, error) {
+	return tls.Certificate{}, fmt.Errorf("keychain: only supported on macOS: %w", syscall.ENOTSUP)
+}
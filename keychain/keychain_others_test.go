@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package keychain_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/keychain"
+)
+
+func TestTLSCertificate(t *testing.T) {
+	_, err := keychain.TLSCertificate("com.example.svc")
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
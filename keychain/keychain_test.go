@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package keychain
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestAlgorithm(t *testing.T) {
+	tt := []struct {
+		name   string
+		pub    crypto.PublicKey
+		opts   crypto.SignerOpts
+		expect string
+		err    error
+	}{
+		{
+			name:   "ECDSA-SHA256",
+			pub:    &ecdsa.PublicKey{},
+			opts:   crypto.SHA256,
+			expect: "kSecKeyAlgorithmECDSASignatureDigestX962SHA256",
+		},
+		{
+			name:   "ECDSA-SHA384",
+			pub:    &ecdsa.PublicKey{},
+			opts:   crypto.SHA384,
+			expect: "kSecKeyAlgorithmECDSASignatureDigestX962SHA384",
+		},
+		{
+			name:   "RSA-PKCS1v15-SHA512",
+			pub:    &rsa.PublicKey{},
+			opts:   crypto.SHA512,
+			expect: "kSecKeyAlgorithmRSASignatureDigestPKCS1v15SHA512",
+		},
+		{
+			name:   "RSA-PSS-SHA256",
+			pub:    &rsa.PublicKey{},
+			opts:   &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: rsa.PSSSaltLengthEqualsHash},
+			expect: "kSecKeyAlgorithmRSASignatureDigestPSSSHA256",
+		},
+		{
+			name:   "RSA-PSS-SHA256-SaltLength",
+			pub:    &rsa.PublicKey{},
+			opts:   &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: 32},
+			expect: "kSecKeyAlgorithmRSASignatureDigestPSSSHA256",
+		},
+		{
+			name: "RSA-PSS-SaltLengthAuto",
+			pub:  &rsa.PublicKey{},
+			opts: &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: rsa.PSSSaltLengthAuto},
+			err:  syscall.ENOTSUP,
+		},
+		{
+			name: "ECDSA-MD5",
+			pub:  &ecdsa.PublicKey{},
+			opts: crypto.MD5,
+			err:  syscall.ENOTSUP,
+		},
+		{
+			name: "Ed25519",
+			pub:  ed25519.PublicKey{},
+			opts: crypto.SHA256,
+			err:  syscall.ENOTSUP,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			alg, err := algorithm(tc.pub, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%v, got=%v", tc.err, err)
+			}
+			if alg != tc.expect {
+				t.Errorf("expected algorithm=%s, got=%s", tc.expect, alg)
+			}
+		})
+	}
+}
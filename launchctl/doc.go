@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package launchctl provides typed wrappers around [launchctl(1)].
+//
+// All functions invoke /bin/launchctl and thus do not require cgo.
+// Errors returned by launchctl are reported as [*Error], which includes
+// exit code and error message parsed from its output.
+//
+// On non-macOS platforms (including iOS), all functions return an error
+// wrapping [syscall.ENOTSUP].
+//
+// [launchctl(1)]: https://keith.github.io/xcode-man-pages/launchctl.1.html
+package launchctl
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Domain is a launchd domain target specifier, like "system" or "gui/501".
+//
+// Use [System], [User], [GUI] or [PID] to build domain targets,
+// or [CurrentDomain] to get the domain appropriate for the calling process.
+type Domain string
+
+// System is the system domain, where LaunchDaemons are loaded.
+// Bootstrapping services into system domain requires root.
+const System Domain = "system"
+
+// User returns the per-user background domain for the uid. Services in
+// this domain run regardless of whether the user is logged in or not.
+func User(uid int) Domain {
+	return Domain("user/" + strconv.Itoa(uid))
+}
+
+// GUI returns the per-user GUI (Aqua) domain for the uid. This is
+// the domain where LaunchAgents of a logged-in user are loaded.
+func GUI(uid int) Domain {
+	return Domain("gui/" + strconv.Itoa(uid))
+}
+
+// PID returns the domain of the process with the given pid.
+func PID(pid int) Domain {
+	return Domain("pid/" + strconv.Itoa(pid))
+}
+
+// String implements [fmt.Stringer].
+func (d Domain) String() string {
+	return string(d)
+}
+
+// Service returns service target specifier for the label in the domain,
+// for example "gui/501/com.example.svc".
+func (d Domain) Service(label string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(string(d), "/"), label)
+}
+
+// ValidateLabel checks if label can be used in a service target specifier
+// and as name of a plist file, so that it neither refers to a different
+// service, nor to files outside of the plist directory.
+//
+//   - [syscall.EINVAL] is returned if label is empty, contains path
+//     separators or NUL bytes, or is "." or "..".
+func ValidateLabel(label string) error {
+	if label == "" || label == "." || label == ".." || strings.ContainsAny(label, "/\x00") {
+		return fmt.Errorf("launchctl: invalid label(%q): %w", label, syscall.EINVAL)
+	}
+	return nil
+}
+
+// UID returns the uid of per-user ([User] and [GUI]) domains.
+// For other domains, ok is false.
+func (d Domain) UID() (uid int, ok bool) {
+	kind, rest, found := strings.Cut(strings.TrimSuffix(string(d), "/"), "/")
+	if !found || (kind != "user" && kind != "gui") {
+		return 0, false
+	}
+	uid, err := strconv.Atoi(rest)
+	if err != nil || uid < 0 {
+		return 0, false
+	}
+	return uid, true
+}
+
+// CurrentDomain returns the domain appropriate for the calling process.
+//
+//   - [System], if running as root.
+//   - [GUI] of the current user, if running in an Aqua (GUI) session.
+//   - [User] of the current user otherwise (for example over SSH).
+//
+// On non-macOS platforms (including iOS), this returns an error
+// wrapping [syscall.ENOTSUP].
+func CurrentDomain(ctx context.Context) (Domain, error) {
+	name, err := ManagerName(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	uid := os.Getuid()
+	switch {
+	case uid == 0:
+		return System, nil
+	case name == "Aqua":
+		return GUI(uid), nil
+	default:
+		return User(uid), nil
+	}
+}
+
+// ManagerName returns the name of the launchd manager of the session
+// of the calling process, i.e. its session type, for example "Aqua",
+// "Background", "StandardIO" or "System".
+//
+//   - [*Error] is returned if launchctl fails.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func ManagerName(ctx context.Context) (string, error) {
+	out, err := run(ctx, "managername")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
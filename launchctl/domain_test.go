@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl_test
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+func TestDomain(t *testing.T) {
+	tt := []struct {
+		name    string
+		domain  launchctl.Domain
+		target  string
+		service string
+	}{
+		{
+			name:    "system",
+			domain:  launchctl.System,
+			target:  "system",
+			service: "system/com.example.svc",
+		},
+		{
+			name:    "user",
+			domain:  launchctl.User(501),
+			target:  "user/501",
+			service: "user/501/com.example.svc",
+		},
+		{
+			name:    "gui",
+			domain:  launchctl.GUI(501),
+			target:  "gui/501",
+			service: "gui/501/com.example.svc",
+		},
+		{
+			name:    "pid",
+			domain:  launchctl.PID(1024),
+			target:  "pid/1024",
+			service: "pid/1024/com.example.svc",
+		},
+		{
+			name:    "trailing-slash",
+			domain:  launchctl.Domain("gui/501/"),
+			target:  "gui/501/",
+			service: "gui/501/com.example.svc",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.domain.String() != tc.target {
+				t.Errorf("expected target=%s, got=%s", tc.target, tc.domain)
+			}
+			if v := tc.domain.Service("com.example.svc"); v != tc.service {
+				t.Errorf("expected service=%s, got=%s", tc.service, v)
+			}
+		})
+	}
+}
+
+func TestDomainUID(t *testing.T) {
+	tt := []struct {
+		domain launchctl.Domain
+		uid    int
+		ok     bool
+	}{
+		{domain: launchctl.System},
+		{domain: launchctl.User(501), uid: 501, ok: true},
+		{domain: launchctl.GUI(502), uid: 502, ok: true},
+		{domain: launchctl.Domain("gui/503/"), uid: 503, ok: true},
+		{domain: launchctl.GUI(0), uid: 0, ok: true},
+		{domain: launchctl.PID(1024)},
+		{domain: launchctl.Domain("gui/")},
+		{domain: launchctl.Domain("gui/-1")},
+		{domain: launchctl.Domain("gui/foo")},
+		{domain: launchctl.Domain("login/501")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.domain.String(), func(t *testing.T) {
+			uid, ok := tc.domain.UID()
+			if ok != tc.ok {
+				t.Errorf("expected ok=%t, got=%t", tc.ok, ok)
+			}
+			if uid != tc.uid {
+				t.Errorf("expected uid=%d, got=%d", tc.uid, uid)
+			}
+		})
+	}
+}
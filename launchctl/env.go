@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// validateEnvKey checks if key is a valid environment variable name.
+func validateEnvKey(key string) error {
+	if key == "" || strings.ContainsAny(key, "=\x00") {
+		return fmt.Errorf("launchctl: invalid environment variable name(%q): %w", key, syscall.EINVAL)
+	}
+	return nil
+}
+
+// Getenv returns the value of the environment variable in the
+// launchd user environment of the caller's domain.
+// Empty string is returned if variable is not set.
+//
+//   - [*Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned if key is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Getenv(ctx context.Context, key string) (string, error) {
+	if err := validateEnvKey(key); err != nil {
+		return "", err
+	}
+
+	out, err := run(ctx, "getenv", key)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// Setenv sets the environment variable in the launchd user environment
+// of the caller's domain. Variables are inherited by all services
+// subsequently started in the domain, including GUI applications.
+//
+//   - [*Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned if key is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Setenv(ctx context.Context, key, value string) error {
+	return SetEnv(ctx, map[string]string{key: value})
+}
+
+// Unsetenv removes the environment variable from the launchd user
+// environment of the caller's domain.
+//
+//   - [*Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned if key is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Unsetenv(ctx context.Context, key string) error {
+	if err := validateEnvKey(key); err != nil {
+		return err
+	}
+	_, err := run(ctx, "unsetenv", key)
+	return err
+}
+
+// SetEnv sets all the environment variables in the launchd user
+// environment of the caller's domain.
+//
+// Variables are set with a single invocation of launchctl. If it fails,
+// previous values of the variables are restored on a best effort basis,
+// so that environment is not left partially updated.
+//
+//   - [*Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned if any of the keys is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func SetEnv(ctx context.Context, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		if err := validateEnvKey(k); err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	// Save existing values to restore them in case of errors.
+	previous := make(map[string]string, len(keys))
+	for _, k := range keys {
+		v, err := Getenv(ctx, k)
+		if err != nil {
+			return err
+		}
+		previous[k] = v
+	}
+
+	args := make([]string, 0, 1+2*len(keys))
+	args = append(args, "setenv")
+	for _, k := range keys {
+		args = append(args, k, env[k])
+	}
+
+	_, err := run(ctx, args...)
+	if err != nil {
+		// Context may already be cancelled, thus use a context without
+		// cancellation to restore the environment.
+		restoreCtx := context.WithoutCancel(ctx)
+		for _, k := range keys {
+			if previous[k] == "" {
+				err = errors.Join(err, Unsetenv(restoreCtx, k))
+			} else {
+				_, rerr := run(restoreCtx, "setenv", k, previous[k])
+				err = errors.Join(err, rerr)
+			}
+		}
+	}
+	return err
+}
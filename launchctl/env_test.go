@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+func TestSetEnv_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	tt := []struct {
+		name string
+		key  string
+	}{
+		{name: "empty", key: ""},
+		{name: "equals", key: "A=B"},
+		{name: "null", key: "A\x00"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := launchctl.SetEnv(ctx, map[string]string{tc.key: "value"})
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("SetEnv expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+
+			_, err = launchctl.Getenv(ctx, tc.key)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("Getenv expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+
+			err = launchctl.Unsetenv(ctx, tc.key)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("Unsetenv expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
+
+func TestSetEnv_Empty(t *testing.T) {
+	if err := launchctl.SetEnv(context.Background(), nil); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
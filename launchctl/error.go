@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Error codes reported by launchctl, in addition to POSIX errno values.
+// These can be viewed with "launchctl error <code>".
+const (
+	codeServiceNotFound     = 113 // Could not find specified service
+	codeServiceDisabled     = 119 // Service is disabled
+	codeBadPathPermissions  = 122 // Path had bad ownership/permissions
+	codeDomainNotSupported  = 125 // Domain does not support specified action
+	codeSessionNotSupported = 134 // Service cannot load in requested session
+	codeSIP                 = 150 // Operation not permitted while SIP is engaged
+)
+
+// POSIX errno values on macOS. These are defined here instead of using
+// constants from [syscall], as their values differ across platforms.
+const (
+	errnoEPERM    = 1
+	errnoESRCH    = 3
+	errnoEACCES   = 13
+	errnoEEXIST   = 17
+	errnoEALREADY = 37
+	errnoMax      = 106 // ELAST
+)
+
+// exitCodeServiceNotFound is the exit code of launchctl when
+// the specified service is not found in the domain.
+const exitCodeServiceNotFound = codeServiceNotFound
+
+// Errors which can be matched against [*Error] with [errors.Is].
+//
+// Because launchctl often reports different errors with the same code,
+// matching is based on both error code and error message, and an error
+// may match more than one of these, for example an error due to SIP matches
+// both [ErrSIP] and [ErrPermission].
+var (
+	// ErrNotFound indicates that service or domain was not found.
+	ErrNotFound = errors.New("launchctl: service not found")
+
+	// ErrAlreadyLoaded indicates that service is already loaded.
+	ErrAlreadyLoaded = errors.New("launchctl: service already loaded")
+
+	// ErrPermission indicates that caller does not have sufficient
+	// privileges, for example bootstrapping into system domain as non-root.
+	ErrPermission = errors.New("launchctl: permission denied")
+
+	// ErrSIP indicates that operation is not permitted by
+	// System Integrity Protection.
+	ErrSIP = errors.New("launchctl: operation not permitted by System Integrity Protection")
+
+	// ErrDisabled indicates that service is disabled.
+	ErrDisabled = errors.New("launchctl: service is disabled")
+
+	// ErrInvalidPlist indicates that plist file is invalid or has
+	// incorrect ownership or permissions.
+	ErrInvalidPlist = errors.New("launchctl: invalid plist")
+
+	// ErrUnsupportedDomain indicates that operation is not supported
+	// in the domain or session.
+	ErrUnsupportedDomain = errors.New("launchctl: operation not supported in domain")
+)
+
+// Error is returned when launchctl exits with non-zero exit code.
+type Error struct {
+	// Args are the arguments passed to launchctl.
+	Args []string
+
+	// ExitCode is the exit code of launchctl.
+	ExitCode int
+
+	// Code is the error code reported by launchctl in its output,
+	// for example 5 in "Bootstrap failed: 5: Input/output error".
+	// This is zero if output does not contain an error code.
+	Code int
+
+	// Message is the error message reported by launchctl.
+	Message string
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	var cmd string
+	if len(e.Args) > 0 {
+		cmd = e.Args[0]
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("launchctl: %s failed with exit code %d", cmd, e.ExitCode)
+	}
+	return fmt.Sprintf("launchctl: %s failed with exit code %d: %s", cmd, e.ExitCode, e.Message)
+}
+
+// Is reports whether error matches the target.
+//
+//nolint:cyclop // switch on target errors.
+func (e *Error) Is(target error) bool {
+	msg := strings.ToLower(e.Message)
+	switch target {
+	case ErrNotFound:
+		return e.ExitCode == codeServiceNotFound ||
+			e.Code == codeServiceNotFound ||
+			e.Code == errnoESRCH ||
+			strings.Contains(msg, "could not find")
+	case ErrAlreadyLoaded:
+		return e.Code == errnoEALREADY ||
+			e.Code == errnoEEXIST ||
+			strings.Contains(msg, "already loaded") ||
+			strings.Contains(msg, "already bootstrapped")
+	case ErrPermission:
+		return e.Code == errnoEPERM ||
+			e.Code == errnoEACCES ||
+			e.Code == codeSIP ||
+			strings.Contains(msg, "not permitted") ||
+			strings.Contains(msg, "permission denied")
+	case ErrSIP:
+		return e.Code == codeSIP ||
+			strings.Contains(msg, "system integrity protection")
+	case ErrDisabled:
+		return e.Code == codeServiceDisabled ||
+			strings.Contains(msg, "service is disabled")
+	case ErrInvalidPlist:
+		return e.Code == codeBadPathPermissions ||
+			strings.Contains(msg, "invalid property list") ||
+			strings.Contains(msg, "bad ownership") ||
+			strings.Contains(msg, "malformed")
+	case ErrUnsupportedDomain:
+		return e.Code == codeDomainNotSupported ||
+			e.Code == codeSessionNotSupported
+	}
+	return false
+}
+
+// Unwrap returns [syscall.Errno] for error codes which are POSIX
+// errno values, so that errors can be matched against them and
+// errors like [os.ErrPermission]. As launchctl is only available
+// on macOS, this is only meaningful on macOS.
+func (e *Error) Unwrap() error {
+	if e.Code > 0 && e.Code <= errnoMax {
+		return syscall.Errno(e.Code)
+	}
+	return nil
+}
+
+// errorCodeRegexp matches error messages like
+// "Bootstrap failed: 5: Input/output error".
+var errorCodeRegexp = regexp.MustCompile(`(?m)^[\w -]+ failed: (\d+): (.+)$`)
+
+// newError builds [*Error] from the arguments, exit code and
+// output of launchctl.
+func newError(args []string, exitCode int, output []byte) *Error {
+	e := &Error{
+		Args:     args,
+		ExitCode: exitCode,
+	}
+
+	out := strings.TrimSpace(string(output))
+	if m := errorCodeRegexp.FindStringSubmatch(out); m != nil {
+		e.Code, _ = strconv.Atoi(m[1])
+		e.Message = strings.TrimSpace(m[0])
+		return e
+	}
+
+	// Use the last non-empty line as error message, as launchctl
+	// sometimes prints usage information before it.
+	lines := strings.Split(out, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			e.Message = line
+			break
+		}
+	}
+	return e
+}
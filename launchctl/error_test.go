@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestNewError(t *testing.T) {
+	tt := []struct {
+		name    string
+		output  string
+		code    int
+		message string
+	}{
+		{
+			name:    "bootstrap",
+			output:  "Bootstrap failed: 5: Input/output error\n",
+			code:    5,
+			message: "Bootstrap failed: 5: Input/output error",
+		},
+		{
+			name:    "bootout",
+			output:  "Boot-out failed: 3: No such process\n",
+			code:    3,
+			message: "Boot-out failed: 3: No such process",
+		},
+		{
+			name:    "usage",
+			output:  "Usage: launchctl enable <service-target>\nUnrecognized target specifier.\n",
+			message: "Unrecognized target specifier.",
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newError([]string{"bootstrap"}, 5, []byte(tc.output))
+			if err.ExitCode != 5 {
+				t.Errorf("expected exit code=5, got=%d", err.ExitCode)
+			}
+			if err.Code != tc.code {
+				t.Errorf("expected code=%d, got=%d", tc.code, err.Code)
+			}
+			if err.Message != tc.message {
+				t.Errorf("expected message=%q, got=%q", tc.message, err.Message)
+			}
+			if err.Error() == "" {
+				t.Errorf("expected non empty error string")
+			}
+		})
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	tt := []struct {
+		name     string
+		exitCode int
+		output   string
+		is       []error
+		not      []error
+	}{
+		{
+			name:     "not-found",
+			exitCode: 113,
+			output:   "Could not find service \"com.example.svc\" in domain for port\n",
+			is:       []error{ErrNotFound},
+			not:      []error{ErrAlreadyLoaded, ErrPermission},
+		},
+		{
+			name:     "bootout-not-loaded",
+			exitCode: 3,
+			output:   "Boot-out failed: 3: No such process\n",
+			is:       []error{ErrNotFound, syscall.ESRCH},
+			not:      []error{ErrAlreadyLoaded},
+		},
+		{
+			name:     "already-loaded",
+			exitCode: 37,
+			output:   "Bootstrap failed: 37: Operation already in progress\n",
+			is:       []error{ErrAlreadyLoaded},
+			not:      []error{ErrNotFound, ErrPermission},
+		},
+		{
+			name:     "legacy-already-loaded",
+			exitCode: 0,
+			output:   "/Library/LaunchAgents/com.example.svc.plist: service already loaded\n",
+			is:       []error{ErrAlreadyLoaded},
+		},
+		{
+			name:     "permission",
+			exitCode: 1,
+			output:   "Bootstrap failed: 1: Operation not permitted\n",
+			is:       []error{ErrPermission, os.ErrPermission, syscall.EPERM},
+			not:      []error{ErrSIP},
+		},
+		{
+			name:     "sip",
+			exitCode: 150,
+			output:   "Bootstrap failed: 150: Operation not permitted while System Integrity Protection is engaged\n",
+			is:       []error{ErrSIP, ErrPermission},
+			not:      []error{ErrNotFound},
+		},
+		{
+			name:     "disabled",
+			exitCode: 119,
+			output:   "Bootstrap failed: 119: Service is disabled\n",
+			is:       []error{ErrDisabled},
+		},
+		{
+			name:     "bad-permissions",
+			exitCode: 122,
+			output:   "Bootstrap failed: 122: Path had bad ownership/permissions\n",
+			is:       []error{ErrInvalidPlist},
+		},
+		{
+			name:     "io-error",
+			exitCode: 5,
+			output:   "Bootstrap failed: 5: Input/output error\n",
+			is:       []error{syscall.EIO},
+			not:      []error{ErrNotFound, ErrAlreadyLoaded, ErrPermission, ErrInvalidPlist},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newError([]string{"bootstrap"}, tc.exitCode, []byte(tc.output))
+			for _, target := range tc.is {
+				if !errors.Is(err, target) {
+					t.Errorf("expected error(%s) to match %s", err, target)
+				}
+			}
+			for _, target := range tc.not {
+				if errors.Is(err, target) {
+					t.Errorf("expected error(%s) to not match %s", err, target)
+				}
+			}
+		})
+	}
+}
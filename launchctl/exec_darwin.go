@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// run runs launchctl with the given arguments and returns its stdout.
+func run(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, launchctlPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		// Context errors take precedence over exit errors,
+		// as process would have been killed.
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("launchctl: %w", ctx.Err())
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Some sub-commands print errors on stdout.
+			output := append(stderr.Bytes(), stdout.Bytes()...)
+			return nil, newError(args, exitErr.ExitCode(), output)
+		}
+		return nil, fmt.Errorf("launchctl: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// process is a running command, whose stdout is being streamed.
+type process struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+// Close stops the process and waits for it to exit.
+func (p *process) Close() error {
+	_ = p.cmd.Process.Kill()
+	err := p.ReadCloser.Close()
+	_ = p.cmd.Wait()
+	return err
+}
+
+// stream starts the command and returns its stdout.
+// Stderr of the command is discarded.
+func stream(ctx context.Context, name string, args ...string) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("launchctl: %w", err)
+	}
+
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("launchctl: failed to start %s: %w", name, err)
+	}
+	return &process{ReadCloser: stdout, cmd: cmd}, nil
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"syscall"
+)
+
+// Os specific implementation of running launchctl.
+func run(_ context.Context, _ ...string) ([]byte, error) {
+	return nil, fmt.Errorf("launchctl: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of streaming command output.
+func stream(_ context.Context, _ string, _ ...string) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("launchctl: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"syscall"
+)
+
+// Path to launchctl binary. Absolute path is used to avoid
+// using a binary with same name in PATH.
+const launchctlPath = "/bin/launchctl"
+
+// Bootstrap loads the service defined by the plist file(s) into the domain.
+//
+//   - [syscall.EINVAL] is returned if no paths are specified.
+//   - [*Error] is returned if launchctl fails to bootstrap the service.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Bootstrap(ctx context.Context, domain Domain, paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("launchctl: bootstrap requires a plist path: %w", syscall.EINVAL)
+	}
+	_, err := run(ctx, append([]string{"bootstrap", domain.String()}, paths...)...)
+	return err
+}
+
+// Bootout removes the service with the given label from the domain.
+// If the service is running, it is stopped.
+//
+//   - [*Error] is returned if launchctl fails to bootout the service.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Bootout(ctx context.Context, domain Domain, label string) error {
+	if err := ValidateLabel(label); err != nil {
+		return err
+	}
+	_, err := run(ctx, "bootout", domain.Service(label))
+	return err
+}
+
+// Enable enables the service with the given label in the domain.
+//
+// Enabled state is persistent across boots, and unlike Disabled key in
+// the plist, it takes precedence over it.
+//
+//   - [*Error] is returned if launchctl fails to enable the service.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Enable(ctx context.Context, domain Domain, label string) error {
+	if err := ValidateLabel(label); err != nil {
+		return err
+	}
+	_, err := run(ctx, "enable", domain.Service(label))
+	return err
+}
+
+// Disable disables the service with the given label in the domain.
+// Disabled services cannot be bootstrapped. This does not stop
+// the service if it is already running.
+//
+//   - [*Error] is returned if launchctl fails to disable the service.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Disable(ctx context.Context, domain Domain, label string) error {
+	if err := ValidateLabel(label); err != nil {
+		return err
+	}
+	_, err := run(ctx, "disable", domain.Service(label))
+	return err
+}
+
+// KickstartOptions are options for [Kickstart].
+type KickstartOptions struct {
+	// Kill the running instance of the service before restarting it.
+	Kill bool
+}
+
+// Kickstart runs the service with the given label in the domain
+// immediately, regardless of its launch conditions.
+//
+//   - [*Error] is returned if launchctl fails to kickstart the service.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Kickstart(ctx context.Context, domain Domain, label string, opts KickstartOptions) error {
+	if err := ValidateLabel(label); err != nil {
+		return err
+	}
+	args := []string{"kickstart"}
+	if opts.Kill {
+		args = append(args, "-k")
+	}
+	args = append(args, domain.Service(label))
+	_, err := run(ctx, args...)
+	return err
+}
+
+// Kill sends the signal to the running instance of the service with
+// the given label in the domain.
+//
+//   - [*Error] is returned if launchctl fails to signal the service,
+//     for example, if the service is not running.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Kill(ctx context.Context, domain Domain, label string, sig syscall.Signal) error {
+	if err := ValidateLabel(label); err != nil {
+		return err
+	}
+	_, err := run(ctx, "kill", strconv.Itoa(int(sig)), domain.Service(label))
+	return err
+}
+
+// Loaded reports whether the service with the given label is loaded
+// in the domain.
+//
+//   - [*Error] is returned if launchctl fails for reasons other than
+//     service not being found.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Loaded(ctx context.Context, domain Domain, label string) (bool, error) {
+	if err := ValidateLabel(label); err != nil {
+		return false, err
+	}
+	_, err := run(ctx, "print", domain.Service(label))
+	if err != nil {
+		var e *Error
+		if errors.As(err, &e) && e.ExitCode == exitCodeServiceNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// AsUser runs launchctl subcommand specified by args in the bootstrap
+// context of the user with the given uid, i.e. "launchctl asuser".
+// This is useful when managing per-user agents from a root context,
+// for example by MDM tools or installer scripts, as some subcommands
+// operate on the bootstrap namespace of the caller. Output of the
+// subcommand is returned.
+//
+// This requires root.
+//
+//   - [syscall.EINVAL] is returned if args are empty or uid is invalid.
+//   - [*Error] is returned if launchctl fails.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func AsUser(ctx context.Context, uid int, args ...string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("launchctl: asuser requires a subcommand: %w", syscall.EINVAL)
+	}
+	if uid < 0 {
+		return nil, fmt.Errorf("launchctl: invalid uid(%d): %w", uid, syscall.EINVAL)
+	}
+	argv := make([]string, 0, len(args)+3)
+	argv = append(argv, "asuser", strconv.Itoa(uid), launchctlPath)
+	argv = append(argv, args...)
+	return run(ctx, argv...)
+}
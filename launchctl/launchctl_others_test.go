@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchctl_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+func TestUnsupported(t *testing.T) {
+	ctx := context.Background()
+	tt := []struct {
+		name string
+		fn   func() error
+	}{
+		{
+			name: "Bootstrap",
+			fn: func() error {
+				return launchctl.Bootstrap(ctx, launchctl.GUI(501), "/tmp/com.example.svc.plist")
+			},
+		},
+		{
+			name: "Bootout",
+			fn: func() error {
+				return launchctl.Bootout(ctx, launchctl.GUI(501), "com.example.svc")
+			},
+		},
+		{
+			name: "Enable",
+			fn: func() error {
+				return launchctl.Enable(ctx, launchctl.GUI(501), "com.example.svc")
+			},
+		},
+		{
+			name: "Disable",
+			fn: func() error {
+				return launchctl.Disable(ctx, launchctl.GUI(501), "com.example.svc")
+			},
+		},
+		{
+			name: "Kickstart",
+			fn: func() error {
+				return launchctl.Kickstart(ctx, launchctl.GUI(501), "com.example.svc",
+					launchctl.KickstartOptions{Kill: true})
+			},
+		},
+		{
+			name: "Kill",
+			fn: func() error {
+				return launchctl.Kill(ctx, launchctl.GUI(501), "com.example.svc", syscall.SIGHUP)
+			},
+		},
+		{
+			name: "Loaded",
+			fn: func() error {
+				_, err := launchctl.Loaded(ctx, launchctl.GUI(501), "com.example.svc")
+				return err
+			},
+		},
+		{
+			name: "Print",
+			fn: func() error {
+				_, err := launchctl.Print(ctx, launchctl.GUI(501), "com.example.svc")
+				return err
+			},
+		},
+		{
+			name: "List",
+			fn: func() error {
+				_, err := launchctl.List(ctx, launchctl.GUI(501))
+				return err
+			},
+		},
+		{
+			name: "Getenv",
+			fn: func() error {
+				_, err := launchctl.Getenv(ctx, "PATH")
+				return err
+			},
+		},
+		{
+			name: "Setenv",
+			fn: func() error {
+				return launchctl.Setenv(ctx, "PATH", "/usr/bin")
+			},
+		},
+		{
+			name: "Unsetenv",
+			fn: func() error {
+				return launchctl.Unsetenv(ctx, "PATH")
+			},
+		},
+		{
+			name: "Restart",
+			fn: func() error {
+				_, err := launchctl.Restart(ctx, launchctl.GUI(501), "com.example.svc",
+					launchctl.RestartOptions{})
+				return err
+			},
+		},
+		{
+			name: "AsUser",
+			fn: func() error {
+				_, err := launchctl.AsUser(ctx, 501, "print", "gui/501")
+				return err
+			},
+		},
+		{
+			name: "StreamLogs",
+			fn: func() error {
+				_, err := launchctl.StreamLogs(ctx, "com.example.svc")
+				return err
+			},
+		},
+		{
+			name: "ManagerName",
+			fn: func() error {
+				_, err := launchctl.ManagerName(ctx)
+				return err
+			},
+		},
+		{
+			name: "CurrentDomain",
+			fn: func() error {
+				_, err := launchctl.CurrentDomain(ctx)
+				return err
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fn()
+			if !errors.Is(err, syscall.ENOTSUP) {
+				t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+func TestValidateLabel(t *testing.T) {
+	tt := []struct {
+		label  string
+		expect error
+	}{
+		{label: "com.example.svc"},
+		{label: "com.example..svc"},
+		{label: "", expect: syscall.EINVAL},
+		{label: ".", expect: syscall.EINVAL},
+		{label: "..", expect: syscall.EINVAL},
+		{label: "../../etc/x", expect: syscall.EINVAL},
+		{label: "com/example", expect: syscall.EINVAL},
+		{label: "com.example\x00svc", expect: syscall.EINVAL},
+	}
+	for _, tc := range tt {
+		t.Run(tc.label, func(t *testing.T) {
+			if err := launchctl.ValidateLabel(tc.label); !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%v, got=%v", tc.expect, err)
+			}
+		})
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	ctx := context.Background()
+	tt := []struct {
+		name string
+		fn   func() error
+	}{
+		{
+			name: "Bootstrap-NoPaths",
+			fn: func() error {
+				return launchctl.Bootstrap(ctx, launchctl.GUI(501))
+			},
+		},
+		{
+			name: "Bootout-EmptyLabel",
+			fn: func() error {
+				return launchctl.Bootout(ctx, launchctl.GUI(501), "")
+			},
+		},
+		{
+			name: "Enable-InvalidLabel",
+			fn: func() error {
+				return launchctl.Enable(ctx, launchctl.GUI(501), "com.example/svc")
+			},
+		},
+		{
+			name: "Disable-InvalidLabel",
+			fn: func() error {
+				return launchctl.Disable(ctx, launchctl.GUI(501), "com.example/svc")
+			},
+		},
+		{
+			name: "Kickstart-InvalidLabel",
+			fn: func() error {
+				return launchctl.Kickstart(ctx, launchctl.GUI(501), "../com.example.svc",
+					launchctl.KickstartOptions{Kill: true})
+			},
+		},
+		{
+			name: "Kill-InvalidLabel",
+			fn: func() error {
+				return launchctl.Kill(ctx, launchctl.GUI(501), "com.example/svc", syscall.SIGHUP)
+			},
+		},
+		{
+			name: "Loaded-EmptyLabel",
+			fn: func() error {
+				_, err := launchctl.Loaded(ctx, launchctl.GUI(501), "")
+				return err
+			},
+		},
+		{
+			name: "Print-InvalidLabel",
+			fn: func() error {
+				_, err := launchctl.Print(ctx, launchctl.GUI(501), "com.example/svc")
+				return err
+			},
+		},
+		{
+			name: "Restart-InvalidLabel",
+			fn: func() error {
+				_, err := launchctl.Restart(ctx, launchctl.GUI(501), "com.example/svc",
+					launchctl.RestartOptions{})
+				return err
+			},
+		},
+		{
+			name: "StreamLogs-EmptyLabel",
+			fn: func() error {
+				_, err := launchctl.StreamLogs(ctx, "")
+				return err
+			},
+		},
+		{
+			name: "AsUser-NoSubcommand",
+			fn: func() error {
+				_, err := launchctl.AsUser(ctx, 501)
+				return err
+			},
+		},
+		{
+			name: "AsUser-InvalidUID",
+			fn: func() error {
+				_, err := launchctl.AsUser(ctx, -1, "print", "gui/501")
+				return err
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.fn()
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListEntry is a service in the domain, as reported by [List].
+type ListEntry struct {
+	// Label of the service.
+	Label string
+
+	// PID is the process id of the running instance of the service.
+	// This is zero if service is not running.
+	PID int
+
+	// LastExitStatus is the exit code of the last instance of the service.
+	// Negative values indicate that it was terminated by a signal, for
+	// example -9 for SIGKILL. This is zero if service never exited.
+	LastExitStatus int
+}
+
+// Running reports whether service is running.
+func (e ListEntry) Running() bool {
+	return e.PID > 0
+}
+
+// List returns all services in the domain.
+//
+//   - [*Error] is returned if launchctl fails.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func List(ctx context.Context, domain Domain) ([]ListEntry, error) {
+	out, err := run(ctx, "print", domain.String())
+	if err != nil {
+		return nil, err
+	}
+	return parseList(out)
+}
+
+// parseList parses services of the domain from output of launchctl print.
+func parseList(data []byte) ([]ListEntry, error) {
+	root, err := parseBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(root.blocks) != 1 {
+		return nil, fmt.Errorf("launchctl: unexpected print output")
+	}
+
+	services := root.blocks[0].child("services")
+	if services == nil {
+		return nil, nil
+	}
+
+	// Each item is of the form "<pid> <last-exit-status> <label>",
+	// where last exit status is "-" if service never exited.
+	entries := make([]ListEntry, 0, len(services.items))
+	for _, item := range services.items {
+		fields := strings.Fields(item)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("launchctl: unexpected service entry: %q", item)
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("launchctl: invalid pid in service entry: %q", item)
+		}
+
+		var status int
+		if fields[1] != "-" {
+			status, err = strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("launchctl: invalid status in service entry: %q", item)
+			}
+		}
+
+		entries = append(entries, ListEntry{
+			Label:          fields[2],
+			PID:            pid,
+			LastExitStatus: status,
+		})
+	}
+	return entries, nil
+}
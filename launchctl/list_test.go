@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	data, err := os.ReadFile("testdata/print-domain.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %s", err)
+	}
+
+	entries, err := parseList(data)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := []ListEntry{
+		{Label: "com.apple.SafariHistoryServiceAgent"},
+		{Label: "com.example.svc", PID: 1234},
+		{Label: "com.example.killed", LastExitStatus: -9},
+	}
+	if !reflect.DeepEqual(expect, entries) {
+		t.Errorf("expected=%#v\ngot=%#v", expect, entries)
+	}
+}
+
+func TestParseList_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "invalid-pid", data: "gui/501 = {\n\tservices = {\n\t\tx 0 com.example\n\t}\n}\n"},
+		{name: "invalid-status", data: "gui/501 = {\n\tservices = {\n\t\t0 x com.example\n\t}\n}\n"},
+		{name: "invalid-entry", data: "gui/501 = {\n\tservices = {\n\t\tcom.example\n\t}\n}\n"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseList([]byte(tc.data))
+			if err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Path to log binary.
+const logPath = "/usr/bin/log"
+
+// StreamLogs streams unified log messages of the service with the given
+// label, using "log stream". Messages logged with the label as subsystem
+// are included, and if the service is running, so are all messages logged
+// by its process. Service is looked up in the domain of the calling process
+// and then in the system domain. It is not an error if service is not
+// loaded, in which case only messages with the label as subsystem
+// are streamed.
+//
+// Streaming stops when the returned [io.ReadCloser] is closed or when
+// the context is cancelled. Output is in the default style of "log stream".
+// Note that the new instance of service is not followed when the service
+// restarts, call StreamLogs again to follow it.
+//
+//   - [*Error] is returned if launchctl fails for reasons other than
+//     service not being found.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func StreamLogs(ctx context.Context, label string) (io.ReadCloser, error) {
+	if err := ValidateLabel(label); err != nil {
+		return nil, err
+	}
+	pid, err := servicePID(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+	return stream(ctx, logPath, "stream", "--level", "debug",
+		"--predicate", logPredicate(label, pid))
+}
+
+// servicePID returns the pid of the service in the current domain
+// or system domain. If service is not found or is not running,
+// zero is returned.
+func servicePID(ctx context.Context, label string) (int, error) {
+	current, err := CurrentDomain(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	domains := []Domain{current}
+	if current != System {
+		domains = append(domains, System)
+	}
+
+	for _, domain := range domains {
+		svc, err := Print(ctx, domain, label)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return 0, err
+		}
+		return svc.PID, nil
+	}
+	return 0, nil
+}
+
+// logQuote returns s as a string literal for log predicates.
+func logQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// logPredicate returns "log stream" predicate for the label and pid.
+// If pid is not positive, only subsystem is matched.
+func logPredicate(label string, pid int) string {
+	predicate := "subsystem == " + logQuote(label)
+	if pid > 0 {
+		predicate = fmt.Sprintf("%s OR processIdentifier == %d", predicate, pid)
+	}
+	return predicate
+}
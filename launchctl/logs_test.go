@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import "testing"
+
+func TestLogPredicate(t *testing.T) {
+	tt := []struct {
+		name   string
+		label  string
+		pid    int
+		expect string
+	}{
+		{
+			name:   "not-running",
+			label:  "com.example.svc",
+			expect: `subsystem == "com.example.svc"`,
+		},
+		{
+			name:   "running",
+			label:  "com.example.svc",
+			pid:    1024,
+			expect: `subsystem == "com.example.svc" OR processIdentifier == 1024`,
+		},
+		{
+			name:   "quote",
+			label:  `com.example."svc\`,
+			expect: `subsystem == "com.example.\"svc\\"`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := logPredicate(tc.label, tc.pid); v != tc.expect {
+				t.Errorf("expected=%s, got=%s", tc.expect, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Service is the state of a service as reported by launchctl print.
+//
+// Output of launchctl print is not considered API by Apple and may change
+// between macOS versions. Fields which are not present in the output
+// have zero values.
+type Service struct {
+	// Label of the service.
+	Label string
+
+	// Path of the plist file of the service.
+	Path string
+
+	// Type of the service, for example "LaunchAgent" or "LaunchDaemon".
+	Type string
+
+	// State of the service, for example "running", "waiting" or "not running".
+	State string
+
+	// Program is the path of the executable of the service.
+	Program string
+
+	// Arguments is the argument vector of the service.
+	Arguments []string
+
+	// Environment is the environment of the service.
+	Environment map[string]string
+
+	// PID is the process id of the running instance of the service.
+	// This is zero if service is not running.
+	PID int
+
+	// Runs is the number of times service was started.
+	Runs int
+
+	// LastExitCode is the exit code of the last instance of the service.
+	// This is zero if service never exited.
+	LastExitCode int
+
+	// LastExitReason is the description of the last exit of the service,
+	// for example "EX_CONFIG", "(never exited)" or "Terminated: 15".
+	LastExitReason string
+
+	// ExitTimeout is the number of seconds launchd waits for service to
+	// exit after sending SIGTERM.
+	ExitTimeout int
+
+	// SpawnType is the spawn type of the service, for example "daemon"
+	// or "interactive".
+	SpawnType string
+
+	// Sockets are the sockets of the service.
+	Sockets []SocketState
+
+	// Endpoints are the mach service endpoints of the service.
+	Endpoints []EndpointState
+
+	// Properties are the properties of the service,
+	// for example "runatload" or "keepalive".
+	Properties []string
+}
+
+// Running reports whether service is running.
+func (s *Service) Running() bool {
+	return s.PID > 0 || s.State == "running"
+}
+
+// SocketState is state of a socket of the service.
+type SocketState struct {
+	// Name of the socket as specified in the plist.
+	Name string
+
+	// Type of the socket, for example "stream" or "dgram".
+	Type string
+
+	// Passive reports whether socket is a listening socket.
+	Passive bool
+
+	// Active reports whether socket has been activated by the service.
+	Active bool
+}
+
+// EndpointState is state of a mach service endpoint of the service.
+type EndpointState struct {
+	// Name of the mach service.
+	Name string
+
+	// Active reports whether endpoint has pending messages.
+	Active bool
+
+	// Managed reports whether endpoint is managed by launchd.
+	Managed bool
+}
+
+// Print returns the state of the service with the given label in the domain.
+//
+//   - [*Error] is returned if launchctl fails or if service is not found.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Print(ctx context.Context, domain Domain, label string) (*Service, error) {
+	if err := ValidateLabel(label); err != nil {
+		return nil, err
+	}
+	out, err := run(ctx, "print", domain.Service(label))
+	if err != nil {
+		return nil, err
+	}
+	return parseService(out)
+}
+
+// block is a parsed block of launchctl print output.
+type block struct {
+	name   string
+	values map[string]string
+	blocks []*block
+	items  []string
+}
+
+// child returns the child block with the given name.
+func (b *block) child(name string) *block {
+	for _, c := range b.blocks {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// integer returns integer value of the key, ignoring any trailing text.
+func (b *block) integer(key string) int {
+	v, _, _ := strings.Cut(b.values[key], " ")
+	i, _ := strconv.Atoi(strings.TrimSuffix(v, ":"))
+	return i
+}
+
+// boolean returns boolean value of the key.
+func (b *block) boolean(key string) bool {
+	return b.values[key] == "1"
+}
+
+// parseBlocks parses output of launchctl print into blocks.
+func parseBlocks(data []byte) (*block, error) {
+	root := &block{values: make(map[string]string)}
+	stack := []*block{root}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		current := stack[len(stack)-1]
+		switch {
+		case line == "":
+		case line == "}":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("launchctl: unbalanced braces in output")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasSuffix(line, " = {"):
+			b := &block{
+				name:   strings.Trim(strings.TrimSuffix(line, " = {"), `"`),
+				values: make(map[string]string),
+			}
+			current.blocks = append(current.blocks, b)
+			stack = append(stack, b)
+		case strings.Contains(line, " => "):
+			k, v, _ := strings.Cut(line, " => ")
+			current.values[k] = v
+		case strings.Contains(line, " = "):
+			k, v, _ := strings.Cut(line, " = ")
+			current.values[k] = v
+		default:
+			current.items = append(current.items, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("launchctl: failed to read output: %w", err)
+	}
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("launchctl: unbalanced braces in output")
+	}
+	return root, nil
+}
+
+// parseService parses output of launchctl print for a service.
+func parseService(data []byte) (*Service, error) {
+	root, err := parseBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(root.blocks) != 1 {
+		return nil, fmt.Errorf("launchctl: unexpected print output")
+	}
+
+	b := root.blocks[0]
+	svc := &Service{
+		Label:       b.name[strings.LastIndex(b.name, "/")+1:],
+		Path:        b.values["path"],
+		Type:        b.values["type"],
+		State:       b.values["state"],
+		Program:     b.values["program"],
+		PID:         b.integer("pid"),
+		Runs:        b.integer("runs"),
+		ExitTimeout: b.integer("exit timeout"),
+	}
+
+	if v, _, _ := strings.Cut(b.values["spawn type"], " "); v != "" {
+		svc.SpawnType = v
+	}
+
+	// last exit code = 78: EX_CONFIG
+	// last exit code = (never exited)
+	if v, ok := b.values["last exit code"]; ok {
+		code, reason, found := strings.Cut(v, ": ")
+		if i, err := strconv.Atoi(code); err == nil {
+			svc.LastExitCode = i
+			if found {
+				svc.LastExitReason = reason
+			}
+		} else {
+			svc.LastExitReason = v
+		}
+	}
+
+	// Signal takes precedence over exit code.
+	if v, ok := b.values["last terminating signal"]; ok {
+		svc.LastExitReason = v
+	}
+
+	if args := b.child("arguments"); args != nil {
+		svc.Arguments = args.items
+	}
+
+	if env := b.child("environment"); env != nil {
+		svc.Environment = env.values
+	}
+
+	if sockets := b.child("sockets"); sockets != nil {
+		for _, s := range sockets.blocks {
+			svc.Sockets = append(svc.Sockets, SocketState{
+				Name:    s.name,
+				Type:    s.values["type"],
+				Passive: s.boolean("passive"),
+				Active:  s.boolean("active"),
+			})
+		}
+	}
+
+	if endpoints := b.child("endpoints"); endpoints != nil {
+		for _, e := range endpoints.blocks {
+			svc.Endpoints = append(svc.Endpoints, EndpointState{
+				Name:    e.name,
+				Active:  e.boolean("active"),
+				Managed: e.boolean("managed"),
+			})
+		}
+	}
+
+	if v := b.values["properties"]; v != "" {
+		for _, p := range strings.Split(v, "|") {
+			if p = strings.TrimSpace(p); p != "" {
+				svc.Properties = append(svc.Properties, p)
+			}
+		}
+	}
+	return svc, nil
+}
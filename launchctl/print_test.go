@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseService(t *testing.T) {
+	data, err := os.ReadFile("testdata/print.txt")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %s", err)
+	}
+
+	svc, err := parseService(data)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := &Service{
+		Label:     "com.example.svc",
+		Path:      "/Users/user/Library/LaunchAgents/com.example.svc.plist",
+		Type:      "LaunchAgent",
+		State:     "running",
+		Program:   "/usr/local/bin/svc",
+		Arguments: []string{"/usr/local/bin/svc", "--verbose"},
+		Environment: map[string]string{
+			"XPC_SERVICE_NAME": "com.example.svc",
+			"GREETING":         "hello = world",
+		},
+		PID:            1234,
+		Runs:           3,
+		LastExitCode:   78,
+		LastExitReason: "EX_CONFIG",
+		ExitTimeout:    5,
+		SpawnType:      "daemon",
+		Sockets: []SocketState{
+			{Name: "http", Type: "stream", Passive: true, Active: true},
+			{Name: "udp", Type: "dgram", Passive: true},
+		},
+		Endpoints: []EndpointState{
+			{Name: "com.example.svc.xpc", Managed: true},
+		},
+		Properties: []string{"runatload", "inferred program"},
+	}
+
+	if !reflect.DeepEqual(expect, svc) {
+		t.Errorf("expected=%#v\ngot=%#v", expect, svc)
+	}
+
+	if !svc.Running() {
+		t.Errorf("expected service to be running")
+	}
+}
+
+func TestParseService_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "unbalanced-open", data: "gui/501/com.example.svc = {\n"},
+		{name: "unbalanced-close", data: "}\n"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseService([]byte(tc.data))
+			if err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultPollInterval is the default interval between polling service state.
+const defaultPollInterval = 250 * time.Millisecond
+
+// RestartOptions are options for [Restart].
+type RestartOptions struct {
+	// PollInterval is the interval between checking whether the new
+	// instance of the service is running. Defaults to 250ms.
+	PollInterval time.Duration
+}
+
+// Restart restarts the service with the given label in the domain and
+// waits until the new instance of the service is running.
+//
+// Running instance of the service (if any) is killed and service is started
+// again with kickstart. Use a context with deadline or timeout to bound
+// the time spent waiting for the new instance.
+//
+//   - [*Error] is returned if launchctl fails or if service is not found.
+//   - [context.DeadlineExceeded] is returned if new instance is not running
+//     before context deadline.
+//   - [syscall.EINVAL] is returned if label is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Restart(ctx context.Context, domain Domain, label string, opts RestartOptions) (*Service, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	before, err := Print(ctx, domain, label)
+	if err != nil {
+		return nil, err
+	}
+
+	err = Kickstart(ctx, domain, label, KickstartOptions{Kill: true})
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		svc, err := Print(ctx, domain, label)
+		if err != nil {
+			return nil, err
+		}
+
+		// Service is considered restarted if it is running with a new pid,
+		// or has been started more times than before, as pids may be reused.
+		if svc.PID > 0 && (svc.PID != before.PID || svc.Runs > before.Runs) {
+			return svc, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("launchctl: service(%s) did not restart: %w",
+				domain.Service(label), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && cgo && launchd_cgo && !launchd_simulator
+
+package launchd
+
+/*
+#include <stdlib.h>
+#include <launch.h>
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/trace"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// activationBackend is the implementation of launch_activate_socket.
+const activationBackend = "cgo"
+
+// listenerFdsWithName returns file descriptors corresponding to the named socket.
+//
+// Unlike the default implementation, this calls launch_activate_socket
+// and free via cgo, without using go:linkname. See activate_darwin.go
+// for documentation of launch_activate_socket.
+func listenerFdsWithName(ctx context.Context, name string) ([]int32, error) {
+	if strings.IndexByte(name, 0) >= 0 {
+		return nil, fmt.Errorf("launchd: invalid socket name(%s): %w", name, syscall.EINVAL)
+	}
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	var fds *C.int
+	var count C.size_t
+
+	region := trace.StartRegion(ctx, "launch_activate_socket")
+	rc, _ := ignoringEINTR(func() (uintptr, syscall.Errno) {
+		return uintptr(C.launch_activate_socket(cname, &fds, &count)), 0
+	})
+	region.End()
+
+	debug("launchd: launch_activate_socket",
+		slog.String("name", name),
+		slog.String("backend", "cgo"),
+		slog.Int("rc", int(rc)),
+		slog.Uint64("count", uint64(count)),
+	)
+
+	if rc != 0 {
+		return nil, activationError(name, syscall.Errno(rc))
+	}
+	if err := checkActivated(name, uintptr(unsafe.Pointer(fds)), uint64(count)); err != nil {
+		if fds != nil {
+			C.free(unsafe.Pointer(fds))
+		}
+		return nil, err
+	}
+
+	fdSlice := make([]int32, 0, int(count))
+	for _, fd := range unsafe.Slice(fds, int(count)) {
+		fdSlice = append(fdSlice, int32(fd))
+	}
+
+	debug("launchd: activated file descriptors",
+		slog.String("name", name),
+		slog.Any("fds", fdSlice),
+	)
+
+	// de-allocate *fds.
+	region = trace.StartRegion(ctx, "free")
+	C.free(unsafe.Pointer(fds))
+	region.End()
+	return fdSlice, nil
+}
+
+// listenerFdsWithNames returns file descriptors corresponding to each of the
+// named sockets, along with per socket errors.
+func listenerFdsWithNames(ctx context.Context, names []string) ([][]int32, []error) {
+	return listenerFdsEach(ctx, names)
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// CoverDir returns the absolute path of coverage data directory of the
+// calling test binary, or empty if coverage is not enabled or if neither
+// -test.gocoverdir flag nor GOCOVERDIR environment variable is specified.
+//
+// Remote test binaries must be given an absolute path, as their working
+// directory is different from that of the calling test.
+//
+// This uses unexported test flag: -test.gocoverdir.
+// https://github.com/golang/go/issues/51430#issuecomment-1344711300
+func CoverDir(tb testing.TB) string {
+	tb.Helper()
+	if testing.CoverMode() == "" {
+		return ""
+	}
+
+	var dir string
+	if f := flag.Lookup("test.gocoverdir"); f != nil {
+		dir = f.Value.String()
+	}
+	if dir == "" {
+		dir = strings.TrimSpace(os.Getenv("GOCOVERDIR"))
+	}
+	if dir == "" {
+		return ""
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to get absolute path of coverage dir(%s): %s", dir, err)
+	}
+	return abs
+}
+
+// MergeCoverage merges coverage data written by remote test binaries to src,
+// into [CoverDir] of the calling test, with "go tool covdata merge".
+//
+// This is useful when remote test binary cannot write directly to [CoverDir],
+// for example when it runs as a different user. MergeCoverage is a no-op if
+// [CoverDir] is empty or src does not contain any coverage data.
+func MergeCoverage(tb testing.TB, src string) {
+	tb.Helper()
+	dst := CoverDir(tb)
+	if dst == "" {
+		return
+	}
+
+	// Coverage data files are named covmeta.<hash> and covcounters.<hash>.<pid>.<nanotime>.
+	meta, err := filepath.Glob(filepath.Join(src, "covmeta.*"))
+	if err != nil || len(meta) == 0 {
+		tb.Logf("No coverage data in %s", src)
+		return
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		goBin = filepath.Join(runtime.GOROOT(), "bin", "go")
+	}
+
+	//nolint:gosec // arguments are paths.
+	cmd := exec.Command(goBin, "tool", "covdata", "merge", "-i="+src, "-o="+dst)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to merge coverage data from %s: %s: %s", src, err, output)
+	}
+	tb.Logf("Merged coverage data from %s to %s", src, dst)
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchdtest"
+)
+
+func TestCoverDir(t *testing.T) {
+	dir := launchdtest.CoverDir(t)
+	if testing.CoverMode() == "" && dir != "" {
+		t.Errorf("expected no coverage dir when coverage is disabled, got=%s", dir)
+	}
+	if dir != "" && !filepath.IsAbs(dir) {
+		t.Errorf("expected absolute coverage dir, got=%s", dir)
+	}
+}
+
+// TestRemoteCover is run by [TestMergeCoverage].
+func TestRemoteCover(t *testing.T) {
+	if !launchdtest.Remote() {
+		t.SkipNow()
+	}
+	defer launchdtest.Done(t)
+	launchdtest.Report(t, true, "")
+}
+
+func TestMergeCoverage(t *testing.T) {
+	// No-op without coverage data.
+	launchdtest.MergeCoverage(t, t.TempDir())
+
+	dst := launchdtest.CoverDir(t)
+	if dst == "" {
+		t.Skip("coverage dir is not specified")
+	}
+
+	src := t.TempDir()
+	result := launchdtest.Simulate(t, "^TestRemoteCover$", launchdtest.WithCoverDir(src))
+	if result.OK() != 1 {
+		t.Fatalf("expected remote events=1, got=%d", result.OK())
+	}
+
+	before, _ := filepath.Glob(filepath.Join(dst, "covcounters.*"))
+	launchdtest.MergeCoverage(t, src)
+	after, _ := filepath.Glob(filepath.Join(dst, "covcounters.*"))
+	if len(after) <= len(before) {
+		entries, _ := os.ReadDir(dst)
+		t.Errorf("expected merged coverage counters in %s, got=%v", dst, entries)
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package launchdtest provides a harness for testing launchd integration,
+// like socket activation, by running tests under launchd.
+//
+// [Run] installs a temporary LaunchAgent which runs the test binary itself,
+// restricted to the given remote tests, with the specified sockets and
+// environment. Remote tests detect that they are running under the harness
+// with [Remote], publish their results with [Report] and signal completion
+// with [Done]. The agent is removed when the calling test completes.
+//
+//	func TestRemote(t *testing.T) {
+//		if !launchdtest.Remote() {
+//			t.SkipNow()
+//		}
+//		defer launchdtest.Done(t)
+//
+//		_, err := launchd.Listeners("http")
+//		launchdtest.Report(t, err == nil, fmt.Sprint(err))
+//	}
+//
+//	func TestLaunchd(t *testing.T) {
+//		result := launchdtest.Run(t, "^TestRemote$",
+//			launchdtest.WithSockets(map[string]plist.Socket{
+//				"http": {SockServiceName: strconv.Itoa(launchdtest.FreePort(t))},
+//			}),
+//		)
+//		if result.Errors() > 0 {
+//			t.Errorf("remote tests failed")
+//		}
+//	}
+//
+// On non-macOS platforms (including iOS), [Run] skips the calling test.
+// [Simulate] runs remote tests on other unix platforms, with sockets bound
+// by the harness. Sockets bound with [Listen] and passed with [WithFiles]
+// avoid races of picking ports with [FreePort].
+//
+// [AssertPlistEqual] compares generated property lists with golden files,
+// ignoring key ordering and formatting, which is useful for snapshot testing
+// plists generated with the plist package.
+package launchdtest
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// UpdateGoldenEnv is the environment variable which, when set to "1" or
+// "true", makes [AssertPlistEqual] write golden files instead of comparing.
+const UpdateGoldenEnv = "LAUNCHDTEST_UPDATE_GOLDEN"
+
+// RenderPlist returns normalized XML property list representation of v,
+// with dictionary keys sorted, suitable for comparing with golden files.
+//
+// If v is a []byte, it must be an encoded XML or binary property list.
+// Otherwise v must be a value which can be encoded with [plist.Marshal],
+// like [plist.Job].
+func RenderPlist(v any) ([]byte, error) {
+	data, ok := v.([]byte)
+	if !ok {
+		var err error
+		if data, err = plist.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	// Round trip via generic value, so that struct fields are sorted too.
+	var generic any
+	if err := plist.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return plist.Marshal(generic)
+}
+
+// AssertPlistEqual checks that property list got is equal to the golden
+// file, which may be an XML or binary property list. Key ordering and
+// formatting are ignored. On mismatch, differences are reported with
+// tb.Errorf. See [RenderPlist] for accepted values of got.
+//
+// If [UpdateGoldenEnv] is set, golden file is overwritten with the
+// normalized XML property list representation of got instead.
+func AssertPlistEqual(tb testing.TB, got any, golden string) {
+	tb.Helper()
+
+	rendered, err := RenderPlist(got)
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to render plist: %s", err)
+	}
+
+	if update := strings.ToLower(os.Getenv(UpdateGoldenEnv)); update == "1" || update == "true" {
+		if err = os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			tb.Fatalf("launchdtest: failed to create golden file dir: %s", err)
+		}
+		if err = os.WriteFile(golden, rendered, 0o644); err != nil {
+			tb.Fatalf("launchdtest: failed to update golden file: %s", err)
+		}
+		tb.Logf("Updated golden file: %s", golden)
+		return
+	}
+
+	data, err := os.ReadFile(golden)
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to read golden file(%s): %s", golden, err)
+	}
+
+	var want, have any
+	if err = plist.Unmarshal(data, &want); err != nil {
+		tb.Fatalf("launchdtest: invalid golden file(%s): %s", golden, err)
+	}
+	if err = plist.Unmarshal(rendered, &have); err != nil {
+		tb.Fatalf("launchdtest: failed to decode plist: %s", err)
+	}
+
+	changes, err := plist.Diff(want, have)
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to compare plists: %s", err)
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	for _, c := range changes {
+		b.WriteString("\n\t")
+		b.WriteString(c.String())
+	}
+	tb.Errorf("launchdtest: plist does not match golden file(%s):%s", golden, b.String())
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchdtest"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// recorder records errors reported by assertions.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func testJob() plist.Job {
+	return plist.Job{
+		Label:            "com.example.svc",
+		ProgramArguments: []string{"/usr/local/bin/svc", "--flag"},
+		RunAtLoad:        true,
+		Sockets: map[string]plist.Socket{
+			"http": {SockType: "stream", SockServiceName: "8080"},
+		},
+	}
+}
+
+func TestAssertPlistEqual(t *testing.T) {
+	t.Setenv(launchdtest.UpdateGoldenEnv, "")
+	job := testJob()
+	data, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("failed to marshal job: %s", err)
+	}
+
+	for _, golden := range []string{"testdata/job.plist", "testdata/job.bplist"} {
+		t.Run(filepath.Base(golden), func(t *testing.T) {
+			launchdtest.AssertPlistEqual(t, job, golden)
+			launchdtest.AssertPlistEqual(t, &job, golden)
+			launchdtest.AssertPlistEqual(t, data, golden)
+		})
+	}
+}
+
+func TestAssertPlistEqual_Mismatch(t *testing.T) {
+	t.Setenv(launchdtest.UpdateGoldenEnv, "")
+	job := testJob()
+	job.Sockets["http"] = plist.Socket{SockType: "stream", SockServiceName: "8081"}
+
+	r := &recorder{TB: t}
+	launchdtest.AssertPlistEqual(r, job, "testdata/job.bplist")
+	if len(r.errors) != 1 {
+		t.Fatalf("expected errors=1, got=%d", len(r.errors))
+	}
+	if !strings.Contains(r.errors[0], "~ Sockets.http.SockServiceName: 8080 => 8081") {
+		t.Errorf("expected error to contain the change, got=%s", r.errors[0])
+	}
+}
+
+func TestAssertPlistEqual_Update(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "golden", "job.plist")
+	t.Setenv(launchdtest.UpdateGoldenEnv, "1")
+	launchdtest.AssertPlistEqual(t, testJob(), golden)
+
+	data, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("expected golden file to be written: %s", err)
+	}
+	expect, err := launchdtest.RenderPlist(testJob())
+	if err != nil {
+		t.Fatalf("failed to render plist: %s", err)
+	}
+	if string(data) != string(expect) {
+		t.Errorf("expected golden=%s, got=%s", expect, data)
+	}
+
+	t.Setenv(launchdtest.UpdateGoldenEnv, "")
+	launchdtest.AssertPlistEqual(t, testJob(), golden)
+}
+
+func TestRenderPlist(t *testing.T) {
+	xmlData, err := os.ReadFile("testdata/job.plist")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %s", err)
+	}
+	binData, err := os.ReadFile("testdata/job.bplist")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %s", err)
+	}
+
+	fromXML, err := launchdtest.RenderPlist(xmlData)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	fromBinary, err := launchdtest.RenderPlist(binData)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	fromJob, err := launchdtest.RenderPlist(testJob())
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if string(fromXML) != string(fromBinary) || string(fromXML) != string(fromJob) {
+		t.Errorf("expected identical renders, got:\n%s\n%s\n%s", fromXML, fromBinary, fromJob)
+	}
+
+	if _, err = launchdtest.RenderPlist([]byte("invalid")); err == nil {
+		t.Errorf("expected error for invalid plist")
+	}
+}
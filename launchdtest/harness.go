@@ -0,0 +1,330 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// DefaultTimeout is the default time budget for remote tests.
+const DefaultTimeout = 30 * time.Second
+
+// Event is a result published by a remote test with [Report].
+type Event struct {
+	// Name is the name of the remote test.
+	Name string `json:"name"`
+
+	// Success reports whether the remote test was successful.
+	Success bool `json:"success,omitempty"`
+
+	// Message is an optional message, typically the error.
+	Message string `json:"message,omitempty"`
+}
+
+// Result is the result of running remote tests with [Run].
+type Result struct {
+	// Label is the label of the temporary LaunchAgent.
+	Label string
+
+	// Events are the results published by remote tests.
+	Events []Event
+
+	// Stdout is the standard output of the remote test binary.
+	Stdout []byte
+
+	// Stderr is the standard error of the remote test binary.
+	Stderr []byte
+}
+
+// OK returns the number of successful events.
+func (r *Result) OK() int {
+	var n int
+	for _, e := range r.Events {
+		if e.Success {
+			n++
+		}
+	}
+	return n
+}
+
+// Errors returns the number of failed events.
+func (r *Result) Errors() int {
+	return len(r.Events) - r.OK()
+}
+
+// Option configures [Run].
+type Option func(*options)
+
+type options struct {
+	sockets  map[string]plist.Socket
+	files    map[string][]*os.File
+	env      map[string]string
+	coverDir string
+	timeout  time.Duration
+}
+
+// WithSockets sets the Sockets dictionary of the LaunchAgent.
+func WithSockets(sockets map[string]plist.Socket) Option {
+	return func(o *options) {
+		o.sockets = sockets
+	}
+}
+
+// WithFiles sets sockets already bound by the calling test, keyed by name,
+// which [Simulate] hands to the test binary in addition to the sockets of
+// [WithSockets]. Names must not be used by both. Files are not closed by
+// [Simulate]. Use [Listen] to bind sockets to ports chosen by the kernel.
+//
+// This is not supported by [Run], as launchd binds sockets of the job itself.
+func WithFiles(files map[string][]*os.File) Option {
+	return func(o *options) {
+		o.files = files
+	}
+}
+
+// WithEnv sets additional environment variables of the remote tests.
+func WithEnv(env map[string]string) Option {
+	return func(o *options) {
+		o.env = env
+	}
+}
+
+// WithCoverDir sets the directory where remote test binary writes
+// coverage data. Defaults to [CoverDir] of the calling test.
+//
+// Use a separate directory and [MergeCoverage] if remote test binary
+// cannot write to the coverage directory of the calling test.
+func WithCoverDir(dir string) Option {
+	return func(o *options) {
+		o.coverDir = dir
+	}
+}
+
+// WithTimeout sets the time budget for remote tests, after which [Run]
+// fails the calling test. Defaults to [DefaultTimeout].
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.timeout = d
+		}
+	}
+}
+
+// Run runs the tests of the current test binary matching pattern
+// (as in -test.run) under launchd, as a temporary LaunchAgent in the
+// current domain, and returns the events published by them. It blocks
+// until remote tests call [Done] or timeout is exceeded.
+//
+// Output of the remote test binary is logged with tb.Log. The agent
+// is booted out and its files are removed when tb completes.
+//
+// Run skips tb on non-macOS platforms (including iOS) and fails it
+// if agent cannot be installed or if remote tests time out.
+func Run(tb testing.TB, pattern string, opts ...Option) *Result {
+	tb.Helper()
+
+	o := newOptions(tb, opts)
+	if len(o.files) > 0 {
+		tb.Fatalf("launchdtest: WithFiles is not supported by Run")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+
+	domain, err := launchctl.CurrentDomain(ctx)
+	if errors.Is(err, syscall.ENOTSUP) {
+		tb.Skipf("launchdtest: %s", err)
+	}
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to get current domain: %s", err)
+	}
+
+	result := &Result{Label: label(tb)}
+	server, events := collect(tb, cancel)
+	defer server.Close()
+
+	job, err := newJob(tb, result.Label, pattern, server.URL, o)
+	if err != nil {
+		tb.Fatalf("launchdtest: %s", err)
+	}
+
+	path := filepath.Join(filepath.Dir(job.StandardOutPath), result.Label+".plist")
+	data, err := plist.Marshal(job)
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to encode plist: %s", err)
+	}
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		tb.Fatalf("launchdtest: failed to write plist: %s", err)
+	}
+
+	tb.Logf("Bootstrapping %s in %s", result.Label, domain)
+	if err = launchctl.Bootstrap(ctx, domain, path); err != nil {
+		tb.Fatalf("launchdtest: failed to bootstrap agent: %s", err)
+	}
+	tb.Cleanup(func() {
+		err := launchctl.Bootout(context.Background(), domain, result.Label)
+		if err != nil && !errors.Is(err, launchctl.ErrNotFound) {
+			tb.Errorf("launchdtest: failed to boot out agent: %s", err)
+		}
+	})
+
+	tb.Logf("Waiting for remote tests to publish results...")
+	<-ctx.Done()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		tb.Errorf("launchdtest: timed out waiting for remote tests (remote panic?)")
+	}
+
+	result.Stdout, _ = os.ReadFile(job.StandardOutPath)
+	result.Stderr, _ = os.ReadFile(job.StandardErrorPath)
+	logOutput(tb, "Remote Stdout", result.Stdout)
+	logOutput(tb, "Remote Stderr", result.Stderr)
+
+	result.Events = events()
+	return result
+}
+
+// newOptions returns options with defaults applied.
+func newOptions(tb testing.TB, opts []Option) options {
+	o := options{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	if o.coverDir == "" {
+		o.coverDir = CoverDir(tb)
+	}
+	return o
+}
+
+// collect starts a server which collects events published by remote tests
+// and calls cancel when remote tests signal completion with [Done].
+// Returned function returns the events received so far.
+func collect(tb testing.TB, cancel context.CancelFunc) (*httptest.Server, func() []Event) {
+	var mu sync.Mutex
+	var events []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var event Event
+			if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				tb.Errorf("launchdtest: invalid event: %s", err)
+				return
+			}
+			if event.Success {
+				tb.Logf("%s => SUCCESS", event.Name)
+			} else {
+				tb.Logf("%s => ERROR %s", event.Name, event.Message)
+			}
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		case http.MethodDelete:
+			tb.Logf("Received all test events")
+			cancel()
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return server, func() []Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(events)
+	}
+}
+
+// newJob returns the LaunchAgent running remote tests.
+func newJob(tb testing.TB, label, pattern, addr string, o options) (plist.Job, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return plist.Job{}, err
+	}
+
+	dir := tb.TempDir()
+	env := map[string]string{ServerAddrEnv: addr}
+	for k, v := range o.env {
+		env[k] = v
+	}
+
+	job := plist.Job{
+		Label:                label,
+		ProgramArguments:     testArgs(exe, pattern, o),
+		EnvironmentVariables: env,
+		RunAtLoad:            true,
+		StandardOutPath:      filepath.Join(dir, "stdout.log"),
+		StandardErrorPath:    filepath.Join(dir, "stderr.log"),
+		Sockets:              o.sockets,
+	}
+	return job, job.Validate()
+}
+
+// testArgs returns arguments of the test binary exe, running
+// only tests matching pattern.
+func testArgs(exe, pattern string, o options) []string {
+	args := []string{
+		exe,
+		"-test.count=1",
+		"-test.run=" + pattern,
+		"-test.timeout=" + o.timeout.String(),
+		"-test.v=true",
+	}
+	if o.coverDir != "" {
+		args = append(args, "-test.gocoverdir="+o.coverDir)
+	}
+	return args
+}
+
+// label returns a random label for the agent.
+func label(tb testing.TB) string {
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		tb.Fatalf("launchdtest: failed to generate label: %s", err)
+	}
+	return "test.go-launchd." + hex.EncodeToString(b)
+}
+
+// FreePort asks the kernel for a free port on localhost, for use
+// as SockServiceName of the sockets of [Run].
+//
+// Port is released before it is returned, thus it might be taken by
+// another process before launchd binds it. With [Simulate], use [Listen]
+// and [WithFiles] instead, which bind sockets without such a race.
+func FreePort(tb testing.TB) int {
+	tb.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		tb.Fatalf("launchdtest: failed to get free port: %s", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// logOutput logs output line by line with the prefix.
+func logOutput(tb testing.TB, prefix string, output []byte) {
+	tb.Helper()
+	for _, line := range bytes.Split(bytes.TrimRight(output, "\n"), []byte("\n")) {
+		if len(line) > 0 {
+			tb.Logf("(%s) %s", prefix, line)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchdtest_test
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchdtest"
+)
+
+func TestRun_Unsupported(t *testing.T) {
+	var skipped bool
+	t.Run("Run", func(t *testing.T) {
+		t.Cleanup(func() { skipped = t.Skipped() })
+		launchdtest.Run(t, "^TestNone$")
+		t.Errorf("expected Run to skip on unsupported platforms")
+	})
+	if !skipped {
+		t.Errorf("expected Run to skip on unsupported platforms")
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchdtest"
+)
+
+func TestResult(t *testing.T) {
+	r := launchdtest.Result{
+		Events: []launchdtest.Event{
+			{Name: "a", Success: true},
+			{Name: "b", Message: "failed"},
+			{Name: "c", Success: true},
+		},
+	}
+	if r.OK() != 2 {
+		t.Errorf("expected ok=2, got=%d", r.OK())
+	}
+	if r.Errors() != 1 {
+		t.Errorf("expected errors=1, got=%d", r.Errors())
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	if port := launchdtest.FreePort(t); port <= 0 || port > 65535 {
+		t.Errorf("expected valid port, got=%d", port)
+	}
+}
+
+func TestReport(t *testing.T) {
+	var mu sync.Mutex
+	var events []launchdtest.Event
+	var done bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPost:
+			var event launchdtest.Event
+			if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+				t.Errorf("invalid event: %s", err)
+			}
+			events = append(events, event)
+		case http.MethodDelete:
+			done = true
+		}
+	}))
+	defer server.Close()
+
+	// Without server address, Report and Done are no-op.
+	t.Setenv(launchdtest.ServerAddrEnv, "")
+	if launchdtest.Remote() {
+		t.Errorf("expected Remote=false without %s", launchdtest.ServerAddrEnv)
+	}
+	launchdtest.Report(t, true, "")
+	launchdtest.Done(t)
+
+	t.Setenv(launchdtest.ServerAddrEnv, server.URL)
+	if !launchdtest.Remote() {
+		t.Errorf("expected Remote=true with %s", launchdtest.ServerAddrEnv)
+	}
+	launchdtest.Report(t, false, "message")
+	launchdtest.Done(t)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 {
+		t.Fatalf("expected events=1, got=%d", len(events))
+	}
+	expect := launchdtest.Event{Name: t.Name(), Message: "message"}
+	if events[0] != expect {
+		t.Errorf("expected event=%+v, got=%+v", expect, events[0])
+	}
+	if !done {
+		t.Errorf("expected Done to be received")
+	}
+}
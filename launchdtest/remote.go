@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// ServerAddrEnv is the environment variable with the address of the
+// server of the harness, which collects results of remote tests.
+const ServerAddrEnv = "GO_TEST_SERVER_ADDR"
+
+// Remote reports whether the current process is a remote test binary
+// started by [Run].
+func Remote() bool {
+	return os.Getenv(ServerAddrEnv) != ""
+}
+
+// Report publishes the result of the remote test tb to the harness.
+// It is a no-op if the current process is not started by [Run].
+func Report(tb testing.TB, ok bool, msg string) {
+	tb.Helper()
+	if !Remote() {
+		return
+	}
+
+	body, err := json.Marshal(Event{Name: tb.Name(), Success: ok, Message: msg})
+	if err != nil {
+		tb.Errorf("launchdtest: %s", err)
+		return
+	}
+	send(tb, http.MethodPost, body)
+}
+
+// Done signals the harness that all remote tests have completed.
+// It is a no-op if the current process is not started by [Run].
+func Done(tb testing.TB) {
+	tb.Helper()
+	if Remote() {
+		send(tb, http.MethodDelete, nil)
+	}
+}
+
+// send sends a request to the harness.
+func send(tb testing.TB, method string, body []byte) {
+	tb.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, os.Getenv(ServerAddrEnv), bytes.NewReader(body))
+	if err != nil {
+		tb.Errorf("launchdtest: %s", err)
+		return
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		tb.Errorf("launchdtest: %s", err)
+		return
+	}
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchdtest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// SimulatorSocketEnv is the environment variable with the path of the
+// unix socket of the simulated launchd started by [Simulate].
+const SimulatorSocketEnv = "LAUNCHD_SIMULATOR_SOCKET"
+
+// Simulate is like [Run], but instead of installing a LaunchAgent, it emulates
+// launchd by binding the sockets itself and running the test binary as a child
+// process. Sockets are handed to the child over a unix socket with SCM_RIGHTS,
+// when it calls launchd.Files, launchd.Listeners or launchd.PacketListeners.
+//
+// This allows exercising socket activation code paths (except calls to libc)
+// on platforms other than macOS, typically on Linux CI. Test binary must be
+// built with "launchd_simulator" build tag, for example,
+//
+//	go test -tags launchd_simulator ./...
+//
+// Only a subset of socket keys are supported, SockType, SockFamily,
+// SockNodeName, SockServiceName, SockPathName and SockPathMode. Node name
+// "localhost" always resolves to both IPv4 and IPv6 loopback addresses,
+// like on macOS.
+//
+// Simulate skips tb on non-unix platforms.
+func Simulate(tb testing.TB, pattern string, opts ...Option) *Result {
+	tb.Helper()
+	return simulate(tb, pattern, newOptions(tb, opts))
+}
+
+// Listen binds the sockets like [Simulate] and returns their files, for use
+// with [WithFiles]. Inet sockets with empty or "0" SockServiceName are bound
+// to ports chosen by the kernel, which can be read from the files, unlike
+// ports returned by [FreePort], which might be taken by other processes
+// before they are bound. Files are closed when tb completes.
+//
+// Listen skips tb on non-unix platforms.
+func Listen(tb testing.TB, sockets map[string]plist.Socket) map[string][]*os.File {
+	tb.Helper()
+	return listen(tb, sockets)
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package launchdtest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Platform specific implementation of [Simulate].
+func simulate(tb testing.TB, _ string, _ options) *Result {
+	tb.Helper()
+	tb.Skipf("launchdtest: simulator is only supported on unix")
+	return nil
+}
+
+// Platform specific implementation of [Listen].
+func listen(tb testing.TB, _ map[string]plist.Socket) map[string][]*os.File {
+	tb.Helper()
+	tb.Skipf("launchdtest: simulator is only supported on unix")
+	return nil
+}
@@ -0,0 +1,356 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchdtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Platform specific implementation of [Simulate].
+func simulate(tb testing.TB, pattern string, o options) *Result {
+	tb.Helper()
+
+	exe, err := os.Executable()
+	if err != nil {
+		tb.Fatalf("launchdtest: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+
+	result := &Result{Label: label(tb)}
+	server, events := collect(tb, cancel)
+	defer server.Close()
+
+	// Unix socket paths are limited to ~104 bytes, thus do not use tb.TempDir,
+	// which includes the test name.
+	dir, err := os.MkdirTemp("", "launchdtest-")
+	if err != nil {
+		tb.Fatalf("launchdtest: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sim, err := newSimulator(filepath.Join(dir, "launchd.socket"), o.sockets, o.files)
+	if err != nil {
+		tb.Fatalf("launchdtest: %s", err)
+	}
+	defer sim.Close()
+	go sim.Serve()
+
+	var stdout, stderr bytes.Buffer
+	args := testArgs(exe, pattern, o)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		ServerAddrEnv+"="+server.URL,
+		SimulatorSocketEnv+"="+sim.Path(),
+	)
+	for k, v := range o.env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	tb.Logf("Starting %s with simulated launchd", result.Label)
+	if err = cmd.Start(); err != nil {
+		tb.Fatalf("launchdtest: failed to start test binary: %s", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	tb.Logf("Waiting for remote tests to publish results...")
+	select {
+	case err = <-exited:
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			tb.Errorf("launchdtest: timed out waiting for remote tests (remote panic?)")
+			_ = cmd.Process.Kill()
+		}
+		err = <-exited
+	}
+	if err != nil {
+		tb.Logf("Test binary exited: %s", err)
+	}
+
+	result.Stdout = stdout.Bytes()
+	result.Stderr = stderr.Bytes()
+	logOutput(tb, "Remote Stdout", result.Stdout)
+	logOutput(tb, "Remote Stderr", result.Stderr)
+
+	result.Events = events()
+	return result
+}
+
+// simulator emulates launch_activate_socket for processes
+// connecting to its unix socket.
+type simulator struct {
+	ln      *net.UnixListener
+	mu      sync.Mutex
+	closers []io.Closer
+	sockets map[string][]*os.File
+	active  map[string]bool
+}
+
+// Platform specific implementation of [Listen].
+func listen(tb testing.TB, sockets map[string]plist.Socket) map[string][]*os.File {
+	tb.Helper()
+	s := &simulator{sockets: make(map[string][]*os.File, len(sockets))}
+	tb.Cleanup(func() { s.Close() })
+	for name, socket := range sockets {
+		if err := s.bind(name, socket); err != nil {
+			tb.Fatalf("launchdtest: failed to bind socket(%s): %s", name, err)
+		}
+	}
+	return s.sockets
+}
+
+// newSimulator binds the sockets and listens on unix socket at path.
+// Files are sockets already bound by the caller, which are not closed
+// when simulator is closed.
+func newSimulator(path string, sockets map[string]plist.Socket, files map[string][]*os.File) (*simulator, error) {
+	s := &simulator{
+		sockets: make(map[string][]*os.File, len(sockets)+len(files)),
+		active:  make(map[string]bool, len(sockets)+len(files)),
+	}
+	for name, f := range files {
+		if _, ok := sockets[name]; ok {
+			return nil, fmt.Errorf("socket(%s) is specified with both sockets and files: %w", name, syscall.EINVAL)
+		}
+		s.sockets[name] = f
+	}
+	for name, socket := range sockets {
+		if err := s.bind(name, socket); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("failed to bind socket(%s): %w", name, err)
+		}
+	}
+
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.ln = ln
+	return s, nil
+}
+
+// Path returns the path of the unix socket of the simulator.
+func (s *simulator) Path() string {
+	return s.ln.Addr().String()
+}
+
+// Serve handles requests until simulator is closed.
+func (s *simulator) Serve() {
+	for {
+		conn, err := s.ln.AcceptUnix()
+		if err != nil {
+			return
+		}
+		s.handle(conn)
+	}
+}
+
+// Close closes the simulator and all bound sockets.
+func (s *simulator) Close() error {
+	var err error
+	if s.ln != nil {
+		err = s.ln.Close()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.closers {
+		err = errors.Join(err, c.Close())
+	}
+	s.closers = nil
+	return err
+}
+
+// handle handles a single activation request.
+//
+// Request is the name of the socket, terminated by closing the write side of
+// the connection. Response is a 4 byte big endian errno, with the file
+// descriptors sent as SCM_RIGHTS if errno is zero.
+func (s *simulator) handle(conn *net.UnixConn) {
+	defer conn.Close()
+
+	name, err := io.ReadAll(io.LimitReader(conn, 1024))
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	files, ok := s.sockets[string(name)]
+	errno := syscall.Errno(0)
+	switch {
+	case !ok:
+		errno = syscall.ENOENT
+	case s.active[string(name)]:
+		errno = syscall.EALREADY
+	default:
+		s.active[string(name)] = true
+	}
+	s.mu.Unlock()
+
+	var oob []byte
+	if errno == 0 {
+		fds := make([]int, 0, len(files))
+		for _, f := range files {
+			fds = append(fds, int(f.Fd()))
+		}
+		oob = syscall.UnixRights(fds...)
+	}
+	_, _, _ = conn.WriteMsgUnix(binary.BigEndian.AppendUint32(nil, uint32(errno)), oob, nil)
+}
+
+// bind binds the sockets for the socket definition.
+func (s *simulator) bind(name string, socket plist.Socket) error {
+	dgram := socket.SockType == "dgram"
+	if socket.SockType != "" && socket.SockType != "stream" && !dgram {
+		return fmt.Errorf("unsupported socket type(%s): %w", socket.SockType, syscall.ESOCKTNOSUPPORT)
+	}
+
+	var networks, addrs []string
+	if socket.SockPathName != "" {
+		networks = []string{"unix"}
+		if dgram {
+			networks[0] = "unixgram"
+		}
+		addrs = []string{socket.SockPathName}
+	} else {
+		var err error
+		networks, addrs, err = resolve(socket, dgram)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range networks {
+		f, err := s.listen(networks[i], addrs[i])
+		if err != nil {
+			return err
+		}
+		s.sockets[name] = append(s.sockets[name], f)
+	}
+
+	if socket.SockPathName != "" && socket.SockPathMode != 0 {
+		return os.Chmod(socket.SockPathName, os.FileMode(socket.SockPathMode))
+	}
+	return nil
+}
+
+// fileCloser is implemented by listeners and packet conns of the net package.
+type fileCloser interface {
+	io.Closer
+	File() (*os.File, error)
+}
+
+// listen binds to the address and returns its file.
+func (s *simulator) listen(network, addr string) (*os.File, error) {
+	var c fileCloser
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		l, err := net.Listen(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		c = l.(fileCloser)
+	default:
+		l, err := net.ListenPacket(network, addr)
+		if err != nil {
+			return nil, err
+		}
+		c = l.(fileCloser)
+	}
+	s.closers = append(s.closers, c)
+
+	f, err := c.File()
+	if err != nil {
+		return nil, err
+	}
+	s.closers = append(s.closers, f)
+	return f, nil
+}
+
+// resolve returns networks and addresses to bind for the inet socket.
+func resolve(socket plist.Socket, dgram bool) ([]string, []string, error) {
+	proto := "tcp"
+	if dgram {
+		proto = "udp"
+	}
+
+	port := socket.SockServiceName
+	if port == "" {
+		port = "0"
+	}
+
+	var ips []net.IP
+	switch strings.ToLower(socket.SockNodeName) {
+	case "":
+		ips = []net.IP{net.IPv4zero, net.IPv6unspecified}
+	case "localhost":
+		ips = []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	default:
+		var err error
+		ips, err = net.LookupIP(socket.SockNodeName)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var networks, addrs []string
+	for _, ip := range ips {
+		v4 := ip.To4() != nil
+		switch socket.SockFamily {
+		case "IPv4":
+			if !v4 {
+				continue
+			}
+			networks = append(networks, proto+"4")
+		case "IPv6":
+			if v4 {
+				continue
+			}
+			networks = append(networks, proto+"6")
+		case "IPv4v6":
+			// Single socket with the first resolved address.
+			if len(addrs) > 0 {
+				continue
+			}
+			networks = append(networks, proto)
+		case "":
+			if v4 {
+				networks = append(networks, proto+"4")
+			} else {
+				networks = append(networks, proto+"6")
+			}
+		default:
+			return nil, nil, fmt.Errorf("unsupported socket family(%s): %w", socket.SockFamily, syscall.EAFNOSUPPORT)
+		}
+		addrs = append(addrs, net.JoinHostPort(ip.String(), port))
+	}
+
+	if len(addrs) == 0 {
+		return nil, nil, fmt.Errorf("no addresses for %s: %w", socket.SockNodeName, syscall.EADDRNOTAVAIL)
+	}
+	return networks, addrs, nil
+}
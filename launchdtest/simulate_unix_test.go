@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchdtest
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// activate sends an activation request to the simulator.
+func activate(t *testing.T, path, name string) ([]int, error) {
+	t.Helper()
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatalf("failed to connect to simulator: %s", err)
+	}
+	defer conn.Close()
+
+	_, _ = conn.Write([]byte(name))
+	_ = conn.CloseWrite()
+
+	buf := make([]byte, 4)
+	oob := make([]byte, syscall.CmsgSpace(16*4))
+	_, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("failed to read response: %s", err)
+	}
+	if errno := syscall.Errno(binary.BigEndian.Uint32(buf)); errno != 0 {
+		return nil, errno
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		t.Fatalf("invalid control message: %s", err)
+	}
+	var fds []int
+	for i := range msgs {
+		rights, _ := syscall.ParseUnixRights(&msgs[i])
+		fds = append(fds, rights...)
+	}
+	return fds, nil
+}
+
+func TestSimulator(t *testing.T) {
+	dir, err := os.MkdirTemp("", "launchdtest-")
+	if err != nil {
+		t.Fatalf("failed to create dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	files := Listen(t, map[string]plist.Socket{
+		"bound": {SockNodeName: "localhost", SockFamily: "IPv4", SockServiceName: "0"},
+	})
+	sim, err := newSimulator(filepath.Join(dir, "launchd.socket"), map[string]plist.Socket{
+		"tcp":  {SockNodeName: "localhost", SockFamily: "IPv4", SockType: "stream"},
+		"udp":  {SockNodeName: "localhost", SockType: "dgram"},
+		"unix": {SockPathName: filepath.Join(dir, "unix.socket"), SockPathMode: 0o600},
+	}, files)
+	if err != nil {
+		t.Fatalf("failed to create simulator: %s", err)
+	}
+	t.Cleanup(func() { sim.Close() })
+	go sim.Serve()
+
+	tt := []struct {
+		name  string
+		count int
+		err   error
+	}{
+		{name: "tcp", count: 1},
+		{name: "tcp", err: syscall.EALREADY},
+		{name: "udp", count: 2},
+		{name: "unix", count: 1},
+		{name: "bound", count: 1},
+		{name: "no-such-socket", err: syscall.ENOENT},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			fds, err := activate(t, sim.Path(), tc.name)
+			for _, fd := range fds {
+				syscall.Close(fd)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%v, got=%v", tc.err, err)
+			}
+			if len(fds) != tc.count {
+				t.Errorf("expected fds=%d, got=%d", tc.count, len(fds))
+			}
+		})
+	}
+
+	stat, err := os.Stat(filepath.Join(dir, "unix.socket"))
+	if err != nil {
+		t.Fatalf("failed to stat unix socket: %s", err)
+	}
+	if stat.Mode().Perm() != 0o600 {
+		t.Errorf("expected mode=%s, got=%s", os.FileMode(0o600), stat.Mode().Perm())
+	}
+}
+
+func TestNewSimulator_DuplicateName(t *testing.T) {
+	dir, err := os.MkdirTemp("", "launchdtest-")
+	if err != nil {
+		t.Fatalf("failed to create dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	socket := plist.Socket{SockNodeName: "localhost", SockFamily: "IPv4"}
+	files := Listen(t, map[string]plist.Socket{"tcp": socket})
+	_, err = newSimulator(filepath.Join(dir, "launchd.socket"), map[string]plist.Socket{"tcp": socket}, files)
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%v", syscall.EINVAL, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tt := []struct {
+		name     string
+		socket   plist.Socket
+		networks []string
+		err      error
+	}{
+		{
+			name:     "Localhost",
+			socket:   plist.Socket{SockNodeName: "localhost"},
+			networks: []string{"tcp4", "tcp6"},
+		},
+		{
+			name:     "Wildcard-IPv6",
+			socket:   plist.Socket{SockFamily: "IPv6"},
+			networks: []string{"tcp6"},
+		},
+		{
+			name:     "DualStack",
+			socket:   plist.Socket{SockNodeName: "localhost", SockFamily: "IPv4v6"},
+			networks: []string{"tcp"},
+		},
+		{
+			name:   "InvalidFamily",
+			socket: plist.Socket{SockFamily: "IPX"},
+			err:    syscall.EAFNOSUPPORT,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			networks, _, err := resolve(tc.socket, false)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%v, got=%v", tc.err, err)
+			}
+			if len(networks) != len(tc.networks) {
+				t.Fatalf("expected networks=%v, got=%v", tc.networks, networks)
+			}
+			for i := range networks {
+				if networks[i] != tc.networks[i] {
+					t.Errorf("expected networks=%v, got=%v", tc.networks, networks)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LegacyKind is the kind of a [LegacyItem].
+type LegacyKind int
+
+const (
+	// LegacyLoginItem is a login item registered with System Events
+	// or LSSharedFileList, which opens the application at login.
+	LegacyLoginItem LegacyKind = iota + 1
+
+	// LegacyStartupItem is a StartupItem in /Library/StartupItems.
+	// StartupItems are not run by macOS 10.10 (Yosemite) and later.
+	LegacyStartupItem
+)
+
+// String implements [fmt.Stringer].
+func (k LegacyKind) String() string {
+	switch k {
+	case LegacyLoginItem:
+		return "login-item"
+	case LegacyStartupItem:
+		return "startup-item"
+	default:
+		return fmt.Sprintf("LegacyKind(%d)", int(k))
+	}
+}
+
+// LegacyItem is a legacy mechanism used by an application to start
+// at login or boot, which should be migrated to a LaunchAgent,
+// LaunchDaemon or SMAppService registration.
+type LegacyItem struct {
+	// Kind of the item.
+	Kind LegacyKind
+
+	// Name of the login item or StartupItem.
+	Name string
+
+	// Path of the application opened by the login item,
+	// or path of the StartupItem directory.
+	Path string
+}
+
+// startupItemsDir is the directory containing StartupItems.
+const startupItemsDir = "/Library/StartupItems"
+
+// LegacyItems returns legacy login items and StartupItems of the application
+// bundle at appPath, for example "/Applications/Example.app". Login items
+// match if they open the application or its name is the name of the
+// application without ".app" suffix. StartupItems match if their name is
+// the name of the application.
+//
+// Login items are listed via System Events, thus the calling process
+// may need to be allowed to control System Events in
+// Privacy & Security > Automation settings.
+//
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func LegacyItems(ctx context.Context, appPath string) ([]LegacyItem, error) {
+	return legacyItems(ctx, appPath)
+}
+
+// MigrateLegacyItems migrates legacy items to a modern mechanism.
+// install is called first to install the replacement, for example with
+// [Install] or by registering an SMAppService. Items are only removed if
+// install succeeds, so that application keeps starting at login if
+// migration fails. All items are processed, even if removing some of them
+// fails, and errors are returned joined with [errors.Join].
+//
+// Removing StartupItems requires root privileges.
+//
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func MigrateLegacyItems(ctx context.Context, items []LegacyItem, install func(context.Context) error) error {
+	if install != nil {
+		if err := install(ctx); err != nil {
+			return fmt.Errorf("launchd: failed to install replacement of legacy items: %w", err)
+		}
+	}
+
+	var err error
+	for _, item := range items {
+		if rErr := removeLegacyItem(ctx, item); rErr != nil {
+			err = errors.Join(err, fmt.Errorf("launchd: failed to remove %s(%s): %w", item.Kind, item.Name, rErr))
+		}
+	}
+	return err
+}
+
+// appName returns name of the application bundle without ".app" suffix.
+func appName(appPath string) string {
+	return strings.TrimSuffix(path.Base(appPath), ".app")
+}
+
+// parseLoginItems parses output of login items script, which has
+// a tab separated name and path per line, and returns items
+// matching the application.
+func parseLoginItems(out, appPath string) []LegacyItem {
+	appPath = path.Clean(appPath)
+	name := appName(appPath)
+
+	var items []LegacyItem
+	for _, line := range strings.Split(out, "\n") {
+		itemName, itemPath, ok := strings.Cut(strings.TrimRight(line, "\r"), "\t")
+		if !ok || itemName == "" {
+			continue
+		}
+		itemPath = strings.TrimSuffix(itemPath, "/")
+		if itemName == name || itemPath == appPath || strings.HasPrefix(itemPath, appPath+"/") {
+			items = append(items, LegacyItem{Kind: LegacyLoginItem, Name: itemName, Path: itemPath})
+		}
+	}
+	return items
+}
+
+// startupItems returns StartupItems in dir matching the application.
+func startupItems(dir, appPath string) ([]LegacyItem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("launchd: %w", err)
+	}
+
+	name := appName(appPath)
+	var items []LegacyItem
+	for _, entry := range entries {
+		if entry.IsDir() && strings.EqualFold(entry.Name(), name) {
+			items = append(items, LegacyItem{
+				Kind: LegacyStartupItem,
+				Name: entry.Name(),
+				Path: filepath.Join(dir, entry.Name()),
+			})
+		}
+	}
+	return items, nil
+}
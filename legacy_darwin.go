@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// loginItemsScript lists login items as tab separated name and path per line.
+const loginItemsScript = `set out to ""
+tell application "System Events"
+	repeat with i in login items
+		set out to out & (name of i) & tab & (path of i) & linefeed
+	end repeat
+end tell
+return out`
+
+// osascript runs the AppleScript and returns its output.
+func osascript(ctx context.Context, script string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "/usr/bin/osascript", "-e", script)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("launchd: osascript failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Os specific implementation of [LegacyItems].
+func legacyItems(ctx context.Context, appPath string) ([]LegacyItem, error) {
+	out, err := osascript(ctx, loginItemsScript)
+	if err != nil {
+		return nil, err
+	}
+	items := parseLoginItems(out, appPath)
+
+	startup, err := startupItems(startupItemsDir, appPath)
+	if err != nil {
+		return items, err
+	}
+	return append(items, startup...), nil
+}
+
+// removeLegacyItem removes the legacy item.
+func removeLegacyItem(ctx context.Context, item LegacyItem) error {
+	switch item.Kind {
+	case LegacyLoginItem:
+		script := fmt.Sprintf(`tell application "System Events" to delete login item %s`,
+			appleScriptQuote(item.Name))
+		_, err := osascript(ctx, script)
+		return err
+	case LegacyStartupItem:
+		if !strings.HasPrefix(item.Path, startupItemsDir+"/") {
+			return fmt.Errorf("path(%s) is not in %s: %w", item.Path, startupItemsDir, syscall.EINVAL)
+		}
+		return os.RemoveAll(item.Path)
+	default:
+		return fmt.Errorf("unknown kind(%s): %w", item.Kind, syscall.EINVAL)
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [LegacyItems].
+func legacyItems(_ context.Context, _ string) ([]LegacyItem, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// removeLegacyItem is only supported on macOS.
+func removeLegacyItem(_ context.Context, _ LegacyItem) error {
+	return fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
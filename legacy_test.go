@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLegacyKind_String(t *testing.T) {
+	tt := []struct {
+		kind   LegacyKind
+		expect string
+	}{
+		{kind: LegacyLoginItem, expect: "login-item"},
+		{kind: LegacyStartupItem, expect: "startup-item"},
+		{kind: 0, expect: "LegacyKind(0)"},
+	}
+	for _, tc := range tt {
+		if got := tc.kind.String(); got != tc.expect {
+			t.Errorf("expected=%s, got=%s", tc.expect, got)
+		}
+	}
+}
+
+func TestParseLoginItems(t *testing.T) {
+	out := "Example\t/Applications/Example.app/\n" +
+		"Example Helper\t/Applications/Example.app/Contents/Library/LoginItems/Helper.app\n" +
+		"Other\t/Applications/Other.app\n" +
+		"Example\t/Users/test/Applications/Example.app\r\n" +
+		"\n" +
+		"invalid\n"
+
+	expect := []LegacyItem{
+		{Kind: LegacyLoginItem, Name: "Example", Path: "/Applications/Example.app"},
+		{Kind: LegacyLoginItem, Name: "Example Helper", Path: "/Applications/Example.app/Contents/Library/LoginItems/Helper.app"},
+		{Kind: LegacyLoginItem, Name: "Example", Path: "/Users/test/Applications/Example.app"},
+	}
+	if got := parseLoginItems(out, "/Applications/Example.app/"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected items=%+v, got=%+v", expect, got)
+	}
+	if got := parseLoginItems("", "/Applications/Example.app"); got != nil {
+		t.Errorf("expected no items, got=%+v", got)
+	}
+}
+
+func TestStartupItems(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"example", "Other"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %s", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Example"), nil, 0o644); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+
+	items, err := startupItems(dir, "/Applications/Example.app")
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	expect := []LegacyItem{{Kind: LegacyStartupItem, Name: "example", Path: filepath.Join(dir, "example")}}
+	if !reflect.DeepEqual(items, expect) {
+		t.Errorf("expected items=%+v, got=%+v", expect, items)
+	}
+
+	items, err = startupItems(filepath.Join(dir, "nonexistent"), "/Applications/Example.app")
+	if err != nil || items != nil {
+		t.Errorf("expected no items and no error, got=%+v, %s", items, err)
+	}
+}
+
+func TestMigrateLegacyItems_InstallFailed(t *testing.T) {
+	installErr := errors.New("install failed")
+	item := LegacyItem{Kind: LegacyStartupItem, Name: "Example", Path: "/Library/StartupItems/Example"}
+	err := MigrateLegacyItems(context.Background(), []LegacyItem{item}, func(context.Context) error {
+		return installErr
+	})
+	if !errors.Is(err, installErr) {
+		t.Errorf("expected error=%s, got=%s", installErr, err)
+	}
+
+	// No items to remove.
+	installed := false
+	err = MigrateLegacyItems(context.Background(), nil, func(context.Context) error {
+		installed = true
+		return nil
+	})
+	if err != nil || !installed {
+		t.Errorf("expected install to be called without error, got=%t, %s", installed, err)
+	}
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"sync"
+)
+
+// nameLocks is a set of mutexes keyed by socket name. Mutexes are
+// removed once they are no longer in use, so that activating many
+// distinct socket names does not grow the set indefinitely.
+type nameLocks struct {
+	mu    sync.Mutex
+	locks map[string]*nameLock
+}
+
+// nameLock is a mutex with number of goroutines holding or waiting on it.
+type nameLock struct {
+	sync.Mutex
+	refs int
+}
+
+// activationLocks serializes activation of sockets with the same name.
+//
+//nolint:gochecknoglobals // shared by all activations.
+var activationLocks nameLocks
+
+// lock locks the mutex for name and returns a function to unlock it.
+func (l *nameLocks) lock(name string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*nameLock)
+	}
+	nl, ok := l.locks[name]
+	if !ok {
+		nl = &nameLock{}
+		l.locks[name] = nl
+	}
+	nl.refs++
+	l.mu.Unlock()
+
+	nl.Lock()
+	return func() {
+		nl.Unlock()
+		l.mu.Lock()
+		nl.refs--
+		if nl.refs == 0 {
+			delete(l.locks, name)
+		}
+		l.mu.Unlock()
+	}
+}
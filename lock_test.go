@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrent_NameLocks(t *testing.T) {
+	var locks nameLocks
+	var inflight [4]atomic.Int32
+	var overlap atomic.Int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			n := i % len(inflight)
+			unlock := locks.lock(fmt.Sprintf("socket-%d", n))
+			defer unlock()
+
+			if inflight[n].Add(1) != 1 {
+				t.Errorf("expected calls with the same name to be serialized")
+			}
+			// Wait for goroutines with other names to overlap.
+			for j := range inflight {
+				if j != n && inflight[j].Load() > 0 {
+					overlap.Store(1)
+				}
+			}
+			time.Sleep(time.Millisecond)
+			inflight[n].Add(-1)
+		}(i)
+	}
+	wg.Wait()
+
+	if overlap.Load() == 0 {
+		t.Errorf("expected calls with different names to run concurrently")
+	}
+
+	locks.mu.Lock()
+	defer locks.mu.Unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("expected locks to be released, got=%d", len(locks.locks))
+	}
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// activationLogger is the logger for activation events.
+//
+//nolint:gochecknoglobals // set via SetLogger.
+var activationLogger atomic.Pointer[slog.Logger]
+
+// SetLogger sets the logger used to emit debug events during socket
+// activation, like calls to launch_activate_socket, returned file
+// descriptors, socket types and constructed listeners. This is useful
+// to diagnose why activation returns fewer sockets than expected.
+//
+// Events are logged at [slog.LevelDebug]. Setting logger to nil,
+// which is the default, disables logging.
+func SetLogger(l *slog.Logger) {
+	activationLogger.Store(l)
+}
+
+// debug logs an activation event, if logger is set.
+func debug(msg string, attrs ...slog.Attr) {
+	if l := activationLogger.Load(); l != nil {
+		l.LogAttrs(context.Background(), slog.LevelDebug, msg, attrs...)
+	}
+}
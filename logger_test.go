@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { SetLogger(nil) })
+
+	debug("launchd: launch_activate_socket", slog.String("name", "Listeners"))
+	if !strings.Contains(buf.String(), `level=DEBUG msg="launchd: launch_activate_socket" name=Listeners`) {
+		t.Errorf("expected debug event, got=%s", buf.String())
+	}
+
+	buf.Reset()
+	SetLogger(nil)
+	debug("launchd: launch_activate_socket", slog.String("name", "Listeners"))
+	if buf.Len() != 0 {
+		t.Errorf("expected no events when logger is nil, got=%s", buf.String())
+	}
+}
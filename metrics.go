@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// MetricsRecorder records metrics of socket activation. Implementations
+// must be safe for concurrent use. See metrics package for an
+// implementation which publishes metrics via expvar.
+type MetricsRecorder interface {
+	// SocketsActivated records number of file descriptors
+	// activated for the socket.
+	SocketsActivated(name string, count int)
+
+	// ActivationLatency records time taken to activate the socket,
+	// including failed activations.
+	ActivationLatency(name string, d time.Duration)
+
+	// ActivationError records failure to activate the socket.
+	ActivationError(name string, err error)
+
+	// ListenerError records failure to build a listener or
+	// packet connection from an activated file descriptor.
+	ListenerError(name string, err error)
+}
+
+// metricsRecorder holds the recorder set via SetMetricsRecorder.
+type metricsRecorder struct {
+	MetricsRecorder
+}
+
+//nolint:gochecknoglobals // set via SetMetricsRecorder.
+var recorder atomic.Pointer[metricsRecorder]
+
+// SetMetricsRecorder sets the recorder for socket activation metrics.
+// Setting recorder to nil, which is the default, disables metrics.
+func SetMetricsRecorder(r MetricsRecorder) {
+	if r == nil {
+		recorder.Store(nil)
+		return
+	}
+	recorder.Store(&metricsRecorder{r})
+}
+
+// recordActivation records result of activating the socket.
+func recordActivation(name string, start time.Time, count int, err error) {
+	r := recorder.Load()
+	if r == nil {
+		return
+	}
+
+	r.ActivationLatency(name, time.Since(start))
+	if err != nil {
+		r.ActivationError(name, err)
+		return
+	}
+	r.SocketsActivated(name, count)
+}
+
+// recordListenerError records failure to build a listener.
+func recordListenerError(name string, err error) {
+	if r := recorder.Load(); r != nil && err != nil {
+		r.ListenerError(name, err)
+	}
+}
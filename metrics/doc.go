@@ -0,0 +1,8 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package metrics provides implementations of launchd.MetricsRecorder.
+//
+// It is a separate package, as importing [expvar] registers its handler
+// on [net/http.DefaultServeMux].
+package metrics
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+var _ launchd.MetricsRecorder = (*Expvar)(nil)
+
+// Expvar is a launchd.MetricsRecorder which publishes metrics via [expvar].
+//
+// Metrics are published as a map with following keys, each of which
+// is a map keyed by socket name:
+//
+//   - sockets_activated: number of activated file descriptors.
+//   - activation_latency_ns: latency of the last activation in nanoseconds.
+//   - activation_errors: number of failed activations.
+//   - listener_errors: number of failures building listeners.
+type Expvar struct {
+	activated *expvar.Map
+	latency   *expvar.Map
+	errors    *expvar.Map
+	listeners *expvar.Map
+}
+
+// NewExpvar returns an [Expvar] recorder, publishing metrics with the given
+// name, for example "launchd". Like [expvar.Publish], it panics if name
+// is already in use.
+func NewExpvar(name string) *Expvar {
+	e := &Expvar{
+		activated: new(expvar.Map),
+		latency:   new(expvar.Map),
+		errors:    new(expvar.Map),
+		listeners: new(expvar.Map),
+	}
+
+	m := expvar.NewMap(name)
+	m.Set("sockets_activated", e.activated)
+	m.Set("activation_latency_ns", e.latency)
+	m.Set("activation_errors", e.errors)
+	m.Set("listener_errors", e.listeners)
+	return e
+}
+
+// SocketsActivated implements launchd.MetricsRecorder.
+func (e *Expvar) SocketsActivated(name string, count int) {
+	e.activated.Add(name, int64(count))
+}
+
+// ActivationLatency implements launchd.MetricsRecorder.
+func (e *Expvar) ActivationLatency(name string, d time.Duration) {
+	v := new(expvar.Int)
+	v.Set(d.Nanoseconds())
+	e.latency.Set(name, v)
+}
+
+// ActivationError implements launchd.MetricsRecorder.
+func (e *Expvar) ActivationError(name string, _ error) {
+	e.errors.Add(name, 1)
+}
+
+// ListenerError implements launchd.MetricsRecorder.
+func (e *Expvar) ListenerError(name string, _ error) {
+	e.listeners.Add(name, 1)
+}
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package metrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/metrics"
+)
+
+func TestExpvar(t *testing.T) {
+	e := metrics.NewExpvar("launchd_test")
+	e.SocketsActivated("Listeners", 2)
+	e.SocketsActivated("Listeners", 1)
+	e.ActivationLatency("Listeners", time.Millisecond)
+	e.ActivationError("Missing", errors.New("not found"))
+	e.ListenerError("Listeners", errors.New("bad socket"))
+
+	var got map[string]map[string]int64
+	if err := json.Unmarshal([]byte(expvar.Get("launchd_test").String()), &got); err != nil {
+		t.Fatalf("failed to decode expvar: %s", err)
+	}
+
+	expect := map[string]map[string]int64{
+		"sockets_activated":     {"Listeners": 3},
+		"activation_latency_ns": {"Listeners": int64(time.Millisecond)},
+		"activation_errors":     {"Missing": 1},
+		"listener_errors":       {"Listeners": 1},
+	}
+	for k, v := range expect {
+		for name, n := range v {
+			if got[k][name] != n {
+				t.Errorf("expected %s[%s]=%d, got=%d", k, name, n, got[k][name])
+			}
+		}
+	}
+}
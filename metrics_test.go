@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+type testRecorder struct {
+	mu        sync.Mutex
+	activated map[string]int
+	errors    map[string]int
+	latency   map[string]time.Duration
+}
+
+func (r *testRecorder) SocketsActivated(name string, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.activated[name] += count
+}
+
+func (r *testRecorder) ActivationLatency(name string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latency[name] = d
+}
+
+func (r *testRecorder) ActivationError(name string, _ error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[name]++
+}
+
+func (r *testRecorder) ListenerError(_ string, _ error) {}
+
+func TestSetMetricsRecorder(t *testing.T) {
+	r := &testRecorder{
+		activated: make(map[string]int),
+		errors:    make(map[string]int),
+		latency:   make(map[string]time.Duration),
+	}
+	launchd.SetMetricsRecorder(r)
+	t.Cleanup(func() { launchd.SetMetricsRecorder(nil) })
+
+	// Activation fails as test process is not managed by launchd
+	// or platform is not supported.
+	_, err := launchd.Files("com.github.tprasadtp.go-launchd.metrics")
+	if err == nil {
+		t.Fatalf("expected activation to fail")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.errors["com.github.tprasadtp.go-launchd.metrics"] != 1 {
+		t.Errorf("expected 1 activation error, got=%d", r.errors["com.github.tprasadtp.go-launchd.metrics"])
+	}
+	if _, ok := r.latency["com.github.tprasadtp.go-launchd.metrics"]; !ok {
+		t.Errorf("expected activation latency to be recorded")
+	}
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package mobileconfig generates configuration profiles (.mobileconfig)
+// for distributing launchd jobs with MDM.
+//
+// Configuration profiles cannot install launchd jobs by themselves.
+// Plist files and executables are typically deployed with an installer
+// package, which can use the parent package to install the job. Profiles
+// generated by this package complement the installer package:
+//
+//   - A managed preferences (com.apple.ManagedClient.preferences) payload
+//     embeds the job definition in the preference domain named after the
+//     label of the job. Installers and the job itself can read the job
+//     definition managed by the fleet administrator with CFPreferences or
+//     "defaults read", instead of shipping it in the installer package.
+//   - A managed login items (com.apple.servicemanagement) payload approves
+//     the job on macOS 13 (Ventura) and later, so that users are not
+//     prompted to allow it in System Settings and cannot disable it.
+//
+// Profiles are not signed. Most MDM solutions sign profiles when they are
+// uploaded.
+package mobileconfig
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package mobileconfig
+
+import (
+	"crypto/sha1" //nolint:gosec // used for name based uuids, as specified by RFC 4122.
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Payload types.
+const (
+	TypeConfiguration      = "Configuration"
+	TypeManagedPreferences = "com.apple.ManagedClient.preferences"
+	TypeServiceManagement  = "com.apple.servicemanagement"
+)
+
+// Rule types of managed login items.
+const (
+	RuleTypeLabel          = "Label"
+	RuleTypeLabelPrefix    = "LabelPrefix"
+	RuleTypeTeamIdentifier = "TeamIdentifier"
+	RuleTypeBundleID       = "BundleIdentifier"
+)
+
+// Profile is a configuration profile.
+type Profile struct {
+	PayloadContent           []any  `plist:"PayloadContent"`
+	PayloadDescription       string `plist:"PayloadDescription,omitempty"`
+	PayloadDisplayName       string `plist:"PayloadDisplayName"`
+	PayloadIdentifier        string `plist:"PayloadIdentifier"`
+	PayloadOrganization      string `plist:"PayloadOrganization,omitempty"`
+	PayloadRemovalDisallowed bool   `plist:"PayloadRemovalDisallowed"`
+	PayloadScope             string `plist:"PayloadScope"`
+	PayloadType              string `plist:"PayloadType"`
+	PayloadUUID              string `plist:"PayloadUUID"`
+	PayloadVersion           int    `plist:"PayloadVersion"`
+}
+
+// ManagedPreferences is a com.apple.ManagedClient.preferences payload.
+type ManagedPreferences struct {
+	PayloadContent     map[string]ManagedDomain `plist:"PayloadContent"`
+	PayloadDisplayName string                   `plist:"PayloadDisplayName"`
+	PayloadIdentifier  string                   `plist:"PayloadIdentifier"`
+	PayloadType        string                   `plist:"PayloadType"`
+	PayloadUUID        string                   `plist:"PayloadUUID"`
+	PayloadVersion     int                      `plist:"PayloadVersion"`
+}
+
+// ManagedDomain are managed preferences of a preference domain.
+type ManagedDomain struct {
+	Forced []ManagedSettings `plist:"Forced"`
+}
+
+// ManagedSettings are forced preference settings.
+type ManagedSettings struct {
+	Settings any `plist:"mcx_preference_settings"`
+}
+
+// ServiceManagement is a com.apple.servicemanagement payload,
+// which manages login items and background tasks.
+type ServiceManagement struct {
+	Rules              []Rule `plist:"Rules"`
+	PayloadDisplayName string `plist:"PayloadDisplayName"`
+	PayloadIdentifier  string `plist:"PayloadIdentifier"`
+	PayloadType        string `plist:"PayloadType"`
+	PayloadUUID        string `plist:"PayloadUUID"`
+	PayloadVersion     int    `plist:"PayloadVersion"`
+}
+
+// Rule is a rule of managed login items.
+type Rule struct {
+	RuleType  string `plist:"RuleType"`
+	RuleValue string `plist:"RuleValue"`
+	Comment   string `plist:"Comment,omitempty"`
+}
+
+// Option configures [New].
+type Option func(*options)
+
+type options struct {
+	organization string
+	description  string
+	teamID       string
+	removable    bool
+}
+
+// WithOrganization sets the organization shown in the profile.
+func WithOrganization(org string) Option {
+	return func(o *options) {
+		o.organization = org
+	}
+}
+
+// WithDescription sets the description of the profile.
+func WithDescription(desc string) Option {
+	return func(o *options) {
+		o.description = desc
+	}
+}
+
+// WithTeamIdentifier approves all login items and background tasks signed
+// by the team, in addition to the job. This is useful when the job is
+// bundled with other helpers signed by the same team.
+func WithTeamIdentifier(team string) Option {
+	return func(o *options) {
+		o.teamID = team
+	}
+}
+
+// WithRemovable allows users to remove the profile.
+// By default, removal of the profile is disallowed.
+func WithRemovable() Option {
+	return func(o *options) {
+		o.removable = true
+	}
+}
+
+// New returns a system scoped profile with the given identifier for the job.
+// See package documentation for payloads included in the profile.
+//
+// Payload UUIDs are derived from identifiers of the payloads, so that
+// generating the profile again results in the same profile, which MDM
+// solutions treat as an update rather than a new profile.
+//
+//   - [syscall.EINVAL] is returned if identifier is empty or job is invalid.
+func New(identifier string, job plist.Job, opts ...Option) (*Profile, error) {
+	if strings.TrimSpace(identifier) == "" {
+		return nil, fmt.Errorf("mobileconfig: identifier is empty: %w", syscall.EINVAL)
+	}
+	if err := job.Validate(); err != nil {
+		return nil, fmt.Errorf("mobileconfig: invalid job: %w: %w", err, syscall.EINVAL)
+	}
+
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	prefsID := identifier + ".preferences"
+	prefs := ManagedPreferences{
+		PayloadContent: map[string]ManagedDomain{
+			job.Label: {Forced: []ManagedSettings{{Settings: job}}},
+		},
+		PayloadDisplayName: "Job definition of " + job.Label,
+		PayloadIdentifier:  prefsID,
+		PayloadType:        TypeManagedPreferences,
+		PayloadUUID:        uuid(prefsID),
+		PayloadVersion:     1,
+	}
+
+	rules := []Rule{{RuleType: RuleTypeLabel, RuleValue: job.Label, Comment: job.Label}}
+	if o.teamID != "" {
+		rules = append(rules, Rule{RuleType: RuleTypeTeamIdentifier, RuleValue: o.teamID})
+	}
+	smID := identifier + ".servicemanagement"
+	sm := ServiceManagement{
+		Rules:              rules,
+		PayloadDisplayName: "Managed login items for " + job.Label,
+		PayloadIdentifier:  smID,
+		PayloadType:        TypeServiceManagement,
+		PayloadUUID:        uuid(smID),
+		PayloadVersion:     1,
+	}
+
+	return &Profile{
+		PayloadContent:           []any{prefs, sm},
+		PayloadDescription:       o.description,
+		PayloadDisplayName:       job.Label,
+		PayloadIdentifier:        identifier,
+		PayloadOrganization:      o.organization,
+		PayloadRemovalDisallowed: !o.removable,
+		PayloadScope:             "System",
+		PayloadType:              TypeConfiguration,
+		PayloadUUID:              uuid(identifier),
+		PayloadVersion:           1,
+	}, nil
+}
+
+// Marshal returns the profile as an XML property list,
+// which can be saved as a .mobileconfig file.
+func (p *Profile) Marshal() ([]byte, error) {
+	return plist.Marshal(p)
+}
+
+// uuidNamespace is the namespace of name based uuids of payloads.
+// This is the ISO OID namespace from RFC 4122.
+var uuidNamespace = [16]byte{
+	0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1,
+	0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8,
+}
+
+// uuid returns version 5 (SHA-1 name based) uuid for the name.
+func uuid(name string) string {
+	h := sha1.New() //nolint:gosec // see import.
+	h.Write(uuidNamespace[:])
+	h.Write([]byte(name))
+	sum := h.Sum(nil)
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%X-%X-%X-%X-%X", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
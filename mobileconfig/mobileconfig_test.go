@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package mobileconfig
+
+import (
+	"errors"
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestUUID(t *testing.T) {
+	// uuid.uuid5(uuid.NAMESPACE_OID, "com.example.profile") in python.
+	expect := "1A230ACC-57B4-5EA0-AC93-F84EE23E5ECC"
+	if got := uuid("com.example.profile"); got != expect {
+		t.Errorf("expected uuid=%s, got=%s", expect, got)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	job := plist.Job{Label: "com.example.svc", Program: "/usr/local/bin/svc"}
+	tt := []struct {
+		name       string
+		identifier string
+		job        plist.Job
+	}{
+		{name: "empty-identifier", job: job},
+		{name: "invalid-job", identifier: "com.example.profile", job: plist.Job{Label: "com.example.svc"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.identifier, tc.job)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	job := plist.Job{
+		Label:            "com.example.svc",
+		ProgramArguments: []string{"/usr/local/bin/svc"},
+		RunAtLoad:        true,
+	}
+	p, err := New("com.example.profile", job,
+		WithOrganization("Example"),
+		WithDescription("Example service"),
+		WithTeamIdentifier("ABCDE12345"),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	data, err := p.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal profile: %s", err)
+	}
+
+	var decoded struct {
+		PayloadIdentifier        string
+		PayloadType              string
+		PayloadUUID              string
+		PayloadOrganization      string
+		PayloadRemovalDisallowed bool
+		PayloadContent           []struct {
+			PayloadType    string
+			PayloadContent map[string]struct {
+				Forced []struct {
+					Settings plist.Job `plist:"mcx_preference_settings"`
+				}
+			}
+			Rules []Rule
+		}
+	}
+	if err = plist.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode profile: %s\n%s", err, data)
+	}
+
+	if decoded.PayloadIdentifier != "com.example.profile" ||
+		decoded.PayloadType != TypeConfiguration ||
+		decoded.PayloadUUID != uuid("com.example.profile") ||
+		decoded.PayloadOrganization != "Example" ||
+		!decoded.PayloadRemovalDisallowed {
+		t.Errorf("unexpected profile: %+v", decoded)
+	}
+	if len(decoded.PayloadContent) != 2 {
+		t.Fatalf("expected 2 payloads, got=%d", len(decoded.PayloadContent))
+	}
+
+	prefs := decoded.PayloadContent[0]
+	if prefs.PayloadType != TypeManagedPreferences {
+		t.Errorf("expected payload type=%s, got=%s", TypeManagedPreferences, prefs.PayloadType)
+	}
+	domain, ok := prefs.PayloadContent[job.Label]
+	if !ok || len(domain.Forced) != 1 || !reflect.DeepEqual(domain.Forced[0].Settings, job) {
+		t.Errorf("expected job in managed preferences, got=%+v", prefs.PayloadContent)
+	}
+
+	sm := decoded.PayloadContent[1]
+	expectRules := []Rule{
+		{RuleType: RuleTypeLabel, RuleValue: job.Label, Comment: job.Label},
+		{RuleType: RuleTypeTeamIdentifier, RuleValue: "ABCDE12345"},
+	}
+	if sm.PayloadType != TypeServiceManagement || !reflect.DeepEqual(sm.Rules, expectRules) {
+		t.Errorf("expected rules=%+v, got=%+v (%s)", expectRules, sm.Rules, sm.PayloadType)
+	}
+}
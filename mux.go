@@ -0,0 +1,378 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultSniffTimeout is the default time [Mux] waits for a client
+// to send enough data to match the connection.
+const DefaultSniffTimeout = 10 * time.Second
+
+// maxSniff is the maximum number of bytes read while matching a connection.
+// This is large enough for a TLS record with a ClientHello.
+const maxSniff = 5 + 1<<14
+
+// Matcher reports whether the connection matches, by reading its initial
+// bytes from r. Data read by matchers is replayed to other matchers and to
+// the listener which accepts the connection.
+type Matcher func(r io.Reader) bool
+
+// MatchAny matches any connection without reading from it. This is
+// typically used as the last matcher, as a fallback.
+func MatchAny() Matcher {
+	return func(io.Reader) bool {
+		return true
+	}
+}
+
+// MatchTLS matches connections which start with a TLS handshake record.
+func MatchTLS() Matcher {
+	return func(r io.Reader) bool {
+		var hdr [3]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return false
+		}
+		// Handshake record type, followed by major version 3.
+		return hdr[0] == 0x16 && hdr[1] == 0x03
+	}
+}
+
+// MatchALPN matches TLS connections whose ClientHello advertises any
+// of the protocols via ALPN, for example "h2" or "http/1.1".
+func MatchALPN(protos ...string) Matcher {
+	return func(r io.Reader) bool {
+		hello := clientHello(r)
+		if hello == nil {
+			return false
+		}
+		for _, proto := range hello.SupportedProtos {
+			if slices.Contains(protos, proto) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchHTTP1 matches plaintext HTTP/1.x connections by their request method.
+func MatchHTTP1() Matcher {
+	methods := []string{
+		"GET", "HEAD", "POST", "PUT", "DELETE",
+		"CONNECT", "OPTIONS", "TRACE", "PATCH",
+	}
+	return func(r io.Reader) bool {
+		var method []byte
+		b := make([]byte, 1)
+		for len(method) <= len("OPTIONS") {
+			if _, err := io.ReadFull(r, b); err != nil {
+				return false
+			}
+			if b[0] == ' ' {
+				return slices.Contains(methods, string(method))
+			}
+			method = append(method, b[0])
+		}
+		return false
+	}
+}
+
+// MatchHTTP2 matches plaintext HTTP/2 connections with prior knowledge,
+// by their client connection preface.
+func MatchHTTP2() Matcher {
+	preface := []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")
+	return func(r io.Reader) bool {
+		b := make([]byte, len(preface))
+		if _, err := io.ReadFull(r, b); err != nil {
+			return false
+		}
+		return bytes.Equal(b, preface)
+	}
+}
+
+// clientHello returns ClientHello of the TLS handshake read from r, by
+// starting a server side handshake which is aborted once ClientHello is
+// received. Returns nil if r does not contain a valid ClientHello.
+func clientHello(r io.Reader) *tls.ClientHelloInfo {
+	var hello *tls.ClientHelloInfo
+	errAbort := errors.New("abort")
+	conn := tls.Server(readOnlyConn{r: r}, &tls.Config{
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			hello = info
+			return nil, errAbort
+		},
+	})
+	_ = conn.Handshake()
+	return hello
+}
+
+// readOnlyConn is a [net.Conn] which reads from r and discards writes.
+type readOnlyConn struct {
+	r io.Reader
+}
+
+// Read implements [net.Conn].
+func (c readOnlyConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// Write implements [net.Conn].
+func (readOnlyConn) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Close implements [net.Conn].
+func (readOnlyConn) Close() error {
+	return nil
+}
+
+// LocalAddr implements [net.Conn].
+func (readOnlyConn) LocalAddr() net.Addr {
+	return nil
+}
+
+// RemoteAddr implements [net.Conn].
+func (readOnlyConn) RemoteAddr() net.Addr {
+	return nil
+}
+
+// SetDeadline implements [net.Conn].
+func (readOnlyConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+// SetReadDeadline implements [net.Conn].
+func (readOnlyConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+// SetWriteDeadline implements [net.Conn].
+func (readOnlyConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+// MuxOption configures [Mux].
+type MuxOption func(*Mux)
+
+// WithSniffTimeout sets the time [Mux] waits for a client to send enough
+// data to match the connection. Defaults to [DefaultSniffTimeout].
+func WithSniffTimeout(d time.Duration) MuxOption {
+	return func(m *Mux) {
+		if d > 0 {
+			m.timeout = d
+		}
+	}
+}
+
+// Mux multiplexes connections accepted on a single listener, like an
+// activated socket, to multiple listeners by sniffing their initial bytes.
+// This allows serving TLS and plaintext, or different ALPN protocols,
+// on a single port, as launchd jobs often get exactly one port.
+//
+// Listeners are created with [Mux.Match] before calling [Mux.Serve].
+// Listeners matching TLS connections can be wrapped with [crypto/tls.NewListener]
+// to terminate TLS on the activated socket.
+// Connections are matched against listeners in the order they were created,
+// and connections which do not match any of them are closed.
+type Mux struct {
+	root    net.Listener
+	timeout time.Duration
+	routes  []*muxListener
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewMux returns a new [Mux] for the listener.
+func NewMux(l net.Listener, opts ...MuxOption) *Mux {
+	m := &Mux{
+		root:    l,
+		timeout: DefaultSniffTimeout,
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
+}
+
+// Match returns a listener which accepts connections matching any of the
+// matchers. Returned listener has the address of the multiplexed listener.
+// Closing it does not close the multiplexed listener, and connections
+// matching it are closed afterwards.
+func (m *Mux) Match(matchers ...Matcher) net.Listener {
+	l := &muxListener{
+		mux:      m,
+		matchers: matchers,
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+	}
+	m.routes = append(m.routes, l)
+	return l
+}
+
+// Serve accepts connections on the multiplexed listener and dispatches them
+// to the listeners returned by [Mux.Match]. Serve blocks until ctx is done,
+// [Mux.Close] is called, or the listener fails with a non-temporary error.
+// Listener is closed before returning, after which listeners returned by
+// [Mux.Match] return [net.ErrClosed]. Returned error is nil if ctx is done
+// or Close was called.
+//
+//   - [syscall.EINVAL] is returned if listener is nil.
+func (m *Mux) Serve(ctx context.Context) error {
+	if m.root == nil {
+		return fmt.Errorf("launchd: listener must not be nil: %w", syscall.EINVAL)
+	}
+
+	defer m.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		m.Close()
+	})
+	defer stop()
+
+	for {
+		conn, err := accept(ctx, m.root)
+		if err != nil {
+			select {
+			case <-m.done:
+				return nil
+			default:
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("launchd: mux accept error: %w", err)
+		}
+		go m.dispatch(conn)
+	}
+}
+
+// Close closes the multiplexed listener, and thus all the listeners
+// returned by [Mux.Match].
+func (m *Mux) Close() error {
+	var err error
+	m.once.Do(func() {
+		close(m.done)
+		err = m.root.Close()
+	})
+	return err
+}
+
+// dispatch matches the connection and hands it over to the matching listener.
+func (m *Mux) dispatch(conn net.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(m.timeout))
+	s := &sniffer{src: io.LimitReader(conn, maxSniff)}
+	for _, l := range m.routes {
+		for _, match := range l.matchers {
+			s.reset()
+			if !match(s) {
+				continue
+			}
+
+			_ = conn.SetReadDeadline(time.Time{})
+			c := &sniffedConn{Conn: conn, buf: s.buf.Bytes()}
+			select {
+			case l.conns <- c:
+			case <-l.done:
+				conn.Close()
+			case <-m.done:
+				conn.Close()
+			}
+			return
+		}
+	}
+
+	debug("launchd: mux closing unmatched connection",
+		slog.String("addr", m.root.Addr().String()),
+		slog.String("client", conn.RemoteAddr().String()),
+	)
+	conn.Close()
+}
+
+// sniffer records data read from src, so that it can be replayed.
+type sniffer struct {
+	src io.Reader
+	buf bytes.Buffer
+	off int
+}
+
+// reset replays recorded data from the start.
+func (s *sniffer) reset() {
+	s.off = 0
+}
+
+// Read implements [io.Reader].
+func (s *sniffer) Read(p []byte) (int, error) {
+	if s.off < s.buf.Len() {
+		n := copy(p, s.buf.Bytes()[s.off:])
+		s.off += n
+		return n, nil
+	}
+	n, err := s.src.Read(p)
+	s.buf.Write(p[:n])
+	s.off += n
+	return n, err
+}
+
+// sniffedConn replays data read while matching, before reading from Conn.
+type sniffedConn struct {
+	net.Conn
+	buf []byte
+}
+
+// Read implements [net.Conn].
+func (c *sniffedConn) Read(p []byte) (int, error) {
+	if len(c.buf) > 0 {
+		n := copy(p, c.buf)
+		c.buf = c.buf[n:]
+		return n, nil
+	}
+	return c.Conn.Read(p)
+}
+
+type muxListener struct {
+	mux      *Mux
+	matchers []Matcher
+	conns    chan net.Conn
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Accept implements [net.Listener].
+func (l *muxListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+	case <-l.mux.done:
+	}
+	return nil, fmt.Errorf("launchd: mux listener is closed: %w", net.ErrClosed)
+}
+
+// Close implements [net.Listener].
+func (l *muxListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+// Addr implements [net.Listener].
+func (l *muxListener) Addr() net.Addr {
+	return l.mux.root.Addr()
+}
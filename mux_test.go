@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// helloBytes returns the first record written by a TLS client with ALPN protos.
+func helloBytes(t *testing.T, protos ...string) []byte {
+	t.Helper()
+	client, server := net.Pipe()
+	defer server.Close()
+
+	go func() {
+		defer client.Close()
+		_ = tls.Client(client, &tls.Config{
+			ServerName: "example.com",
+			NextProtos: protos,
+			MinVersion: tls.VersionTLS12,
+		}).Handshake()
+	}()
+
+	var hdr [5]byte
+	if _, err := io.ReadFull(server, hdr[:]); err != nil {
+		t.Fatalf("failed to read record header: %s", err)
+	}
+	body := make([]byte, int(hdr[3])<<8|int(hdr[4]))
+	if _, err := io.ReadFull(server, body); err != nil {
+		t.Fatalf("failed to read record: %s", err)
+	}
+	return append(hdr[:], body...)
+}
+
+func TestMatchers(t *testing.T) {
+	h2 := helloBytes(t, "h2", "http/1.1")
+	plain := helloBytes(t)
+	tt := []struct {
+		name    string
+		matcher Matcher
+		data    []byte
+		expect  bool
+	}{
+		{name: "any", matcher: MatchAny(), expect: true},
+		{name: "tls", matcher: MatchTLS(), data: plain, expect: true},
+		{name: "tls-http1", matcher: MatchTLS(), data: []byte("GET / HTTP/1.1\r\n")},
+		{name: "alpn-h2", matcher: MatchALPN("h2"), data: h2, expect: true},
+		{name: "alpn-none", matcher: MatchALPN("h2"), data: plain},
+		{name: "alpn-mismatch", matcher: MatchALPN("acme-tls/1"), data: h2},
+		{name: "alpn-http1", matcher: MatchALPN("h2"), data: []byte("GET / HTTP/1.1\r\n")},
+		{name: "http1-get", matcher: MatchHTTP1(), data: []byte("GET / HTTP/1.1\r\n"), expect: true},
+		{name: "http1-options", matcher: MatchHTTP1(), data: []byte("OPTIONS * HTTP/1.1\r\n"), expect: true},
+		{name: "http1-unknown", matcher: MatchHTTP1(), data: []byte("FOOBARBAZ / HTTP/1.1\r\n")},
+		{name: "http1-tls", matcher: MatchHTTP1(), data: plain},
+		{name: "http2", matcher: MatchHTTP2(), data: []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"), expect: true},
+		{name: "http2-http1", matcher: MatchHTTP2(), data: []byte("PRI / HTTP/1.1\r\n")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.matcher(bytes.NewReader(tc.data)); got != tc.expect {
+				t.Errorf("expected match=%t, got=%t", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestSniffer(t *testing.T) {
+	s := &sniffer{src: bytes.NewReader([]byte("hello world"))}
+	b := make([]byte, 5)
+	if _, err := io.ReadFull(s, b); err != nil || string(b) != "hello" {
+		t.Fatalf("expected=hello, got=%q(%v)", b, err)
+	}
+
+	s.reset()
+	b = make([]byte, 11)
+	if _, err := io.ReadFull(s, b); err != nil || string(b) != "hello world" {
+		t.Fatalf("expected replay, got=%q(%v)", b, err)
+	}
+}
+
+func TestMux(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	m := NewMux(l, WithSniffTimeout(5*time.Second))
+	routes := map[string]net.Listener{
+		"h2":    m.Match(MatchALPN("h2")),
+		"tls":   m.Match(MatchTLS()),
+		"http1": m.Match(MatchHTTP1()),
+		"other": m.Match(MatchAny()),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- m.Serve(ctx)
+	}()
+
+	tt := []struct {
+		name string
+		data []byte
+	}{
+		{name: "h2", data: helloBytes(t, "h2")},
+		{name: "tls", data: helloBytes(t, "http/1.1")},
+		{name: "http1", data: []byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")},
+		{name: "other", data: []byte("SSH-2.0-OpenSSH_9.6\r\n")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err)
+			}
+			defer client.Close()
+			if _, err = client.Write(tc.data); err != nil {
+				t.Fatalf("failed to write: %s", err)
+			}
+
+			conn, err := routes[tc.name].Accept()
+			if err != nil {
+				t.Fatalf("failed to accept: %s", err)
+			}
+			defer conn.Close()
+
+			// Sniffed data is replayed.
+			got := make([]byte, len(tc.data))
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if _, err = io.ReadFull(conn, got); err != nil {
+				t.Fatalf("failed to read: %s", err)
+			}
+			if !bytes.Equal(got, tc.data) {
+				t.Errorf("expected data to be replayed, got=%q", got)
+			}
+		})
+	}
+
+	cancel()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("mux did not stop after context was canceled")
+	}
+	if _, err = routes["other"].Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected error=%s, got=%s", net.ErrClosed, err)
+	}
+}
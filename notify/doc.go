@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package notify implements readiness and liveness notifications for
+// services managed by launchd, mirroring semantics of [sd_notify(3)].
+//
+// launchd itself does not track readiness of jobs. Instead, state is
+// published via mechanisms configured in EnvironmentVariables of the job,
+// so that process supervisors, installers and health probes can observe it:
+//
+//   - If NOTIFY_SOCKET is set to a path of a unix datagram socket, state
+//     changes are sent to it as newline separated KEY=VALUE assignments,
+//     same as sd_notify.
+//   - If LAUNCHD_STATE_FILE is set, current state of the service is
+//     atomically written to it in the same format, along with TIMESTAMP
+//     of the last update.
+//
+// If neither is set, notifications are no-ops.
+//
+// As launchd has no equivalent of systemd's WatchdogSec, liveness is
+// emulated with a heartbeat file updated by [Heartbeat] or [Watchdog],
+// and a monitor job generated by plist.Watchdog, which restarts the job
+// when heartbeat goes stale.
+//
+// [sd_notify(3)]: https://www.freedesktop.org/software/systemd/man/latest/sd_notify.html
+package notify
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Environment variables which configure notification mechanisms.
+const (
+	// SocketEnv is the path of unix datagram socket to send notifications to.
+	SocketEnv = "NOTIFY_SOCKET"
+
+	// StateFileEnv is the path of the file to write current state to.
+	StateFileEnv = "LAUNCHD_STATE_FILE"
+)
+
+// Well known state assignments, same as sd_notify.
+const (
+	// StateReady indicates that service has finished starting up.
+	StateReady = "READY=1"
+
+	// StateStopping indicates that service is beginning its shutdown.
+	StateStopping = "STOPPING=1"
+
+	// StateReloading indicates that service is reloading its configuration.
+	StateReloading = "RELOADING=1"
+
+	// StateWatchdog updates the watchdog timestamp (liveness).
+	StateWatchdog = "WATCHDOG=1"
+)
+
+var (
+	mu    sync.Mutex
+	state = make(map[string]string)
+)
+
+// Notify sends state assignments like "READY=1" or "STATUS=..." to all
+// configured mechanisms. It returns false if no mechanism is configured.
+//
+//   - [syscall.EINVAL] is returned if assignment is invalid.
+func Notify(assignments ...string) (bool, error) {
+	for _, item := range assignments {
+		key, _, ok := strings.Cut(item, "=")
+		if !ok || key == "" || strings.ContainsAny(item, "\n") {
+			return false, fmt.Errorf("notify: invalid state assignment(%q): %w", item, syscall.EINVAL)
+		}
+	}
+
+	socket := os.Getenv(SocketEnv)
+	stateFile := os.Getenv(StateFileEnv)
+	if socket == "" && stateFile == "" {
+		return false, nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var err error
+	if socket != "" {
+		err = errors.Join(err, send(socket, assignments))
+	}
+
+	if stateFile != "" {
+		update(assignments, time.Now())
+		err = errors.Join(err, writeState(stateFile))
+	}
+	return true, err
+}
+
+// Ready notifies that service has finished starting up.
+// Stopping state is cleared, if set previously.
+func Ready() error {
+	_, err := Notify(StateReady, "STOPPING=0", "RELOADING=0")
+	return err
+}
+
+// Stopping notifies that service is beginning its shutdown.
+func Stopping() error {
+	_, err := Notify("READY=0", StateStopping)
+	return err
+}
+
+// Reloading notifies that service is reloading its configuration.
+// Call [Ready] once reload is complete.
+func Reloading() error {
+	_, err := Notify(StateReloading)
+	return err
+}
+
+// Status sets free-form status of the service.
+func Status(format string, args ...any) error {
+	msg := strings.ReplaceAll(fmt.Sprintf(format, args...), "\n", " ")
+	_, err := Notify("STATUS=" + msg)
+	return err
+}
+
+// send sends assignments to the unix datagram socket.
+func send(path string, assignments []string) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("notify: failed to connect to socket(%s): %w", path, err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(strings.Join(assignments, "\n"))); err != nil {
+		return fmt.Errorf("notify: failed to send notification: %w", err)
+	}
+	return nil
+}
+
+// update updates state with assignments.
+func update(assignments []string, now time.Time) {
+	for _, item := range assignments {
+		key, value, _ := strings.Cut(item, "=")
+		state[key] = value
+	}
+	state["MAINPID"] = strconv.Itoa(os.Getpid())
+	state["TIMESTAMP"] = strconv.FormatInt(now.Unix(), 10)
+}
+
+// formatState returns state as sorted KEY=VALUE lines.
+func formatState(s map[string]string) []byte {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(s[k])
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// writeState atomically writes state to the file.
+func writeState(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("notify: failed to create state file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err = f.Write(formatState(state)); err != nil {
+		return fmt.Errorf("notify: failed to write state file: %w", err)
+	}
+
+	if err = f.Chmod(0o644); err != nil {
+		return fmt.Errorf("notify: failed to set state file mode: %w", err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("notify: failed to close state file: %w", err)
+	}
+
+	if err = os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("notify: failed to rename state file: %w", err)
+	}
+	return nil
+}
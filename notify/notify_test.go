@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package notify_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/notify"
+)
+
+func TestNotify_NotConfigured(t *testing.T) {
+	t.Setenv(notify.SocketEnv, "")
+	t.Setenv(notify.StateFileEnv, "")
+
+	ok, err := notify.Notify(notify.StateReady)
+	if ok {
+		t.Errorf("expected ok=false when not configured")
+	}
+	if err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
+
+func TestNotify_Invalid(t *testing.T) {
+	tt := []string{"", "READY", "=1", "STATUS=a\nb"}
+	for _, tc := range tt {
+		t.Run(tc, func(t *testing.T) {
+			_, err := notify.Notify(tc)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
+
+func TestNotify_StateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	t.Setenv(notify.SocketEnv, "")
+	t.Setenv(notify.StateFileEnv, path)
+
+	read := func() string {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read state file: %s", err)
+		}
+		return string(b)
+	}
+
+	if err := notify.Ready(); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if err := notify.Status("serving %d listeners", 2); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	state := read()
+	for _, expect := range []string{"READY=1\n", "STOPPING=0\n", "STATUS=serving 2 listeners\n", "MAINPID=", "TIMESTAMP="} {
+		if !strings.Contains(state, expect) {
+			t.Errorf("expected state to contain=%q, got=%s", expect, state)
+		}
+	}
+
+	if err := notify.Stopping(); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	state = read()
+	for _, expect := range []string{"READY=0\n", "STOPPING=1\n"} {
+		if !strings.Contains(state, expect) {
+			t.Errorf("expected state to contain=%q, got=%s", expect, state)
+		}
+	}
+}
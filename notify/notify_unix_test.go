@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package notify_test
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/notify"
+)
+
+func TestNotify_Socket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer conn.Close()
+
+	t.Setenv(notify.SocketEnv, path)
+	t.Setenv(notify.StateFileEnv, "")
+
+	ok, err := notify.Notify(notify.StateReady, "STATUS=ok")
+	if !ok || err != nil {
+		t.Fatalf("expected ok=true and no error, got ok=%t, err=%s", ok, err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read: %s", err)
+	}
+
+	if expect := "READY=1\nSTATUS=ok"; string(buf[:n]) != expect {
+		t.Errorf("expected=%q, got=%q", expect, buf[:n])
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables which configure watchdog of the job.
+// These are set by plist.Watchdog.
+const (
+	// WatchdogFileEnv is the path of the heartbeat file.
+	WatchdogFileEnv = "LAUNCHD_WATCHDOG_FILE"
+
+	// WatchdogSecEnv is the watchdog timeout in seconds.
+	WatchdogSecEnv = "LAUNCHD_WATCHDOG_SEC"
+)
+
+// WatchdogEnabled returns watchdog timeout of the job.
+// If watchdog is not configured, ok is false.
+func WatchdogEnabled() (timeout time.Duration, ok bool) {
+	if os.Getenv(WatchdogFileEnv) == "" {
+		return 0, false
+	}
+
+	sec, err := strconv.Atoi(os.Getenv(WatchdogSecEnv))
+	if err != nil || sec <= 0 {
+		return 0, false
+	}
+	return time.Duration(sec) * time.Second, true
+}
+
+// Heartbeat updates mtime of the heartbeat file, creating it if necessary,
+// and sends WATCHDOG=1 to configured notification mechanisms.
+// It is a no-op if watchdog is not configured.
+func Heartbeat() error {
+	var err error
+	if path := os.Getenv(WatchdogFileEnv); path != "" {
+		err = touch(path, time.Now())
+	}
+
+	_, nErr := Notify(StateWatchdog)
+	return errors.Join(err, nErr)
+}
+
+// touch updates mtime of the file, creating it if necessary.
+func touch(path string, now time.Time) error {
+	err := os.Chtimes(path, now, now)
+	if errors.Is(err, os.ErrNotExist) {
+		var f *os.File
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+		if err == nil {
+			err = f.Close()
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("notify: failed to update heartbeat file: %w", err)
+	}
+	return nil
+}
+
+// Watchdog sends heartbeats every third of the watchdog timeout, until
+// context is cancelled. It returns immediately if watchdog is not
+// configured. Errors updating the heartbeat are ignored, as the
+// monitor restarts the job if heartbeat goes stale.
+//
+// Watchdog is typically run in its own goroutine. Note that heartbeats
+// only indicate that the process is alive. For a more meaningful liveness
+// signal, call [Heartbeat] from the main loop of the service instead.
+func Watchdog(ctx context.Context) {
+	timeout, ok := WatchdogEnabled()
+	if !ok {
+		return
+	}
+
+	_ = Heartbeat()
+	ticker := time.NewTicker(timeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = Heartbeat()
+		}
+	}
+}
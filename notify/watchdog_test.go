@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package notify_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/notify"
+)
+
+func TestWatchdogEnabled(t *testing.T) {
+	tt := []struct {
+		name    string
+		file    string
+		sec     string
+		timeout time.Duration
+		ok      bool
+	}{
+		{name: "enabled", file: "/tmp/hb", sec: "30", timeout: 30 * time.Second, ok: true},
+		{name: "no-file", sec: "30"},
+		{name: "no-timeout", file: "/tmp/hb"},
+		{name: "invalid-timeout", file: "/tmp/hb", sec: "foo"},
+		{name: "negative-timeout", file: "/tmp/hb", sec: "-1"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(notify.WatchdogFileEnv, tc.file)
+			t.Setenv(notify.WatchdogSecEnv, tc.sec)
+			timeout, ok := notify.WatchdogEnabled()
+			if ok != tc.ok {
+				t.Errorf("expected ok=%t, got=%t", tc.ok, ok)
+			}
+			if timeout != tc.timeout {
+				t.Errorf("expected timeout=%s, got=%s", tc.timeout, timeout)
+			}
+		})
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heartbeat")
+	t.Setenv(notify.SocketEnv, "")
+	t.Setenv(notify.StateFileEnv, "")
+	t.Setenv(notify.WatchdogFileEnv, path)
+	t.Setenv(notify.WatchdogSecEnv, "3")
+
+	if err := notify.Heartbeat(); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	stale := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, stale, stale); err != nil {
+		t.Fatalf("failed to set mtime: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	notify.Watchdog(ctx)
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat heartbeat file: %s", err)
+	}
+	if time.Since(fi.ModTime()) > time.Minute {
+		t.Errorf("expected heartbeat to be updated, got mtime=%s", fi.ModTime())
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package notifyd provides bindings for Darwin notifications (libnotify)
+// without using cgo.
+//
+// Darwin notifications are lightweight, stateless, system-wide events
+// identified by name, like "com.example.svc.config-changed". They are
+// commonly used alongside launchd to signal other processes, as launchd
+// jobs can also be started on notifications via LaunchEvents.
+//
+// Notifications carry no payload and multiple notifications may be
+// coalesced into one, thus receivers should re-read any state
+// associated with the notification, like configuration files.
+//
+// As dispatch queues and blocks cannot be used without cgo, notifications
+// are received via notify_register_file_descriptor instead of
+// notify_register_dispatch.
+//
+// On non-macOS platforms (including iOS), all functions return an error
+// wrapping [syscall.ENOTSUP].
+package notifyd
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package notifyd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Post posts a notification with the given name.
+//
+//   - [syscall.EINVAL] is returned if name is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Post(name string) error {
+	if err := validate(name); err != nil {
+		return err
+	}
+	return post(name)
+}
+
+// Subscription receives notifications with a given name.
+type Subscription struct {
+	// C receives a value when one or more notifications are posted.
+	// Notifications posted while a value is pending are coalesced.
+	// C is closed when subscription is closed.
+	C <-chan struct{}
+
+	r      io.ReadCloser
+	cancel func() error
+	once   sync.Once
+	done   chan struct{}
+}
+
+// Subscribe returns a subscription for notifications with the given name.
+// Caller must close the subscription when it is no longer required.
+//
+//   - [syscall.EINVAL] is returned if name is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Subscribe(name string) (*Subscription, error) {
+	if err := validate(name); err != nil {
+		return nil, err
+	}
+	return subscribe(name)
+}
+
+// newSubscription returns a subscription which receives notifications as
+// 4 byte tokens read from r. cancel is called when subscription is closed.
+func newSubscription(r io.ReadCloser, cancel func() error) *Subscription {
+	c := make(chan struct{}, 1)
+	s := &Subscription{
+		C:      c,
+		r:      r,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.done)
+		defer close(c)
+
+		buf := make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			select {
+			case c <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return s
+}
+
+// Close cancels the subscription and closes C. It is safe to call
+// Close multiple times.
+func (s *Subscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		if s.cancel != nil {
+			err = s.cancel()
+		}
+		err = errors.Join(err, s.r.Close())
+		<-s.done
+	})
+	if err != nil {
+		return fmt.Errorf("notifyd: failed to close subscription: %w", err)
+	}
+	return nil
+}
+
+// validate validates notification name.
+func validate(name string) error {
+	if name == "" || strings.ContainsRune(name, 0) {
+		return fmt.Errorf("notifyd: invalid name(%q): %w", name, syscall.EINVAL)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package notifyd
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// Os specific implementation of [Post].
+func post(name string) error {
+	if err := macos.NotifyPost(name); err != nil {
+		return fmt.Errorf("notifyd: failed to post(%s): %w", name, err)
+	}
+	return nil
+}
+
+// Os specific implementation of [Subscribe].
+func subscribe(name string) (*Subscription, error) {
+	fd, token, err := macos.NotifyRegisterFileDescriptor(name)
+	if err != nil {
+		return nil, fmt.Errorf("notifyd: failed to register(%s): %w", name, err)
+	}
+
+	// Use non-blocking mode, so that reads use the runtime poller and
+	// closing the file unblocks pending reads.
+	if err = syscall.SetNonblock(fd, true); err != nil {
+		_ = macos.NotifyCancel(token)
+		_ = syscall.Close(fd)
+		return nil, fmt.Errorf("notifyd: failed to set non-blocking mode: %w", err)
+	}
+
+	f := os.NewFile(uintptr(fd), "notifyd:"+name)
+	return newSubscription(f, func() error {
+		return macos.NotifyCancel(token)
+	}), nil
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package notifyd_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/notifyd"
+)
+
+func TestPostSubscribe(t *testing.T) {
+	name := fmt.Sprintf("com.github.tprasadtp.go-launchd.test.%d", os.Getpid())
+	s, err := notifyd.Subscribe(name)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %s", err)
+	}
+	defer s.Close()
+
+	if err = notifyd.Post(name); err != nil {
+		t.Fatalf("failed to post: %s", err)
+	}
+
+	select {
+	case <-s.C:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected notification")
+	}
+
+	if err = s.Close(); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
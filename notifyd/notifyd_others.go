@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package notifyd
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [Post].
+func post(_ string) error {
+	return fmt.Errorf("notifyd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Subscribe].
+func subscribe(_ string) (*Subscription, error) {
+	return nil, fmt.Errorf("notifyd: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package notifyd
+
+import (
+	"errors"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSubscription(t *testing.T) {
+	r, w := io.Pipe()
+	cancelled := false
+	s := newSubscription(r, func() error {
+		cancelled = true
+		return nil
+	})
+
+	// Multiple notifications are coalesced.
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write([]byte{0, 0, 0, 1}); err != nil {
+			t.Fatalf("failed to write token: %s", err)
+		}
+	}
+
+	select {
+	case <-s.C:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected notification")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("expected no error on second close, got=%s", err)
+	}
+	if !cancelled {
+		t.Errorf("expected registration to be cancelled")
+	}
+
+	// Drain pending notification, if any.
+	for range s.C {
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"", "com.example\x00svc"} {
+		if err := Post(name); !errors.Is(err, syscall.EINVAL) {
+			t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+		}
+		if _, err := Subscribe(name); !errors.Is(err, syscall.EINVAL) {
+			t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Orphan is a plist file of a job whose program no longer exists,
+// typically left behind by an application which was removed without
+// running its uninstaller.
+type Orphan struct {
+	// Path is the path of the plist file.
+	Path string
+
+	// Label is the label of the job.
+	Label string
+
+	// Program is the path of the missing program of the job.
+	Program string
+
+	// Daemon reports whether plist file is in a LaunchDaemons directory.
+	Daemon bool
+}
+
+// orphanDirs returns directories scanned by [Orphans] by default.
+func orphanDirs() []string {
+	dirs := []string{"/Library/LaunchDaemons", "/Library/LaunchAgents"}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "Library", "LaunchAgents"))
+	}
+	return dirs
+}
+
+// Orphans scans directories for plist files of jobs whose Program or
+// first element of ProgramArguments is an absolute path which no longer
+// exists. If no directories are specified, /Library/LaunchDaemons,
+// /Library/LaunchAgents and ~/Library/LaunchAgents are scanned.
+// Directories which do not exist are skipped.
+//
+// Jobs with relative program paths are not reported, as they are
+// resolved by launchd. Plist files which cannot be read or parsed are
+// skipped and errors for them are returned joined with [errors.Join],
+// along with orphans found. Use [RemoveOrphans] to remove them.
+func Orphans(dirs ...string) ([]Orphan, error) {
+	if len(dirs) == 0 {
+		dirs = orphanDirs()
+	}
+
+	var orphans []Orphan
+	var err error
+	for _, dir := range dirs {
+		entries, rErr := os.ReadDir(dir)
+		if rErr != nil {
+			if !errors.Is(rErr, fs.ErrNotExist) {
+				err = errors.Join(err, fmt.Errorf("launchd: %w", rErr))
+			}
+			continue
+		}
+
+		daemon := filepath.Base(dir) == "LaunchDaemons"
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), ".plist") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			o, ok, oErr := orphan(path)
+			if oErr != nil {
+				err = errors.Join(err, oErr)
+				continue
+			}
+			if ok {
+				o.Daemon = daemon
+				orphans = append(orphans, o)
+			}
+		}
+	}
+	return orphans, err
+}
+
+// orphan checks whether plist file at path is an orphan.
+func orphan(path string) (Orphan, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Orphan{}, false, fmt.Errorf("launchd: %w", err)
+	}
+
+	var job plist.Job
+	if err = plist.Unmarshal(data, &job); err != nil {
+		return Orphan{}, false, fmt.Errorf("launchd: %s: %w", path, err)
+	}
+
+	program := job.Program
+	if program == "" && len(job.ProgramArguments) > 0 {
+		program = job.ProgramArguments[0]
+	}
+	if !filepath.IsAbs(program) {
+		return Orphan{}, false, nil
+	}
+
+	if _, err = os.Stat(program); !errors.Is(err, fs.ErrNotExist) {
+		return Orphan{}, false, nil
+	}
+	return Orphan{Path: path, Label: job.Label, Program: program}, true, nil
+}
+
+// RemoveOrphans unloads jobs of the orphans if loaded and removes their
+// plist files. LaunchDaemons are unloaded from the system domain.
+// LaunchAgents are unloaded from the domain specified with [WithDomain],
+// or the domain of the calling process. Agents loaded in domains of other
+// users are unloaded when they log out. [WithEscalator] can be used to
+// remove plist files which require root privileges.
+//
+// All orphans are processed, even if removing some of them fails,
+// and errors are returned joined with [errors.Join].
+//
+//   - [*launchctl.Error] is returned if launchctl fails.
+//   - [syscall.EINVAL] is returned for orphans with invalid labels,
+//     for example labels containing path separators, or for orphans
+//     whose path is not an absolute path of a plist file.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func RemoveOrphans(ctx context.Context, orphans []Orphan, opts ...InstallOption) error {
+	return removeOrphans(ctx, orphans, newInstallOptions(opts))
+}
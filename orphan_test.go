@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// writeJob writes the job as plist file to dir.
+func writeJob(t *testing.T, dir string, job plist.Job) {
+	t.Helper()
+	data, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("failed to marshal job: %s", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, job.Label+".plist"), data, 0o644); err != nil {
+		t.Fatalf("failed to write plist: %s", err)
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	root := t.TempDir()
+	agents := filepath.Join(root, "LaunchAgents")
+	daemons := filepath.Join(root, "LaunchDaemons")
+	for _, dir := range []string{agents, daemons} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatalf("failed to create directory: %s", err)
+		}
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to get executable: %s", err)
+	}
+	missing := filepath.Join(root, "bin", "missing")
+
+	writeJob(t, agents, plist.Job{Label: "com.example.present", Program: exe})
+	writeJob(t, agents, plist.Job{Label: "com.example.relative", ProgramArguments: []string{"sh", "-c", "true"}})
+	writeJob(t, agents, plist.Job{Label: "com.example.agent", ProgramArguments: []string{missing, "-v"}})
+	writeJob(t, daemons, plist.Job{Label: "com.example.daemon", Program: missing})
+	if err = os.WriteFile(filepath.Join(daemons, "invalid.plist"), []byte("invalid"), 0o644); err != nil {
+		t.Fatalf("failed to write plist: %s", err)
+	}
+	if err = os.WriteFile(filepath.Join(daemons, "README"), []byte("not a plist"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	orphans, err := Orphans(agents, daemons, filepath.Join(root, "nonexistent"))
+	if err == nil {
+		t.Errorf("expected error for invalid plist file")
+	}
+
+	expect := []Orphan{
+		{
+			Path:    filepath.Join(agents, "com.example.agent.plist"),
+			Label:   "com.example.agent",
+			Program: missing,
+		},
+		{
+			Path:    filepath.Join(daemons, "com.example.daemon.plist"),
+			Label:   "com.example.daemon",
+			Program: missing,
+			Daemon:  true,
+		},
+	}
+	if !reflect.DeepEqual(orphans, expect) {
+		t.Errorf("expected orphans=%+v, got=%+v", expect, orphans)
+	}
+}
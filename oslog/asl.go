@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package oslog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ASL levels, from asl.h.
+const (
+	aslLevelCritical = 2
+	aslLevelError    = 3
+	aslLevelNotice   = 5
+	aslLevelInfo     = 6
+	aslLevelDebug    = 7
+)
+
+// NewASLHandler returns a [Handler] which writes to Apple System Log (ASL)
+// instead of os_log, with subsystem as the facility. It is intended for
+// legacy systems where os_log is not available or not collected, and
+// behaves same as handler returned by [NewHandler], except that:
+//
+//   - Category is included in the message, as ASL has no categories.
+//   - Private attributes are always redacted, as ASL has no
+//     concept of private data.
+//
+// [NewHandler] falls back to ASL automatically if os_log is not available.
+//
+//   - [syscall.EINVAL] is returned if subsystem or category is invalid.
+//   - [syscall.ENOTSUP] is returned if ASL is not available, or on
+//     non-macOS platforms (including iOS).
+func NewASLHandler(subsystem, category string, opts *HandlerOptions) (*Handler, error) {
+	if subsystem == "" || strings.ContainsRune(subsystem, 0) || strings.ContainsRune(category, 0) {
+		return nil, fmt.Errorf("oslog: invalid subsystem(%q) or category(%q): %w",
+			subsystem, category, syscall.EINVAL)
+	}
+
+	log, err := newASLLogger(subsystem, category)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handler{subsystem: subsystem, log: log, asl: true}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h, nil
+}
+
+// aslLevel returns ASL level for the message type.
+func aslLevel(t Type) string {
+	var level int
+	switch t {
+	case TypeDebug:
+		level = aslLevelDebug
+	case TypeInfo:
+		level = aslLevelInfo
+	case TypeError:
+		level = aslLevelError
+	case TypeFault:
+		level = aslLevelCritical
+	default:
+		level = aslLevelNotice
+	}
+	return strconv.Itoa(level)
+}
+
+// aslMessage returns ASL message with category and redacted private part.
+func aslMessage(category, public, private string) string {
+	msg := public
+	if category != "" {
+		msg = "[" + category + "] " + msg
+	}
+	if private != "" {
+		msg += " <private>"
+	}
+	return msg
+}
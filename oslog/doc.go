@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package oslog provides a [log/slog.Handler] which writes to Apple's
+// unified logging system (os_log) without using cgo.
+//
+// Daemons managed by launchd should log to unified logging instead of
+// files, as logs are rotated, compressed and persisted by the system, and
+// can be viewed with Console.app or "log stream --predicate
+// 'subsystem == "com.example.svc"'".
+//
+// Records are written as the message followed by attributes formatted as
+// key=value pairs. As unified logging records timestamp, level and process
+// of each message, these are not included in the message. Attributes
+// created with [Private] are redacted, unless logging of private data is
+// enabled for the subsystem.
+//
+// On systems where os_log is not available, [NewHandler] falls back to
+// Apple System Log (ASL). [NewASLHandler] can be used to always log to ASL.
+//
+// On non-macOS platforms (including iOS), [NewHandler] returns an error
+// wrapping [syscall.ENOTSUP].
+package oslog
@@ -0,0 +1,320 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package oslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode"
+)
+
+// Type is the type of a unified logging message, os_log_type_t.
+type Type uint8
+
+// Message types of unified logging.
+const (
+	// TypeDefault messages are persisted.
+	TypeDefault Type = 0x00
+
+	// TypeInfo messages are kept in memory and only persisted
+	// when an error or fault is logged.
+	TypeInfo Type = 0x01
+
+	// TypeDebug messages are only captured when debug
+	// logging is enabled for the subsystem.
+	TypeDebug Type = 0x02
+
+	// TypeError messages indicate process level errors.
+	TypeError Type = 0x10
+
+	// TypeFault messages indicate system level or multi-process errors.
+	TypeFault Type = 0x11
+)
+
+// LevelFault is the level of records logged as [TypeFault].
+const LevelFault = slog.LevelError + 4
+
+// String returns name of the message type.
+func (t Type) String() string {
+	switch t {
+	case TypeDefault:
+		return "default"
+	case TypeInfo:
+		return "info"
+	case TypeDebug:
+		return "debug"
+	case TypeError:
+		return "error"
+	case TypeFault:
+		return "fault"
+	default:
+		return "Type(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// TypeForLevel returns the message type for the level.
+//
+//   - Levels below [slog.LevelInfo] are logged as [TypeDebug].
+//   - Levels below [slog.LevelError], including [slog.LevelInfo] and
+//     [slog.LevelWarn], are logged as [TypeDefault], so that they are
+//     persisted like messages written to stderr.
+//   - Levels below [LevelFault] are logged as [TypeError].
+//   - Levels at or above [LevelFault] are logged as [TypeFault].
+func TypeForLevel(level slog.Level) Type {
+	switch {
+	case level < slog.LevelInfo:
+		return TypeDebug
+	case level < slog.LevelError:
+		return TypeDefault
+	case level < LevelFault:
+		return TypeError
+	default:
+		return TypeFault
+	}
+}
+
+// private wraps values of attributes created with [Private].
+type private struct {
+	value slog.Value
+}
+
+// Private returns an attribute whose value is redacted in unified logging,
+// unless logging of private data is enabled for the subsystem.
+func Private(key string, value any) slog.Attr {
+	return slog.Any(key, private{value: slog.AnyValue(value)})
+}
+
+// HandlerOptions are options for [Handler].
+type HandlerOptions struct {
+	// Level is the minimum level of records to log.
+	// Defaults to [slog.LevelInfo].
+	Level slog.Leveler
+}
+
+// Handler is a [slog.Handler] which writes to unified logging.
+type Handler struct {
+	subsystem string
+	opts      HandlerOptions
+	log       logger
+	attrs     []field
+	prefix    string
+	asl       bool
+}
+
+// field is a formatted attribute.
+type field struct {
+	text    string
+	private bool
+}
+
+// logger writes to a log of unified logging.
+type logger interface {
+	enabled(t Type) bool
+	write(t Type, public, private string)
+}
+
+// cache of loggers keyed by subsystem and category,
+// as logs are never released.
+var cache sync.Map
+
+// NewHandler returns a [Handler] which writes to the log for the
+// subsystem and category. Subsystem is typically the label of the job,
+// like "com.example.svc", and category identifies the component of the
+// service, like "http". If opts is nil, default options are used.
+//
+//   - [syscall.EINVAL] is returned if subsystem or category is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func NewHandler(subsystem, category string, opts *HandlerOptions) (*Handler, error) {
+	if subsystem == "" || strings.ContainsRune(subsystem, 0) || strings.ContainsRune(category, 0) {
+		return nil, fmt.Errorf("oslog: invalid subsystem(%q) or category(%q): %w",
+			subsystem, category, syscall.EINVAL)
+	}
+
+	log, err := cachedLogger(subsystem, category)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handler{subsystem: subsystem, log: log}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h, nil
+}
+
+// cachedLogger returns the logger for subsystem and category,
+// creating it if necessary.
+func cachedLogger(subsystem, category string) (logger, error) {
+	key := subsystem + "\x00" + category
+	if v, ok := cache.Load(key); ok {
+		return v.(logger), nil
+	}
+
+	log, err := newLogger(subsystem, category)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := cache.LoadOrStore(key, log)
+	return v.(logger), nil
+}
+
+// WithCategory returns a handler which writes to the given category of the
+// same subsystem, with same options and attributes.
+//
+//   - [syscall.EINVAL] is returned if category is invalid.
+func (h *Handler) WithCategory(category string) (*Handler, error) {
+	if strings.ContainsRune(category, 0) {
+		return nil, fmt.Errorf("oslog: invalid category(%q): %w", category, syscall.EINVAL)
+	}
+
+	var log logger
+	var err error
+	if h.asl {
+		log, err = newASLLogger(h.subsystem, category)
+	} else {
+		log, err = cachedLogger(h.subsystem, category)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	h2 := *h
+	h2.log = log
+	return &h2, nil
+}
+
+// Enabled reports whether handler handles records at the given level.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel && h.log.enabled(TypeForLevel(level))
+}
+
+// Handle writes the record to unified logging.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	fields := slices.Clip(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, a, false)
+		return true
+	})
+
+	public, private := format(r.Message, fields)
+	h.log.write(TypeForLevel(r.Level), public, private)
+	return nil
+}
+
+// WithAttrs returns a handler whose records include attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		h2.attrs = appendAttr(h2.attrs, h.prefix, a, false)
+	}
+	return &h2
+}
+
+// WithGroup returns a handler which qualifies keys of subsequent
+// attributes with the group name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// appendAttr appends formatted attribute to fields. Groups are flattened,
+// with keys qualified by group names.
+func appendAttr(fields []field, prefix string, a slog.Attr, priv bool) []field {
+	a.Value = a.Value.Resolve()
+	if p, ok := a.Value.Any().(private); ok {
+		a.Value = p.value.Resolve()
+		priv = true
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if a.Key != "" {
+			prefix = prefix + a.Key + "."
+		}
+		for _, ga := range attrs {
+			fields = appendAttr(fields, prefix, ga, priv)
+		}
+		return fields
+	}
+
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	return append(fields, field{
+		text:    prefix + a.Key + "=" + formatValue(a.Value),
+		private: priv,
+	})
+}
+
+// formatValue formats the value, quoting strings if necessary.
+func formatValue(v slog.Value) string {
+	var s string
+	switch v.Kind() {
+	case slog.KindTime:
+		s = v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		s = v.Duration().String()
+	default:
+		s = v.String()
+	}
+
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// needsQuoting reports whether s must be quoted.
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// format returns public and private parts of the message.
+func format(msg string, fields []field) (string, string) {
+	var public, private strings.Builder
+	public.WriteString(msg)
+	for _, f := range fields {
+		b := &public
+		if f.private {
+			b = &private
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(f.text)
+	}
+	return public.String(), private.String()
+}
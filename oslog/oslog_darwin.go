@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package oslog
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// osLog writes to os_log.
+type osLog struct {
+	log macos.OSLog
+}
+
+func (l *osLog) enabled(t Type) bool {
+	return l.log.Enabled(macos.OSLogType(t))
+}
+
+func (l *osLog) write(t Type, public, private string) {
+	l.log.Log(macos.OSLogType(t), public, private)
+}
+
+// aslLog writes to Apple System Log.
+type aslLog struct {
+	facility string
+	category string
+}
+
+func (l *aslLog) enabled(_ Type) bool {
+	return true
+}
+
+func (l *aslLog) write(t Type, public, private string) {
+	macos.ASLSend(map[string]string{
+		macos.ASLKeyFacility: l.facility,
+		macos.ASLKeyLevel:    aslLevel(t),
+		macos.ASLKeyMessage:  aslMessage(l.category, public, private),
+	})
+}
+
+// Os specific implementation of creating a logger. Apple System Log
+// is used if os_log is not available.
+func newLogger(subsystem, category string) (logger, error) {
+	if log := macos.OSLogCreate(subsystem, category); log != 0 {
+		return &osLog{log: log}, nil
+	}
+	return newASLLogger(subsystem, category)
+}
+
+// Os specific implementation of creating an ASL logger.
+func newASLLogger(subsystem, category string) (logger, error) {
+	if !macos.ASLAvailable() {
+		return nil, fmt.Errorf("oslog: apple system log is not available: %w", syscall.ENOTSUP)
+	}
+	return &aslLog{facility: subsystem, category: category}, nil
+}
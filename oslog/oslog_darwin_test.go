@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package oslog_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/oslog"
+)
+
+func TestNewHandler(t *testing.T) {
+	h, err := oslog.NewHandler("com.github.tprasadtp.go-launchd", "test", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	logger := slog.New(h)
+	logger.Info("oslog test", "key", "value", oslog.Private("secret", "value"))
+	logger.Error("oslog test error", "code", 1)
+
+	c, err := h.WithCategory("other")
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	slog.New(c).Info("oslog test category")
+}
+
+func TestNewASLHandler(t *testing.T) {
+	h, err := oslog.NewASLHandler("com.github.tprasadtp.go-launchd", "test", nil)
+	if err != nil {
+		t.Skipf("apple system log is not available: %s", err)
+	}
+	slog.New(h).Info("asl test", "key", "value", oslog.Private("secret", "value"))
+}
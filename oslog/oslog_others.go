@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package oslog
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of creating a logger.
+func newLogger(_, _ string) (logger, error) {
+	return nil, fmt.Errorf("oslog: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of creating an ASL logger.
+func newASLLogger(_, _ string) (logger, error) {
+	return nil, fmt.Errorf("oslog: only supported on macOS: %w", syscall.ENOTSUP)
+}
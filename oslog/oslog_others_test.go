@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package oslog_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/oslog"
+)
+
+func TestNewHandler_Unsupported(t *testing.T) {
+	_, err := oslog.NewHandler("com.example.svc", "test", nil)
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
+
+func TestNewASLHandler_Unsupported(t *testing.T) {
+	_, err := oslog.NewASLHandler("com.example.svc", "test", nil)
+	if !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
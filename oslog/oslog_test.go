@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package oslog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type message struct {
+	t       Type
+	public  string
+	private string
+}
+
+type fakeLogger struct {
+	messages []message
+}
+
+func (l *fakeLogger) enabled(t Type) bool {
+	return t != TypeDebug
+}
+
+func (l *fakeLogger) write(t Type, public, private string) {
+	l.messages = append(l.messages, message{t: t, public: public, private: private})
+}
+
+func TestTypeForLevel(t *testing.T) {
+	tt := []struct {
+		level  slog.Level
+		expect Type
+	}{
+		{level: slog.LevelDebug, expect: TypeDebug},
+		{level: slog.LevelInfo, expect: TypeDefault},
+		{level: slog.LevelWarn, expect: TypeDefault},
+		{level: slog.LevelError, expect: TypeError},
+		{level: LevelFault, expect: TypeFault},
+		{level: LevelFault + 4, expect: TypeFault},
+	}
+	for _, tc := range tt {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			got := TypeForLevel(tc.level)
+			if got != tc.expect {
+				t.Errorf("expected=%s, got=%s", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	fake := &fakeLogger{}
+	h := &Handler{subsystem: "com.example.svc", log: fake}
+	logger := slog.New(h).With("component", "http").WithGroup("req")
+
+	logger.Info("request served",
+		"path", "/index.html",
+		"status", 200,
+		"took", 1500*time.Millisecond,
+		slog.Group("client", "addr", "127.0.0.1"),
+		Private("user", "jane doe"),
+	)
+	logger.Debug("not logged")
+	logger.Error("failed", "err", errors.New("connection reset"))
+
+	expect := []message{
+		{
+			t:       TypeDefault,
+			public:  `request served component=http req.path=/index.html req.status=200 req.took=1.5s req.client.addr=127.0.0.1`,
+			private: `req.user="jane doe"`,
+		},
+		{
+			t:      TypeError,
+			public: `failed component=http req.err="connection reset"`,
+		},
+	}
+
+	if len(fake.messages) != len(expect) {
+		t.Fatalf("expected %d messages, got=%d: %+v", len(expect), len(fake.messages), fake.messages)
+	}
+	for i := range expect {
+		if fake.messages[i] != expect[i] {
+			t.Errorf("expected=%+v, got=%+v", expect[i], fake.messages[i])
+		}
+	}
+}
+
+func TestHandler_Level(t *testing.T) {
+	h := &Handler{
+		log:  &fakeLogger{},
+		opts: HandlerOptions{Level: slog.LevelWarn},
+	}
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Errorf("expected info to be disabled")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Errorf("expected error to be enabled")
+	}
+}
+
+func TestNewHandler_Invalid(t *testing.T) {
+	tt := []struct {
+		name      string
+		subsystem string
+		category  string
+	}{
+		{name: "empty-subsystem"},
+		{name: "nul-subsystem", subsystem: "com.example\x00svc"},
+		{name: "nul-category", subsystem: "com.example.svc", category: "a\x00b"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewHandler(tc.subsystem, tc.category, nil)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
+
+func TestASLMessage(t *testing.T) {
+	tt := []struct {
+		name     string
+		category string
+		public   string
+		private  string
+		expect   string
+	}{
+		{name: "public", public: "started port=80", expect: "started port=80"},
+		{name: "category", category: "http", public: "started", expect: "[http] started"},
+		{name: "private", public: "login", private: "user=jane", expect: "login <private>"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := aslMessage(tc.category, tc.public, tc.private)
+			if got != tc.expect {
+				t.Errorf("expected=%q, got=%q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestASLLevel(t *testing.T) {
+	tt := []struct {
+		t      Type
+		expect string
+	}{
+		{t: TypeDebug, expect: "7"},
+		{t: TypeInfo, expect: "6"},
+		{t: TypeDefault, expect: "5"},
+		{t: TypeError, expect: "3"},
+		{t: TypeFault, expect: "2"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.t.String(), func(t *testing.T) {
+			if got := aslLevel(tc.t); got != tc.expect {
+				t.Errorf("expected=%s, got=%s", tc.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+// osVersion returns empty string, as it is not macOS.
+func osVersion() string {
+	return ""
+}
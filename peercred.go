@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+)
+
+// Creds are credentials of the peer process of a unix socket connection.
+// Credentials are those of the peer at the time it connected to the socket.
+type Creds struct {
+	// PID is the process ID of the peer.
+	PID int
+
+	// UID is the user ID of the peer. As LOCAL_PEERCRED only reports
+	// effective user ID, this is always the same as EUID.
+	UID uint32
+
+	// EUID is the effective user ID of the peer.
+	EUID uint32
+
+	// GID is the effective group ID of the peer.
+	GID uint32
+
+	// Groups are the supplementary groups of the peer, including GID.
+	// This is truncated to 16 groups by the kernel.
+	Groups []uint32
+}
+
+// PeerCredentials returns credentials of the peer process of a unix socket
+// connection, like the ones accepted from listeners of launchd activated
+// unix sockets, using LOCAL_PEERCRED and LOCAL_PEERPID socket options.
+// This is useful for privileged helpers to authorize their callers.
+//
+//   - [syscall.EAFNOSUPPORT] is returned if conn is not a unix socket connection.
+//   - [syscall.ENOTCONN] is returned if conn is not connected.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func PeerCredentials(conn net.Conn) (*Creds, error) {
+	return peerCredentials(conn)
+}
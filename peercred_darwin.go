@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// getsockopt calls getsockopt(2) on socket fd, with value of size n at val.
+// This uses [syscall.Syscall6] instead of libSystem, as [syscall] does not
+// provide getsockopt for arbitrary values.
+func getsockopt(fd uintptr, level, name int, val unsafe.Pointer, n uint32) error {
+	_, _, errno := syscall.Syscall6(syscall.SYS_GETSOCKOPT, fd, uintptr(level), uintptr(name),
+		uintptr(val), uintptr(unsafe.Pointer(&n)), 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// controlUnix calls fn with file descriptor of the unix socket connection.
+func controlUnix(conn net.Conn, fn func(fd uintptr) error) error {
+	if conn == nil {
+		return fmt.Errorf("launchd: connection is nil: %w", syscall.EINVAL)
+	}
+	if _, ok := conn.LocalAddr().(*net.UnixAddr); !ok {
+		return fmt.Errorf("launchd: not a unix socket connection: %w", syscall.EAFNOSUPPORT)
+	}
+
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return fmt.Errorf("launchd: connection(%T) does not support syscall.Conn: %w", conn, syscall.EINVAL)
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return fmt.Errorf("launchd: %w", err)
+	}
+
+	var ep error
+	err = rc.Control(func(fd uintptr) {
+		ep = fn(fd)
+	})
+	if err != nil {
+		return fmt.Errorf("launchd: %w", err)
+	}
+	return ep
+}
+
+// Os specific implementation of [PeerCredentials].
+func peerCredentials(conn net.Conn) (*Creds, error) {
+	var creds Creds
+	err := controlUnix(conn, func(fd uintptr) error {
+		err := peerXucred(fd, &creds)
+		if err != nil {
+			return err
+		}
+
+		creds.PID, err = syscall.GetsockoptInt(int(fd), solLocal, localPeerPID)
+		if err != nil {
+			return fmt.Errorf("launchd: error getting peer pid: %w", os.NewSyscallError("getsockopt", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd_test
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+// connPair returns accepted and dialed connections for a listener on network.
+func connPair(t *testing.T, network, addr string) (net.Conn, net.Conn) {
+	t.Helper()
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	client, err := net.Dial(network, l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+	})
+
+	server, err := l.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+	t.Cleanup(func() {
+		server.Close()
+	})
+	return server, client
+}
+
+func TestPeerCredentials(t *testing.T) {
+	server, _ := connPair(t, "unix", filepath.Join(t.TempDir(), "peer.sock"))
+	creds, err := launchd.PeerCredentials(server)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if creds.PID != os.Getpid() {
+		t.Errorf("expected pid=%d, got=%d", os.Getpid(), creds.PID)
+	}
+	if creds.EUID != uint32(os.Geteuid()) || creds.UID != creds.EUID {
+		t.Errorf("expected uid=euid=%d, got uid=%d, euid=%d", os.Geteuid(), creds.UID, creds.EUID)
+	}
+	if creds.GID != uint32(os.Getegid()) {
+		t.Errorf("expected gid=%d, got=%d", os.Getegid(), creds.GID)
+	}
+	if len(creds.Groups) == 0 {
+		t.Errorf("expected groups to be populated")
+	}
+}
+
+func TestPeerCredentials_NotUnix(t *testing.T) {
+	server, _ := connPair(t, "tcp4", "127.0.0.1:0")
+	creds, err := launchd.PeerCredentials(server)
+	if creds != nil {
+		t.Errorf("expected no credentials for tcp connection")
+	}
+	if !errors.Is(err, syscall.EAFNOSUPPORT) {
+		t.Errorf("expected error=%s, got=%s", syscall.EAFNOSUPPORT, err)
+	}
+}
+
+func TestVerifyPeer(t *testing.T) {
+	tt := []struct {
+		name        string
+		network     string
+		requirement string
+		expect      error
+	}{
+		{
+			name:        "RequirementNotSatisfied",
+			network:     "unix",
+			requirement: `identifier "com.example.does-not-exist" and anchor apple`,
+			expect:      launchd.ErrPeerRequirement,
+		},
+		{
+			name:        "InvalidRequirement",
+			network:     "unix",
+			requirement: `this is not a requirement`,
+			expect:      syscall.EINVAL,
+		},
+		{
+			name:        "NotUnix",
+			network:     "tcp4",
+			requirement: `anchor apple`,
+			expect:      syscall.EAFNOSUPPORT,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			addr := "127.0.0.1:0"
+			if tc.network == "unix" {
+				addr = filepath.Join(t.TempDir(), "peer.sock")
+			}
+			server, _ := connPair(t, tc.network, addr)
+			err := launchd.VerifyPeer(server, tc.requirement)
+			if !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%s, got=%s", tc.expect, err)
+			}
+		})
+	}
+}
+
+func TestPeerAuditToken(t *testing.T) {
+	server, _ := connPair(t, "unix", filepath.Join(t.TempDir(), "peer.sock"))
+	token, err := launchd.PeerAuditToken(server)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if token.PID() != os.Getpid() {
+		t.Errorf("expected pid=%d, got=%d", os.Getpid(), token.PID())
+	}
+	if token.EUID() != uint32(os.Geteuid()) {
+		t.Errorf("expected euid=%d, got=%d", os.Geteuid(), token.EUID())
+	}
+	if token.UID() != uint32(os.Getuid()) {
+		t.Errorf("expected uid=%d, got=%d", os.Getuid(), token.UID())
+	}
+
+	// Test binaries are ad-hoc signed on arm64 and unsigned on amd64.
+	_, err = token.SigningIdentifier()
+	if err != nil && !errors.Is(err, launchd.ErrPeerRequirement) {
+		t.Errorf("expected no error or error=%s, got=%s", launchd.ErrPeerRequirement, err)
+	}
+}
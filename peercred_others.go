@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Os specific implementation of [PeerCredentials].
+func peerCredentials(_ net.Conn) (*Creds, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
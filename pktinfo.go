@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// WithPacketInfo enables IP_RECVPKTINFO or IPV6_RECVPKTINFO on UDP sockets
+// returned by [Open] as packet connections, so that [ReadFromWithDst] can
+// return the destination address of received packets. Multi-homed UDP
+// servers, typically listening on the unspecified address, must reply from
+// the address the client sent the request to, with [WriteToWithSrc].
+//
+// Failing to enable packet info is handled like any other socket which
+// cannot be used, i.e. [Open] returns an error unless [WithPartial] is used.
+func WithPacketInfo() Option {
+	return func(o *options) {
+		o.packetInfo = true
+	}
+}
+
+// PacketInfo is the packet information of a received UDP packet.
+type PacketInfo struct {
+	// Dst is the destination address of the packet, i.e. the local
+	// address the client sent the packet to. For IPv6 sockets, this is an
+	// IPv4-mapped IPv6 address for packets received over IPv4.
+	Dst net.IP
+
+	// IfIndex is the index of the interface the packet was received on.
+	IfIndex int
+
+	// ECN is the ECN codepoint of the packet, if receiving it was enabled
+	// with [WithECN]. Otherwise, it is [ECNNotECT].
+	ECN ECN
+}
+
+// ReadFromWithDst reads a packet from the UDP connection like
+// [net.UDPConn.ReadFromUDP], along with its [PacketInfo]. Returned
+// packet info is nil if the packet did not include it, typically because
+// neither [WithPacketInfo] nor [WithECN] was used.
+//
+//   - [syscall.ENOTSUP] is returned on platforms other than macOS and Linux.
+func ReadFromWithDst(c *net.UDPConn, b []byte) (int, *net.UDPAddr, *PacketInfo, error) {
+	return readFromWithDst(c, b)
+}
+
+// WriteToWithSrc writes a packet to addr like [net.UDPConn.WriteToUDP],
+// using the destination address of info, as returned by [ReadFromWithDst],
+// as the source address of the packet. If info is nil or does not have
+// a destination address, this is the same as [net.UDPConn.WriteToUDP].
+//
+//   - [syscall.ENOTSUP] is returned on platforms other than macOS and Linux.
+func WriteToWithSrc(c *net.UDPConn, b []byte, addr *net.UDPAddr, info *PacketInfo) (int, error) {
+	if info == nil || info.Dst == nil {
+		return c.WriteToUDP(b, addr)
+	}
+	return writeToWithSrc(c, b, addr, info)
+}
+
+// packetInfo enables packet info on UDP packet connections of the result,
+// if configured. Packet connections which fail are closed and reported as
+// errors, like sockets which could not be used.
+func (r *Result) packetInfo(o *options) {
+	if !o.packetInfo {
+		return
+	}
+
+	var err error
+	packetConns := r.PacketConns[:0]
+	for _, p := range r.PacketConns {
+		if c, ok := p.(*net.UDPConn); ok {
+			if e := setPacketInfo(c); e != nil {
+				err = errors.Join(err, fmt.Errorf("launchd: failed to enable packet info(%s): %w", p.LocalAddr(), e))
+				_ = p.Close()
+				continue
+			}
+		}
+		packetConns = append(packetConns, p)
+	}
+	r.PacketConns = packetConns
+	if err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+}
+
+// isIPv4Conn reports whether the UDP connection is an IPv4 socket.
+// Addresses of IPv4 sockets are 4 bytes long.
+func isIPv4Conn(c *net.UDPConn) bool {
+	addr, ok := c.LocalAddr().(*net.UDPAddr)
+	return ok && len(addr.IP) == net.IPv4len
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin && !linux
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// setPacketInfo enables IP_RECVPKTINFO or IPV6_RECVPKTINFO on the socket.
+func setPacketInfo(_ *net.UDPConn) error {
+	return fmt.Errorf("launchd: packet info is only supported on macOS and Linux: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [ReadFromWithDst].
+func readFromWithDst(_ *net.UDPConn, _ []byte) (int, *net.UDPAddr, *PacketInfo, error) {
+	return 0, nil, nil, fmt.Errorf("launchd: packet info is only supported on macOS and Linux: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [WriteToWithSrc].
+func writeToWithSrc(_ *net.UDPConn, _ []byte, _ *net.UDPAddr, _ *PacketInfo) (int, error) {
+	return 0, fmt.Errorf("launchd: packet info is only supported on macOS and Linux: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin || linux
+
+package launchd
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Sizes of in_pktinfo and in6_pktinfo structures,
+// which are the same on macOS and Linux.
+const (
+	sizeofInet4Pktinfo = 12
+	sizeofInet6Pktinfo = 20
+)
+
+// setPacketInfo enables IP_RECVPKTINFO or IPV6_RECVPKTINFO on the socket.
+func setPacketInfo(c *net.UDPConn) error {
+	if isIPv4Conn(c) {
+		return setSockopt(c, syscall.IPPROTO_IP, ipRecvPktinfo, 1)
+	}
+	return setSockopt(c, syscall.IPPROTO_IPV6, ipv6RecvPktinfo, 1)
+}
+
+// setSockopt sets integer socket option of the UDP connection.
+func setSockopt(c *net.UDPConn, level, name, value int) error {
+	rc, err := c.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	err = rc.Control(func(fd uintptr) {
+		serr = syscall.SetsockoptInt(int(fd), level, name, value)
+	})
+	if err != nil {
+		return err
+	}
+	if serr != nil {
+		return os.NewSyscallError("setsockopt", serr)
+	}
+	return nil
+}
+
+// Os specific implementation of [ReadFromWithDst].
+func readFromWithDst(c *net.UDPConn, b []byte) (int, *net.UDPAddr, *PacketInfo, error) {
+	oob := make([]byte, syscall.CmsgSpace(sizeofInet6Pktinfo)+syscall.CmsgSpace(4))
+	n, oobn, _, addr, err := c.ReadMsgUDP(b, oob)
+	if err != nil {
+		return n, addr, nil, err
+	}
+	return n, addr, parsePacketInfo(oob[:oobn]), nil
+}
+
+// parsePacketInfo returns [PacketInfo] from control messages,
+// or nil if there is none.
+func parsePacketInfo(oob []byte) *PacketInfo {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil
+	}
+
+	var info PacketInfo
+	var found bool
+	for _, m := range msgs {
+		level, typ := m.Header.Level, m.Header.Type
+		switch {
+		case level == syscall.IPPROTO_IP && typ == ipPktinfo && len(m.Data) >= sizeofInet4Pktinfo:
+			info.IfIndex = int(binary.NativeEndian.Uint32(m.Data[0:4]))
+			info.Dst = net.IP(append([]byte(nil), m.Data[8:12]...))
+		case level == syscall.IPPROTO_IPV6 && typ == ipv6Pktinfo && len(m.Data) >= sizeofInet6Pktinfo:
+			info.Dst = net.IP(append([]byte(nil), m.Data[0:16]...))
+			info.IfIndex = int(binary.NativeEndian.Uint32(m.Data[16:20]))
+		case level == syscall.IPPROTO_IP && typ == ipTOSCmsg && len(m.Data) >= 1:
+			// TOS is the first byte, regardless of the size of the data.
+			info.ECN = ECN(m.Data[0] & ecnMask)
+		case level == syscall.IPPROTO_IPV6 && typ == ipv6TClass && len(m.Data) >= 4:
+			info.ECN = ECN(binary.NativeEndian.Uint32(m.Data[0:4]) & ecnMask)
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	return &info
+}
+
+// Os specific implementation of [WriteToWithSrc].
+func writeToWithSrc(c *net.UDPConn, b []byte, addr *net.UDPAddr, info *PacketInfo) (int, error) {
+	n, _, err := c.WriteMsgUDP(b, packetInfoOOB(isIPv4Conn(c), info), addr)
+	return n, err
+}
+
+// packetInfoOOB returns control message with IP_PKTINFO or IPV6_PKTINFO,
+// which sets the source address of the packet to the destination address of
+// info. Interface index is only used for link-local addresses, so that the
+// outgoing interface is otherwise chosen by routing.
+func packetInfoOOB(ipv4 bool, info *PacketInfo) []byte {
+	var level, typ int
+	var data []byte
+	if ipv4 {
+		level, typ = syscall.IPPROTO_IP, ipPktinfo
+		data = make([]byte, sizeofInet4Pktinfo)
+		copy(data[4:8], info.Dst.To4())
+	} else {
+		level, typ = syscall.IPPROTO_IPV6, ipv6Pktinfo
+		data = make([]byte, sizeofInet6Pktinfo)
+		copy(data[0:16], info.Dst.To16())
+		if info.Dst.IsLinkLocalUnicast() {
+			binary.NativeEndian.PutUint32(data[16:20], uint32(info.IfIndex))
+		}
+	}
+
+	oob := make([]byte, syscall.CmsgSpace(len(data)))
+	h := (*syscall.Cmsghdr)(unsafe.Pointer(&oob[0]))
+	h.Level = int32(level)
+	h.Type = int32(typ)
+	h.SetLen(syscall.CmsgLen(len(data)))
+	copy(oob[syscall.CmsgLen(0):], data)
+	return oob
+}
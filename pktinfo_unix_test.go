@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin || linux
+
+package launchd
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPacketInfo(t *testing.T) {
+	tt := []struct {
+		name     string
+		network  string
+		listen   string
+		loopback net.IP
+	}{
+		{name: "IPv4", network: "udp4", listen: "0.0.0.0:0", loopback: net.IPv4(127, 0, 0, 1)},
+		{name: "IPv6", network: "udp6", listen: "[::]:0", loopback: net.IPv6loopback},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := net.ListenPacket(tc.network, tc.listen)
+			if err != nil {
+				t.Skipf("failed to listen: %s", err)
+			}
+			r := &Result{Name: "test", PacketConns: []net.PacketConn{p}}
+			defer r.Close()
+
+			r.packetInfo(newOptions([]Option{WithPacketInfo()}))
+			if len(r.PacketConns) != 1 || r.Partial() {
+				t.Fatalf("expected no error, got=%v", r.Err())
+			}
+			server := p.(*net.UDPConn)
+
+			port := server.LocalAddr().(*net.UDPAddr).Port
+			client, err := net.DialUDP(tc.network, nil, &net.UDPAddr{IP: tc.loopback, Port: port})
+			if err != nil {
+				t.Skipf("failed to dial: %s", err)
+			}
+			defer client.Close()
+			_ = client.SetDeadline(time.Now().Add(5 * time.Second))
+			_ = server.SetDeadline(time.Now().Add(5 * time.Second))
+
+			if _, err = client.Write([]byte("ping")); err != nil {
+				t.Fatalf("failed to write: %s", err)
+			}
+			b := make([]byte, 16)
+			n, addr, info, err := ReadFromWithDst(server, b)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			if string(b[:n]) != "ping" {
+				t.Errorf("expected data=ping, got=%q", b[:n])
+			}
+			if info == nil || !info.Dst.Equal(tc.loopback) {
+				t.Fatalf("expected dst=%s, got=%+v", tc.loopback, info)
+			}
+			if info.IfIndex == 0 {
+				t.Errorf("expected non-zero interface index")
+			}
+
+			if _, err = WriteToWithSrc(server, []byte("pong"), addr, info); err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			n, from, err := client.ReadFromUDP(b)
+			if err != nil {
+				t.Fatalf("failed to read reply: %s", err)
+			}
+			if string(b[:n]) != "pong" || !from.IP.Equal(tc.loopback) {
+				t.Errorf("expected pong from %s, got=%q from %s", tc.loopback, b[:n], from)
+			}
+		})
+	}
+}
+
+func TestParsePacketInfo_Invalid(t *testing.T) {
+	for _, oob := range [][]byte{nil, {0x01}, make([]byte, 64)} {
+		if info := parsePacketInfo(oob); info != nil {
+			t.Errorf("expected no packet info for %v, got=%+v", oob, info)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+// binaryMagic is the header of binary property lists.
+const binaryMagic = "bplist00"
+
+// binaryTrailerSize is the size of the trailer of binary property lists.
+const binaryTrailerSize = 32
+
+// maxDepth limits nesting of containers in both binary and XML
+// property lists, so that hostile input cannot exhaust the stack.
+const maxDepth = 512
+
+// binaryEpoch is the reference date of dates in binary property lists.
+var binaryEpoch = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// binaryParser parses binary property lists (bplist00).
+type binaryParser struct {
+	data    []byte
+	offsets []uint64
+	refSize int
+	visited []bool
+	depth   int
+
+	// budget is the number of objects which can still be decoded.
+	// Shared references are decoded each time they are referenced,
+	// thus this bounds the work for plists with shared containers.
+	budget uint64
+}
+
+// parseBinary parses the binary property list into a generic value.
+func parseBinary(data []byte) (any, error) {
+	if len(data) < len(binaryMagic)+binaryTrailerSize || !bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return nil, fmt.Errorf("plist: invalid binary plist header")
+	}
+
+	trailer := data[len(data)-binaryTrailerSize:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	count := binary.BigEndian.Uint64(trailer[8:])
+	top := binary.BigEndian.Uint64(trailer[16:])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:])
+
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 {
+		return nil, fmt.Errorf("plist: invalid binary plist trailer")
+	}
+	end := uint64(len(data) - binaryTrailerSize)
+	if count == 0 || top >= count || tableOffset >= end || count > (end-tableOffset)/uint64(offsetSize) {
+		return nil, fmt.Errorf("plist: invalid binary plist trailer")
+	}
+
+	p := &binaryParser{
+		data:    data[:end],
+		offsets: make([]uint64, count),
+		refSize: refSize,
+		visited: make([]bool, count),
+	}
+	// Each object is decoded once, and once per reference to it. Unless
+	// containers are shared, references cannot exceed the size of the plist.
+	p.budget = count + end/uint64(refSize)
+	for i := range p.offsets {
+		start := tableOffset + uint64(i*offsetSize)
+		p.offsets[i] = readUint(data[start : start+uint64(offsetSize)])
+		if p.offsets[i] < uint64(len(binaryMagic)) || p.offsets[i] >= tableOffset {
+			return nil, fmt.Errorf("plist: invalid binary plist object offset")
+		}
+	}
+	return p.object(top)
+}
+
+// readUint reads big endian unsigned integer of up to 8 bytes.
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// bytes returns n bytes at offset off.
+func (p *binaryParser) bytes(off, n uint64) ([]byte, error) {
+	if off > uint64(len(p.data)) || n > uint64(len(p.data))-off {
+		return nil, fmt.Errorf("plist: binary plist object out of bounds")
+	}
+	return p.data[off : off+n], nil
+}
+
+// length returns the length of the object with marker at off,
+// and the offset of its contents.
+func (p *binaryParser) length(off uint64) (uint64, uint64, error) {
+	marker := p.data[off]
+	if marker&0x0F != 0x0F {
+		return uint64(marker & 0x0F), off + 1, nil
+	}
+
+	b, err := p.bytes(off+1, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	if b[0]&0xF0 != 0x10 || b[0]&0x0F > 3 {
+		return 0, 0, fmt.Errorf("plist: invalid binary plist length")
+	}
+	size := uint64(1) << (b[0] & 0x0F)
+	v, err := p.bytes(off+2, size)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Length of any object cannot exceed the size of the plist.
+	n := readUint(v)
+	if n > uint64(len(p.data)) {
+		return 0, 0, fmt.Errorf("plist: binary plist object out of bounds")
+	}
+	return n, off + 2 + size, nil
+}
+
+// refs returns n object references at offset off.
+func (p *binaryParser) refs(off, n uint64) ([]uint64, error) {
+	b, err := p.bytes(off, n*uint64(p.refSize))
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]uint64, n)
+	for i := range refs {
+		refs[i] = readUint(b[i*p.refSize : (i+1)*p.refSize])
+	}
+	return refs, nil
+}
+
+// object parses the object with the given reference.
+//
+//nolint:gocognit,cyclop,funlen // marker switch.
+func (p *binaryParser) object(ref uint64) (any, error) {
+	if ref >= uint64(len(p.offsets)) {
+		return nil, fmt.Errorf("plist: invalid binary plist object reference")
+	}
+	if p.visited[ref] || p.depth >= maxDepth {
+		return nil, fmt.Errorf("plist: binary plist contains cycles or is too deeply nested")
+	}
+	if p.budget == 0 {
+		return nil, fmt.Errorf("plist: binary plist references too many objects")
+	}
+	p.budget--
+	p.visited[ref] = true
+	p.depth++
+	defer func() {
+		p.visited[ref] = false
+		p.depth--
+	}()
+
+	off := p.offsets[ref]
+	marker := p.data[off]
+	switch marker >> 4 {
+	case 0x0:
+		switch marker {
+		case 0x08:
+			return false, nil
+		case 0x09:
+			return true, nil
+		}
+	case 0x1:
+		size := uint64(1) << (marker & 0x0F)
+		b, err := p.bytes(off+1, size)
+		if err != nil {
+			return nil, err
+		}
+		switch size {
+		case 1, 2, 4, 8:
+			// 1, 2 and 4 byte integers are unsigned, 8 byte integers are signed.
+			return int64(readUint(b)), nil
+		case 16:
+			// 128-bit integers are used only for values over math.MaxInt64.
+			return readUint(b[8:]), nil
+		}
+	case 0x2:
+		switch marker & 0x0F {
+		case 2:
+			b, err := p.bytes(off+1, 4)
+			if err != nil {
+				return nil, err
+			}
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+		case 3:
+			b, err := p.bytes(off+1, 8)
+			if err != nil {
+				return nil, err
+			}
+			return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+		}
+	case 0x3:
+		if marker == 0x33 {
+			b, err := p.bytes(off+1, 8)
+			if err != nil {
+				return nil, err
+			}
+			secs := math.Float64frombits(binary.BigEndian.Uint64(b))
+			return binaryEpoch.Add(time.Duration(secs * float64(time.Second))), nil
+		}
+	case 0x4, 0x5, 0x6:
+		n, start, err := p.length(off)
+		if err != nil {
+			return nil, err
+		}
+		switch marker >> 4 {
+		case 0x4:
+			b, err := p.bytes(start, n)
+			if err != nil {
+				return nil, err
+			}
+			return bytes.Clone(b), nil
+		case 0x5:
+			b, err := p.bytes(start, n)
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		default:
+			b, err := p.bytes(start, n*2)
+			if err != nil {
+				return nil, err
+			}
+			u := make([]uint16, n)
+			for i := range u {
+				u[i] = binary.BigEndian.Uint16(b[i*2:])
+			}
+			return string(utf16.Decode(u)), nil
+		}
+	case 0xA:
+		n, start, err := p.length(off)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := p.refs(start, n)
+		if err != nil {
+			return nil, err
+		}
+		a := make([]any, 0, len(refs))
+		for _, r := range refs {
+			v, err := p.object(r)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		return a, nil
+	case 0xD:
+		n, start, err := p.length(off)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := p.refs(start, n*2)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]any, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := p.object(refs[i])
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("plist: binary plist dictionary key must be a string, got %T", k)
+			}
+			v, err := p.object(refs[n+i])
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("plist: unsupported binary plist object(0x%02x)", marker)
+}
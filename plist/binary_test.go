@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestUnmarshal_Binary(t *testing.T) {
+	xmlData, err := os.ReadFile("testdata/job.plist")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %s", err)
+	}
+	binData, err := os.ReadFile("testdata/job.bplist")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %s", err)
+	}
+
+	var fromXML, fromBinary any
+	if err = plist.Unmarshal(xmlData, &fromXML); err != nil {
+		t.Fatalf("expected no error decoding xml, got=%s", err)
+	}
+	if err = plist.Unmarshal(binData, &fromBinary); err != nil {
+		t.Fatalf("expected no error decoding binary, got=%s", err)
+	}
+	if !reflect.DeepEqual(fromXML, fromBinary) {
+		t.Errorf("expected binary=%v, got=%v", fromXML, fromBinary)
+	}
+
+	m, _ := fromBinary.(map[string]any)
+	expect := map[string]any{
+		"ExitTimeOut": int64(30),
+		"Nice":        int64(-5),
+		"Big":         uint64(18446744073709551615),
+		"Date":        time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),
+		"Data":        []byte("\x00\x01launchd"),
+	}
+	for k, v := range expect {
+		if !reflect.DeepEqual(m[k], v) {
+			t.Errorf("expected %s=%#v, got=%#v", k, v, m[k])
+		}
+	}
+
+	var job plist.Job
+	if err = plist.Unmarshal(binData, &job); err != nil {
+		t.Fatalf("expected no error decoding job, got=%s", err)
+	}
+	if job.Label != "com.example.svc" || job.ProgramArguments[1] != "--name=café ☕" {
+		t.Errorf("unexpected job: %+v", job)
+	}
+}
+
+func TestUnmarshal_BinaryInvalid(t *testing.T) {
+	data, err := os.ReadFile("testdata/job.bplist")
+	if err != nil {
+		t.Fatalf("failed to read testdata: %s", err)
+	}
+
+	cycle := []byte("bplist00" +
+		"\xa1\x00" + // array referencing itself
+		"\x08" + // offset table
+		"\x00\x00\x00\x00\x00\x00\x01\x01" +
+		"\x00\x00\x00\x00\x00\x00\x00\x01" +
+		"\x00\x00\x00\x00\x00\x00\x00\x00" +
+		"\x00\x00\x00\x00\x00\x00\x00\x0a")
+
+	tt := []struct {
+		name string
+		data []byte
+	}{
+		{name: "HeaderOnly", data: []byte("bplist00")},
+		{name: "Truncated", data: data[:len(data)-8]},
+		{name: "InvalidTrailer", data: append(data[:len(data)-32:len(data)-32], make([]byte, 32)...)},
+		{name: "Cycle", data: cycle},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var v any
+			if err := plist.Unmarshal(tc.data, &v); err == nil {
+				t.Errorf("expected error, got=nil")
+			}
+		})
+	}
+}
+
+// sharedBinary returns a binary plist of n nested arrays, each referencing
+// the next array twice. Decoding it naively takes 2^n steps.
+func sharedBinary(n int) []byte {
+	data := []byte("bplist00")
+	offsets := make([]byte, 0, n+1)
+	for i := 0; i < n; i++ {
+		offsets = append(offsets, byte(len(data)))
+		data = append(data, 0xA2, byte(i+1), byte(i+1))
+	}
+	offsets = append(offsets, byte(len(data)))
+	data = append(data, 0x09)
+
+	table := len(data)
+	data = append(data, offsets...)
+	trailer := make([]byte, 32)
+	trailer[6], trailer[7] = 1, 1
+	binary.BigEndian.PutUint64(trailer[8:], uint64(len(offsets)))
+	binary.BigEndian.PutUint64(trailer[24:], uint64(table))
+	return append(data, trailer...)
+}
+
+func TestUnmarshal_BinarySharedReferences(t *testing.T) {
+	t.Run("shallow", func(t *testing.T) {
+		var v any
+		if err := plist.Unmarshal(sharedBinary(2), &v); err != nil {
+			t.Errorf("expected no error, got=%s", err)
+		}
+		expect := []any{[]any{true, true}, []any{true, true}}
+		if !reflect.DeepEqual(expect, v) {
+			t.Errorf("expected=%#v, got=%#v", expect, v)
+		}
+	})
+
+	t.Run("exponential", func(t *testing.T) {
+		start := time.Now()
+		var v any
+		if err := plist.Unmarshal(sharedBinary(40), &v); err == nil {
+			t.Errorf("expected an error")
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("expected to fail fast, took=%s", d)
+		}
+	})
+}
+
+// nestedXML returns an XML plist with n nested arrays.
+func nestedXML(n int) []byte {
+	return []byte("<plist>" + strings.Repeat("<array>", n) + strings.Repeat("</array>", n) + "</plist>")
+}
+
+func TestUnmarshal_XMLDepth(t *testing.T) {
+	var v any
+	if err := plist.Unmarshal(nestedXML(16), &v); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+	if err := plist.Unmarshal(nestedXML(100000), &v); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, name := range []string{"testdata/job.plist", "testdata/job.bplist"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			f.Fatalf("failed to read testdata: %s", err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte("bplist00"))
+	f.Add([]byte("<plist><dict><key>Sockets</key><array/></dict></plist>"))
+	// Hostile plists: shared references and deep nesting.
+	f.Add(sharedBinary(2))
+	f.Add(sharedBinary(40))
+	f.Add(nestedXML(100000))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Malformed plists must return errors, never panic. As work is
+		// bounded by the size of the input, decoding must be fast.
+		start := time.Now()
+		var v any
+		_ = plist.Unmarshal(data, &v)
+		var job plist.Job
+		_ = plist.Unmarshal(data, &job)
+		if d := time.Since(start); d > 2*time.Second {
+			t.Errorf("decoding %d bytes took=%s", len(data), d)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"time"
+)
+
+// CalendarInterval is an entry in StartCalendarInterval of the job.
+//
+// Fields which are nil are wildcards. All specified fields must match.
+type CalendarInterval struct {
+	// Minute of the hour (0-59).
+	Minute *int `plist:"Minute,omitempty"`
+
+	// Hour of the day (0-23).
+	Hour *int `plist:"Hour,omitempty"`
+
+	// Day of the month (1-31).
+	Day *int `plist:"Day,omitempty"`
+
+	// Weekday (0-7), where both 0 and 7 are Sunday.
+	Weekday *int `plist:"Weekday,omitempty"`
+
+	// Month of the year (1-12).
+	Month *int `plist:"Month,omitempty"`
+}
+
+// match reports whether field matches the value.
+func match(field *int, v int) bool {
+	return field == nil || *field == v
+}
+
+// matchDay reports whether the day of t matches the interval.
+func (c CalendarInterval) matchDay(t time.Time) bool {
+	weekday := int(t.Weekday())
+	return match(c.Month, int(t.Month())) &&
+		match(c.Day, t.Day()) &&
+		(match(c.Weekday, weekday) || (weekday == 0 && match(c.Weekday, 7)))
+}
+
+// Next returns the first time strictly after t, truncated to minutes,
+// which matches the interval, in location of t. Zero time is returned
+// if interval never matches, for example February 30th.
+func (c CalendarInterval) Next(t time.Time) time.Time {
+	start := t.Truncate(time.Minute).Add(time.Minute)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+
+	// Any valid day pattern matches at least once in 8 years,
+	// as February 29th may be skipped in a century year.
+	for i := 0; i < 8*366; i++ {
+		d := day.AddDate(0, 0, i)
+		if !c.matchDay(d) {
+			continue
+		}
+		for hour := 0; hour < 24; hour++ {
+			if !match(c.Hour, hour) {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if !match(c.Minute, minute) {
+					continue
+				}
+				v := time.Date(d.Year(), d.Month(), d.Day(), hour, minute, 0, 0, d.Location())
+				if !v.Before(start) {
+					return v
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// NextCalendarTime returns the first time strictly after t at which
+// the job is started by its StartCalendarInterval. Zero time is returned
+// if job has no calendar intervals or none of them match.
+func (j *Job) NextCalendarTime(t time.Time) time.Time {
+	var next time.Time
+	for _, c := range j.StartCalendarInterval {
+		v := c.Next(t)
+		if !v.IsZero() && (next.IsZero() || v.Before(next)) {
+			next = v
+		}
+	}
+	return next
+}
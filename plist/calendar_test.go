@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func ptr(v int) *int {
+	return &v
+}
+
+func TestCalendarInterval_Next(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2024, time.March, 13, 10, 30, 15, 0, time.UTC)
+	tt := []struct {
+		name     string
+		interval plist.CalendarInterval
+		expect   time.Time
+	}{
+		{
+			name:   "every-minute",
+			expect: time.Date(2024, time.March, 13, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily-later-today",
+			interval: plist.CalendarInterval{Hour: ptr(11), Minute: ptr(0)},
+			expect:   time.Date(2024, time.March, 13, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily-tomorrow",
+			interval: plist.CalendarInterval{Hour: ptr(3), Minute: ptr(15)},
+			expect:   time.Date(2024, time.March, 14, 3, 15, 0, 0, time.UTC),
+		},
+		{
+			name:     "hourly",
+			interval: plist.CalendarInterval{Minute: ptr(0)},
+			expect:   time.Date(2024, time.March, 13, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly-sunday",
+			interval: plist.CalendarInterval{Weekday: ptr(0), Hour: ptr(2), Minute: ptr(0)},
+			expect:   time.Date(2024, time.March, 17, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly-sunday-7",
+			interval: plist.CalendarInterval{Weekday: ptr(7), Hour: ptr(2), Minute: ptr(0)},
+			expect:   time.Date(2024, time.March, 17, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly",
+			interval: plist.CalendarInterval{Day: ptr(1), Hour: ptr(0), Minute: ptr(0)},
+			expect:   time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap-day",
+			interval: plist.CalendarInterval{Month: ptr(2), Day: ptr(29), Hour: ptr(0), Minute: ptr(0)},
+			expect:   time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "never",
+			interval: plist.CalendarInterval{Month: ptr(2), Day: ptr(30)},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := tc.interval.Next(now); !v.Equal(tc.expect) {
+				t.Errorf("expected=%s, got=%s", tc.expect, v)
+			}
+		})
+	}
+}
+
+func TestJob_NextCalendarTime(t *testing.T) {
+	now := time.Date(2024, time.March, 13, 10, 30, 0, 0, time.UTC)
+	job := plist.Job{
+		StartCalendarInterval: []plist.CalendarInterval{
+			{Hour: ptr(12), Minute: ptr(0)},
+			{Hour: ptr(11), Minute: ptr(0)},
+		},
+	}
+
+	expect := time.Date(2024, time.March, 13, 11, 0, 0, 0, time.UTC)
+	if v := job.NextCalendarTime(now); !v.Equal(expect) {
+		t.Errorf("expected=%s, got=%s", expect, v)
+	}
+
+	if v := (&plist.Job{}).NextCalendarTime(now); !v.IsZero() {
+		t.Errorf("expected zero time, got=%s", v)
+	}
+}
+
+func TestMarshal_StartCalendarInterval(t *testing.T) {
+	job := plist.Job{
+		Label:                 "com.example.svc",
+		StartCalendarInterval: []plist.CalendarInterval{{Hour: ptr(3), Minute: ptr(0)}},
+	}
+
+	b, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := `	<key>StartCalendarInterval</key>
+	<array>
+		<dict>
+			<key>Minute</key>
+			<integer>0</integer>
+			<key>Hour</key>
+			<integer>3</integer>
+		</dict>
+	</array>
+`
+	if !strings.Contains(string(b), expect) {
+		t.Errorf("expected to contain=%s\ngot=%s", expect, b)
+	}
+}
+
+func TestUnmarshal_StartCalendarInterval(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.example.svc</string>
+	<key>StartCalendarInterval</key>
+	<dict>
+		<key>Hour</key>
+		<integer>3</integer>
+	</dict>
+</dict>
+</plist>
+`
+	var job plist.Job
+	if err := plist.Unmarshal([]byte(data), &job); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if len(job.StartCalendarInterval) != 1 {
+		t.Fatalf("expected 1 interval, got=%d", len(job.StartCalendarInterval))
+	}
+
+	c := job.StartCalendarInterval[0]
+	if c.Hour == nil || *c.Hour != 3 || c.Minute != nil {
+		t.Errorf("expected Hour=3 and no Minute, got=%+v", c)
+	}
+}
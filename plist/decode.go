@@ -0,0 +1,367 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unmarshaler is implemented by types which can unmarshal themselves
+// from a decoded property list value.
+//
+// Value passed to UnmarshalPlist is one of map[string]any, []any, string,
+// bool, int64, uint64, float64, []byte or [time.Time].
+type Unmarshaler interface {
+	UnmarshalPlist(v any) error
+}
+
+// Unmarshal parses XML or binary property list data and stores the result
+// in the value pointed to by v.
+//
+// Struct fields are matched by their "plist" struct tag or field name.
+// Unknown keys are ignored. If v is a pointer to an empty interface,
+// dictionaries are decoded as map[string]any and arrays as []any.
+func Unmarshal(data []byte, v any) error {
+	return NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Decoder reads XML or binary property lists from an input stream.
+type Decoder struct {
+	r *bufio.Reader
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads XML or binary property list from its input and stores it in
+// the value pointed to by v. Binary property lists are read until EOF.
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("plist: decode requires a non-nil pointer, got %T", v)
+	}
+
+	var value any
+	if magic, _ := d.r.Peek(len(binaryMagic)); string(magic) == binaryMagic {
+		data, err := io.ReadAll(d.r)
+		if err != nil {
+			return fmt.Errorf("plist: failed to read binary plist: %w", err)
+		}
+		if value, err = parseBinary(data); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if value, err = parseXML(xml.NewDecoder(d.r)); err != nil {
+			return err
+		}
+	}
+	return assign(value, rv.Elem())
+}
+
+// parseXML parses the XML property list into a generic value.
+func parseXML(dec *xml.Decoder) (any, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("plist: no plist element found")
+			}
+			return nil, fmt.Errorf("plist: invalid xml: %w", err)
+		}
+
+		if se, ok := tok.(xml.StartElement); ok {
+			if se.Name.Local != "plist" {
+				return nil, fmt.Errorf("plist: unexpected element <%s>", se.Name.Local)
+			}
+			value, end, err := parseXMLValue(dec, 0)
+			if err != nil {
+				return nil, err
+			}
+			if end {
+				return nil, fmt.Errorf("plist: empty plist element")
+			}
+			return value, nil
+		}
+	}
+}
+
+// parseXMLValue parses the next value at nesting depth. If the next element
+// is an end element, end is true.
+//
+//nolint:gocognit,cyclop // element name switch.
+func parseXMLValue(dec *xml.Decoder, depth int) (value any, end bool, err error) {
+	var se xml.StartElement
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false, fmt.Errorf("plist: invalid xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil, true, nil
+		case xml.StartElement:
+			se = t
+		default:
+			continue
+		}
+		break
+	}
+
+	if (se.Name.Local == "dict" || se.Name.Local == "array") && depth >= maxDepth {
+		return nil, false, fmt.Errorf("plist: xml plist is too deeply nested")
+	}
+
+	switch se.Name.Local {
+	case "dict":
+		m := make(map[string]any)
+		for {
+			key, end, err := parseXMLValue(dec, depth+1)
+			if err != nil {
+				return nil, false, err
+			}
+			if end {
+				return m, false, nil
+			}
+			k, ok := key.(xmlKey)
+			if !ok {
+				return nil, false, fmt.Errorf("plist: expected <key> in <dict>")
+			}
+			v, end, err := parseXMLValue(dec, depth+1)
+			if err != nil {
+				return nil, false, err
+			}
+			if end {
+				return nil, false, fmt.Errorf("plist: missing value for key(%s)", k)
+			}
+			m[string(k)] = v
+		}
+	case "array":
+		a := make([]any, 0)
+		for {
+			v, end, err := parseXMLValue(dec, depth+1)
+			if err != nil {
+				return nil, false, err
+			}
+			if end {
+				return a, false, nil
+			}
+			a = append(a, v)
+		}
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, false, fmt.Errorf("plist: invalid xml: %w", err)
+		}
+		return se.Name.Local == "true", false, nil
+	}
+
+	var text string
+	if err := dec.DecodeElement(&text, &se); err != nil {
+		return nil, false, fmt.Errorf("plist: invalid xml: %w", err)
+	}
+
+	switch se.Name.Local {
+	case "key":
+		return xmlKey(text), false, nil
+	case "string":
+		return text, false, nil
+	case "integer":
+		text = strings.TrimSpace(text)
+		if i, err := strconv.ParseInt(text, 0, 64); err == nil {
+			return i, false, nil
+		}
+		u, err := strconv.ParseUint(text, 0, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("plist: invalid integer(%s)", text)
+		}
+		return u, false, nil
+	case "real":
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("plist: invalid real(%s)", text)
+		}
+		return f, false, nil
+	case "date":
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, false, fmt.Errorf("plist: invalid date(%s)", text)
+		}
+		return t, false, nil
+	case "data":
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+		if err != nil {
+			return nil, false, fmt.Errorf("plist: invalid data: %w", err)
+		}
+		return b, false, nil
+	default:
+		return nil, false, fmt.Errorf("plist: unsupported element <%s>", se.Name.Local)
+	}
+}
+
+// xmlKey is a dictionary key, used only while parsing.
+type xmlKey string
+
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+// assign stores generic value v in rv.
+//
+//nolint:gocognit,cyclop,funlen // type switch on reflect kinds.
+func assign(v any, rv reflect.Value) error {
+	if rv.CanAddr() && rv.Addr().Type().Implements(unmarshalerType) {
+		if err := rv.Addr().Interface().(Unmarshaler).UnmarshalPlist(v); err != nil {
+			return fmt.Errorf("plist: error unmarshaling %s: %w", rv.Type(), err)
+		}
+		return nil
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("plist: cannot unmarshal %T into %s", v, rv.Type())
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return assign(v, rv.Elem())
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return mismatch()
+		}
+		rv.Set(reflect.ValueOf(v))
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return mismatch()
+		}
+		rv.SetBool(b)
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch n := v.(type) {
+		case int64:
+			i = n
+		case uint64:
+			if n > math.MaxInt64 {
+				return fmt.Errorf("plist: integer %d overflows %s", n, rv.Type())
+			}
+			i = int64(n)
+		default:
+			return mismatch()
+		}
+		if rv.OverflowInt(i) {
+			return fmt.Errorf("plist: integer %d overflows %s", i, rv.Type())
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch n := v.(type) {
+		case int64:
+			if n < 0 {
+				return fmt.Errorf("plist: integer %d overflows %s", n, rv.Type())
+			}
+			u = uint64(n)
+		case uint64:
+			u = n
+		default:
+			return mismatch()
+		}
+		if rv.OverflowUint(u) {
+			return fmt.Errorf("plist: integer %d overflows %s", u, rv.Type())
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		switch n := v.(type) {
+		case float64:
+			rv.SetFloat(n)
+		case int64:
+			rv.SetFloat(float64(n))
+		case uint64:
+			rv.SetFloat(float64(n))
+		default:
+			return mismatch()
+		}
+	case reflect.Slice:
+		if b, ok := v.([]byte); ok && rv.Type().Elem().Kind() == reflect.Uint8 {
+			rv.SetBytes(bytes.Clone(b))
+			return nil
+		}
+		a, ok := v.([]any)
+		if !ok {
+			// Some keys like StartCalendarInterval accept either a single
+			// dictionary or an array of dictionaries.
+			m, isDict := v.(map[string]any)
+			if !isDict {
+				return mismatch()
+			}
+			a = []any{m}
+		}
+		s := reflect.MakeSlice(rv.Type(), len(a), len(a))
+		for i := range a {
+			if err := assign(a[i], s.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("plist: unsupported map key type %s", rv.Type().Key())
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(m)))
+		}
+		for k, mv := range m {
+			ev := reflect.New(rv.Type().Elem()).Elem()
+			if err := assign(mv, ev); err != nil {
+				return err
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), ev)
+		}
+	case reflect.Struct:
+		if rv.Type() == reflect.TypeOf(time.Time{}) {
+			t, ok := v.(time.Time)
+			if !ok {
+				return mismatch()
+			}
+			rv.Set(reflect.ValueOf(t))
+			return nil
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		for _, f := range structFields(rv.Type()) {
+			if fv, ok := m[f.name]; ok {
+				if err := assign(fv, rv.Field(f.index)); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		return errors.New("plist: unsupported type " + rv.Type().String())
+	}
+	return nil
+}
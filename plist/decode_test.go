@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestUnmarshal_RoundTrip(t *testing.T) {
+	passive := false
+	owner := 501
+	job := plist.Job{
+		Label:            "com.example.svc",
+		ProgramArguments: []string{"/usr/local/bin/svc", "--flag=<value>"},
+		RunAtLoad:        true,
+		ExitTimeOut:      30,
+		EnvironmentVariables: map[string]string{
+			"A": "1",
+		},
+		InetdCompatibility: &plist.InetdCompatibility{Wait: true},
+		MachServices: map[string]plist.MachService{
+			"com.example.svc.default": {},
+			"com.example.svc.reset":   {ResetAtClose: true},
+		},
+		Sockets: map[string]plist.Socket{
+			"tcp": {
+				SockServiceName: "8080",
+				SockPassive:     &passive,
+			},
+			"unix": {
+				SockPathName:  "/var/run/svc.socket",
+				SockPathMode:  0o600,
+				SockPathOwner: &owner,
+			},
+		},
+	}
+
+	b, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	var got plist.Job
+	if err = plist.Unmarshal(b, &got); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if !reflect.DeepEqual(job, got) {
+		t.Errorf("expected=%#v\ngot=%#v", job, got)
+	}
+}
+
+func TestUnmarshal_MachServices(t *testing.T) {
+	tt := []struct {
+		name    string
+		service plist.MachService
+		expect  string
+	}{
+		{name: "default", service: plist.MachService{}, expect: "<true/>"},
+		{name: "disabled", service: plist.MachService{Disabled: true}, expect: "<false/>"},
+		{name: "options", service: plist.MachService{ResetAtClose: true}, expect: "<key>ResetAtClose</key>"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			job := plist.Job{
+				Label:        "com.example.svc",
+				MachServices: map[string]plist.MachService{"com.example.svc": tc.service},
+			}
+
+			b, err := plist.Marshal(job)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			if !strings.Contains(string(b), tc.expect) {
+				t.Errorf("expected to contain=%s\ngot=%s", tc.expect, b)
+			}
+
+			var got plist.Job
+			if err = plist.Unmarshal(b, &got); err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			if !reflect.DeepEqual(job, got) {
+				t.Errorf("expected=%#v\ngot=%#v", job, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_SocketArray(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+    <dict>
+        <key>Label</key>
+        <string>com.example.svc</string>
+        <key>Sockets</key>
+        <dict>
+            <key>Listeners</key>
+            <array>
+                <dict>
+                    <key>SockServiceName</key>
+                    <string>8080</string>
+                </dict>
+                <dict>
+                    <key>SockPathName</key>
+                    <string>/var/run/svc.sock</string>
+                </dict>
+            </array>
+        </dict>
+    </dict>
+</plist>
+`
+	var job plist.Job
+	if err := plist.Unmarshal([]byte(data), &job); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := []plist.Socket{
+		{SockServiceName: "8080"},
+		{SockPathName: "/var/run/svc.sock"},
+	}
+	if got := job.Sockets["Listeners"].Entries(); !reflect.DeepEqual(expect, got) {
+		t.Errorf("expected=%#v\ngot=%#v", expect, got)
+	}
+
+	b, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	var got plist.Job
+	if err = plist.Unmarshal(b, &got); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	if !reflect.DeepEqual(job, got) {
+		t.Errorf("expected=%#v\ngot=%#v", job, got)
+	}
+}
+
+func TestUnmarshal_Generic(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+    <dict>
+        <key>Label</key>
+        <string>com.example.svc</string>
+        <!-- comment -->
+        <key>Integer</key>
+        <integer>448</integer>
+        <key>Real</key>
+        <real>1.5</real>
+        <key>Data</key>
+        <data>
+            aGVsbG8=
+        </data>
+        <key>Array</key>
+        <array>
+            <true />
+            <false/>
+        </array>
+    </dict>
+</plist>
+`
+	var got any
+	if err := plist.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := map[string]any{
+		"Label":   "com.example.svc",
+		"Integer": int64(448),
+		"Real":    1.5,
+		"Data":    []byte("hello"),
+		"Array":   []any{true, false},
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("expected=%#v\ngot=%#v", expect, got)
+	}
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	tt := []struct {
+		name string
+		data string
+	}{
+		{name: "empty", data: ""},
+		{name: "not-plist", data: "<html></html>"},
+		{name: "empty-plist", data: "<plist></plist>"},
+		{name: "unclosed", data: "<plist><dict><key>Label</key>"},
+		{name: "missing-value", data: "<plist><dict><key>Label</key></dict></plist>"},
+		{name: "invalid-integer", data: "<plist><integer>x</integer></plist>"},
+		{name: "type-mismatch", data: "<plist><dict><key>Label</key><true/></dict></plist>"},
+		{name: "socket-array-empty", data: "<plist><dict><key>Sockets</key><dict><key>s</key><array/></dict></dict></plist>"},
+		{name: "socket-array-value", data: "<plist><dict><key>Sockets</key><dict><key>s</key><array><true/></array></dict></dict></plist>"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var job plist.Job
+			if err := plist.Unmarshal([]byte(tc.data), &job); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Change is a difference between two property lists.
+type Change struct {
+	// Key is the path of the changed value, like "Sockets.http.SockServiceName"
+	// or "ProgramArguments[1]".
+	Key string
+
+	// Old is the old value. It is nil if the key was added.
+	Old any
+
+	// New is the new value. It is nil if the key was removed.
+	New any
+}
+
+// String implements [fmt.Stringer].
+func (c Change) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("+ %s: %v", c.Key, c.New)
+	case c.New == nil:
+		return fmt.Sprintf("- %s: %v", c.Key, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %v => %v", c.Key, c.Old, c.New)
+	}
+}
+
+// Diff returns differences from property list representation of a to
+// that of b, sorted by key. Both values must be encodable with [Marshal].
+//
+// As values are compared after encoding them, semantically equivalent
+// values, for example a [Job] and a map[string]any decoded from its
+// plist file, are considered equal.
+func Diff(a, b any) ([]Change, error) {
+	av, err := normalize(a)
+	if err != nil {
+		return nil, err
+	}
+
+	bv, err := normalize(b)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffValue("", av, bv, nil)
+	slices.SortStableFunc(changes, func(x, y Change) int {
+		return strings.Compare(x.Key, y.Key)
+	})
+	return changes, nil
+}
+
+// normalize returns the generic representation of the value.
+func normalize(v any) (any, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var rv any
+	if err = Unmarshal(data, &rv); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func diffValue(key string, a, b any, changes []Change) []Change {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		for k, v := range av {
+			if w, ok := bv[k]; ok {
+				changes = diffValue(joinKey(key, k), v, w, changes)
+			} else {
+				changes = append(changes, Change{Key: joinKey(key, k), Old: v})
+			}
+		}
+		for k, w := range bv {
+			if _, ok := av[k]; !ok {
+				changes = append(changes, Change{Key: joinKey(key, k), New: w})
+			}
+		}
+		return changes
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(av), len(bv)); i++ {
+			k := fmt.Sprintf("%s[%d]", key, i)
+			switch {
+			case i >= len(av):
+				changes = append(changes, Change{Key: k, New: bv[i]})
+			case i >= len(bv):
+				changes = append(changes, Change{Key: k, Old: av[i]})
+			default:
+				changes = diffValue(k, av[i], bv[i], changes)
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		changes = append(changes, Change{Key: key, Old: a, New: b})
+	}
+	return changes
+}
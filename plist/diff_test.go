@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestDiff(t *testing.T) {
+	old := plist.Job{
+		Label:            "com.example.svc",
+		ProgramArguments: []string{"/usr/local/bin/svc", "--verbose"},
+		RunAtLoad:        true,
+		EnvironmentVariables: map[string]string{
+			"A": "1",
+		},
+	}
+
+	changes, err := plist.Diff(old, old)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got=%v", changes)
+	}
+
+	updated := old
+	updated.ProgramArguments = []string{"/usr/local/bin/svc"}
+	updated.RunAtLoad = false
+	updated.ExitTimeOut = 10
+	updated.EnvironmentVariables = map[string]string{"A": "2"}
+
+	changes, err = plist.Diff(old, updated)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := []plist.Change{
+		{Key: "EnvironmentVariables.A", Old: "1", New: "2"},
+		{Key: "ExitTimeOut", New: int64(10)},
+		{Key: "ProgramArguments[1]", Old: "--verbose"},
+		{Key: "RunAtLoad", Old: true},
+	}
+	if !reflect.DeepEqual(expect, changes) {
+		t.Errorf("expected=%v\ngot=%v", expect, changes)
+	}
+}
@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package plist provides a typed model for launchd job definitions.
+//
+// Types in this package mirror the keys documented in [launchd.plist(5)]
+// and can be rendered to XML property lists with [Marshal]. [Unmarshal]
+// decodes both XML and binary property lists. Unlike the parent package,
+// this package is pure go and works on all platforms, which makes it
+// suitable for tools generating plists for macOS hosts.
+//
+// [launchd.plist(5)]: https://keith.github.io/xcode-man-pages/launchd.plist.5.html
+package plist
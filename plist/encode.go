@@ -0,0 +1,233 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+`
+
+// Marshaler is implemented by types which can marshal themselves
+// into a value which can be encoded as a property list.
+//
+// Returned value is encoded instead of the receiver. This allows
+// types to be rendered in different forms depending on their values,
+// for example, a boolean or a dictionary.
+type Marshaler interface {
+	MarshalPlist() (any, error)
+}
+
+// Marshal returns XML property list encoding of v.
+//
+// Structs are encoded as dictionaries in the order their fields are
+// declared. Struct fields can be customized with "plist" struct tag,
+// which is similar to [encoding/json]. Maps must have string keys and
+// are encoded as dictionaries, sorted by keys.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encoder writes XML property lists to an output stream.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes XML property list encoding of v to the stream.
+func (e *Encoder) Encode(v any) error {
+	if v == nil {
+		return fmt.Errorf("plist: cannot encode nil value")
+	}
+
+	w := bufio.NewWriter(e.w)
+	_, _ = w.WriteString(xmlHeader)
+	if err := encodeValue(w, reflect.ValueOf(v), 0); err != nil {
+		return err
+	}
+	_, _ = w.WriteString("</plist>\n")
+	return w.Flush()
+}
+
+// field is an encodable struct field.
+type field struct {
+	name      string
+	index     int
+	omitEmpty bool
+}
+
+// structFields returns encodable fields of struct type t.
+func structFields(t reflect.Type) []field {
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("plist"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fields = append(fields, field{
+			name:      name,
+			index:     i,
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		})
+	}
+	return fields
+}
+
+// isEmpty reports whether v should be omitted when omitempty is specified.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+
+func writeIndent(w *bufio.Writer, depth int) {
+	for i := 0; i < depth; i++ {
+		_ = w.WriteByte('\t')
+	}
+}
+
+func writeElement(w *bufio.Writer, depth int, tag, text string) {
+	writeIndent(w, depth)
+	_, _ = fmt.Fprintf(w, "<%s>", tag)
+	_ = xml.EscapeText(w, []byte(text))
+	_, _ = fmt.Fprintf(w, "</%s>\n", tag)
+}
+
+//nolint:gocognit,cyclop // type switch on reflect kinds.
+func encodeValue(w *bufio.Writer, v reflect.Value, depth int) error {
+	if !v.IsValid() {
+		return fmt.Errorf("plist: cannot encode invalid value")
+	}
+
+	if v.Type().Implements(marshalerType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return fmt.Errorf("plist: cannot encode nil %s", v.Type())
+		}
+		rv, err := v.Interface().(Marshaler).MarshalPlist()
+		if err != nil {
+			return fmt.Errorf("plist: error marshaling %s: %w", v.Type(), err)
+		}
+		if rv == nil {
+			return fmt.Errorf("plist: %s marshaled to nil", v.Type())
+		}
+		return encodeValue(w, reflect.ValueOf(rv), depth)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return fmt.Errorf("plist: cannot encode nil %s", v.Type())
+		}
+		return encodeValue(w, v.Elem(), depth)
+	case reflect.Bool:
+		writeIndent(w, depth)
+		if v.Bool() {
+			_, _ = w.WriteString("<true/>\n")
+		} else {
+			_, _ = w.WriteString("<false/>\n")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeElement(w, depth, "integer", strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeElement(w, depth, "integer", strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		writeElement(w, depth, "real", strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.String:
+		writeElement(w, depth, "string", v.String())
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			writeElement(w, depth, "date", t.UTC().Format(time.RFC3339))
+			return nil
+		}
+		writeIndent(w, depth)
+		_, _ = w.WriteString("<dict>\n")
+		for _, f := range structFields(v.Type()) {
+			fv := v.Field(f.index)
+			if f.omitEmpty && isEmpty(fv) {
+				continue
+			}
+			writeElement(w, depth+1, "key", f.name)
+			if err := encodeValue(w, fv, depth+1); err != nil {
+				return err
+			}
+		}
+		writeIndent(w, depth)
+		_, _ = w.WriteString("</dict>\n")
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("plist: unsupported map key type %s", v.Type().Key())
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+		writeIndent(w, depth)
+		_, _ = w.WriteString("<dict>\n")
+		for _, k := range keys {
+			writeElement(w, depth+1, "key", k.String())
+			if err := encodeValue(w, v.MapIndex(k), depth+1); err != nil {
+				return err
+			}
+		}
+		writeIndent(w, depth)
+		_, _ = w.WriteString("</dict>\n")
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			writeElement(w, depth, "data", base64.StdEncoding.EncodeToString(data))
+			return nil
+		}
+		writeIndent(w, depth)
+		_, _ = w.WriteString("<array>\n")
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(w, v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+		writeIndent(w, depth)
+		_, _ = w.WriteString("</array>\n")
+	default:
+		return errors.New("plist: unsupported type " + v.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestMarshal(t *testing.T) {
+	job := plist.Job{
+		Label:            "com.example.svc",
+		ProgramArguments: []string{"/usr/local/bin/svc", "--flag=<value>"},
+		RunAtLoad:        true,
+		EnvironmentVariables: map[string]string{
+			"B": "2",
+			"A": "1",
+		},
+	}
+
+	b, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.example.svc</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>/usr/local/bin/svc</string>
+		<string>--flag=&lt;value&gt;</string>
+	</array>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>A</key>
+		<string>1</string>
+		<key>B</key>
+		<string>2</string>
+	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+</dict>
+</plist>
+`
+	if string(b) != expect {
+		t.Errorf("expected=%s\ngot=%s", expect, b)
+	}
+}
+
+func TestMarshal_Invalid(t *testing.T) {
+	tt := []struct {
+		name  string
+		value any
+	}{
+		{name: "nil", value: nil},
+		{name: "nil-pointer", value: (*plist.Job)(nil)},
+		{name: "non-string-map-key", value: map[int]string{1: "a"}},
+		{name: "channel", value: make(chan int)},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := plist.Marshal(tc.value)
+			if err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestMarshal_MachServices(t *testing.T) {
+	job := plist.Job{
+		Label: "com.example.svc",
+		MachServices: map[string]plist.MachService{
+			"com.example.svc.default": {},
+			"com.example.svc.options": {
+				ResetAtClose:     true,
+				HideUntilCheckIn: true,
+			},
+			"com.example.svc.reset": {
+				ResetAtClose: true,
+			},
+		},
+	}
+
+	b, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := `	<key>MachServices</key>
+	<dict>
+		<key>com.example.svc.default</key>
+		<true/>
+		<key>com.example.svc.options</key>
+		<dict>
+			<key>ResetAtClose</key>
+			<true/>
+			<key>HideUntilCheckIn</key>
+			<true/>
+		</dict>
+		<key>com.example.svc.reset</key>
+		<dict>
+			<key>ResetAtClose</key>
+			<true/>
+		</dict>
+	</dict>
+`
+	if !strings.Contains(string(b), expect) {
+		t.Errorf("expected to contain=%s\ngot=%s", expect, b)
+	}
+}
+
+func TestMarshal_InetdCompatibility(t *testing.T) {
+	job := plist.Job{
+		Label:              "com.example.svc",
+		InetdCompatibility: &plist.InetdCompatibility{},
+	}
+
+	b, err := plist.Marshal(job)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	expect := `	<key>inetdCompatibility</key>
+	<dict>
+		<key>Wait</key>
+		<false/>
+	</dict>
+`
+	if !strings.Contains(string(b), expect) {
+		t.Errorf("expected to contain=%s\ngot=%s", expect, b)
+	}
+}
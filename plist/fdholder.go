@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+// fdHolderSocketEnv is the path of the socket of the fd holder.
+// This must be same as environment variable used by the handoff package.
+const fdHolderSocketEnv = "LAUNCHD_FD_HOLDER_SOCKET"
+
+// FDHolder returns a job which retains file descriptors of the job
+// across restarts, emulating the fd store of systemd.
+//
+// Returned job runs the executable of the job with given arguments,
+// which is expected to call handoff.Hold, and is kept alive by launchd.
+// Both jobs are configured with environment variable
+// LAUNCHD_FD_HOLDER_SOCKET set to socket, which is the path of the unix
+// socket used to store and fetch file descriptors.
+//
+//   - [syscall.EINVAL] is returned if arguments are invalid.
+func FDHolder(job *Job, socket string, args ...string) (Job, error) {
+	switch {
+	case job == nil || job.Label == "":
+		return Job{}, fmt.Errorf("plist: fd holder requires a job with label: %w", syscall.EINVAL)
+	case !strings.HasPrefix(socket, "/"):
+		return Job{}, fmt.Errorf("plist: socket(%s) is not an absolute path: %w", socket, syscall.EINVAL)
+	}
+
+	program := job.Program
+	if program == "" && len(job.ProgramArguments) > 0 {
+		program = job.ProgramArguments[0]
+	}
+	if program == "" {
+		return Job{}, fmt.Errorf("plist: fd holder requires a job with program: %w", syscall.EINVAL)
+	}
+
+	if job.EnvironmentVariables == nil {
+		job.EnvironmentVariables = make(map[string]string)
+	}
+	job.EnvironmentVariables[fdHolderSocketEnv] = socket
+
+	return Job{
+		Label:                job.Label + ".fdholder",
+		UserName:             job.UserName,
+		GroupName:            job.GroupName,
+		ProgramArguments:     append([]string{program}, args...),
+		EnvironmentVariables: map[string]string{fdHolderSocketEnv: socket},
+		RunAtLoad:            true,
+		KeepAlive:            KeepAlive{Always: true},
+	}, nil
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestFDHolder(t *testing.T) {
+	job := plist.Job{
+		Label:            "com.example.svc",
+		ProgramArguments: []string{"/usr/local/bin/svc", "serve"},
+	}
+
+	holder, err := plist.FDHolder(&job, "/tmp/com.example.svc.fds", "fd-holder")
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if err = holder.Validate(); err != nil {
+		t.Errorf("expected valid holder job, got=%s", err)
+	}
+
+	if holder.Label != "com.example.svc.fdholder" {
+		t.Errorf("expected label=com.example.svc.fdholder, got=%s", holder.Label)
+	}
+
+	expect := []string{"/usr/local/bin/svc", "fd-holder"}
+	if !slices.Equal(holder.ProgramArguments, expect) {
+		t.Errorf("expected ProgramArguments=%v, got=%v", expect, holder.ProgramArguments)
+	}
+
+	if !holder.KeepAlive.Always || !holder.RunAtLoad {
+		t.Errorf("expected holder to be kept alive and run at load")
+	}
+
+	for _, env := range []map[string]string{job.EnvironmentVariables, holder.EnvironmentVariables} {
+		if v := env["LAUNCHD_FD_HOLDER_SOCKET"]; v != "/tmp/com.example.svc.fds" {
+			t.Errorf("expected LAUNCHD_FD_HOLDER_SOCKET=/tmp/com.example.svc.fds, got=%s", v)
+		}
+	}
+}
+
+func TestFDHolder_Invalid(t *testing.T) {
+	tt := []struct {
+		name   string
+		job    *plist.Job
+		socket string
+	}{
+		{name: "nil-job", socket: "/tmp/fds"},
+		{name: "no-label", job: &plist.Job{Program: "/bin/svc"}, socket: "/tmp/fds"},
+		{name: "no-program", job: &plist.Job{Label: "a"}, socket: "/tmp/fds"},
+		{name: "relative", job: &plist.Job{Label: "a", Program: "/bin/svc"}, socket: "fds"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := plist.FDHolder(tc.job, tc.socket)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
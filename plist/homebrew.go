@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// HomebrewLabelPrefix is the prefix of labels of jobs managed
+// by "brew services".
+const HomebrewLabelPrefix = "homebrew.mxcl."
+
+// HomebrewLabel returns the label used by "brew services" for the formula.
+func HomebrewLabel(formula string) string {
+	return HomebrewLabelPrefix + formula
+}
+
+// HomebrewPrefix returns the Homebrew installation prefix. HOMEBREW_PREFIX
+// environment variable is used if set, otherwise default prefix for the
+// architecture is returned, i.e. /opt/homebrew on arm64 and /usr/local
+// on amd64.
+func HomebrewPrefix() string {
+	if v := os.Getenv("HOMEBREW_PREFIX"); v != "" {
+		return v
+	}
+	if runtime.GOARCH == "arm64" {
+		return "/opt/homebrew"
+	}
+	return "/usr/local"
+}
+
+// Homebrew returns a LaunchAgent job for the formula which is compatible
+// with jobs generated by "brew services". Thus, it can be managed
+// with "brew services" and vice versa.
+//
+// args is the argument vector of the job and args[0] must be
+// an absolute path, typically under prefix/opt/<formula>/bin.
+// If prefix is empty, [HomebrewPrefix] is used.
+//
+// Job is kept alive, runs in prefix/var and its stdout and stderr are
+// redirected to prefix/var/log/<formula>.log.
+func Homebrew(formula, prefix string, args ...string) Job {
+	if prefix == "" {
+		prefix = HomebrewPrefix()
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	log := path.Join(prefix, "var", "log", formula+".log")
+	return Job{
+		Label:            HomebrewLabel(formula),
+		ProgramArguments: args,
+		EnvironmentVariables: map[string]string{
+			"PATH": strings.Join([]string{
+				path.Join(prefix, "bin"),
+				path.Join(prefix, "sbin"),
+				"/usr/bin",
+				"/bin",
+				"/usr/sbin",
+				"/sbin",
+			}, ":"),
+		},
+		WorkingDirectory:  path.Join(prefix, "var"),
+		RunAtLoad:         true,
+		KeepAlive:         KeepAlive{Always: true},
+		StandardOutPath:   log,
+		StandardErrorPath: log,
+	}
+}
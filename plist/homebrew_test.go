@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestHomebrew(t *testing.T) {
+	job := plist.Homebrew("svc", "/opt/homebrew/", "/opt/homebrew/opt/svc/bin/svc", "serve")
+	if err := job.Validate(); err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	tt := []struct {
+		name   string
+		expect string
+		got    string
+	}{
+		{name: "Label", expect: "homebrew.mxcl.svc", got: job.Label},
+		{name: "WorkingDirectory", expect: "/opt/homebrew/var", got: job.WorkingDirectory},
+		{name: "StandardOutPath", expect: "/opt/homebrew/var/log/svc.log", got: job.StandardOutPath},
+		{name: "StandardErrorPath", expect: "/opt/homebrew/var/log/svc.log", got: job.StandardErrorPath},
+		{
+			name:   "PATH",
+			expect: "/opt/homebrew/bin:/opt/homebrew/sbin:/usr/bin:/bin:/usr/sbin:/sbin",
+			got:    job.EnvironmentVariables["PATH"],
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.expect {
+				t.Errorf("expected=%s, got=%s", tc.expect, tc.got)
+			}
+		})
+	}
+
+	if !job.RunAtLoad || !job.KeepAlive.Always {
+		t.Errorf("expected RunAtLoad and KeepAlive to be true")
+	}
+}
+
+func TestHomebrewPrefix(t *testing.T) {
+	t.Setenv("HOMEBREW_PREFIX", "/home/linuxbrew/.linuxbrew")
+	if v := plist.HomebrewPrefix(); v != "/home/linuxbrew/.linuxbrew" {
+		t.Errorf("expected=/home/linuxbrew/.linuxbrew, got=%s", v)
+	}
+
+	job := plist.Homebrew("svc", "", "/home/linuxbrew/.linuxbrew/bin/svc")
+	if job.WorkingDirectory != "/home/linuxbrew/.linuxbrew/var" {
+		t.Errorf("expected=/home/linuxbrew/.linuxbrew/var, got=%s", job.WorkingDirectory)
+	}
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+// Job is a launchd job definition.
+//
+// Only Label is mandatory. Fields with zero values are omitted
+// when rendering the plist, so launchd defaults apply to them.
+type Job struct {
+	// Label uniquely identifies the job to launchd.
+	Label string `plist:"Label"`
+
+	// Disabled is a hint to launchctl to not submit the job.
+	Disabled bool `plist:"Disabled,omitempty"`
+
+	// UserName is the user to run the job as. Only applicable
+	// to jobs loaded into the system domain.
+	UserName string `plist:"UserName,omitempty"`
+
+	// GroupName is the group to run the job as. Only applicable
+	// to jobs loaded into the system domain.
+	GroupName string `plist:"GroupName,omitempty"`
+
+	// InetdCompatibility indicates that the job expects to be run
+	// as if it were launched from inetd.
+	InetdCompatibility *InetdCompatibility `plist:"inetdCompatibility,omitempty"`
+
+	// Program is the absolute path of the executable. If not specified,
+	// first element of ProgramArguments is used.
+	Program string `plist:"Program,omitempty"`
+
+	// ProgramArguments is the argument vector passed to the program.
+	ProgramArguments []string `plist:"ProgramArguments,omitempty"`
+
+	// EnvironmentVariables are set before running the job.
+	EnvironmentVariables map[string]string `plist:"EnvironmentVariables,omitempty"`
+
+	// WorkingDirectory is the directory to chdir(2) to before
+	// running the job.
+	WorkingDirectory string `plist:"WorkingDirectory,omitempty"`
+
+	// RunAtLoad starts the job as soon as it is loaded.
+	RunAtLoad bool `plist:"RunAtLoad,omitempty"`
+
+	// KeepAlive keeps the job running regardless of the demand,
+	// or while any of its conditions hold.
+	KeepAlive KeepAlive `plist:"KeepAlive,omitempty"`
+
+	// StartInterval starts the job every StartInterval seconds.
+	StartInterval int `plist:"StartInterval,omitempty"`
+
+	// StartCalendarInterval starts the job at times matching any of
+	// the intervals, similar to cron. If system is asleep, job is
+	// started when it wakes up.
+	StartCalendarInterval []CalendarInterval `plist:"StartCalendarInterval,omitempty"`
+
+	// EnableTransactions enables tracking of transactions
+	// (os_transaction) for the job.
+	EnableTransactions bool `plist:"EnableTransactions,omitempty"`
+
+	// ExitTimeOut is the number of seconds to wait between sending
+	// SIGTERM and SIGKILL when stopping the job.
+	ExitTimeOut int `plist:"ExitTimeOut,omitempty"`
+
+	// ThrottleInterval is the minimum number of seconds between
+	// two consecutive spawns of the job.
+	ThrottleInterval int `plist:"ThrottleInterval,omitempty"`
+
+	// StandardInPath is the file to use as stdin.
+	StandardInPath string `plist:"StandardInPath,omitempty"`
+
+	// StandardOutPath is the file to use as stdout.
+	StandardOutPath string `plist:"StandardOutPath,omitempty"`
+
+	// StandardErrorPath is the file to use as stderr.
+	StandardErrorPath string `plist:"StandardErrorPath,omitempty"`
+
+	// MachServices are the mach services to be registered
+	// with the bootstrap subsystem, keyed by service name.
+	MachServices map[string]MachService `plist:"MachServices,omitempty"`
+
+	// Sockets are the sockets to be created by launchd on behalf
+	// of the job, keyed by name used for activation.
+	Sockets map[string]Socket `plist:"Sockets,omitempty"`
+}
+
+// InetdCompatibility is the inetdCompatibility dictionary of the job.
+type InetdCompatibility struct {
+	// Wait corresponds to "wait" and "nowait" options of inetd.
+	//
+	// If true, the listening socket is passed via stdin and the job
+	// is responsible for accepting connections. If false, launchd
+	// accepts the connection and passes it via stdin and stdout.
+	Wait bool `plist:"Wait"`
+}
+
+// Socket is an entry in the Sockets dictionary of the job.
+//
+// If Additional is empty, it is rendered as a dictionary. Otherwise, it is
+// rendered as an array of dictionaries, starting with the socket itself
+// followed by the additional entries, which launchd creates sockets for
+// under the same name.
+type Socket struct {
+	// SockType is one of "stream", "dgram" or "seqpacket".
+	// Defaults to "stream".
+	SockType string `plist:"SockType,omitempty"`
+
+	// SockPassive indicates whether socket is a listening socket.
+	// Defaults to true.
+	SockPassive *bool `plist:"SockPassive,omitempty"`
+
+	// SockNodeName is the node (host) to connect or bind to.
+	SockNodeName string `plist:"SockNodeName,omitempty"`
+
+	// SockServiceName is the service name or port number
+	// to connect or bind to.
+	SockServiceName string `plist:"SockServiceName,omitempty"`
+
+	// SockFamily is one of "IPv4", "IPv6" or "IPv4v6".
+	SockFamily string `plist:"SockFamily,omitempty"`
+
+	// SockProtocol is the protocol to be passed to socket(2).
+	// Only "TCP" and "UDP" are supported.
+	SockProtocol string `plist:"SockProtocol,omitempty"`
+
+	// SockPathName is the path of the unix domain socket.
+	SockPathName string `plist:"SockPathName,omitempty"`
+
+	// SockPathOwner is the user id of the unix domain socket path.
+	SockPathOwner *int `plist:"SockPathOwner,omitempty"`
+
+	// SockPathGroup is the group id of the unix domain socket path.
+	SockPathGroup *int `plist:"SockPathGroup,omitempty"`
+
+	// SockPathMode is the mode of the unix domain socket path.
+	// Note that launchd expects this to be a decimal integer,
+	// thus use go octal literals like 0o600.
+	SockPathMode int `plist:"SockPathMode,omitempty"`
+
+	// Additional are the other entries for the same name,
+	// when the value is an array of dictionaries.
+	Additional []Socket `plist:"-"`
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// KeepAlive is the KeepAlive value of the job.
+//
+// If only Always is set, it is rendered as boolean true, which keeps the job
+// running regardless of the demand. Otherwise, it is rendered as a dictionary
+// of conditions, and launchd keeps the job running while any of them holds.
+type KeepAlive struct {
+	// Always keeps the job running regardless of the demand.
+	// It is ignored if any of the conditions are specified.
+	Always bool `plist:"-"`
+
+	// SuccessfulExit restarts the job if it exited with zero status (true)
+	// or with non-zero status (false).
+	SuccessfulExit *bool `plist:"SuccessfulExit,omitempty"`
+
+	// Crashed restarts the job if it exited due to a signal (true)
+	// or without one (false).
+	Crashed *bool `plist:"Crashed,omitempty"`
+
+	// NetworkState keeps the job running while network is up (true)
+	// or down (false).
+	NetworkState *bool `plist:"NetworkState,omitempty"`
+
+	// PathState keeps the job running while the path exists (true)
+	// or does not exist (false), keyed by path.
+	PathState map[string]bool `plist:"PathState,omitempty"`
+
+	// OtherJobEnabled keeps the job running while the other job is
+	// loaded (true) or is not loaded (false), keyed by label.
+	OtherJobEnabled map[string]bool `plist:"OtherJobEnabled,omitempty"`
+
+	// AfterInitialDemand does not start the job at load, until it is
+	// started manually or by demand, before applying other conditions.
+	AfterInitialDemand bool `plist:"AfterInitialDemand,omitempty"`
+}
+
+// conditional reports whether any of the conditions are specified.
+func (k *KeepAlive) conditional() bool {
+	return k.SuccessfulExit != nil || k.Crashed != nil || k.NetworkState != nil ||
+		len(k.PathState) > 0 || len(k.OtherJobEnabled) > 0 || k.AfterInitialDemand
+}
+
+// MarshalPlist implements [Marshaler].
+func (k KeepAlive) MarshalPlist() (any, error) {
+	if !k.conditional() {
+		return k.Always, nil
+	}
+	type conditions KeepAlive
+	return conditions(k), nil
+}
+
+// UnmarshalPlist implements [Unmarshaler].
+func (k *KeepAlive) UnmarshalPlist(v any) error {
+	type conditions KeepAlive
+	switch t := v.(type) {
+	case bool:
+		*k = KeepAlive{Always: t}
+		return nil
+	case map[string]any:
+		*k = KeepAlive{}
+		return assign(t, reflect.ValueOf((*conditions)(k)).Elem())
+	default:
+		return fmt.Errorf("invalid KeepAlive value type %T", v)
+	}
+}
+
+// DependsOn declares that the job depends on the jobs with given labels,
+// by adding them to KeepAlive OtherJobEnabled, so that launchd starts the
+// job and keeps it running once any of them is loaded. Note that this does
+// not guarantee that dependencies are ready to accept connections, which
+// dependents should wait for at runtime.
+func (j *Job) DependsOn(labels ...string) {
+	if len(labels) == 0 {
+		return
+	}
+	if j.KeepAlive.OtherJobEnabled == nil {
+		j.KeepAlive.OtherJobEnabled = make(map[string]bool, len(labels))
+	}
+	for _, label := range labels {
+		j.KeepAlive.OtherJobEnabled[label] = true
+	}
+}
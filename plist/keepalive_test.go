@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestMarshal_KeepAlive(t *testing.T) {
+	crashed := true
+	tt := []struct {
+		name      string
+		keepAlive plist.KeepAlive
+		expect    string
+	}{
+		{
+			name:      "zero",
+			keepAlive: plist.KeepAlive{},
+		},
+		{
+			name:      "always",
+			keepAlive: plist.KeepAlive{Always: true},
+			expect: `	<key>KeepAlive</key>
+	<true/>
+`,
+		},
+		{
+			name: "conditions",
+			keepAlive: plist.KeepAlive{
+				Always:          true,
+				Crashed:         &crashed,
+				OtherJobEnabled: map[string]bool{"com.example.db": true},
+			},
+			expect: `	<key>KeepAlive</key>
+	<dict>
+		<key>Crashed</key>
+		<true/>
+		<key>OtherJobEnabled</key>
+		<dict>
+			<key>com.example.db</key>
+			<true/>
+		</dict>
+	</dict>
+`,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := plist.Marshal(plist.Job{Label: "com.example.svc", KeepAlive: tc.keepAlive})
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			if tc.expect == "" {
+				if strings.Contains(string(b), "KeepAlive") {
+					t.Errorf("expected KeepAlive to be omitted, got=%s", b)
+				}
+				return
+			}
+			if !strings.Contains(string(b), tc.expect) {
+				t.Errorf("expected to contain=%s\ngot=%s", tc.expect, b)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_KeepAlive(t *testing.T) {
+	network := false
+	tt := []struct {
+		name string
+		job  plist.Job
+	}{
+		{
+			name: "always",
+			job:  plist.Job{Label: "com.example.svc", KeepAlive: plist.KeepAlive{Always: true}},
+		},
+		{
+			name: "conditions",
+			job: plist.Job{
+				Label: "com.example.svc",
+				KeepAlive: plist.KeepAlive{
+					NetworkState:       &network,
+					PathState:          map[string]bool{"/var/run/svc.pid": false},
+					OtherJobEnabled:    map[string]bool{"com.example.db": true},
+					AfterInitialDemand: true,
+				},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := plist.Marshal(tc.job)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+
+			var got plist.Job
+			if err = plist.Unmarshal(b, &got); err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+
+			if !reflect.DeepEqual(tc.job, got) {
+				t.Errorf("expected=%#v\ngot=%#v", tc.job, got)
+			}
+		})
+	}
+}
+
+func TestJob_DependsOn(t *testing.T) {
+	job := plist.Job{Label: "com.example.svc"}
+	job.DependsOn()
+	if job.KeepAlive.OtherJobEnabled != nil {
+		t.Errorf("expected no dependencies, got=%v", job.KeepAlive.OtherJobEnabled)
+	}
+
+	job.DependsOn("com.example.db", "com.example.cache")
+	job.DependsOn("com.example.db")
+	expect := map[string]bool{"com.example.db": true, "com.example.cache": true}
+	if !reflect.DeepEqual(job.KeepAlive.OtherJobEnabled, expect) {
+		t.Errorf("expected=%v, got=%v", expect, job.KeepAlive.OtherJobEnabled)
+	}
+}
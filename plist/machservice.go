@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MachService is an entry in the MachServices dictionary of the job.
+//
+// Zero value is rendered as boolean true, which registers the service
+// with default options. If Disabled is set, it is rendered as boolean
+// false. Otherwise, it is rendered as a dictionary with only non-default
+// options.
+type MachService struct {
+	// Disabled is rendered as boolean false, which does not register
+	// the service. Other options are ignored if this is set.
+	Disabled bool `plist:"-"`
+
+	// ResetAtClose resets the demand when port is closed.
+	// This is useful for services which must be restarted
+	// if a client has crashed while holding a send right.
+	ResetAtClose bool `plist:"ResetAtClose,omitempty"`
+
+	// HideUntilCheckIn hides the service from lookups until
+	// the job has checked in with launchd.
+	HideUntilCheckIn bool `plist:"HideUntilCheckIn,omitempty"`
+}
+
+// MarshalPlist implements [Marshaler].
+func (m MachService) MarshalPlist() (any, error) {
+	if m.Disabled {
+		return false, nil
+	}
+	if m == (MachService{}) {
+		return true, nil
+	}
+	type options MachService
+	return options(m), nil
+}
+
+// UnmarshalPlist implements [Unmarshaler].
+func (m *MachService) UnmarshalPlist(v any) error {
+	type options MachService
+	switch t := v.(type) {
+	case bool:
+		*m = MachService{Disabled: !t}
+		return nil
+	case map[string]any:
+		*m = MachService{}
+		return assign(t, reflect.ValueOf((*options)(m)).Elem())
+	default:
+		return fmt.Errorf("invalid MachService value type %T", v)
+	}
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// sessionEnv are environment variables which are specific to the login
+// session or the shell of the calling process, and are thus excluded by
+// [FromProcess]. launchd sets appropriate values for some of them, like
+// HOME, USER and TMPDIR.
+var sessionEnv = []string{
+	"_", "COLORTERM", "HOME", "LOGNAME", "LaunchInstanceID", "OLDPWD", "PWD",
+	"SECURITYSESSIONID", "SHELL", "SHLVL", "TERM", "TMPDIR", "USER",
+}
+
+// sessionEnvPrefixes are prefixes of environment variables excluded
+// by [FromProcess], see sessionEnv.
+var sessionEnvPrefixes = []string{"__CF", "LISTEN_", "SSH_", "TERM_", "XPC_"}
+
+// FromProcess returns a best-effort job definition for the current process,
+// to bootstrap a plist for a service which has been run manually.
+//
+// Program is the executable of the current process and arguments are its
+// command line arguments. Environment variables, except those specific to
+// the login session or shell (like HOME, USER, SHELL, TERM and SSH_*),
+// are included, as is the working directory. sockets maps socket names to
+// addresses of listeners of the process, typically obtained from Addr
+// of [net.Listener] or LocalAddr of [net.PacketConn]. Listeners bound to
+// unspecified addresses listen on all addresses of both IPv4 and IPv6.
+//
+// Returned job should be reviewed before installing it, as it can
+// include secrets from the environment.
+//
+//   - [syscall.EINVAL] is returned if label is empty or if an address
+//     cannot be represented as a socket.
+func FromProcess(label string, sockets map[string]net.Addr) (Job, error) {
+	if label == "" {
+		return Job{}, fmt.Errorf("plist: label is empty: %w", syscall.EINVAL)
+	}
+
+	program, err := os.Executable()
+	if err != nil {
+		return Job{}, fmt.Errorf("plist: failed to get executable: %w", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return Job{}, fmt.Errorf("plist: failed to get working directory: %w", err)
+	}
+
+	job := Job{
+		Label:                label,
+		ProgramArguments:     append([]string{program}, os.Args[1:]...),
+		EnvironmentVariables: processEnv(os.Environ()),
+		WorkingDirectory:     wd,
+	}
+
+	for name, addr := range sockets {
+		s, err := socketFromAddr(addr)
+		if err != nil {
+			return Job{}, fmt.Errorf("plist: socket(%s): %w", name, err)
+		}
+		if job.Sockets == nil {
+			job.Sockets = make(map[string]Socket, len(sockets))
+		}
+		job.Sockets[name] = s
+	}
+	return job, nil
+}
+
+// processEnv returns env as a map, excluding session specific variables.
+// If there are no variables, nil is returned.
+func processEnv(env []string) map[string]string {
+	var out map[string]string
+loop:
+	for _, kv := range env {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" || slices.Contains(sessionEnv, k) {
+			continue
+		}
+		for _, prefix := range sessionEnvPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				continue loop
+			}
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// socketFromAddr returns the socket listening on addr.
+func socketFromAddr(addr net.Addr) (Socket, error) {
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		s := inetSocket(addr.IP, addr.Port, addr.Zone)
+		s.SockType = "stream"
+		return s, nil
+	case *net.UDPAddr:
+		s := inetSocket(addr.IP, addr.Port, addr.Zone)
+		s.SockType = "dgram"
+		return s, nil
+	case *net.UnixAddr:
+		if !strings.HasPrefix(addr.Name, "/") {
+			return Socket{}, fmt.Errorf("unix socket path(%s) is not absolute: %w", addr.Name, syscall.EINVAL)
+		}
+		s := Socket{SockType: "stream", SockPathName: addr.Name}
+		switch addr.Net {
+		case "unixgram":
+			s.SockType = "dgram"
+		case "unixpacket":
+			s.SockType = "seqpacket"
+		}
+		if fi, err := os.Stat(addr.Name); err == nil {
+			s.SockPathMode = int(fi.Mode().Perm())
+		}
+		return s, nil
+	case nil:
+		return Socket{}, fmt.Errorf("address is nil: %w", syscall.EINVAL)
+	default:
+		return Socket{}, fmt.Errorf("unsupported address type %T: %w", addr, syscall.EINVAL)
+	}
+}
+
+// inetSocket returns socket for the ip and port. Unspecified addresses
+// are omitted, so that launchd listens on all addresses.
+func inetSocket(ip net.IP, port int, zone string) Socket {
+	s := Socket{SockServiceName: strconv.Itoa(port)}
+	switch {
+	case ip == nil || ip.IsUnspecified():
+	case ip.To4() != nil:
+		s.SockNodeName = ip.String()
+		s.SockFamily = "IPv4"
+	default:
+		s.SockNodeName = ip.String()
+		if zone != "" {
+			s.SockNodeName += "%" + zone
+		}
+		s.SockFamily = "IPv6"
+	}
+	return s
+}
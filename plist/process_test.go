@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"errors"
+	"net"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestProcessEnv(t *testing.T) {
+	env := []string{
+		"PATH=/usr/bin:/bin",
+		"HOME=/Users/test",
+		"TERM=xterm-256color",
+		"TERM_PROGRAM=Apple_Terminal",
+		"SSH_AUTH_SOCK=/tmp/ssh.sock",
+		"XPC_SERVICE_NAME=0",
+		"LISTEN_FDS=1",
+		"_=/usr/bin/env",
+		"LOG_LEVEL=debug",
+		"HOMEBREW_PREFIX=/opt/homebrew",
+		"EMPTY=",
+		"invalid",
+	}
+	expect := map[string]string{
+		"PATH":            "/usr/bin:/bin",
+		"LOG_LEVEL":       "debug",
+		"HOMEBREW_PREFIX": "/opt/homebrew",
+		"EMPTY":           "",
+	}
+	if got := processEnv(env); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected env=%v, got=%v", expect, got)
+	}
+	if got := processEnv([]string{"HOME=/"}); got != nil {
+		t.Errorf("expected nil env, got=%v", got)
+	}
+}
+
+func TestSocketFromAddr(t *testing.T) {
+	tt := []struct {
+		name   string
+		addr   net.Addr
+		expect Socket
+		err    error
+	}{
+		{
+			name:   "tcp-unspecified",
+			addr:   &net.TCPAddr{Port: 8080},
+			expect: Socket{SockType: "stream", SockServiceName: "8080"},
+		},
+		{
+			name:   "tcp4",
+			addr:   &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080},
+			expect: Socket{SockType: "stream", SockNodeName: "127.0.0.1", SockServiceName: "8080", SockFamily: "IPv4"},
+		},
+		{
+			name:   "udp6",
+			addr:   &net.UDPAddr{IP: net.IPv6loopback, Port: 53},
+			expect: Socket{SockType: "dgram", SockNodeName: "::1", SockServiceName: "53", SockFamily: "IPv6"},
+		},
+		{
+			name:   "udp6-zone",
+			addr:   &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 53, Zone: "en0"},
+			expect: Socket{SockType: "dgram", SockNodeName: "fe80::1%en0", SockServiceName: "53", SockFamily: "IPv6"},
+		},
+		{
+			name:   "unixgram",
+			addr:   &net.UnixAddr{Name: "/nonexistent/log.sock", Net: "unixgram"},
+			expect: Socket{SockType: "dgram", SockPathName: "/nonexistent/log.sock"},
+		},
+		{name: "unix-relative", addr: &net.UnixAddr{Name: "svc.sock", Net: "unix"}, err: syscall.EINVAL},
+		{name: "nil", err: syscall.EINVAL},
+		{name: "ip", addr: &net.IPAddr{IP: net.IPv4(127, 0, 0, 1)}, err: syscall.EINVAL},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := socketFromAddr(tc.addr)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+			if !reflect.DeepEqual(s, tc.expect) {
+				t.Errorf("expected socket=%+v, got=%+v", tc.expect, s)
+			}
+		})
+	}
+}
+
+func TestFromProcess(t *testing.T) {
+	if _, err := FromProcess("", nil); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+
+	job, err := FromProcess("com.example.svc", map[string]net.Addr{
+		"Listeners": &net.TCPAddr{Port: 8080},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	exe, _ := os.Executable()
+	if len(job.ProgramArguments) == 0 || job.ProgramArguments[0] != exe {
+		t.Errorf("expected program=%s, got=%q", exe, job.ProgramArguments)
+	}
+	if wd, _ := os.Getwd(); job.WorkingDirectory != wd {
+		t.Errorf("expected working directory=%s, got=%s", wd, job.WorkingDirectory)
+	}
+	if _, ok := job.Sockets["Listeners"]; !ok {
+		t.Errorf("expected socket Listeners, got=%+v", job.Sockets)
+	}
+	if _, ok := job.EnvironmentVariables["HOME"]; ok {
+		t.Errorf("expected HOME to be excluded")
+	}
+	if err = job.Validate(); err != nil {
+		t.Errorf("expected valid job, got=%s", err)
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Entries returns the socket and its additional entries, in the order
+// they appear in the array of dictionaries. Returned entries do not
+// have additional entries.
+func (s Socket) Entries() []Socket {
+	first := s
+	first.Additional = nil
+	entries := []Socket{first}
+	for _, entry := range s.Additional {
+		entries = append(entries, entry.Entries()...)
+	}
+	return entries
+}
+
+// MarshalPlist implements [Marshaler].
+func (s Socket) MarshalPlist() (any, error) {
+	type entry Socket
+	if len(s.Additional) == 0 {
+		return entry(s), nil
+	}
+
+	entries := s.Entries()
+	rv := make([]any, 0, len(entries))
+	for _, e := range entries {
+		rv = append(rv, entry(e))
+	}
+	return rv, nil
+}
+
+// UnmarshalPlist implements [Unmarshaler].
+func (s *Socket) UnmarshalPlist(v any) error {
+	type entry Socket
+	switch t := v.(type) {
+	case map[string]any:
+		*s = Socket{}
+		return assign(t, reflect.ValueOf((*entry)(s)).Elem())
+	case []any:
+		if len(t) == 0 {
+			return fmt.Errorf("empty Socket array")
+		}
+		*s = Socket{}
+		for i, item := range t {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return fmt.Errorf("invalid Socket array value type %T", item)
+			}
+			var e Socket
+			if err := assign(m, reflect.ValueOf((*entry)(&e)).Elem()); err != nil {
+				return err
+			}
+			if i == 0 {
+				*s = e
+			} else {
+				s.Additional = append(s.Additional, e)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid Socket value type %T", v)
+	}
+}
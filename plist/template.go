@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs returns functions available to templates rendered by
+// [RenderTemplate]. These render values as property list elements and
+// return an error for invalid values, so that rendered plists are
+// well-formed regardless of data.
+//
+//   - string renders <string> element with value escaped.
+//   - integer renders <integer> element.
+//   - bool renders <true/> or <false/> element.
+//   - port renders <string> element with port number, as expected by
+//     SockServiceName. Value must be an integer or string between 1 and 65535.
+//   - path renders <string> element with the cleaned absolute path.
+//     Value must be an absolute path.
+//   - array renders <array> of <string> elements.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"string":  templateString,
+		"integer": templateInteger,
+		"bool":    templateBool,
+		"port":    templatePort,
+		"path":    templatePath,
+		"array":   templateArray,
+	}
+}
+
+// RenderTemplate renders the property list template with data and returns
+// the rendered plist, for keeping plists as templates in configuration
+// repositories. Template uses [text/template] syntax with functions from
+// [TemplateFuncs]. Referencing missing keys of maps is an error.
+//
+// Rendered plist is decoded as a [Job] and validated with [Job.Validate].
+// Rendered plist is returned along with the validation error,
+// if it can be decoded.
+func RenderTemplate(tmpl string, data any) ([]byte, error) {
+	t, err := template.New("plist").
+		Option("missingkey=error").
+		Funcs(TemplateFuncs()).
+		Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("plist: invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("plist: failed to render template: %w", err)
+	}
+
+	var job Job
+	if err = Unmarshal(buf.Bytes(), &job); err != nil {
+		return nil, fmt.Errorf("plist: rendered template is not a valid plist: %w", err)
+	}
+	return buf.Bytes(), job.Validate()
+}
+
+// escape escapes s for use in xml character data.
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func templateString(v any) string {
+	return "<string>" + escape(fmt.Sprint(v)) + "</string>"
+}
+
+func templateInteger(v any) (string, error) {
+	n, err := toInt(v)
+	if err != nil {
+		return "", err
+	}
+	return "<integer>" + strconv.Itoa(n) + "</integer>", nil
+}
+
+func templateBool(v any) (string, error) {
+	var b bool
+	switch v := v.(type) {
+	case bool:
+		b = v
+	case string:
+		var err error
+		if b, err = strconv.ParseBool(v); err != nil {
+			return "", fmt.Errorf("invalid bool(%s)", v)
+		}
+	default:
+		return "", fmt.Errorf("invalid bool(%v) of type %T", v, v)
+	}
+	if b {
+		return "<true/>", nil
+	}
+	return "<false/>", nil
+}
+
+func templatePort(v any) (string, error) {
+	n, err := toInt(v)
+	if err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid port(%v)", v)
+	}
+	return "<string>" + strconv.Itoa(n) + "</string>", nil
+}
+
+func templatePath(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, "/") {
+		return "", fmt.Errorf("path(%v) is not an absolute path", v)
+	}
+	return "<string>" + escape(path.Clean(s)) + "</string>", nil
+}
+
+func templateArray(v any) (string, error) {
+	items, ok := v.([]string)
+	if !ok {
+		return "", fmt.Errorf("invalid array(%v) of type %T, expected []string", v, v)
+	}
+	var b strings.Builder
+	b.WriteString("<array>")
+	for _, item := range items {
+		b.WriteString(templateString(item))
+	}
+	b.WriteString("</array>")
+	return b.String(), nil
+}
+
+// toInt converts integers and numeric strings to int.
+func toInt(v any) (int, error) {
+	switch v := v.(type) {
+	case int:
+		return v, nil
+	case int8:
+		return int(v), nil
+	case int16:
+		return int(v), nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case uint8:
+		return int(v), nil
+	case uint16:
+		return int(v), nil
+	case uint32:
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer(%s)", v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("invalid integer(%v) of type %T", v, v)
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	{{ string .Label }}
+	<key>ProgramArguments</key>
+	{{ array .Args }}
+	<key>RunAtLoad</key>
+	{{ bool .RunAtLoad }}
+	<key>ExitTimeOut</key>
+	{{ integer .ExitTimeout }}
+	<key>Sockets</key>
+	<dict>
+		<key>Listeners</key>
+		<dict>
+			<key>SockServiceName</key>
+			{{ port .Port }}
+		</dict>
+		<key>Control</key>
+		<dict>
+			<key>SockPathName</key>
+			{{ path .Socket }}
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+
+type templateData struct {
+	Label       string
+	Args        []string
+	RunAtLoad   bool
+	ExitTimeout int
+	Port        any
+	Socket      any
+}
+
+func TestRenderTemplate(t *testing.T) {
+	data := templateData{
+		Label:       "com.example.svc",
+		Args:        []string{"/usr/local/bin/svc", "--name=<a&b>"},
+		RunAtLoad:   true,
+		ExitTimeout: 30,
+		Port:        8080,
+		Socket:      "/var/run//svc.sock",
+	}
+
+	out, err := RenderTemplate(testTemplate, data)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	var job Job
+	if err = Unmarshal(out, &job); err != nil {
+		t.Fatalf("failed to decode rendered plist: %s", err)
+	}
+	expect := Job{
+		Label:            "com.example.svc",
+		ProgramArguments: []string{"/usr/local/bin/svc", "--name=<a&b>"},
+		RunAtLoad:        true,
+		ExitTimeOut:      30,
+		Sockets: map[string]Socket{
+			"Listeners": {SockServiceName: "8080"},
+			"Control":   {SockPathName: "/var/run/svc.sock"},
+		},
+	}
+	if !reflect.DeepEqual(job, expect) {
+		t.Errorf("expected job=%+v, got=%+v", expect, job)
+	}
+}
+
+func TestRenderTemplate_Errors(t *testing.T) {
+	valid := templateData{
+		Label:  "com.example.svc",
+		Args:   []string{"/usr/local/bin/svc"},
+		Port:   "8080",
+		Socket: "/var/run/svc.sock",
+	}
+
+	tt := []struct {
+		name   string
+		tmpl   string
+		data   any
+		output bool
+	}{
+		{name: "invalid-template", tmpl: "{{ .Label ", data: valid},
+		{name: "unknown-func", tmpl: "{{ unknown .Label }}", data: valid},
+		{name: "missing-key", tmpl: testTemplate, data: map[string]any{"Label": "a"}},
+		{name: "invalid-port", tmpl: testTemplate, data: templateData{
+			Label: "a", Args: []string{"/a"}, Port: 70000, Socket: "/a.sock",
+		}},
+		{name: "relative-path", tmpl: testTemplate, data: templateData{
+			Label: "a", Args: []string{"/a"}, Port: 80, Socket: "a.sock",
+		}},
+		{name: "not-plist", tmpl: "{{ .Label }}", data: valid},
+		{name: "invalid-job", tmpl: testTemplate, output: true, data: templateData{
+			Label: "invalid label", Args: []string{"/a"}, Port: 80, Socket: "/a.sock",
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := RenderTemplate(tc.tmpl, tc.data)
+			if err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if tc.output != (len(out) > 0) {
+				t.Errorf("expected output=%t, got=%q", tc.output, out)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	funcs := TemplateFuncs()
+	tt := []struct {
+		fn     string
+		input  any
+		expect string
+		err    bool
+	}{
+		{fn: "string", input: "a<b", expect: "<string>a&lt;b</string>"},
+		{fn: "integer", input: "42", expect: "<integer>42</integer>"},
+		{fn: "integer", input: 1.5, err: true},
+		{fn: "bool", input: "true", expect: "<true/>"},
+		{fn: "bool", input: false, expect: "<false/>"},
+		{fn: "bool", input: "yes", err: true},
+		{fn: "port", input: uint16(443), expect: "<string>443</string>"},
+		{fn: "port", input: 0, err: true},
+		{fn: "path", input: "/a/../b", expect: "<string>/b</string>"},
+		{fn: "path", input: 1, err: true},
+		{fn: "array", input: []string{"a"}, expect: "<array><string>a</string></array>"},
+		{fn: "array", input: []int{1}, err: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.fn, func(t *testing.T) {
+			var out string
+			var err error
+			switch fn := funcs[tc.fn].(type) {
+			case func(any) string:
+				out = fn(tc.input)
+			case func(any) (string, error):
+				out, err = fn(tc.input)
+			default:
+				t.Fatalf("unexpected func type %T", fn)
+			}
+			if (err != nil) != tc.err {
+				t.Errorf("expected error=%t, got=%v", tc.err, err)
+			}
+			if out != tc.expect {
+				t.Errorf("expected output=%s, got=%s", tc.expect, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Validate performs basic validation of the job definition.
+//
+// This only checks for common mistakes and a valid job does not
+// guarantee that launchd will accept it.
+func (j *Job) Validate() error {
+	var err error
+	if strings.TrimSpace(j.Label) == "" {
+		err = errors.Join(err, fmt.Errorf("plist: label is empty"))
+	} else if strings.ContainsAny(j.Label, "/ \t\n") {
+		err = errors.Join(err, fmt.Errorf("plist: label(%s) is invalid", j.Label))
+	}
+
+	switch {
+	case j.Program == "" && len(j.ProgramArguments) == 0:
+		err = errors.Join(err, fmt.Errorf("plist: either Program or ProgramArguments must be specified"))
+	case j.Program != "" && !filepath.IsAbs(j.Program):
+		err = errors.Join(err, fmt.Errorf("plist: program(%s) is not an absolute path", j.Program))
+	}
+
+	for label := range j.KeepAlive.OtherJobEnabled {
+		switch {
+		case strings.TrimSpace(label) == "" || strings.ContainsAny(label, "/ \t\n"):
+			err = errors.Join(err, fmt.Errorf("plist: dependency label(%s) is invalid", label))
+		case label == j.Label:
+			err = errors.Join(err, fmt.Errorf("plist: job(%s) cannot depend on itself", label))
+		}
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestJobValidate(t *testing.T) {
+	tt := []struct {
+		name string
+		job  plist.Job
+		ok   bool
+	}{
+		{
+			name: "program",
+			job:  plist.Job{Label: "com.example.svc", Program: "/usr/local/bin/svc"},
+			ok:   true,
+		},
+		{
+			name: "program-arguments",
+			job:  plist.Job{Label: "com.example.svc", ProgramArguments: []string{"svc"}},
+			ok:   true,
+		},
+		{
+			name: "empty-label",
+			job:  plist.Job{Program: "/usr/local/bin/svc"},
+		},
+		{
+			name: "invalid-label",
+			job:  plist.Job{Label: "com/example", Program: "/usr/local/bin/svc"},
+		},
+		{
+			name: "no-program",
+			job:  plist.Job{Label: "com.example.svc"},
+		},
+		{
+			name: "relative-program",
+			job:  plist.Job{Label: "com.example.svc", Program: "bin/svc"},
+		},
+		{
+			name: "dependency",
+			job: plist.Job{
+				Label:     "com.example.svc",
+				Program:   "/usr/local/bin/svc",
+				KeepAlive: plist.KeepAlive{OtherJobEnabled: map[string]bool{"com.example.db": true}},
+			},
+			ok: true,
+		},
+		{
+			name: "invalid-dependency",
+			job: plist.Job{
+				Label:     "com.example.svc",
+				Program:   "/usr/local/bin/svc",
+				KeepAlive: plist.KeepAlive{OtherJobEnabled: map[string]bool{"com example": true}},
+			},
+		},
+		{
+			name: "self-dependency",
+			job: plist.Job{
+				Label:     "com.example.svc",
+				Program:   "/usr/local/bin/svc",
+				KeepAlive: plist.KeepAlive{OtherJobEnabled: map[string]bool{"com.example.svc": true}},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.job.Validate()
+			if tc.ok && err != nil {
+				t.Errorf("expected no error, got=%s", err)
+			}
+			if !tc.ok && err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
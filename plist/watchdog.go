@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Environment variables used to configure watchdog of the job.
+// These must be same as environment variables used by the notify package.
+const (
+	watchdogFileEnv = "LAUNCHD_WATCHDOG_FILE"
+	watchdogSecEnv  = "LAUNCHD_WATCHDOG_SEC"
+)
+
+// shellQuote returns s quoted for use in POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Watchdog emulates systemd's WatchdogSec for the job.
+//
+// launchd has no watchdog of its own. Instead, the job periodically updates
+// mtime of the heartbeat file, and the returned monitor job, which runs
+// every timeout/2, restarts the job with "launchctl kickstart -k" when
+// heartbeat is older than timeout. Heartbeat file is removed when the job
+// is restarted, so that jobs which are not running are not restarted
+// repeatedly.
+//
+// Job is updated with environment variables LAUNCHD_WATCHDOG_FILE and
+// LAUNCHD_WATCHDOG_SEC, which are used by the notify package to update
+// the heartbeat. domain is the domain target where both jobs are
+// loaded, like "system" or "gui/501".
+//
+//   - [syscall.EINVAL] is returned if arguments are invalid.
+func Watchdog(job *Job, domain, heartbeat string, timeout time.Duration) (Job, error) {
+	switch {
+	case job == nil || job.Label == "":
+		return Job{}, fmt.Errorf("plist: watchdog requires a job with label: %w", syscall.EINVAL)
+	case domain == "":
+		return Job{}, fmt.Errorf("plist: watchdog requires a domain: %w", syscall.EINVAL)
+	case !strings.HasPrefix(heartbeat, "/"):
+		return Job{}, fmt.Errorf("plist: heartbeat(%s) is not an absolute path: %w", heartbeat, syscall.EINVAL)
+	case timeout < 2*time.Second:
+		return Job{}, fmt.Errorf("plist: watchdog timeout(%s) must be at least 2s: %w", timeout, syscall.EINVAL)
+	}
+
+	seconds := int(timeout / time.Second)
+	if job.EnvironmentVariables == nil {
+		job.EnvironmentVariables = make(map[string]string)
+	}
+	job.EnvironmentVariables[watchdogFileEnv] = heartbeat
+	job.EnvironmentVariables[watchdogSecEnv] = strconv.Itoa(seconds)
+
+	target := strings.TrimSuffix(domain, "/") + "/" + job.Label
+	script := strings.Join([]string{
+		"f=" + shellQuote(heartbeat),
+		`[ -e "$f" ] || exit 0`,
+		`m=$(/usr/bin/stat -f %m "$f") || exit 0`,
+		fmt.Sprintf(`[ $(( $(/bin/date +%%s) - m )) -gt %d ] || exit 0`, seconds),
+		`/bin/rm -f "$f"`,
+		"exec /bin/launchctl kickstart -k " + shellQuote(target),
+	}, "\n")
+
+	return Job{
+		Label:            job.Label + ".watchdog",
+		UserName:         job.UserName,
+		GroupName:        job.GroupName,
+		ProgramArguments: []string{"/bin/sh", "-c", script},
+		StartInterval:    max(seconds/2, 1),
+	}, nil
+}
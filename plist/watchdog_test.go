@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package plist_test
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestWatchdog(t *testing.T) {
+	job := plist.Job{
+		Label:   "com.example.svc",
+		Program: "/usr/local/bin/svc",
+	}
+
+	monitor, err := plist.Watchdog(&job, "gui/501", "/tmp/com.example.svc.heartbeat", 30*time.Second)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	if err = monitor.Validate(); err != nil {
+		t.Errorf("expected valid monitor job, got=%s", err)
+	}
+
+	if v := job.EnvironmentVariables["LAUNCHD_WATCHDOG_FILE"]; v != "/tmp/com.example.svc.heartbeat" {
+		t.Errorf("expected LAUNCHD_WATCHDOG_FILE=/tmp/com.example.svc.heartbeat, got=%s", v)
+	}
+
+	if v := job.EnvironmentVariables["LAUNCHD_WATCHDOG_SEC"]; v != "30" {
+		t.Errorf("expected LAUNCHD_WATCHDOG_SEC=30, got=%s", v)
+	}
+
+	if monitor.Label != "com.example.svc.watchdog" {
+		t.Errorf("expected label=com.example.svc.watchdog, got=%s", monitor.Label)
+	}
+
+	if monitor.StartInterval != 15 {
+		t.Errorf("expected StartInterval=15, got=%d", monitor.StartInterval)
+	}
+
+	script := monitor.ProgramArguments[len(monitor.ProgramArguments)-1]
+	for _, expect := range []string{
+		"f='/tmp/com.example.svc.heartbeat'",
+		"-gt 30 ]",
+		"kickstart -k 'gui/501/com.example.svc'",
+	} {
+		if !strings.Contains(script, expect) {
+			t.Errorf("expected script to contain=%s, got=%s", expect, script)
+		}
+	}
+}
+
+func TestWatchdog_Invalid(t *testing.T) {
+	tt := []struct {
+		name      string
+		job       *plist.Job
+		domain    string
+		heartbeat string
+		timeout   time.Duration
+	}{
+		{name: "nil-job", domain: "system", heartbeat: "/tmp/hb", timeout: time.Minute},
+		{name: "no-label", job: &plist.Job{}, domain: "system", heartbeat: "/tmp/hb", timeout: time.Minute},
+		{name: "no-domain", job: &plist.Job{Label: "a"}, heartbeat: "/tmp/hb", timeout: time.Minute},
+		{name: "relative", job: &plist.Job{Label: "a"}, domain: "system", heartbeat: "hb", timeout: time.Minute},
+		{name: "timeout", job: &plist.Job{Label: "a"}, domain: "system", heartbeat: "/tmp/hb", timeout: time.Second},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := plist.Watchdog(tc.job, tc.domain, tc.heartbeat, tc.timeout)
+			if !errors.Is(err, syscall.EINVAL) {
+				t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+			}
+		})
+	}
+}
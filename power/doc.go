@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package power provides power management assertions without using cgo.
+//
+// Daemons performing long running work, like large transfers, can prevent
+// the system from sleeping due to user inactivity while the work is in
+// progress. Socket activated jobs should hold assertions only while
+// serving requests, so that the system can sleep when they are idle.
+//
+// Assertions can be inspected with "pmset -g assertions".
+//
+// On non-macOS platforms (including iOS), all functions return an error
+// wrapping [syscall.ENOTSUP].
+package power
@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package power
+
+// PreventIdleSleep prevents the system from sleeping due to user
+// inactivity, until returned release function is called. reason is shown
+// in "pmset -g assertions" and should describe the work being performed.
+// Display may still sleep. Release function is safe to call more than once.
+//
+// Assertions are released automatically when the process exits.
+//
+//   - [syscall.EINVAL] is returned if reason is empty or contains NUL bytes.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func PreventIdleSleep(reason string) (release func(), err error) {
+	return preventIdleSleep(reason)
+}
+
+// PreventDisplaySleep is like [PreventIdleSleep], but also prevents
+// display from sleeping due to user inactivity.
+//
+//   - [syscall.EINVAL] is returned if reason is empty or contains NUL bytes.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func PreventDisplaySleep(reason string) (release func(), err error) {
+	return preventDisplaySleep(reason)
+}
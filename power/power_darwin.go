@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package power
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// assert creates assertion of the given type.
+func assert(kind, reason string) (func(), error) {
+	if reason == "" || strings.ContainsRune(reason, 0) {
+		return nil, fmt.Errorf("power: invalid reason(%q): %w", reason, syscall.EINVAL)
+	}
+
+	id, err := macos.IOPMAssertionCreateWithName(kind, reason)
+	if err != nil {
+		return nil, fmt.Errorf("power: failed to create assertion: %w", err)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			_ = macos.IOPMAssertionRelease(id)
+		})
+	}, nil
+}
+
+// Os specific implementation of [PreventIdleSleep].
+func preventIdleSleep(reason string) (func(), error) {
+	return assert(macos.AssertionPreventUserIdleSystemSleep, reason)
+}
+
+// Os specific implementation of [PreventDisplaySleep].
+func preventDisplaySleep(reason string) (func(), error) {
+	return assert(macos.AssertionPreventUserIdleDisplaySleep, reason)
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package power_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/power"
+)
+
+func TestPreventIdleSleep(t *testing.T) {
+	release, err := power.PreventIdleSleep("go-launchd test")
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	release()
+	release()
+
+	_, err = power.PreventIdleSleep("")
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package power
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [PreventIdleSleep].
+func preventIdleSleep(_ string) (func(), error) {
+	return nil, fmt.Errorf("power: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [PreventDisplaySleep].
+func preventDisplaySleep(_ string) (func(), error) {
+	return nil, fmt.Errorf("power: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of running pmset.
+func pmset(_ context.Context, _ ...string) error {
+	return fmt.Errorf("power: only supported on macOS: %w", syscall.ENOTSUP)
+}
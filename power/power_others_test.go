@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package power_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/power"
+)
+
+func TestUnsupported(t *testing.T) {
+	tt := map[string]func(string) (func(), error){
+		"PreventIdleSleep":    power.PreventIdleSleep,
+		"PreventDisplaySleep": power.PreventDisplaySleep,
+	}
+	for name, fn := range tt {
+		t.Run(name, func(t *testing.T) {
+			release, err := fn("Transferring files")
+			if release != nil {
+				t.Errorf("expected no release function on non-darwin platform")
+			}
+			if !errors.Is(err, syscall.ENOTSUP) {
+				t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+			}
+		})
+	}
+}
+
+func TestScheduleWake(t *testing.T) {
+	ts := time.Now().Add(time.Hour)
+	if err := power.ScheduleWake(context.Background(), ts, "com.example.svc"); !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+	if err := power.CancelWake(context.Background(), ts, "com.example.svc"); !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
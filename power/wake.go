@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package power
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// pmsetTimeLayout is the date format accepted by pmset(1).
+const pmsetTimeLayout = "01/02/06 15:04:05"
+
+// pmsetArgs returns pmset arguments for scheduling a wake event.
+func pmsetArgs(cancel bool, t time.Time, owner string) []string {
+	args := []string{"schedule"}
+	if cancel {
+		args = append(args, "cancel")
+	}
+	args = append(args, "wake", t.Local().Format(pmsetTimeLayout))
+	if owner != "" {
+		args = append(args, owner)
+	}
+	return args
+}
+
+// ScheduleWake schedules the system to wake from sleep at t, using
+// pmset(1). owner identifies the event in "pmset -g sched", and is
+// required to cancel it with [CancelWake]. Event is removed once it has
+// occurred. This requires root.
+//
+// launchd starts jobs with StartCalendarInterval when the system wakes,
+// if their scheduled time passed while the system was asleep. Thus, a
+// wake event paired with such a job results in "wake then run" behavior.
+// See [ScheduleJobWake].
+//
+//   - [syscall.EINVAL] is returned if t is not in the future.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func ScheduleWake(ctx context.Context, t time.Time, owner string) error {
+	if !t.After(time.Now()) {
+		return fmt.Errorf("power: wake time(%s) is not in the future: %w", t, syscall.EINVAL)
+	}
+	return pmset(ctx, pmsetArgs(false, t, owner)...)
+}
+
+// CancelWake cancels the wake event scheduled with [ScheduleWake]
+// with the same time and owner.
+//
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func CancelWake(ctx context.Context, t time.Time, owner string) error {
+	return pmset(ctx, pmsetArgs(true, t, owner)...)
+}
+
+// ScheduleJobWake schedules the system to wake lead duration before
+// the next start of the job by its StartCalendarInterval, with job label
+// as owner. It returns the scheduled wake time. Call this every time
+// the job runs to schedule the next wake.
+//
+//   - [syscall.EINVAL] is returned if job has no StartCalendarInterval.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func ScheduleJobWake(ctx context.Context, job plist.Job, lead time.Duration) (time.Time, error) {
+	now := time.Now()
+	next := job.NextCalendarTime(now.Add(lead))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("power: job(%s) has no valid StartCalendarInterval: %w",
+			job.Label, syscall.EINVAL)
+	}
+
+	wake := next.Add(-lead)
+	if err := ScheduleWake(ctx, wake, job.Label); err != nil {
+		return time.Time{}, err
+	}
+	return wake, nil
+}
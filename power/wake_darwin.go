@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package power
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// pmset runs pmset with the given arguments.
+func pmset(ctx context.Context, args ...string) error {
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "/usr/bin/pmset", args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("power: %w", ctx.Err())
+		}
+		return fmt.Errorf("power: pmset failed: %w: %s", err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package power
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestPMSetArgs(t *testing.T) {
+	ts := time.Date(2024, time.March, 13, 3, 4, 5, 0, time.Local)
+	tt := []struct {
+		name   string
+		cancel bool
+		owner  string
+		expect []string
+	}{
+		{
+			name:   "schedule",
+			owner:  "com.example.svc",
+			expect: []string{"schedule", "wake", "03/13/24 03:04:05", "com.example.svc"},
+		},
+		{
+			name:   "cancel",
+			cancel: true,
+			owner:  "com.example.svc",
+			expect: []string{"schedule", "cancel", "wake", "03/13/24 03:04:05", "com.example.svc"},
+		},
+		{
+			name:   "no-owner",
+			expect: []string{"schedule", "wake", "03/13/24 03:04:05"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := pmsetArgs(tc.cancel, ts, tc.owner); !slices.Equal(v, tc.expect) {
+				t.Errorf("expected=%v, got=%v", tc.expect, v)
+			}
+		})
+	}
+}
+
+func TestScheduleWake_Past(t *testing.T) {
+	err := ScheduleWake(context.Background(), time.Now().Add(-time.Minute), "com.example.svc")
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
+
+func TestScheduleJobWake_NoCalendar(t *testing.T) {
+	_, err := ScheduleJobWake(context.Background(), plist.Job{Label: "com.example.svc"}, time.Minute)
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"syscall"
+)
+
+// DialFunc connects to the upstream of [Proxy].
+type DialFunc func(ctx context.Context) (net.Conn, error)
+
+// DialAddress returns [DialFunc] which connects to the address on the named
+// network, for example "tcp" and "127.0.0.1:8080" or "unix" and a socket path.
+func DialAddress(network, address string) DialFunc {
+	return func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, address)
+	}
+}
+
+// DialSocket returns [DialFunc] which connects to the unix socket with the
+// given name of another job with the given label, with [DialContext].
+func DialSocket(label, socketName string) DialFunc {
+	return func(ctx context.Context) (net.Conn, error) {
+		return DialContext(ctx, label, socketName)
+	}
+}
+
+// Proxy accepts connections on the listener and forwards each of them to a
+// connection returned by dial, copying data in both directions until either
+// side closes its connection. This can be used to expose a unix socket of
+// a sandboxed job over an activated TCP socket, or vice versa.
+//
+// Connections are accepted with [AcceptLoop], and Proxy blocks until ctx is
+// done or the listener fails with a non-temporary error. Listener is closed
+// before returning, and Proxy waits for forwarded connections to be closed.
+// Connections which could not be dialed are closed and logged. Returned
+// error is nil if ctx is done.
+//
+//   - [syscall.EINVAL] is returned if listener or dial is nil.
+func Proxy(ctx context.Context, l net.Listener, dial DialFunc) error {
+	if l == nil || dial == nil {
+		return fmt.Errorf("launchd: listener and dial must not be nil: %w", syscall.EINVAL)
+	}
+	return AcceptLoop(ctx, l, func(ctx context.Context, conn net.Conn, _ *PeerInfo) {
+		forward(ctx, conn, dial)
+	})
+}
+
+// forward forwards the accepted connection to the upstream connection.
+// Both connections are closed when ctx is done or either of them is closed.
+func forward(ctx context.Context, conn net.Conn, dial DialFunc) {
+	upstream, err := dial(ctx)
+	if err != nil {
+		debug("launchd: proxy dial error",
+			slog.String("client", conn.RemoteAddr().String()),
+			slog.Any("err", err),
+		)
+		return
+	}
+	defer upstream.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+		upstream.Close()
+	})
+	defer stop()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		copyHalf(upstream, conn)
+	}()
+	go func() {
+		defer wg.Done()
+		copyHalf(conn, upstream)
+	}()
+	wg.Wait()
+}
+
+// closeWriter is implemented by [*net.TCPConn] and [*net.UnixConn].
+type closeWriter interface {
+	CloseWrite() error
+}
+
+// copyHalf copies from src to dst, and then half closes dst if supported,
+// so that the peer observes EOF while the other direction is still open.
+// Otherwise, dst is closed.
+func copyHalf(dst, src net.Conn) {
+	_, _ = io.Copy(dst, src)
+	if cw, ok := dst.(closeWriter); ok {
+		_ = cw.CloseWrite()
+	} else {
+		_ = dst.Close()
+	}
+}
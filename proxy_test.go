@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// echoServer starts an echo server on the listener.
+func echoServer(t *testing.T, l net.Listener) {
+	t.Helper()
+	t.Cleanup(func() {
+		l.Close()
+	})
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+}
+
+func TestProxy(t *testing.T) {
+	tt := []struct {
+		name     string
+		network  string
+		upstream string
+	}{
+		{name: "tcp-to-tcp", network: "tcp", upstream: "127.0.0.1:0"},
+		{name: "tcp-to-unix", network: "unix", upstream: filepath.Join(t.TempDir(), "u.sock")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.network == "unix" && runtime.GOOS == "windows" {
+				t.Skipf("unix sockets are not supported on windows")
+			}
+
+			upstream, err := net.Listen(tc.network, tc.upstream)
+			if err != nil {
+				t.Fatalf("failed to listen: %s", err)
+			}
+			echoServer(t, upstream)
+
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %s", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				done <- Proxy(ctx, l, DialAddress(tc.network, upstream.Addr().String()))
+			}()
+
+			conn, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				t.Fatalf("failed to dial proxy: %s", err)
+			}
+			defer conn.Close()
+
+			msg := []byte("hello from proxy")
+			if _, err = conn.Write(msg); err != nil {
+				t.Fatalf("failed to write: %s", err)
+			}
+			_ = conn.(*net.TCPConn).CloseWrite()
+
+			_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+			got, err := io.ReadAll(conn)
+			if err != nil {
+				t.Fatalf("failed to read: %s", err)
+			}
+			if string(got) != string(msg) {
+				t.Errorf("expected=%q, got=%q", msg, got)
+			}
+
+			cancel()
+			select {
+			case err = <-done:
+				if err != nil {
+					t.Errorf("expected no error, got=%s", err)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("proxy did not stop after context was canceled")
+			}
+		})
+	}
+}
+
+func TestProxy_Invalid(t *testing.T) {
+	err := Proxy(context.Background(), nil, DialAddress("tcp", "127.0.0.1:0"))
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the rate of connections accepted by listeners
+// returned by [Open] with a token bucket, which is refilled at limit tokens
+// per second and holds at most burst tokens. Bucket is shared by all the
+// listeners of the socket. See [RateLimitListener].
+//
+// This is cheap protection against abuse for on-demand jobs which are
+// exposed to the network. Rate limit is disabled if limit is not positive.
+// Burst less than 1 is treated as 1.
+func WithRateLimit(limit float64, burst int) Option {
+	return func(o *options) {
+		o.rateLimit = limit
+		o.rateBurst = burst
+	}
+}
+
+// RateLimitListener wraps the listener, so that accepted connections
+// are limited to limit per second, with bursts of up to burst connections.
+// Connections accepted in excess of the rate limit are closed immediately
+// and are not returned by Accept. Listener is returned as is if limit
+// is not positive. Burst less than 1 is treated as 1.
+func RateLimitListener(l net.Listener, limit float64, burst int) net.Listener {
+	if limit <= 0 {
+		return l
+	}
+	return &rateLimitListener{Listener: l, bucket: newTokenBucket(limit, burst)}
+}
+
+// tokenBucket is a token bucket rate limiter safe for concurrent use.
+type tokenBucket struct {
+	mu     sync.Mutex
+	limit  float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full token bucket.
+func newTokenBucket(limit float64, burst int) *tokenBucket {
+	burst = max(burst, 1)
+	return &tokenBucket{
+		limit:  limit,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// allow reports whether a token is available at now and takes it.
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.limit)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type rateLimitListener struct {
+	net.Listener
+	bucket *tokenBucket
+}
+
+// Accept implements [net.Listener].
+func (l *rateLimitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.bucket.allow(time.Now()) {
+			return conn, nil
+		}
+		debug("launchd: rate limit exceeded, closing connection",
+			slog.String("addr", l.Addr().String()),
+			slog.String("client", conn.RemoteAddr().String()),
+		)
+		conn.Close()
+	}
+}
+
+// limit wraps listeners of the result with [RateLimitListener],
+// if rate limit is configured.
+func (r *Result) limit(o *options) {
+	if o.rateLimit <= 0 {
+		return
+	}
+	bucket := newTokenBucket(o.rateLimit, o.rateBurst)
+	for i, l := range r.Listeners {
+		r.Listeners[i] = &rateLimitListener{Listener: l, bucket: bucket}
+	}
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(10, 2)
+	now := b.last
+
+	tt := []struct {
+		name   string
+		offset time.Duration
+		expect bool
+	}{
+		{name: "burst-1", expect: true},
+		{name: "burst-2", expect: true},
+		{name: "empty", expect: false},
+		{name: "partial-refill", offset: 50 * time.Millisecond, expect: false},
+		{name: "refill", offset: 100 * time.Millisecond, expect: true},
+		{name: "empty-after-refill", offset: 100 * time.Millisecond, expect: false},
+		{name: "capped-at-burst-1", offset: time.Hour, expect: true},
+		{name: "capped-at-burst-2", offset: time.Hour, expect: true},
+		{name: "capped-at-burst-3", offset: time.Hour, expect: false},
+	}
+	for _, tc := range tt {
+		if got := b.allow(now.Add(tc.offset)); got != tc.expect {
+			t.Errorf("%s: expected allow=%t, got=%t", tc.name, tc.expect, got)
+		}
+	}
+}
+
+func TestRateLimitListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	if RateLimitListener(l, 0, 1) != l {
+		t.Errorf("expected listener to be returned as is without rate limit")
+	}
+
+	l = RateLimitListener(l, 0.001, 1)
+	defer l.Close()
+
+	clients := make([]net.Conn, 2)
+	for i := range clients {
+		clients[i], err = net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %s", err)
+		}
+		defer clients[i].Close()
+	}
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+	defer conn.Close()
+
+	// Second connection exceeds rate limit and is closed by Accept,
+	// which then blocks until listener is closed.
+	done := make(chan error, 1)
+	go func() {
+		c, err := l.Accept()
+		if err == nil {
+			c.Close()
+		}
+		done <- err
+	}()
+
+	_ = clients[1].SetReadDeadline(time.Now().Add(10 * time.Second))
+	if _, err = clients[1].Read(make([]byte, 1)); err == nil {
+		t.Errorf("expected connection exceeding rate limit to be closed")
+	}
+
+	l.Close()
+	if err = <-done; err == nil {
+		t.Errorf("expected accept to fail after listener is closed")
+	}
+}
+
+func TestResult_Limit(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	r := &Result{Listeners: []net.Listener{l}}
+	defer r.Close()
+
+	r.limit(&options{})
+	if r.Listeners[0] != l {
+		t.Errorf("expected listener not to be wrapped without rate limit")
+	}
+
+	r.limit(newOptions([]Option{WithRateLimit(10, 5)}))
+	if _, ok := r.Listeners[0].(*rateLimitListener); !ok {
+		t.Errorf("expected listener to be wrapped, got=%T", r.Listeners[0])
+	}
+}
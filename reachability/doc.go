@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package reachability watches network reachability using SystemConfiguration
+// without using cgo.
+//
+// KeepAlive NetworkState of launchd only starts or keeps jobs alive based on
+// whether any network interface is up, which is too coarse for daemons
+// which should pause outbound work, like uploads or syncing, while offline,
+// but keep serving on activated sockets.
+//
+// As run loops and dispatch queues cannot be used without cgo, changes are
+// detected via the Darwin notification posted by configd whenever network
+// configuration changes, and reachability is then determined with
+// SCNetworkReachabilityGetFlags.
+//
+// On non-macOS platforms (including iOS), all functions return an error
+// wrapping [syscall.ENOTSUP].
+package reachability
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package reachability
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Flags are SCNetworkReachabilityFlags.
+type Flags uint32
+
+// Reachability flags from SCNetworkReachability.h.
+const (
+	FlagTransientConnection  Flags = 1 << 0
+	FlagReachable            Flags = 1 << 1
+	FlagConnectionRequired   Flags = 1 << 2
+	FlagConnectionOnTraffic  Flags = 1 << 3
+	FlagInterventionRequired Flags = 1 << 4
+	FlagConnectionOnDemand   Flags = 1 << 5
+	FlagIsLocalAddress       Flags = 1 << 16
+	FlagIsDirect             Flags = 1 << 17
+	FlagIsWWAN               Flags = 1 << 18
+)
+
+// Reachable reports whether the target is reachable without user
+// intervention, i.e. it is reachable, and either no connection is required
+// or the connection is established automatically on demand or on traffic.
+func (f Flags) Reachable() bool {
+	if f&FlagReachable == 0 {
+		return false
+	}
+	if f&FlagConnectionRequired == 0 {
+		return true
+	}
+	return f&(FlagConnectionOnDemand|FlagConnectionOnTraffic) != 0 &&
+		f&FlagInterventionRequired == 0
+}
+
+// String returns names of the flags set, separated by "|".
+func (f Flags) String() string {
+	names := []struct {
+		flag Flags
+		name string
+	}{
+		{FlagTransientConnection, "transient-connection"},
+		{FlagReachable, "reachable"},
+		{FlagConnectionRequired, "connection-required"},
+		{FlagConnectionOnTraffic, "connection-on-traffic"},
+		{FlagInterventionRequired, "intervention-required"},
+		{FlagConnectionOnDemand, "connection-on-demand"},
+		{FlagIsLocalAddress, "local-address"},
+		{FlagIsDirect, "direct"},
+		{FlagIsWWAN, "wwan"},
+	}
+
+	var s []string
+	for _, n := range names {
+		if f&n.flag != 0 {
+			s = append(s, n.name)
+		}
+	}
+	if len(s) == 0 {
+		return "none"
+	}
+	return strings.Join(s, "|")
+}
+
+// Event is a change in reachability.
+type Event struct {
+	// Up reports whether the target is reachable, see [Flags.Reachable].
+	Up bool
+
+	// Flags are reachability flags of the target.
+	Flags Flags
+
+	// Time is the time the change was detected.
+	Time time.Time
+}
+
+// State returns reachability flags of the host, which can be a host name
+// or an IP address. If host is empty, flags of the default route are
+// returned, i.e. whether internet is reachable. This does not send any
+// packets to the host, but may resolve the host name.
+//
+//   - [syscall.EINVAL] is returned if host is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func State(host string) (Flags, error) {
+	if strings.IndexByte(host, 0) >= 0 {
+		return 0, fmt.Errorf("reachability: invalid host(%q): %w", host, syscall.EINVAL)
+	}
+	return state(host)
+}
+
+// Watch returns a channel which receives an event with the current
+// reachability of the host, like [State], and an event whenever the
+// host becomes reachable or unreachable. Channel is closed when ctx is done.
+// Events are not dropped, but only the latest state is kept, if the receiver
+// is slower than network changes.
+//
+//   - [syscall.EINVAL] is returned if host is invalid.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Watch(ctx context.Context, host string) (<-chan Event, error) {
+	if strings.IndexByte(host, 0) >= 0 {
+		return nil, fmt.Errorf("reachability: invalid host(%q): %w", host, syscall.EINVAL)
+	}
+	return watch(ctx, host)
+}
+
+// watchChanges returns a channel which receives events built with query,
+// initially and whenever changes receives a value and reachability changed.
+// stop is called once ctx is done or changes is closed.
+func watchChanges(
+	ctx context.Context, changes <-chan struct{}, stop func(), query func() (Flags, error),
+) <-chan Event {
+	events := make(chan Event, 1)
+	go func() {
+		defer close(events)
+		defer stop()
+
+		var last *Event
+		send := func() {
+			flags, err := query()
+			if err != nil {
+				return
+			}
+			ev := Event{Up: flags.Reachable(), Flags: flags, Time: time.Now()}
+			if last != nil && last.Up == ev.Up {
+				return
+			}
+			last = &ev
+
+			// Replace pending event with the latest one.
+			select {
+			case <-events:
+			default:
+			}
+			events <- ev
+		}
+
+		send()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-changes:
+				if !ok {
+					return
+				}
+				send()
+			}
+		}
+	}()
+	return events
+}
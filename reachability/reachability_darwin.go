@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package reachability
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+	"github.com/tprasadtp/go-launchd/notifyd"
+)
+
+// Os specific implementation of [State].
+func state(host string) (Flags, error) {
+	flags, err := macos.ReachabilityFlags(host)
+	if err != nil {
+		return 0, fmt.Errorf("reachability: %w", err)
+	}
+	return Flags(flags), nil
+}
+
+// Os specific implementation of [Watch].
+func watch(ctx context.Context, host string) (<-chan Event, error) {
+	// Subscribe before querying the initial state, so that changes
+	// in between are not missed.
+	sub, err := notifyd.Subscribe(macos.NotifyNetworkChange)
+	if err != nil {
+		return nil, fmt.Errorf("reachability: failed to subscribe to network changes: %w", err)
+	}
+	stop := func() {
+		_ = sub.Close()
+	}
+	return watchChanges(ctx, sub.C, stop, func() (Flags, error) {
+		return state(host)
+	}), nil
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package reachability
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [State].
+func state(_ string) (Flags, error) {
+	return 0, fmt.Errorf("reachability: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [Watch].
+func watch(_ context.Context, _ string) (<-chan Event, error) {
+	return nil, fmt.Errorf("reachability: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package reachability
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFlags_Reachable(t *testing.T) {
+	tt := []struct {
+		name   string
+		flags  Flags
+		expect bool
+	}{
+		{name: "none", flags: 0},
+		{name: "reachable", flags: FlagReachable, expect: true},
+		{name: "direct", flags: FlagReachable | FlagIsDirect, expect: true},
+		{name: "connection-required", flags: FlagReachable | FlagConnectionRequired},
+		{
+			name:   "on-demand",
+			flags:  FlagReachable | FlagConnectionRequired | FlagConnectionOnDemand,
+			expect: true,
+		},
+		{
+			name:   "on-traffic",
+			flags:  FlagReachable | FlagConnectionRequired | FlagConnectionOnTraffic,
+			expect: true,
+		},
+		{
+			name: "intervention-required",
+			flags: FlagReachable | FlagConnectionRequired |
+				FlagConnectionOnDemand | FlagInterventionRequired,
+		},
+		{name: "not-reachable", flags: FlagConnectionRequired | FlagTransientConnection},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := tc.flags.Reachable(); v != tc.expect {
+				t.Errorf("expected=%t, got=%t (flags=%s)", tc.expect, v, tc.flags)
+			}
+		})
+	}
+}
+
+func TestFlags_String(t *testing.T) {
+	if v := Flags(0).String(); v != "none" {
+		t.Errorf("expected=none, got=%s", v)
+	}
+	if v := (FlagReachable | FlagIsWWAN).String(); v != "reachable|wwan" {
+		t.Errorf("expected=reachable|wwan, got=%s", v)
+	}
+}
+
+func TestInvalidHost(t *testing.T) {
+	if _, err := State("example\x00.com"); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+	if _, err := Watch(context.Background(), "example\x00.com"); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+}
+
+func TestWatchChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{})
+	stopped := make(chan struct{})
+	results := make(chan Flags, 1)
+	results <- FlagReachable
+
+	events := watchChanges(ctx, changes, func() { close(stopped) }, func() (Flags, error) {
+		return <-results, nil
+	})
+
+	recv := func(expect bool) {
+		t.Helper()
+		select {
+		case ev := <-events:
+			if ev.Up != expect {
+				t.Errorf("expected up=%t, got=%t", expect, ev.Up)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for event")
+		}
+	}
+
+	// Initial state.
+	recv(true)
+
+	// Flags changed but still reachable, no event.
+	results <- FlagReachable | FlagIsDirect
+	changes <- struct{}{}
+
+	// Down.
+	results <- 0
+	changes <- struct{}{}
+	recv(false)
+
+	// Up again.
+	results <- FlagReachable
+	changes <- struct{}{}
+	recv(true)
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("stop not called after ctx is done")
+	}
+	for range events {
+	}
+}
+
+func TestWatchChanges_QueryError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{})
+	events := watchChanges(ctx, changes, func() {}, func() (Flags, error) {
+		return 0, syscall.EIO
+	})
+	close(changes)
+	for ev := range events {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+// Restriction is a platform security mechanism which can prevent
+// jobs from being installed.
+type Restriction int
+
+const (
+	// RestrictionSIP is System Integrity Protection.
+	RestrictionSIP Restriction = iota + 1
+
+	// RestrictionSandbox is App Sandbox.
+	RestrictionSandbox
+)
+
+// String implements [fmt.Stringer].
+func (r Restriction) String() string {
+	switch r {
+	case RestrictionSIP:
+		return "System Integrity Protection"
+	case RestrictionSandbox:
+		return "App Sandbox"
+	default:
+		return fmt.Sprintf("Restriction(%d)", int(r))
+	}
+}
+
+// RestrictedError is returned by [Install] and [Upgrade] when loading
+// the job fails and the failure is likely caused by System Integrity
+// Protection or App Sandbox.
+//
+// launchctl reports such failures with generic errors like
+// "Bootstrap failed: 5: Input/output error". Thus, when bootstrap fails,
+// SIP status, sandbox status of the calling process and paths used by the
+// job are probed to determine the likely cause.
+type RestrictedError struct {
+	// Restriction is the mechanism which likely caused the failure.
+	Restriction Restriction
+
+	// Path is the path protected by the restriction, if known.
+	Path string
+
+	// Hint is a human readable remediation hint.
+	Hint string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error interface.
+func (e *RestrictedError) Error() string {
+	var b strings.Builder
+	b.WriteString("launchd: operation not permitted by ")
+	b.WriteString(e.Restriction.String())
+	if e.Path != "" {
+		fmt.Fprintf(&b, " (%s)", e.Path)
+	}
+	if e.Hint != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Hint)
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *RestrictedError) Unwrap() error {
+	return e.Err
+}
+
+// sandboxHint is the remediation hint for App Sandbox restrictions.
+const sandboxHint = "sandboxed apps cannot bootstrap launchd jobs directly, " +
+	"register bundled agents and daemons with SMAppService instead"
+
+// restrictionStatus is the state of platform restrictions
+// applicable to the calling process.
+type restrictionStatus struct {
+	sip       bool
+	sandboxed bool
+}
+
+// sipProtectedPaths are directories protected by System Integrity
+// Protection. /usr/local is excluded via sipExcludedPaths.
+var sipProtectedPaths = []string{
+	"/System",
+	"/bin",
+	"/sbin",
+	"/usr",
+	"/private/var/db/com.apple.xpc.launchd",
+}
+
+// sipExcludedPaths are directories under sipProtectedPaths
+// which are not protected by System Integrity Protection.
+var sipExcludedPaths = []string{
+	"/usr/local",
+	"/System/Volumes/Data",
+}
+
+// hasPathPrefix reports whether path is equal to or under dir.
+func hasPathPrefix(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// sipProtected reports whether path is protected by
+// System Integrity Protection.
+func sipProtected(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	path = filepath.Clean(path)
+	for _, dir := range sipExcludedPaths {
+		if hasPathPrefix(path, dir) {
+			return false
+		}
+	}
+	for _, dir := range sipProtectedPaths {
+		if hasPathPrefix(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCSRStatus parses output of "csrutil status". If output cannot be
+// parsed ok is false. Custom configurations where only some protections
+// are disabled are reported as enabled.
+func parseCSRStatus(output string) (enabled bool, ok bool) {
+	for _, line := range strings.Split(output, "\n") {
+		_, status, found := strings.Cut(line, "System Integrity Protection status:")
+		if !found {
+			continue
+		}
+		status = strings.ToLower(strings.TrimSpace(status))
+		switch {
+		case strings.HasPrefix(status, "enabled"):
+			return true, true
+		case strings.HasPrefix(status, "disabled"):
+			return false, true
+		case strings.HasPrefix(status, "unknown"):
+			// Custom configuration.
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// mayBeRestricted reports whether err is a launchctl error which
+// could be caused by SIP or sandbox restrictions.
+func mayBeRestricted(err error) bool {
+	var e *launchctl.Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	return errors.Is(e, launchctl.ErrPermission) ||
+		errors.Is(e, launchctl.ErrSIP) ||
+		e.Code == int(syscall.EIO)
+}
+
+// classifyRestriction returns [*RestrictedError] if err is likely caused
+// by restrictions in status, considering paths used by the job.
+// Otherwise err is returned as is.
+func classifyRestriction(err error, status restrictionStatus, paths ...string) error {
+	if err == nil || !mayBeRestricted(err) {
+		return err
+	}
+
+	if status.sandboxed {
+		return &RestrictedError{
+			Restriction: RestrictionSandbox,
+			Hint:        sandboxHint,
+			Err:         err,
+		}
+	}
+
+	if status.sip {
+		for _, path := range paths {
+			if sipProtected(path) {
+				return &RestrictedError{
+					Restriction: RestrictionSIP,
+					Path:        path,
+					Hint: "path is protected and jobs from it cannot be modified, " +
+						"use paths under /Library or /usr/local instead",
+					Err: err,
+				}
+			}
+		}
+
+		if errors.Is(err, launchctl.ErrSIP) {
+			return &RestrictedError{
+				Restriction: RestrictionSIP,
+				Hint: "job is protected and cannot be modified while " +
+					"System Integrity Protection is enabled",
+				Err: err,
+			}
+		}
+	}
+	return err
+}
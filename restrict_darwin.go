@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"context"
+	"os/exec"
+)
+
+// probeRestrictions returns the state of restrictions applicable
+// to the calling process. Probes which fail are ignored.
+func probeRestrictions(ctx context.Context) restrictionStatus {
+	var status restrictionStatus
+
+	status.sandboxed = isSandboxed()
+
+	// Assume SIP is enabled if its status cannot be determined,
+	// as it is enabled by default.
+	status.sip = true
+	out, err := exec.CommandContext(ctx, "/usr/bin/csrutil", "status").Output()
+	if err == nil {
+		if enabled, ok := parseCSRStatus(string(out)); ok {
+			status.sip = enabled
+		}
+	}
+	return status
+}
+
+// diagnoseRestriction wraps bootstrap errors likely caused by SIP
+// or sandbox restrictions in [*RestrictedError].
+func diagnoseRestriction(ctx context.Context, err error, paths ...string) error {
+	if err == nil || !mayBeRestricted(err) {
+		return err
+	}
+	return classifyRestriction(err, probeRestrictions(ctx), paths...)
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+func TestSIPProtected(t *testing.T) {
+	tt := []struct {
+		path   string
+		expect bool
+	}{
+		{path: "/System/Library/LaunchDaemons/com.apple.foo.plist", expect: true},
+		{path: "/usr/libexec/foo", expect: true},
+		{path: "/usr", expect: true},
+		{path: "/bin/sh", expect: true},
+		{path: "/sbin/launchd", expect: true},
+		{path: "/usr/local/bin/svc", expect: false},
+		{path: "/usr/local", expect: false},
+		{path: "/usrlocal/bin/svc", expect: false},
+		{path: "/Library/LaunchDaemons/com.example.svc.plist", expect: false},
+		{path: "/System/Volumes/Data/Users/foo", expect: false},
+		{path: "/opt/homebrew/bin/svc", expect: false},
+		{path: "/usr/local/../bin/sh", expect: true},
+		{path: "usr/bin/foo", expect: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.path, func(t *testing.T) {
+			if v := sipProtected(tc.path); v != tc.expect {
+				t.Errorf("expected=%t, got=%t", tc.expect, v)
+			}
+		})
+	}
+}
+
+func TestParseCSRStatus(t *testing.T) {
+	tt := []struct {
+		name    string
+		output  string
+		enabled bool
+		ok      bool
+	}{
+		{
+			name:    "enabled",
+			output:  "System Integrity Protection status: enabled.\n",
+			enabled: true,
+			ok:      true,
+		},
+		{
+			name:   "disabled",
+			output: "System Integrity Protection status: disabled.\n",
+			ok:     true,
+		},
+		{
+			name: "custom",
+			output: "System Integrity Protection status: unknown (Custom Configuration).\n\n" +
+				"Configuration:\n\tApple Internal: disabled\n\tKext Signing: disabled\n",
+			enabled: true,
+			ok:      true,
+		},
+		{
+			name:   "invalid",
+			output: "csrutil: command not found",
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			enabled, ok := parseCSRStatus(tc.output)
+			if enabled != tc.enabled {
+				t.Errorf("expected enabled=%t, got=%t", tc.enabled, enabled)
+			}
+			if ok != tc.ok {
+				t.Errorf("expected ok=%t, got=%t", tc.ok, ok)
+			}
+		})
+	}
+}
+
+func TestClassifyRestriction(t *testing.T) {
+	eio := &launchctl.Error{
+		Args:     []string{"bootstrap", "system", "/Library/LaunchDaemons/com.example.svc.plist"},
+		ExitCode: 5,
+		Code:     5,
+		Message:  "Bootstrap failed: 5: Input/output error",
+	}
+	sip := &launchctl.Error{
+		Args:     []string{"bootout", "system/com.apple.foo"},
+		ExitCode: 150,
+		Code:     150,
+		Message:  "Boot-out failed: 150: Operation not permitted while System Integrity Protection is engaged",
+	}
+	disabled := &launchctl.Error{
+		Args:     []string{"bootstrap", "system", "/Library/LaunchDaemons/com.example.svc.plist"},
+		ExitCode: 119,
+		Code:     119,
+		Message:  "Bootstrap failed: 119: Service is disabled",
+	}
+
+	tt := []struct {
+		name        string
+		err         error
+		status      restrictionStatus
+		paths       []string
+		restriction Restriction
+		path        string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:   "not-launchctl-error",
+			err:    errors.New("foo"),
+			status: restrictionStatus{sip: true, sandboxed: true},
+		},
+		{
+			name:   "unrelated-launchctl-error",
+			err:    disabled,
+			status: restrictionStatus{sip: true, sandboxed: true},
+		},
+		{
+			name:        "sandbox",
+			err:         eio,
+			status:      restrictionStatus{sip: true, sandboxed: true},
+			paths:       []string{"/usr/local/bin/svc"},
+			restriction: RestrictionSandbox,
+		},
+		{
+			name:        "sip-protected-path",
+			err:         fmt.Errorf("wrapped: %w", eio),
+			status:      restrictionStatus{sip: true},
+			paths:       []string{"/usr/local/bin/svc", "/usr/libexec/svc"},
+			restriction: RestrictionSIP,
+			path:        "/usr/libexec/svc",
+		},
+		{
+			name:   "sip-disabled-protected-path",
+			err:    eio,
+			paths:  []string{"/usr/libexec/svc"},
+			status: restrictionStatus{},
+		},
+		{
+			name:   "sip-unprotected-path",
+			err:    eio,
+			paths:  []string{"/usr/local/bin/svc"},
+			status: restrictionStatus{sip: true},
+		},
+		{
+			name:        "sip-error-code",
+			err:         sip,
+			paths:       []string{"/usr/local/bin/svc"},
+			status:      restrictionStatus{sip: true},
+			restriction: RestrictionSIP,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := classifyRestriction(tc.err, tc.status, tc.paths...)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+
+			var re *RestrictedError
+			if errors.As(err, &re) {
+				if tc.restriction == 0 {
+					t.Fatalf("expected no RestrictedError, got=%s", err)
+				}
+				if re.Restriction != tc.restriction {
+					t.Errorf("expected restriction=%s, got=%s", tc.restriction, re.Restriction)
+				}
+				if re.Path != tc.path {
+					t.Errorf("expected path=%s, got=%s", tc.path, re.Path)
+				}
+				if re.Hint == "" {
+					t.Errorf("expected a hint")
+				}
+			} else if tc.restriction != 0 {
+				t.Errorf("expected RestrictedError, got=%v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Option configures [Open].
+type Option func(*options)
+
+// options for [Open].
+type options struct {
+	partial   bool
+	rateLimit float64
+	rateBurst int
+
+	ensureDualStack bool
+	retry           *RetryPolicy
+	strict          bool
+
+	tcpKeepAlive   time.Duration
+	acceptDeadline time.Duration
+	reusePort      bool
+	fastOpen       int
+	rcvBuf         int
+	sndBuf         int
+	packetInfo     bool
+	ecn            bool
+	trafficClass   int
+	dontFragment   bool
+}
+
+// newOptions returns options with opts applied.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithPartial allows [Open] to return partial results. By default, if any of
+// the activated sockets cannot be used, all listeners built are closed and
+// an error is returned. With this option, usable listeners are returned
+// along with skipped sockets and their errors, without returning an error.
+func WithPartial() Option {
+	return func(o *options) {
+		o.partial = true
+	}
+}
+
+// Strict makes [Open] return an error on any unexpected response from launchd,
+// even if [WithPartial] is used. This is useful to detect changes in behavior
+// of launchd on new or beta releases of macOS early, instead of silently
+// serving on a subset of sockets. In strict mode, an error is returned if
+//
+//   - any of the file descriptors returned is invalid or duplicated.
+//   - any of the activated sockets cannot be classified or used.
+//   - activated sockets are not all of the same kind, i.e. a mix of
+//     listeners, packet connections and connections.
+//
+// As with other errors, [ErrProtocol] is returned for inconsistent responses.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// Result is the result of activating a socket with [Open]. Unlike [Listeners]
+// and [PacketListeners], which return both listeners and an error when some
+// of the activated sockets cannot be used, Result makes the distinction
+// between usable and skipped sockets explicit.
+type Result struct {
+	// Name is the name of the socket in the Sockets dictionary of the job.
+	Name string
+
+	// Listeners are built from activated passive stream sockets.
+	Listeners []net.Listener
+
+	// PacketConns are built from activated passive datagram sockets.
+	PacketConns []net.PacketConn
+
+	// Conns are built from activated sockets which are not passive,
+	// i.e. sockets connected by launchd.
+	Conns []net.Conn
+
+	// Skipped are activated sockets which could not be used. Type, Family
+	// and Addr are zero values if the socket could not be classified.
+	// Files of skipped sockets are not closed.
+	Skipped []Socket
+
+	// Errors are errors encountered while building listeners,
+	// including errors for each of the skipped sockets.
+	Errors []error
+}
+
+// Partial reports whether any of the activated sockets were skipped.
+func (r *Result) Partial() bool {
+	return len(r.Skipped) > 0 || len(r.Errors) > 0
+}
+
+// Err returns errors encountered while building listeners joined with [errors.Join],
+// or nil if there were none.
+func (r *Result) Err() error {
+	return errors.Join(r.Errors...)
+}
+
+// Close closes all listeners, packet connections and connections. Files of skipped
+// sockets are closed as well.
+func (r *Result) Close() error {
+	err := r.closeListeners()
+	for _, s := range r.Skipped {
+		if s.File != nil {
+			err = errors.Join(err, s.File.Close())
+		}
+	}
+	return err
+}
+
+// kinds returns number of kinds of sockets in the result, i.e. whether it has
+// any listeners, packet connections and connections.
+func (r *Result) kinds() int {
+	var n int
+	for _, k := range []int{len(r.Listeners), len(r.PacketConns), len(r.Conns)} {
+		if k > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// closeListeners closes and removes all listeners, packet connections
+// and connections.
+func (r *Result) closeListeners() error {
+	var err error
+	for _, l := range r.Listeners {
+		err = errors.Join(err, l.Close())
+	}
+	for _, l := range r.PacketConns {
+		err = errors.Join(err, l.Close())
+	}
+	for _, c := range r.Conns {
+		err = errors.Join(err, c.Close())
+	}
+	r.Listeners, r.PacketConns, r.Conns = nil, nil, nil
+	return err
+}
+
+// check returns an error if result is partial, unless partial results
+// are allowed, or if result has an unexpected shape in strict mode.
+// Listeners are closed before returning the error, so that callers
+// ignoring the result on error do not leak them.
+func (r *Result) check(o *options) error {
+	if o.strict {
+		if kinds := r.kinds(); kinds > 1 {
+			r.Errors = append(r.Errors, fmt.Errorf(
+				"launchd: socket(%s) has %d kinds of sockets: %w", r.Name, kinds, ErrProtocol))
+		}
+	}
+	if !r.Partial() || (o.partial && !o.strict) {
+		return nil
+	}
+	if err := r.closeListeners(); err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+	return fmt.Errorf("launchd: error building listeners: %w", r.Err())
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"syscall"
+)
+
+// newResult builds [Result] from files of the activated socket. Sockets which
+// are not passive are built as connections. Files which cannot be classified,
+// or of unsupported socket types are skipped. As listeners, packet connections
+// and connections use duplicates of file descriptors, files are closed once
+// they are built, so that closing the result closes the activated sockets.
+func newResult(name string, files []*os.File) *Result {
+	r := &Result{Name: name}
+	skip := func(s Socket, err error) {
+		debug("launchd: skipping socket",
+			slog.String("name", name),
+			slog.Int("fd", int(s.File.Fd())),
+			slog.Any("err", err),
+		)
+		r.Skipped = append(r.Skipped, s)
+		r.Errors = append(r.Errors, err)
+		recordListenerError(name, err)
+	}
+
+	for i, file := range files {
+		s, err := newSocket(name, file)
+		if err != nil {
+			skip(Socket{Name: name, File: file, Index: i}, err)
+			continue
+		}
+		s.Index = i
+
+		switch {
+		case !s.Passive:
+			c, err := s.Conn()
+			if err != nil {
+				skip(s, err)
+				continue
+			}
+			r.Conns = append(r.Conns, c)
+			_ = file.Close()
+		case s.Type == syscall.SOCK_STREAM:
+			l, err := s.Listener()
+			if err != nil {
+				skip(s, err)
+				continue
+			}
+			r.Listeners = append(r.Listeners, l)
+			_ = file.Close()
+		case s.Type == syscall.SOCK_DGRAM:
+			l, err := s.PacketConn()
+			if err != nil {
+				skip(s, err)
+				continue
+			}
+			r.PacketConns = append(r.PacketConns, l)
+			_ = file.Close()
+		default:
+			skip(s, fmt.Errorf("%s: unsupported socket type(%d): %w", name, s.Type, syscall.ESOCKTNOSUPPORT))
+		}
+	}
+	return r
+}
@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// resultFiles returns files of a stream socket, a datagram socket and
+// a pipe, which cannot be classified as a socket.
+func resultFiles(t *testing.T) []*os.File {
+	t.Helper()
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	p, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer p.Close()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %s", err)
+	}
+	t.Cleanup(func() {
+		w.Close()
+	})
+
+	lf, err := l.(fileCloser).File()
+	if err != nil {
+		t.Fatalf("failed to get file: %s", err)
+	}
+	pf, err := p.(fileCloser).File()
+	if err != nil {
+		t.Fatalf("failed to get file: %s", err)
+	}
+
+	files := []*os.File{lf, pf, r}
+	t.Cleanup(func() {
+		for _, f := range files {
+			f.Close()
+		}
+	})
+	return files
+}
+
+func TestNewResult(t *testing.T) {
+	files := resultFiles(t)
+	r := newResult("test", files)
+	t.Cleanup(func() {
+		r.Close()
+	})
+
+	if len(r.Listeners) != 1 {
+		t.Errorf("expected listeners=1, got=%d", len(r.Listeners))
+	}
+	if len(r.PacketConns) != 1 {
+		t.Errorf("expected packet conns=1, got=%d", len(r.PacketConns))
+	}
+	if len(r.Skipped) != 1 || r.Skipped[0].File != files[2] {
+		t.Errorf("expected pipe to be skipped, got=%v", r.Skipped)
+	}
+	if len(r.Errors) != 1 {
+		t.Errorf("expected errors=1, got=%d", len(r.Errors))
+	}
+	if !r.Partial() {
+		t.Errorf("expected partial result")
+	}
+}
+
+func TestNewResult_Conns(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	conn, err := net.Dial("tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer conn.Close()
+
+	file, err := conn.(fileCloser).File()
+	if err != nil {
+		t.Fatalf("failed to get file: %s", err)
+	}
+	defer file.Close()
+
+	r := newResult("test", []*os.File{file})
+	t.Cleanup(func() {
+		r.Close()
+	})
+
+	if len(r.Conns) != 1 {
+		t.Errorf("expected conns=1, got=%d", len(r.Conns))
+	}
+	if len(r.Listeners) != 0 || r.Partial() {
+		t.Errorf("expected no listeners and no skipped sockets, got=%v", r.Skipped)
+	}
+}
+
+func TestResult_CloseActivatedFds(t *testing.T) {
+	files := resultFiles(t)
+	fds := make([]int32, 0, len(files))
+	for _, f := range files {
+		fds = append(fds, int32(f.Fd()))
+	}
+
+	r := newResult("test", files)
+	if len(r.Listeners) != 1 || len(r.PacketConns) != 1 {
+		t.Fatalf("expected listeners=1 and packet conns=1, got=%d, %d", len(r.Listeners), len(r.PacketConns))
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("failed to close result: %s", err)
+	}
+
+	for i, fd := range fds {
+		_, _, errno := syscall.Syscall(syscall.SYS_FCNTL, uintptr(fd), syscall.F_GETFD, 0)
+		if errno != syscall.EBADF {
+			t.Errorf("expected fd(%d) of file(%d) to be closed, got=%v", fd, i, errno)
+		}
+	}
+}
+
+func TestResult_Check(t *testing.T) {
+	tt := []struct {
+		name    string
+		partial bool
+	}{
+		{
+			name: "Strict",
+		},
+		{
+			name:    "Partial",
+			partial: true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newResult("test", resultFiles(t))
+			t.Cleanup(func() {
+				r.Close()
+			})
+
+			err := r.check(&options{partial: tc.partial})
+			if tc.partial {
+				if err != nil {
+					t.Errorf("expected no error, got=%s", err)
+				}
+				if len(r.Listeners) != 1 || len(r.PacketConns) != 1 {
+					t.Errorf("expected listeners to be returned")
+				}
+				return
+			}
+
+			if err == nil {
+				t.Errorf("expected error for partial result")
+			}
+			if len(r.Listeners) != 0 || len(r.PacketConns) != 0 {
+				t.Errorf("expected listeners to be closed")
+			}
+			if len(r.Skipped) != 1 {
+				t.Errorf("expected skipped=1, got=%d", len(r.Skipped))
+			}
+		})
+	}
+}
+
+func TestResult_CheckStrict(t *testing.T) {
+	t.Run("PartialIgnored", func(t *testing.T) {
+		r := newResult("test", resultFiles(t))
+		t.Cleanup(func() {
+			r.Close()
+		})
+		err := r.check(&options{partial: true, strict: true})
+		if err == nil {
+			t.Errorf("expected error for partial result in strict mode")
+		}
+		if len(r.Listeners) != 0 || len(r.PacketConns) != 0 {
+			t.Errorf("expected listeners to be closed")
+		}
+	})
+	t.Run("MixedKinds", func(t *testing.T) {
+		files := resultFiles(t)
+		files[2].Close()
+		r := newResult("test", files[:2])
+		t.Cleanup(func() {
+			r.Close()
+		})
+		if err := r.check(&options{}); err != nil {
+			t.Fatalf("expected no error without strict mode, got=%s", err)
+		}
+		if err := r.check(&options{strict: true}); !errors.Is(err, ErrProtocol) {
+			t.Errorf("expected error=%s, got=%s", ErrProtocol, err)
+		}
+	})
+}
+
+func TestResult_CheckComplete(t *testing.T) {
+	r := &Result{Name: "test"}
+	if err := r.check(&options{}); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
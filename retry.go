@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"log/slog"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy is the policy for retrying transient activation failures.
+// Zero value retries 3 times with delays of 10ms, 20ms and 40ms.
+type RetryPolicy struct {
+	// Retries is the maximum number of retries after the first attempt.
+	// Defaults to 3.
+	Retries int
+
+	// Delay is the delay before the first retry, which is doubled for
+	// each of the subsequent retries. Defaults to 10ms.
+	Delay time.Duration
+
+	// MaxDelay is the maximum delay between retries. Defaults to 1s.
+	MaxDelay time.Duration
+}
+
+// WithRetry retries activation of sockets by [Open], if launch_activate_socket
+// fails with a transient error, i.e. [syscall.EINTR] or [syscall.EAGAIN].
+// This may happen on some macOS versions right after the job is bootstrapped,
+// for example during login. Other errors, like [syscall.EALREADY] or
+// [syscall.ENOENT], are never retried.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = &policy
+	}
+}
+
+// retryable reports whether activation error is transient.
+func retryable(err error) bool {
+	return errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.EAGAIN)
+}
+
+// retry calls fn, and retries it according to the policy while it returns
+// a transient error with n equal to zero. fn is only called once if policy
+// is nil. sleep is used to wait between retries.
+func retry[T any](name string, p *RetryPolicy, sleep func(time.Duration), fn func() (T, int, error)) (T, error) {
+	v, n, err := fn()
+	if p == nil {
+		return v, err
+	}
+
+	retries, delay, maxDelay := p.Retries, p.Delay, p.MaxDelay
+	if retries <= 0 {
+		retries = 3
+	}
+	if delay <= 0 {
+		delay = 10 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = time.Second
+	}
+
+	for i := 0; i < retries && n == 0 && retryable(err); i++ {
+		debug("launchd: transient activation error, retrying",
+			slog.String("name", name),
+			slog.Int("retry", i+1),
+			slog.Duration("delay", delay),
+			slog.Any("err", err),
+		)
+		sleep(delay)
+		delay = min(2*delay, maxDelay)
+		v, n, err = fn()
+	}
+	return v, err
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	tt := []struct {
+		name   string
+		policy *RetryPolicy
+		errs   []error
+		calls  int
+		delays []time.Duration
+		err    error
+	}{
+		{
+			name:  "no-policy",
+			errs:  []error{syscall.EINTR, nil},
+			calls: 1,
+			err:   syscall.EINTR,
+		},
+		{
+			name:   "success",
+			policy: &RetryPolicy{},
+			errs:   []error{nil},
+			calls:  1,
+		},
+		{
+			name:   "eintr",
+			policy: &RetryPolicy{},
+			errs:   []error{syscall.EINTR, newActivationError("test", syscall.EAGAIN, "test"), nil},
+			calls:  3,
+			delays: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:   "exhausted",
+			policy: &RetryPolicy{Retries: 2, Delay: time.Second, MaxDelay: 1500 * time.Millisecond},
+			errs:   []error{syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN, nil},
+			calls:  3,
+			delays: []time.Duration{time.Second, 1500 * time.Millisecond},
+			err:    syscall.EAGAIN,
+		},
+		{
+			name:   "ealready",
+			policy: &RetryPolicy{},
+			errs:   []error{syscall.EALREADY, nil},
+			calls:  1,
+			err:    syscall.EALREADY,
+		},
+		{
+			name:   "enoent",
+			policy: &RetryPolicy{},
+			errs:   []error{syscall.ENOENT, nil},
+			calls:  1,
+			err:    syscall.ENOENT,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			var delays []time.Duration
+			_, err := retry("test", tc.policy, func(d time.Duration) {
+				delays = append(delays, d)
+			}, func() (struct{}, int, error) {
+				err := tc.errs[calls]
+				calls++
+				return struct{}{}, 0, err
+			})
+
+			if tc.err == nil && err != nil {
+				t.Errorf("expected no error, got=%s", err)
+			}
+			if tc.err != nil && !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+			if calls != tc.calls {
+				t.Errorf("expected calls=%d, got=%d", tc.calls, calls)
+			}
+			if len(delays) != len(tc.delays) {
+				t.Fatalf("expected delays=%v, got=%v", tc.delays, delays)
+			}
+			for i := range delays {
+				if delays[i] != tc.delays[i] {
+					t.Errorf("expected delays=%v, got=%v", tc.delays, delays)
+				}
+			}
+		})
+	}
+}
+
+func TestRetry_Partial(t *testing.T) {
+	var calls int
+	_, err := retry("test", &RetryPolicy{}, func(time.Duration) {}, func() (struct{}, int, error) {
+		calls++
+		return struct{}{}, 1, syscall.EINTR
+	})
+	if calls != 1 || !errors.Is(err, syscall.EINTR) {
+		t.Errorf("expected partial results not to be retried, calls=%d, err=%v", calls, err)
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// WithReusePort sets SO_REUSEPORT on TCP and UDP sockets activated by [Open],
+// so that other processes can bind to the same address with [ListenReusePort]
+// and have their own accept queues. This is useful for CPU bound services,
+// which run multiple worker processes sharing a port activated by launchd.
+//
+// A typical pattern is for the job to activate the socket with this option,
+// start N-1 worker processes passing them the address of the activated
+// listener, for example via an environment variable, and serve on the
+// activated listener as the first worker. Each of the workers listens on the
+// address with [ListenReusePort]. As launchd only watches the activated socket,
+// the job should keep serving on it as long as workers are running.
+//
+// How connections are distributed among the sockets is up to the kernel.
+// Unlike Linux, macOS does not balance connections evenly across sockets,
+// so services which only want to share an accept queue across processes,
+// should pass the activated listener to workers with [PassToChild] instead.
+//
+// Failing to set SO_REUSEPORT is handled like any other socket which cannot
+// be used, i.e. [Open] returns an error unless [WithPartial] is used.
+// [syscall.ENOTSUP] is returned for platforms without SO_REUSEPORT.
+func WithReusePort() Option {
+	return func(o *options) {
+		o.reusePort = true
+	}
+}
+
+// ReusePortControl sets SO_REUSEADDR and SO_REUSEPORT on the socket. It can
+// be used as Control function of [net.ListenConfig] or [net.Dialer].
+//
+//   - [syscall.ENOTSUP] is returned on platforms without SO_REUSEPORT.
+func ReusePortControl(_, _ string, c syscall.RawConn) error {
+	return setReusePort(c)
+}
+
+// ListenReusePort listens on the address with SO_REUSEPORT, so that
+// multiple processes can listen on the same address, typically the address
+// of a socket activated by [Open] with [WithReusePort]. Network must be a
+// TCP network, i.e. "tcp", "tcp4" or "tcp6".
+//
+//   - [syscall.EINVAL] is returned if network is not a TCP network.
+//   - [syscall.ENOTSUP] is returned on platforms without SO_REUSEPORT.
+func ListenReusePort(ctx context.Context, network, address string) (net.Listener, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("launchd: network(%s) is not a TCP network: %w", network, syscall.EINVAL)
+	}
+
+	lc := net.ListenConfig{Control: ReusePortControl}
+	l, err := lc.Listen(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: failed to listen on %s: %w", address, err)
+	}
+	return l, nil
+}
+
+// reusePort sets SO_REUSEPORT on TCP listeners and UDP packet connections of
+// the result, if configured. Listeners which fail are closed and reported as
+// errors, like sockets which could not be used.
+func (r *Result) reusePort(o *options) {
+	if !o.reusePort {
+		return
+	}
+
+	set := func(v any) error {
+		switch v.(type) {
+		case *net.TCPListener, *net.UDPConn:
+			rc, err := v.(syscall.Conn).SyscallConn()
+			if err != nil {
+				return err
+			}
+			return setReusePort(rc)
+		default:
+			return nil
+		}
+	}
+
+	var err error
+	listeners := r.Listeners[:0]
+	for _, l := range r.Listeners {
+		if e := set(l); e != nil {
+			err = errors.Join(err, fmt.Errorf("launchd: failed to set SO_REUSEPORT(%s): %w", l.Addr(), e))
+			_ = l.Close()
+			continue
+		}
+		listeners = append(listeners, l)
+	}
+	packetConns := r.PacketConns[:0]
+	for _, p := range r.PacketConns {
+		if e := set(p); e != nil {
+			err = errors.Join(err, fmt.Errorf("launchd: failed to set SO_REUSEPORT(%s): %w", p.LocalAddr(), e))
+			_ = p.Close()
+			continue
+		}
+		packetConns = append(packetConns, p)
+	}
+	r.Listeners, r.PacketConns = listeners, packetConns
+	if err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+}
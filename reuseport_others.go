@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !(aix || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package launchd
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// setReusePort sets SO_REUSEADDR and SO_REUSEPORT on the socket.
+func setReusePort(_ syscall.RawConn) error {
+	return fmt.Errorf("launchd: SO_REUSEPORT is not supported on this platform: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestListenReusePort(t *testing.T) {
+	_, err := ListenReusePort(context.Background(), "unix", "/tmp/reuse.sock")
+	if !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected error=%s, got=%s", syscall.EINVAL, err)
+	}
+
+	l1, err := ListenReusePort(context.Background(), "tcp4", "127.0.0.1:0")
+	if errors.Is(err, syscall.ENOTSUP) {
+		t.Skipf("SO_REUSEPORT is not supported: %s", err)
+	}
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	defer l1.Close()
+
+	l2, err := ListenReusePort(context.Background(), "tcp4", l1.Addr().String())
+	if err != nil {
+		t.Fatalf("expected no error listening on the same address, got=%s", err)
+	}
+	l2.Close()
+}
+
+func TestResult_ReusePort(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	r := &Result{Listeners: []net.Listener{l}}
+	defer r.Close()
+
+	r.reusePort(&options{})
+	if len(r.Listeners) != 1 || r.Partial() {
+		t.Fatalf("expected listener to be unchanged without option")
+	}
+
+	r.reusePort(newOptions([]Option{WithReusePort()}))
+	if err = r.Err(); errors.Is(err, syscall.ENOTSUP) {
+		if len(r.Listeners) != 0 {
+			t.Errorf("expected listener to be closed, got=%d", len(r.Listeners))
+		}
+		t.Skipf("SO_REUSEPORT is not supported: %s", err)
+	}
+	if err != nil || len(r.Listeners) != 1 {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	l2, err := ListenReusePort(context.Background(), "tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatalf("expected no error listening on activated address, got=%s", err)
+	}
+	l2.Close()
+}
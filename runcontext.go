@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+// Session types of launchd, as reported by "launchctl managername".
+const (
+	// SessionSystem is the session of system daemons.
+	SessionSystem = "System"
+
+	// SessionAqua is the GUI session of a logged-in user.
+	SessionAqua = "Aqua"
+
+	// SessionBackground is the per-user background session, which
+	// exists regardless of whether user is logged in.
+	SessionBackground = "Background"
+
+	// SessionLoginWindow is the session of the login window.
+	SessionLoginWindow = "LoginWindow"
+
+	// SessionStandardIO is the session of non-GUI logins, like SSH.
+	SessionStandardIO = "StandardIO"
+)
+
+// Kind is the kind of context the process runs in.
+type Kind int
+
+const (
+	// KindUnknown indicates that context could not be determined.
+	KindUnknown Kind = iota
+
+	// KindDaemon is a system daemon, running in the system domain.
+	KindDaemon
+
+	// KindAgent is a per-user process without access to a GUI session,
+	// for example a per-user background agent or a process started
+	// over SSH.
+	KindAgent
+
+	// KindGUI is a process running in an Aqua GUI session.
+	KindGUI
+)
+
+// String implements [fmt.Stringer].
+func (k Kind) String() string {
+	switch k {
+	case KindUnknown:
+		return "unknown"
+	case KindDaemon:
+		return "daemon"
+	case KindAgent:
+		return "agent"
+	case KindGUI:
+		return "gui"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// RunContext describes the context in which the process runs.
+//
+// Behavior of many macOS APIs depends on it. For example, login keychain
+// is usually only available in GUI sessions, and user notifications
+// cannot be posted by daemons.
+type RunContext struct {
+	// Kind of the context.
+	Kind Kind
+
+	// Session is the launchd session type, like [SessionAqua].
+	Session string
+
+	// Label is the label of the launchd job, if process was started
+	// by launchd as a job. Otherwise, it is empty.
+	Label string
+
+	// UID is the user id of the process.
+	UID int
+}
+
+// Managed reports whether process was started by launchd as a job.
+func (c RunContext) Managed() bool {
+	return c.Label != ""
+}
+
+// Context returns the context in which the calling process runs.
+//
+//   - [*launchctl.Error] is returned if launchctl fails.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Context(ctx context.Context) (RunContext, error) {
+	session, err := launchctl.ManagerName(ctx)
+	if err != nil {
+		return RunContext{}, fmt.Errorf("launchd: failed to get session type: %w", err)
+	}
+	return newRunContext(session, os.Getuid(), os.Getenv("XPC_SERVICE_NAME")), nil
+}
+
+// newRunContext builds [RunContext] from session type, uid and
+// value of XPC_SERVICE_NAME environment variable.
+func newRunContext(session string, uid int, service string) RunContext {
+	rc := RunContext{
+		Session: session,
+		UID:     uid,
+	}
+
+	// Processes started from Terminal have XPC_SERVICE_NAME set to "0".
+	// Apps launched from Finder or Dock (and their child processes) have it
+	// set to "application.<bundle-id>.<n>", which is not a launchd job.
+	if service != "0" && !strings.HasPrefix(service, "application.") {
+		rc.Label = service
+	}
+
+	switch session {
+	case SessionSystem:
+		rc.Kind = KindDaemon
+	case SessionAqua:
+		rc.Kind = KindGUI
+	case SessionBackground, SessionStandardIO, SessionLoginWindow:
+		rc.Kind = KindAgent
+	default:
+		rc.Kind = KindUnknown
+	}
+	return rc
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import "testing"
+
+func TestNewRunContext(t *testing.T) {
+	tt := []struct {
+		name    string
+		session string
+		uid     int
+		service string
+		kind    Kind
+		managed bool
+	}{
+		{name: "daemon", session: "System", service: "com.example.svc", kind: KindDaemon, managed: true},
+		{name: "gui-agent", session: "Aqua", uid: 501, service: "com.example.svc", kind: KindGUI, managed: true},
+		{name: "terminal", session: "Aqua", uid: 501, service: "0", kind: KindGUI},
+		{name: "application", session: "Aqua", uid: 501, service: "application.com.example.app.12345.12346", kind: KindGUI},
+		{name: "background", session: "Background", uid: 501, service: "com.example.svc", kind: KindAgent, managed: true},
+		{name: "ssh", session: "StandardIO", uid: 501, kind: KindAgent},
+		{name: "unknown", session: "Foo", uid: 501, kind: KindUnknown},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := newRunContext(tc.session, tc.uid, tc.service)
+			if rc.Kind != tc.kind {
+				t.Errorf("expected kind=%s, got=%s", tc.kind, rc.Kind)
+			}
+			if rc.Managed() != tc.managed {
+				t.Errorf("expected managed=%t, got=%t", tc.managed, rc.Managed())
+			}
+			if rc.Session != tc.session || rc.UID != tc.uid {
+				t.Errorf("expected session=%s uid=%d, got session=%s uid=%d",
+					tc.session, tc.uid, rc.Session, rc.UID)
+			}
+		})
+	}
+}
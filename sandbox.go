@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+// EntitlementAppSandbox is the entitlement of processes running in App Sandbox.
+const EntitlementAppSandbox = "com.apple.security.app-sandbox"
+
+// IsSandboxed reports whether the calling process is running in App Sandbox.
+// Sandboxed processes cannot write to /Library/LaunchDaemons or
+// ~/Library/LaunchAgents, nor bootstrap jobs with launchctl, thus libraries
+// should use SMAppService (see smapp package) instead.
+//
+// It always returns false on non-macOS platforms (including iOS).
+func IsSandboxed() bool {
+	return isSandboxed()
+}
+
+// HasEntitlement reports whether the calling process is signed with the
+// entitlement and its value is not false. Entitlements with non-boolean
+// values, like arrays of strings, are reported as present.
+//
+// It always returns false on non-macOS platforms (including iOS).
+func HasEntitlement(name string) bool {
+	return hasEntitlement(name)
+}
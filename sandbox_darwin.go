@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package launchd
+
+import (
+	"os"
+)
+
+// Os specific implementation of [IsSandboxed].
+func isSandboxed() bool {
+	// App Sandbox sets container id in environment of sandboxed processes,
+	// but it may be cleared by the process. Thus, check the entitlement too.
+	if os.Getenv("APP_SANDBOX_CONTAINER_ID") != "" {
+		return true
+	}
+	return hasEntitlement(EntitlementAppSandbox)
+}
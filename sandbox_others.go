@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+// Os specific implementation of [IsSandboxed].
+func isSandboxed() bool {
+	return false
+}
+
+// Os specific implementation of [HasEntitlement].
+func hasEntitlement(_ string) bool {
+	return false
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+func TestIsSandboxed(t *testing.T) {
+	t.Setenv("APP_SANDBOX_CONTAINER_ID", "")
+
+	// Test binaries are never signed with App Sandbox entitlement.
+	if launchd.IsSandboxed() {
+		t.Errorf("expected test binary to not be sandboxed")
+	}
+}
+
+func TestHasEntitlement(t *testing.T) {
+	tt := []string{
+		launchd.EntitlementAppSandbox,
+		"com.apple.security.network.server",
+		"",
+		"com.example\x00entitlement",
+	}
+	for _, name := range tt {
+		t.Run(name, func(t *testing.T) {
+			if launchd.HasEntitlement(name) {
+				t.Errorf("expected test binary to not have entitlement %q", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package smapp
+
+import (
+	"debug/macho"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// helperInfo is the subset of Info.plist keys relevant to SMJobBless.
+type helperInfo struct {
+	BundleIdentifier        string            `plist:"CFBundleIdentifier"`
+	SMPrivilegedExecutables map[string]string `plist:"SMPrivilegedExecutables"`
+	SMAuthorizedClients     []string          `plist:"SMAuthorizedClients"`
+}
+
+// sectionPlist decodes property list embedded in __TEXT segment of the
+// Mach-O binary. Both thin and universal binaries are supported.
+func sectionPlist(path, section string, v any) error {
+	var files []*macho.File
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer fat.Close()
+		for _, arch := range fat.Arches {
+			files = append(files, arch.File)
+		}
+	} else {
+		f, err := macho.Open(path)
+		if err != nil {
+			return fmt.Errorf("smapp: %s is not a valid Mach-O binary: %w", path, err)
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	for _, f := range files {
+		s := f.Section(section)
+		if s == nil || s.Seg != "__TEXT" {
+			continue
+		}
+		data, err := s.Data()
+		if err != nil {
+			return fmt.Errorf("smapp: failed to read section %s of %s: %w", section, path, err)
+		}
+		if err = plist.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("smapp: invalid plist in section %s of %s: %w", section, path, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("smapp: %s does not have an embedded %s section", path, section)
+}
+
+// mentionsIdentifier reports whether code signing requirement
+// refers to the given identifier.
+func mentionsIdentifier(requirement, identifier string) bool {
+	return strings.Contains(requirement, fmt.Sprintf("identifier %q", identifier)) ||
+		strings.Contains(requirement, "identifier "+identifier+" ")
+}
+
+// VerifyHelper verifies that the privileged helper tool with the given label
+// is correctly bundled within the application bundle at bundlePath, as
+// required by [Bless].
+//
+// This checks that:
+//
+//   - Helper tool exists at Contents/Library/LaunchServices/<label>.
+//   - Info.plist of the application lists the helper tool in
+//     SMPrivilegedExecutables with a requirement for its identifier.
+//   - Helper tool has an embedded Info.plist (__TEXT,__info_plist) with
+//     CFBundleIdentifier equal to the label and SMAuthorizedClients
+//     with a requirement for the application's identifier.
+//   - Helper tool has an embedded launchd.plist (__TEXT,__launchd_plist)
+//     with Label equal to the label.
+//
+// Code signatures themselves are not verified. This works on all platforms,
+// so that build pipelines can verify application bundles.
+func VerifyHelper(bundlePath, label string) error {
+	if label == "" {
+		return fmt.Errorf("smapp: label is empty")
+	}
+
+	var app helperInfo
+	infoPath := filepath.Join(bundlePath, "Contents", "Info.plist")
+	data, err := os.ReadFile(infoPath)
+	if err != nil {
+		return fmt.Errorf("smapp: failed to read application Info.plist: %w", err)
+	}
+
+	if err = plist.Unmarshal(data, &app); err != nil {
+		return fmt.Errorf("smapp: invalid application Info.plist: %w", err)
+	}
+
+	var errs error
+	requirement, ok := app.SMPrivilegedExecutables[label]
+	switch {
+	case !ok:
+		errs = errors.Join(errs, fmt.Errorf("smapp: %s is not listed in SMPrivilegedExecutables of %s",
+			label, infoPath))
+	case !mentionsIdentifier(requirement, label):
+		errs = errors.Join(errs, fmt.Errorf("smapp: SMPrivilegedExecutables requirement(%s) does not match identifier %s",
+			requirement, label))
+	}
+
+	helperPath := filepath.Join(bundlePath, "Contents", "Library", "LaunchServices", label)
+	if _, err = os.Stat(helperPath); err != nil {
+		return errors.Join(errs, fmt.Errorf("smapp: helper tool not found: %w", err))
+	}
+
+	var helper helperInfo
+	if err = sectionPlist(helperPath, "__info_plist", &helper); err != nil {
+		errs = errors.Join(errs, err)
+	} else {
+		if helper.BundleIdentifier != label {
+			errs = errors.Join(errs, fmt.Errorf("smapp: CFBundleIdentifier(%s) of helper tool does not match label %s",
+				helper.BundleIdentifier, label))
+		}
+
+		authorized := false
+		for _, client := range helper.SMAuthorizedClients {
+			if app.BundleIdentifier != "" && mentionsIdentifier(client, app.BundleIdentifier) {
+				authorized = true
+				break
+			}
+		}
+		if !authorized {
+			errs = errors.Join(errs, fmt.Errorf("smapp: SMAuthorizedClients of helper tool do not allow application %s",
+				app.BundleIdentifier))
+		}
+	}
+
+	var job plist.Job
+	if err = sectionPlist(helperPath, "__launchd_plist", &job); err != nil {
+		errs = errors.Join(errs, err)
+	} else if job.Label != label {
+		errs = errors.Join(errs, fmt.Errorf("smapp: Label(%s) of embedded launchd.plist does not match label %s",
+			job.Label, label))
+	}
+	return errs
+}
+
+// Bless installs the privileged helper tool with the given label using
+// SMJobBless. Helper tool must be bundled within the main application
+// bundle, see [VerifyHelper] for requirements, which are checked before
+// attempting installation.
+//
+// User is prompted for administrator credentials if required.
+// On macOS 13 and later, prefer [Daemon] with [Service.Register].
+//
+//   - [*Error] is returned if installation or authorization fails.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Bless(label string) error {
+	return bless(label)
+}
+
+// mainBundlePath returns path of the application bundle
+// containing the executable.
+func mainBundlePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("smapp: failed to get executable path: %w", err)
+	}
+
+	// Bundle executables are at <bundle>/Contents/MacOS/<name>.
+	dir := filepath.Dir(exe)
+	if filepath.Base(dir) != "MacOS" || filepath.Base(filepath.Dir(dir)) != "Contents" {
+		return "", fmt.Errorf("smapp: executable(%s) is not within an application bundle", exe)
+	}
+	return filepath.Dir(filepath.Dir(dir)), nil
+}
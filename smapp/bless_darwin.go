@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package smapp
+
+import (
+	"errors"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+func bless(label string) error {
+	bundle, err := mainBundlePath()
+	if err != nil {
+		return err
+	}
+
+	if err = VerifyHelper(bundle, label); err != nil {
+		return err
+	}
+
+	auth, err := macos.AuthorizationCreate(
+		[]string{macos.RightBlessPrivilegedHelper},
+		macos.AuthorizationFlagInteractionAllowed|
+			macos.AuthorizationFlagExtendRights|
+			macos.AuthorizationFlagPreAuthorize,
+	)
+	if err != nil {
+		var status macos.OSStatus
+		errors.As(err, &status)
+		return &Error{Op: "authorize", Code: int(status), Message: err.Error()}
+	}
+	defer macos.AuthorizationFree(auth, 0)
+
+	err = macos.SMJobBless(label, auth)
+	if err != nil {
+		var cferr *macos.CFError
+		if errors.As(err, &cferr) {
+			return &Error{Op: "bless", Code: cferr.Code, Message: cferr.Message}
+		}
+		return &Error{Op: "bless", Message: err.Error()}
+	}
+	return nil
+}
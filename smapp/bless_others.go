@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package smapp
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func bless(_ string) error {
+	return fmt.Errorf("smapp: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package smapp_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/smapp"
+)
+
+func TestVerifyHelper(t *testing.T) {
+	const label = "com.example.helper"
+	const info = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>com.example.app</string>
+	<key>SMPrivilegedExecutables</key>
+	<dict>
+		<key>com.example.helper</key>
+		<string>identifier "com.example.helper" and anchor apple generic</string>
+	</dict>
+</dict>
+</plist>
+`
+	tt := []struct {
+		name   string
+		info   string
+		helper []byte
+		expect string
+	}{
+		{
+			name:   "missing-info-plist",
+			expect: "failed to read application Info.plist",
+		},
+		{
+			name:   "not-listed",
+			info:   strings.ReplaceAll(info, "<key>com.example.helper</key>", "<key>com.example.other</key>"),
+			expect: "is not listed in SMPrivilegedExecutables",
+		},
+		{
+			name:   "helper-not-found",
+			info:   info,
+			expect: "helper tool not found",
+		},
+		{
+			name:   "helper-not-macho",
+			info:   info,
+			helper: []byte("#!/bin/sh\n"),
+			expect: "is not a valid Mach-O binary",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := filepath.Join(t.TempDir(), "Example.app")
+			helperDir := filepath.Join(bundle, "Contents", "Library", "LaunchServices")
+			if err := os.MkdirAll(helperDir, 0o755); err != nil {
+				t.Fatalf("failed to create bundle: %s", err)
+			}
+
+			if tc.info != "" {
+				err := os.WriteFile(filepath.Join(bundle, "Contents", "Info.plist"), []byte(tc.info), 0o644)
+				if err != nil {
+					t.Fatalf("failed to write Info.plist: %s", err)
+				}
+			}
+
+			if tc.helper != nil {
+				err := os.WriteFile(filepath.Join(helperDir, label), tc.helper, 0o755)
+				if err != nil {
+					t.Fatalf("failed to write helper: %s", err)
+				}
+			}
+
+			err := smapp.VerifyHelper(bundle, label)
+			if err == nil || !strings.Contains(err.Error(), tc.expect) {
+				t.Errorf("expected error containing %q, got=%v", tc.expect, err)
+			}
+		})
+	}
+}
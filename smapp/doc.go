@@ -0,0 +1,20 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package smapp provides bindings for [SMAppService] without using cgo.
+//
+// SMAppService is the recommended way to register login items, launch
+// agents and launch daemons bundled within an application on macOS 13
+// (Ventura) and later. Unlike manually writing plist files, services
+// registered with SMAppService are shown in System Settings and can be
+// approved or disabled by the user.
+//
+// Agents and daemons must be bundled within the application bundle at
+// Contents/Library/LaunchAgents and Contents/Library/LaunchDaemons
+// respectively, and the application must be signed.
+//
+// For older macOS versions, privileged helper tools can be installed
+// with [Bless], which uses deprecated SMJobBless API.
+//
+// [SMAppService]: https://developer.apple.com/documentation/servicemanagement/smappservice
+package smapp
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package smapp
+
+import (
+	"fmt"
+)
+
+// Status is the registration status of the service.
+type Status int
+
+const (
+	// StatusNotRegistered indicates that service has not been registered
+	// or has been unregistered.
+	StatusNotRegistered Status = 0
+
+	// StatusEnabled indicates that service has been registered
+	// and is eligible to run.
+	StatusEnabled Status = 1
+
+	// StatusRequiresApproval indicates that service has been registered,
+	// but user must approve it in System Settings before it can run.
+	StatusRequiresApproval Status = 2
+
+	// StatusNotFound indicates that service could not be found.
+	StatusNotFound Status = 3
+)
+
+// String implements [fmt.Stringer].
+func (s Status) String() string {
+	switch s {
+	case StatusNotRegistered:
+		return "not-registered"
+	case StatusEnabled:
+		return "enabled"
+	case StatusRequiresApproval:
+		return "requires-approval"
+	case StatusNotFound:
+		return "not-found"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// kind is the kind of the service.
+type kind int
+
+const (
+	kindMainApp kind = iota
+	kindLoginItem
+	kindAgent
+	kindDaemon
+)
+
+// Service is a service which can be registered with SMAppService.
+type Service struct {
+	kind kind
+	name string
+}
+
+// MainApp returns the service for the main application, which registers
+// the application itself as a login item.
+func MainApp() *Service {
+	return &Service{kind: kindMainApp}
+}
+
+// LoginItem returns the login item service with the given bundle identifier.
+// Login item must be bundled at Contents/Library/LoginItems.
+func LoginItem(identifier string) *Service {
+	return &Service{kind: kindLoginItem, name: identifier}
+}
+
+// Agent returns the launch agent service with the given plist name, for
+// example "com.example.agent.plist". Plist file must be bundled at
+// Contents/Library/LaunchAgents.
+func Agent(plistName string) *Service {
+	return &Service{kind: kindAgent, name: plistName}
+}
+
+// Daemon returns the launch daemon service with the given plist name, for
+// example "com.example.daemon.plist". Plist file must be bundled at
+// Contents/Library/LaunchDaemons.
+func Daemon(plistName string) *Service {
+	return &Service{kind: kindDaemon, name: plistName}
+}
+
+// Register registers the service so that it can begin launching.
+//
+//   - [*Error] is returned if registration fails.
+//   - [syscall.ENOTSUP] is returned on macOS versions prior to 13
+//     and on non-macOS platforms (including iOS).
+func (s *Service) Register() error {
+	return s.register()
+}
+
+// Unregister unregisters the service so that it no longer launches.
+//
+//   - [*Error] is returned if un-registration fails.
+//   - [syscall.ENOTSUP] is returned on macOS versions prior to 13
+//     and on non-macOS platforms (including iOS).
+func (s *Service) Unregister() error {
+	return s.unregister()
+}
+
+// Status returns the registration status of the service.
+//
+//   - [syscall.ENOTSUP] is returned on macOS versions prior to 13
+//     and on non-macOS platforms (including iOS).
+func (s *Service) Status() (Status, error) {
+	return s.status()
+}
+
+// OpenSystemSettingsLoginItems opens Login Items panel of System Settings,
+// so that user can approve services with [StatusRequiresApproval].
+//
+//   - [syscall.ENOTSUP] is returned on macOS versions prior to 13
+//     and on non-macOS platforms (including iOS).
+func OpenSystemSettingsLoginItems() error {
+	return openSystemSettingsLoginItems()
+}
+
+// Error codes returned by ServiceManagement framework.
+//
+// See https://developer.apple.com/documentation/servicemanagement/1431088-service_management_errors
+const (
+	ErrCodeInternalFailure     = 2
+	ErrCodeInvalidSignature    = 3
+	ErrCodeAuthorizationFailed = 4
+	ErrCodeToolNotValid        = 5
+	ErrCodeJobNotFound         = 6
+	ErrCodeServiceUnavailable  = 7
+	ErrCodeJobPlistNotFound    = 8
+	ErrCodeJobMustBeEnabled    = 9
+	ErrCodeInvalidPlist        = 10
+	ErrCodeLaunchDeniedByUser  = 11
+	ErrCodeAlreadyRegistered   = 12
+)
+
+// Error is an error returned by ServiceManagement framework.
+type Error struct {
+	// Op is the operation which failed, like "register".
+	Op string
+
+	// Code is the error code, one of ErrCode* constants.
+	Code int
+
+	// Message is the localized description of the error.
+	Message string
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("smapp: %s failed(code=%d): %s", e.Op, e.Code, e.Message)
+}
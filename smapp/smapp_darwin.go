@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios
+
+package smapp
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// Ensure ServiceManagement framework is loaded, so that
+// SMAppService class is registered with Objective-C runtime.
+//
+//go:cgo_import_dynamic _ _ "/System/Library/Frameworks/ServiceManagement.framework/Versions/A/ServiceManagement"
+
+// class returns SMAppService class or an error if it is not available.
+func class() (macos.ID, error) {
+	cls := macos.Class("SMAppService")
+	if cls == 0 {
+		return 0, fmt.Errorf("smapp: SMAppService requires macOS 13 or later: %w", syscall.ENOTSUP)
+	}
+	return cls, nil
+}
+
+// object returns autoreleased SMAppService instance for the service.
+// This must be called within an autorelease pool.
+func (s *Service) object(cls macos.ID) (macos.ID, error) {
+	if s.kind == kindMainApp {
+		return macos.ID(cls.Send("mainAppService")), nil
+	}
+
+	name := macos.NSString(s.name)
+	if name == 0 {
+		return 0, fmt.Errorf("smapp: invalid service name(%q): %w", s.name, syscall.EINVAL)
+	}
+	defer macos.Release(name)
+
+	var selector string
+	switch s.kind {
+	case kindLoginItem:
+		selector = "loginItemServiceWithIdentifier:"
+	case kindAgent:
+		selector = "agentServiceWithPlistName:"
+	default:
+		selector = "daemonServiceWithPlistName:"
+	}
+	return macos.ID(cls.Send(selector, uintptr(name))), nil
+}
+
+// call sends a message of the form "-(BOOL)xxxAndReturnError:(NSError**)".
+func (s *Service) call(op, selector string) error {
+	cls, err := class()
+	if err != nil {
+		return err
+	}
+
+	macos.WithAutoreleasePool(func() {
+		var obj macos.ID
+		obj, err = s.object(cls)
+		if err != nil {
+			return
+		}
+
+		// Pin go pointer passed to Objective-C runtime.
+		var nserr macos.ID
+		var pinner runtime.Pinner
+		pinner.Pin(&nserr)
+		defer pinner.Unpin()
+
+		ok := obj.Send(selector, uintptr(unsafe.Pointer(&nserr)))
+		if ok&0xff == 0 {
+			err = &Error{
+				Op:      op,
+				Code:    int(nserr.Send("code")),
+				Message: macos.GoStringFromNSString(macos.ID(nserr.Send("localizedDescription"))),
+			}
+		}
+	})
+	return err
+}
+
+func (s *Service) register() error {
+	return s.call("register", "registerAndReturnError:")
+}
+
+func (s *Service) unregister() error {
+	return s.call("unregister", "unregisterAndReturnError:")
+}
+
+func (s *Service) status() (Status, error) {
+	cls, err := class()
+	if err != nil {
+		return 0, err
+	}
+
+	var status Status
+	macos.WithAutoreleasePool(func() {
+		var obj macos.ID
+		obj, err = s.object(cls)
+		if err != nil {
+			return
+		}
+		status = Status(obj.Send("status"))
+	})
+	return status, err
+}
+
+func openSystemSettingsLoginItems() error {
+	cls, err := class()
+	if err != nil {
+		return err
+	}
+	macos.WithAutoreleasePool(func() {
+		cls.Send("openSystemSettingsLoginItems")
+	})
+	return nil
+}
@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package smapp
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func (s *Service) register() error {
+	return fmt.Errorf("smapp: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+func (s *Service) unregister() error {
+	return fmt.Errorf("smapp: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+func (s *Service) status() (Status, error) {
+	return StatusNotFound, fmt.Errorf("smapp: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+func openSystemSettingsLoginItems() error {
+	return fmt.Errorf("smapp: only supported on macOS: %w", syscall.ENOTSUP)
+}
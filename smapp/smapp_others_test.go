@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package smapp_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/smapp"
+)
+
+func TestUnsupported(t *testing.T) {
+	services := []*smapp.Service{
+		smapp.MainApp(),
+		smapp.LoginItem("com.example.login-item"),
+		smapp.Agent("com.example.agent.plist"),
+		smapp.Daemon("com.example.daemon.plist"),
+	}
+	for _, svc := range services {
+		if err := svc.Register(); !errors.Is(err, syscall.ENOTSUP) {
+			t.Errorf("Register expected error=%s, got=%s", syscall.ENOTSUP, err)
+		}
+		if err := svc.Unregister(); !errors.Is(err, syscall.ENOTSUP) {
+			t.Errorf("Unregister expected error=%s, got=%s", syscall.ENOTSUP, err)
+		}
+		if _, err := svc.Status(); !errors.Is(err, syscall.ENOTSUP) {
+			t.Errorf("Status expected error=%s, got=%s", syscall.ENOTSUP, err)
+		}
+	}
+	if err := smapp.OpenSystemSettingsLoginItems(); !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("OpenSystemSettingsLoginItems expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+	if err := smapp.Bless("com.example.helper"); !errors.Is(err, syscall.ENOTSUP) {
+		t.Errorf("Bless expected error=%s, got=%s", syscall.ENOTSUP, err)
+	}
+}
@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Socket is an activated socket file descriptor, along with its type,
+// address family and local address, which are determined once during
+// activation and shared by listeners built from it.
+type Socket struct {
+	// Name is the name of the socket in the Sockets dictionary of the job.
+	Name string
+
+	// File is the file backed by the activated file descriptor.
+	File *os.File
+
+	// Type is the socket type, like [syscall.SOCK_STREAM] or [syscall.SOCK_DGRAM].
+	Type int
+
+	// Family is the address family, like [syscall.AF_INET], [syscall.AF_INET6]
+	// or [syscall.AF_UNIX].
+	Family int
+
+	// Addr is the local address of the socket. It is a [*net.TCPAddr],
+	// [*net.UDPAddr] or [*net.UnixAddr] depending on Type and Family.
+	Addr net.Addr
+
+	// Protocol is the protocol of internet sockets, like [syscall.IPPROTO_TCP]
+	// or [syscall.IPPROTO_UDP]. macOS does not support SO_PROTOCOL, thus this
+	// is inferred from Type and not read from the socket: it is
+	// [syscall.IPPROTO_TCP] for stream sockets and [syscall.IPPROTO_UDP]
+	// for datagram sockets, regardless of SockProtocol. This is zero for
+	// other socket types and for unix domain sockets.
+	Protocol int
+
+	// Passive reports whether the socket is a passive socket, as configured
+	// with SockPassive. Passive stream sockets are listening sockets and
+	// passive datagram sockets are not connected. Sockets which are not
+	// passive are connected by launchd and cannot be used as listeners.
+	Passive bool
+
+	// Index is the position of the file descriptor among file descriptors
+	// activated for the name, in the order returned by launchd. A single
+	// name maps to multiple file descriptors, for example when socket
+	// listens on both IPv4 and IPv6 or when the Sockets dictionary has
+	// an array of entries for the name.
+	Index int
+
+	// Entry is the entry in the Sockets dictionary of the job which
+	// produced the socket, as determined by [Annotate]. This is nil
+	// if socket has not been annotated or if no entry matched.
+	Entry *plist.Socket
+}
+
+// Matches reports whether the socket could have been produced by the entry
+// of the Sockets dictionary of the job, by comparing type, family, protocol,
+// passive flag and address of the socket with SockType, SockFamily,
+// SockProtocol, SockPassive, SockPathName, SockNodeName and SockServiceName
+// of the entry. SockNodeName is only compared if it is an IP address, as
+// host names are not resolved. Addresses are not compared for sockets which
+// are not passive, as they refer to the remote address.
+func (s Socket) Matches(entry plist.Socket) bool {
+	stype := syscall.SOCK_STREAM
+	switch entry.SockType {
+	case "", "stream":
+	case "dgram":
+		stype = syscall.SOCK_DGRAM
+	case "seqpacket":
+		stype = syscall.SOCK_SEQPACKET
+	default:
+		return false
+	}
+	if s.Type != stype {
+		return false
+	}
+
+	passive := entry.SockPassive == nil || *entry.SockPassive
+	if s.Passive != passive {
+		return false
+	}
+
+	if entry.SockPathName != "" {
+		addr, ok := s.Addr.(*net.UnixAddr)
+		return s.Family == syscall.AF_UNIX && (!s.Passive || ok && addr.Name == entry.SockPathName)
+	}
+
+	switch {
+	case s.Family != syscall.AF_INET && s.Family != syscall.AF_INET6:
+		return false
+	case entry.SockFamily == "IPv4" && s.Family != syscall.AF_INET:
+		return false
+	case entry.SockFamily == "IPv6" && s.Family != syscall.AF_INET6:
+		return false
+	case entry.SockProtocol == "TCP" && s.Protocol != syscall.IPPROTO_TCP:
+		return false
+	case entry.SockProtocol == "UDP" && s.Protocol != syscall.IPPROTO_UDP:
+		return false
+	}
+
+	if !s.Passive {
+		return true
+	}
+
+	var ip net.IP
+	var port int
+	network := "tcp"
+	switch addr := s.Addr.(type) {
+	case *net.TCPAddr:
+		ip, port = addr.IP, addr.Port
+	case *net.UDPAddr:
+		ip, port, network = addr.IP, addr.Port, "udp"
+	default:
+		return false
+	}
+
+	if entry.SockServiceName != "" {
+		want, err := net.LookupPort(network, entry.SockServiceName)
+		if err != nil || want != port {
+			return false
+		}
+	}
+
+	if entry.SockNodeName != "" {
+		node, _, _ := strings.Cut(entry.SockNodeName, "%")
+		if want := net.ParseIP(node); want != nil && !want.Equal(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Annotate sets [Socket.Entry] of each of the sockets to the first of the
+// entries it [Socket.Matches]. Entries are typically the entry or entries of
+// the Sockets dictionary of the job for the name used to activate sockets,
+// as returned by [plist.Socket.Entries]. This is useful for logging and
+// applying policies per entry, when a single name maps to multiple file
+// descriptors. Sockets which do not match any of the entries are left as is.
+// Use [AnnotateJob] to resolve entries from the job definition.
+func Annotate(sockets []Socket, entries ...plist.Socket) {
+	for i := range sockets {
+		for j := range entries {
+			if sockets[i].Matches(entries[j]) {
+				entry := entries[j]
+				sockets[i].Entry = &entry
+				break
+			}
+		}
+	}
+}
+
+// AnnotateJob is like [Annotate], but entries for each of the sockets are
+// resolved from the Sockets dictionary of the job, by [Socket.Name].
+// Sockets whose name is not in the Sockets dictionary are left as is.
+func AnnotateJob(sockets []Socket, job *plist.Job) {
+	if job == nil {
+		return
+	}
+	for i := range sockets {
+		if entry, ok := job.Sockets[sockets[i].Name]; ok {
+			Annotate(sockets[i:i+1], entry.Entries()...)
+		}
+	}
+}
+
+// Listener returns a [net.Listener] for the passive stream socket.
+// Closing the listener does not close [Socket.File].
+//
+//   - [syscall.ESOCKTNOSUPPORT] is returned if socket is not a stream socket.
+//   - [syscall.EISCONN] is returned if socket is not passive.
+func (s Socket) Listener() (net.Listener, error) {
+	if s.Type != syscall.SOCK_STREAM {
+		return nil, fmt.Errorf("%s: %w", s.Name, syscall.ESOCKTNOSUPPORT)
+	}
+	if !s.Passive {
+		return nil, fmt.Errorf("%s: socket is not passive: %w", s.Name, syscall.EISCONN)
+	}
+	return net.FileListener(s.File)
+}
+
+// PacketConn returns a [net.PacketConn] for the datagram socket.
+// Closing the packet connection does not close [Socket.File].
+//
+//   - [syscall.ESOCKTNOSUPPORT] is returned if socket is not a datagram socket.
+func (s Socket) PacketConn() (net.PacketConn, error) {
+	if s.Type != syscall.SOCK_DGRAM {
+		return nil, fmt.Errorf("%s: %w", s.Name, syscall.ESOCKTNOSUPPORT)
+	}
+	return net.FilePacketConn(s.File)
+}
+
+// Conn returns a [net.Conn] for the socket which is not passive, i.e. a socket
+// connected by launchd. Closing the connection does not close [Socket.File].
+//
+//   - [syscall.ENOTCONN] is returned if socket is passive.
+func (s Socket) Conn() (net.Conn, error) {
+	if s.Passive {
+		return nil, fmt.Errorf("%s: socket is passive: %w", s.Name, syscall.ENOTCONN)
+	}
+	return net.FileConn(s.File)
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+var (
+	tcp4Socket = Socket{
+		Name:     "Listeners",
+		Type:     syscall.SOCK_STREAM,
+		Family:   syscall.AF_INET,
+		Protocol: syscall.IPPROTO_TCP,
+		Passive:  true,
+		Addr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080},
+	}
+	tcp6Socket = Socket{
+		Name:     "Listeners",
+		Type:     syscall.SOCK_STREAM,
+		Family:   syscall.AF_INET6,
+		Protocol: syscall.IPPROTO_TCP,
+		Passive:  true,
+		Addr:     &net.TCPAddr{IP: net.IPv6unspecified, Port: 8080},
+		Index:    1,
+	}
+	udpSocket = Socket{
+		Name:     "Listeners",
+		Type:     syscall.SOCK_DGRAM,
+		Family:   syscall.AF_INET,
+		Protocol: syscall.IPPROTO_UDP,
+		Passive:  true,
+		Addr:     &net.UDPAddr{IP: net.IPv4zero, Port: 53},
+	}
+	unixSocket = Socket{
+		Name:    "Listeners",
+		Type:    syscall.SOCK_STREAM,
+		Family:  syscall.AF_UNIX,
+		Passive: true,
+		Addr:    &net.UnixAddr{Name: "/var/run/svc.sock", Net: "unix"},
+	}
+)
+
+func TestSocket_Matches(t *testing.T) {
+	passive, active := true, false
+	connectedSocket := tcp4Socket
+	connectedSocket.Passive = false
+	tt := []struct {
+		name   string
+		socket Socket
+		entry  plist.Socket
+		expect bool
+	}{
+		{name: "tcp4-port", socket: tcp4Socket, entry: plist.Socket{SockServiceName: "8080"}, expect: true},
+		{name: "tcp4-node", socket: tcp4Socket, entry: plist.Socket{SockNodeName: "127.0.0.1", SockServiceName: "8080"}, expect: true},
+		{name: "tcp4-node-mismatch", socket: tcp4Socket, entry: plist.Socket{SockNodeName: "::1", SockServiceName: "8080"}},
+		{name: "tcp4-hostname", socket: tcp4Socket, entry: plist.Socket{SockNodeName: "localhost", SockServiceName: "8080"}, expect: true},
+		{name: "tcp4-port-mismatch", socket: tcp4Socket, entry: plist.Socket{SockServiceName: "8081"}},
+		{name: "tcp4-family-ipv6", socket: tcp4Socket, entry: plist.Socket{SockFamily: "IPv6", SockServiceName: "8080"}},
+		{name: "tcp4-family-ipv4v6", socket: tcp4Socket, entry: plist.Socket{SockFamily: "IPv4v6", SockServiceName: "8080"}, expect: true},
+		{name: "tcp4-dgram", socket: tcp4Socket, entry: plist.Socket{SockType: "dgram", SockServiceName: "8080"}},
+		{name: "tcp4-unix", socket: tcp4Socket, entry: plist.Socket{SockPathName: "/var/run/svc.sock"}},
+		{name: "tcp4-protocol", socket: tcp4Socket, entry: plist.Socket{SockProtocol: "TCP", SockServiceName: "8080"}, expect: true},
+		{name: "tcp4-protocol-udp", socket: tcp4Socket, entry: plist.Socket{SockProtocol: "UDP", SockServiceName: "8080"}},
+		{name: "tcp4-passive", socket: tcp4Socket, entry: plist.Socket{SockPassive: &passive, SockServiceName: "8080"}, expect: true},
+		{name: "tcp4-active", socket: tcp4Socket, entry: plist.Socket{SockPassive: &active, SockServiceName: "8080"}},
+		{name: "tcp4-connected", socket: connectedSocket, entry: plist.Socket{SockPassive: &active, SockNodeName: "127.0.0.1", SockServiceName: "8080"}, expect: true},
+		{name: "tcp4-connected-passive", socket: connectedSocket, entry: plist.Socket{SockServiceName: "8080"}},
+		{name: "tcp6", socket: tcp6Socket, entry: plist.Socket{SockFamily: "IPv6", SockServiceName: "8080"}, expect: true},
+		{name: "udp", socket: udpSocket, entry: plist.Socket{SockType: "dgram", SockServiceName: "53"}, expect: true},
+		{name: "udp-stream", socket: udpSocket, entry: plist.Socket{SockServiceName: "53"}},
+		{name: "unix", socket: unixSocket, entry: plist.Socket{SockPathName: "/var/run/svc.sock"}, expect: true},
+		{name: "unix-path-mismatch", socket: unixSocket, entry: plist.Socket{SockPathName: "/var/run/other.sock"}},
+		{name: "unix-inet", socket: unixSocket, entry: plist.Socket{SockServiceName: "8080"}},
+		{name: "invalid-type", socket: tcp4Socket, entry: plist.Socket{SockType: "raw", SockServiceName: "8080"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.socket.Matches(tc.entry); got != tc.expect {
+				t.Errorf("expected match=%t, got=%t", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	entries := []plist.Socket{
+		{SockPathName: "/var/run/svc.sock"},
+		{SockFamily: "IPv4", SockServiceName: "8080"},
+		{SockFamily: "IPv6", SockServiceName: "8080"},
+	}
+	sockets := []Socket{tcp4Socket, tcp6Socket, udpSocket, unixSocket}
+	Annotate(sockets, entries...)
+
+	expect := []*plist.Socket{&entries[1], &entries[2], nil, &entries[0]}
+	for i, s := range sockets {
+		switch {
+		case expect[i] == nil && s.Entry != nil:
+			t.Errorf("socket[%d]: expected no entry, got=%+v", i, *s.Entry)
+		case expect[i] != nil && s.Entry == nil:
+			t.Errorf("socket[%d]: expected entry=%+v, got=nil", i, *expect[i])
+		case expect[i] != nil && s.Entry.SockFamily != expect[i].SockFamily:
+			t.Errorf("socket[%d]: expected entry=%+v, got=%+v", i, *expect[i], *s.Entry)
+		}
+	}
+}
+
+func TestAnnotateJob(t *testing.T) {
+	job := &plist.Job{
+		Label: "com.example.svc",
+		Sockets: map[string]plist.Socket{
+			"Listeners": {
+				SockPathName: "/var/run/svc.sock",
+				Additional: []plist.Socket{
+					{SockFamily: "IPv4", SockServiceName: "8080"},
+					{SockFamily: "IPv6", SockServiceName: "8080"},
+				},
+			},
+		},
+	}
+	other := tcp4Socket
+	other.Name = "Other"
+	sockets := []Socket{tcp4Socket, tcp6Socket, udpSocket, unixSocket, other}
+	AnnotateJob(sockets, job)
+
+	// Socket named "Other" is not in the Sockets dictionary.
+	expect := []string{"IPv4", "IPv6", "", "/var/run/svc.sock", ""}
+	for i, s := range sockets {
+		var got string
+		if s.Entry != nil {
+			got = s.Entry.SockFamily + s.Entry.SockPathName
+		}
+		if got != expect[i] {
+			t.Errorf("socket[%d]: expected entry=%q, got=%q", i, expect[i], got)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// newSocket classifies the socket file in a single pass, by determining
+// its type with getsockopt(SO_TYPE), its family and local address with
+// getsockname and whether it is passive with getsockopt(SO_ACCEPTCONN)
+// or getpeername, so that they need not be queried again when building
+// listeners or packet connections.
+func newSocket(name string, file *os.File) (Socket, error) {
+	fd := int(file.Fd())
+	stype, err := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_TYPE)
+	debug("launchd: getsockopt SO_TYPE",
+		slog.String("name", name),
+		slog.Int("fd", fd),
+		slog.Int("type", stype),
+		slog.Any("err", err),
+	)
+	if err != nil {
+		return Socket{}, os.NewSyscallError("getsockopt", err)
+	}
+
+	sa, err := syscall.Getsockname(fd)
+	if err != nil {
+		return Socket{}, os.NewSyscallError("getsockname", err)
+	}
+
+	s := Socket{Name: name, File: file, Type: stype}
+	s.Passive, err = passive(fd, stype)
+	if err != nil {
+		return Socket{}, err
+	}
+
+	switch sa := sa.(type) {
+	case *syscall.SockaddrInet4:
+		s.Family = syscall.AF_INET
+		s.Protocol = inetProtocol(stype)
+		s.Addr = inetAddr(stype, sa.Addr[:], sa.Port, "")
+	case *syscall.SockaddrInet6:
+		s.Family = syscall.AF_INET6
+		s.Protocol = inetProtocol(stype)
+		var zone string
+		if sa.ZoneId != 0 {
+			zone = strconv.FormatUint(uint64(sa.ZoneId), 10)
+			if ifi, err := net.InterfaceByIndex(int(sa.ZoneId)); err == nil {
+				zone = ifi.Name
+			}
+		}
+		s.Addr = inetAddr(stype, sa.Addr[:], sa.Port, zone)
+	case *syscall.SockaddrUnix:
+		s.Family = syscall.AF_UNIX
+		s.Addr = &net.UnixAddr{Name: sa.Name, Net: unixNetwork(stype)}
+	}
+	return s, nil
+}
+
+// passive reports whether the socket is passive. Stream and seqpacket sockets
+// are passive if they are listening. Datagram sockets are passive if they
+// are not connected.
+func passive(fd, stype int) (bool, error) {
+	if stype == syscall.SOCK_STREAM || stype == syscall.SOCK_SEQPACKET {
+		accept, err := syscall.GetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_ACCEPTCONN)
+		if err != nil {
+			return false, os.NewSyscallError("getsockopt", err)
+		}
+		return accept != 0, nil
+	}
+
+	_, err := syscall.Getpeername(fd)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, syscall.ENOTCONN):
+		return true, nil
+	default:
+		return false, os.NewSyscallError("getpeername", err)
+	}
+}
+
+// inetProtocol returns protocol of the internet socket type. Protocol is
+// inferred, as getsockopt(SO_PROTOCOL) is not supported on macOS.
+func inetProtocol(stype int) int {
+	switch stype {
+	case syscall.SOCK_STREAM:
+		return syscall.IPPROTO_TCP
+	case syscall.SOCK_DGRAM:
+		return syscall.IPPROTO_UDP
+	default:
+		return 0
+	}
+}
+
+// inetAddr returns [*net.TCPAddr] or [*net.UDPAddr] depending on socket type.
+func inetAddr(stype int, ip []byte, port int, zone string) net.Addr {
+	addr := net.IP(append([]byte(nil), ip...))
+	if stype == syscall.SOCK_DGRAM {
+		return &net.UDPAddr{IP: addr, Port: port, Zone: zone}
+	}
+	return &net.TCPAddr{IP: addr, Port: port, Zone: zone}
+}
+
+// unixNetwork returns network name of the unix socket type.
+func unixNetwork(stype int) string {
+	switch stype {
+	case syscall.SOCK_DGRAM:
+		return "unixgram"
+	case syscall.SOCK_SEQPACKET:
+		return "unixpacket"
+	default:
+		return "unix"
+	}
+}
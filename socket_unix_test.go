@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// fileCloser is implemented by listeners and packet conns of the net package.
+type fileCloser interface {
+	io.Closer
+	File() (*os.File, error)
+}
+
+func TestNewSocket(t *testing.T) {
+	dir := t.TempDir()
+	tt := []struct {
+		name    string
+		network string
+		addr    string
+		stype   int
+		family  int
+	}{
+		{name: "TCP4", network: "tcp4", addr: "127.0.0.1:0", stype: syscall.SOCK_STREAM, family: syscall.AF_INET},
+		{name: "TCP6", network: "tcp6", addr: "[::1]:0", stype: syscall.SOCK_STREAM, family: syscall.AF_INET6},
+		{name: "UDP4", network: "udp4", addr: "127.0.0.1:0", stype: syscall.SOCK_DGRAM, family: syscall.AF_INET},
+		{name: "Unix", network: "unix", addr: filepath.Join(dir, "s.sock"), stype: syscall.SOCK_STREAM, family: syscall.AF_UNIX},
+		{name: "Unixgram", network: "unixgram", addr: filepath.Join(dir, "d.sock"), stype: syscall.SOCK_DGRAM, family: syscall.AF_UNIX},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var c fileCloser
+			var addr net.Addr
+			if tc.stype == syscall.SOCK_STREAM {
+				l, err := net.Listen(tc.network, tc.addr)
+				if err != nil {
+					t.Skipf("failed to listen on %s: %s", tc.addr, err)
+				}
+				c, addr = l.(fileCloser), l.Addr()
+			} else {
+				l, err := net.ListenPacket(tc.network, tc.addr)
+				if err != nil {
+					t.Skipf("failed to listen on %s: %s", tc.addr, err)
+				}
+				c, addr = l.(fileCloser), l.LocalAddr()
+			}
+			defer c.Close()
+
+			file, err := c.File()
+			if err != nil {
+				t.Fatalf("failed to get file: %s", err)
+			}
+			defer file.Close()
+
+			s, err := newSocket(tc.name, file)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			if s.Type != tc.stype {
+				t.Errorf("expected type=%d, got=%d", tc.stype, s.Type)
+			}
+			if s.Family != tc.family {
+				t.Errorf("expected family=%d, got=%d", tc.family, s.Family)
+			}
+			if s.Addr == nil || s.Addr.String() != addr.String() || s.Addr.Network() != addr.Network() {
+				t.Errorf("expected addr=%s(%s), got=%v", addr, addr.Network(), s.Addr)
+			}
+
+			// Build listener of the matching type and check mismatched type.
+			if tc.stype == syscall.SOCK_STREAM {
+				l, err := s.Listener()
+				if err != nil {
+					t.Fatalf("expected no error building listener, got=%s", err)
+				}
+				l.Close()
+				if _, err = s.PacketConn(); !errors.Is(err, syscall.ESOCKTNOSUPPORT) {
+					t.Errorf("expected error=%s, got=%s", syscall.ESOCKTNOSUPPORT, err)
+				}
+			} else {
+				l, err := s.PacketConn()
+				if err != nil {
+					t.Fatalf("expected no error building packet conn, got=%s", err)
+				}
+				l.Close()
+				if _, err = s.Listener(); !errors.Is(err, syscall.ESOCKTNOSUPPORT) {
+					t.Errorf("expected error=%s, got=%s", syscall.ESOCKTNOSUPPORT, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSocket_NotSocket(t *testing.T) {
+	file, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("failed to open %s: %s", os.DevNull, err)
+	}
+	defer file.Close()
+
+	if _, err = newSocket("devnull", file); !errors.Is(err, syscall.ENOTSOCK) {
+		t.Errorf("expected error=%s, got=%s", syscall.ENOTSOCK, err)
+	}
+}
+
+func TestNewSocket_Passive(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	conn, err := net.Dial("tcp4", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer conn.Close()
+
+	p, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("failed to listen: %s", err)
+	}
+	defer p.Close()
+
+	u, err := net.Dial("udp4", p.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer u.Close()
+
+	tt := []struct {
+		name     string
+		c        fileCloser
+		passive  bool
+		protocol int
+	}{
+		{name: "TCP-Listener", c: l.(fileCloser), passive: true, protocol: syscall.IPPROTO_TCP},
+		{name: "TCP-Conn", c: conn.(fileCloser), protocol: syscall.IPPROTO_TCP},
+		{name: "UDP-Unconnected", c: p.(fileCloser), passive: true, protocol: syscall.IPPROTO_UDP},
+		{name: "UDP-Connected", c: u.(fileCloser), protocol: syscall.IPPROTO_UDP},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := tc.c.File()
+			if err != nil {
+				t.Fatalf("failed to get file: %s", err)
+			}
+			defer file.Close()
+
+			s, err := newSocket(tc.name, file)
+			if err != nil {
+				t.Fatalf("expected no error, got=%s", err)
+			}
+			if s.Passive != tc.passive {
+				t.Errorf("expected passive=%t, got=%t", tc.passive, s.Passive)
+			}
+			if s.Protocol != tc.protocol {
+				t.Errorf("expected protocol=%d, got=%d", tc.protocol, s.Protocol)
+			}
+			if s.Type == syscall.SOCK_STREAM && !tc.passive {
+				if _, err = s.Listener(); !errors.Is(err, syscall.EISCONN) {
+					t.Errorf("expected error=%s, got=%s", syscall.EISCONN, err)
+				}
+			}
+
+			c, err := s.Conn()
+			if tc.passive {
+				if !errors.Is(err, syscall.ENOTCONN) {
+					t.Errorf("expected error=%s, got=%s", syscall.ENOTCONN, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error building conn, got=%s", err)
+			}
+			c.Close()
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Conventional names of sockets in the Sockets dictionary of the job.
+// launchd does not assign any meaning to socket names, but using these
+// allows tools and teams to converge on consistent naming.
+const (
+	// SocketListeners is the name used by Xcode templates and many
+	// existing launchd jobs for their only socket.
+	SocketListeners = "Listeners"
+
+	// SocketHTTP is the name of a socket serving plaintext HTTP.
+	SocketHTTP = "http"
+
+	// SocketHTTPS is the name of a socket serving HTTP over TLS.
+	SocketHTTPS = "https"
+
+	// SocketGRPC is the name of a socket serving gRPC.
+	SocketGRPC = "grpc"
+
+	// SocketMetrics is the name of a socket serving metrics, for example
+	// for Prometheus.
+	SocketMetrics = "metrics"
+
+	// SocketAdmin is the name of a socket serving administrative or
+	// debugging endpoints, typically a unix socket.
+	SocketAdmin = "admin"
+)
+
+// ValidateSocketName checks if name can be used as the name of a socket in the
+// Sockets dictionary of the job and with [Open] and related functions.
+// Name must be non-empty valid UTF-8, without leading or trailing white space,
+// and must not contain control characters, including NUL, which cannot be
+// passed to launch_activate_socket. Name must also not contain ":", which is
+// used as the separator of LISTEN_FDNAMES by [PassToChild].
+//
+//   - [syscall.EINVAL] is returned if name is invalid.
+func ValidateSocketName(name string) error {
+	var reason string
+	switch {
+	case name == "":
+		reason = "name is empty"
+	case !utf8.ValidString(name):
+		reason = "name is not valid UTF-8"
+	case strings.TrimSpace(name) != name:
+		reason = "name has leading or trailing white space"
+	case strings.IndexFunc(name, unicode.IsControl) >= 0:
+		reason = "name contains control characters"
+	case strings.Contains(name, ":"):
+		reason = `name contains ":"`
+	default:
+		return nil
+	}
+	return fmt.Errorf("launchd: invalid socket name(%q), %s: %w", name, reason, syscall.EINVAL)
+}
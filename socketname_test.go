@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+func TestValidateSocketName(t *testing.T) {
+	tt := []struct {
+		name   string
+		socket string
+		expect error
+	}{
+		{name: "Listeners", socket: launchd.SocketListeners},
+		{name: "HTTP", socket: launchd.SocketHTTP},
+		{name: "HTTPS", socket: launchd.SocketHTTPS},
+		{name: "GRPC", socket: launchd.SocketGRPC},
+		{name: "Metrics", socket: launchd.SocketMetrics},
+		{name: "Admin", socket: launchd.SocketAdmin},
+		{name: "Unicode", socket: "café"},
+		{name: "Dots", socket: "com.example.http-alt"},
+		{name: "Empty", socket: "", expect: syscall.EINVAL},
+		{name: "InvalidUTF8", socket: "http\xff", expect: syscall.EINVAL},
+		{name: "Space", socket: " http", expect: syscall.EINVAL},
+		{name: "NUL", socket: "ht\x00tp", expect: syscall.EINVAL},
+		{name: "Newline", socket: "ht\ntp", expect: syscall.EINVAL},
+		{name: "Colon", socket: "http:alt", expect: syscall.EINVAL},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := launchd.ValidateSocketName(tc.socket)
+			if !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%v, got=%v", tc.expect, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// ErrInsecureSocketPath is returned by [VerifySocketPath] when unix socket
+// path is not a socket, or its owner, group or mode do not match the
+// expected values.
+var ErrInsecureSocketPath = errors.New("launchd: insecure unix socket path")
+
+// VerifySocketPath verifies that SockPathName of the socket is a unix socket,
+// not a symbolic link, and that its owner, group and mode match SockPathOwner,
+// SockPathGroup and SockPathMode respectively. Fields which are not set
+// are not verified. This protects against sockets pre-created by other users
+// at the path, which launchd may not replace.
+//
+//   - [ErrInsecureSocketPath] is returned if path is not a socket,
+//     or its owner, group or mode are different from expected values.
+//   - [syscall.EINVAL] is returned if SockPathName is empty.
+//   - [syscall.ENOTSUP] is returned on non-unix platforms.
+func VerifySocketPath(socket plist.Socket) error {
+	return verifySocketPath(socket, false)
+}
+
+// RepairSocketPath is like [VerifySocketPath], but changes owner, group and
+// mode of the socket path to the expected values, instead of returning an
+// error. Changing owner and group typically requires root privileges.
+// [ErrInsecureSocketPath] is still returned if path is not a socket.
+func RepairSocketPath(socket plist.Socket) error {
+	return verifySocketPath(socket, true)
+}
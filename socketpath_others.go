@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package launchd
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Os specific implementation of [VerifySocketPath] and [RepairSocketPath].
+func verifySocketPath(_ plist.Socket, _ bool) error {
+	return fmt.Errorf("launchd: only supported on unix platforms: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+// Os specific implementation of [VerifySocketPath] and [RepairSocketPath].
+func verifySocketPath(socket plist.Socket, repair bool) error {
+	path := socket.SockPathName
+	if path == "" {
+		return fmt.Errorf("launchd: socket path is empty: %w", syscall.EINVAL)
+	}
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("launchd: %w", err)
+	}
+	if fi.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%w: %s is not a socket(%s)", ErrInsecureSocketPath, path, fi.Mode().Type())
+	}
+
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("launchd: failed to get owner of %s: %w", path, syscall.ENOTSUP)
+	}
+
+	uid, gid := -1, -1
+	if socket.SockPathOwner != nil && int(st.Uid) != *socket.SockPathOwner {
+		if !repair {
+			return fmt.Errorf("%w: %s is owned by uid=%d, expected uid=%d",
+				ErrInsecureSocketPath, path, st.Uid, *socket.SockPathOwner)
+		}
+		uid = *socket.SockPathOwner
+	}
+	if socket.SockPathGroup != nil && int(st.Gid) != *socket.SockPathGroup {
+		if !repair {
+			return fmt.Errorf("%w: %s is owned by gid=%d, expected gid=%d",
+				ErrInsecureSocketPath, path, st.Gid, *socket.SockPathGroup)
+		}
+		gid = *socket.SockPathGroup
+	}
+
+	mode := fs.FileMode(socket.SockPathMode) & fs.ModePerm
+	if socket.SockPathMode != 0 && fi.Mode().Perm() != mode {
+		if !repair {
+			return fmt.Errorf("%w: %s has mode=%#o, expected mode=%#o",
+				ErrInsecureSocketPath, path, fi.Mode().Perm(), mode)
+		}
+		if err = os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("launchd: failed to repair socket path: %w", err)
+		}
+	}
+
+	if uid != -1 || gid != -1 {
+		if err = os.Lchown(path, uid, gid); err != nil {
+			return fmt.Errorf("launchd: failed to repair socket path: %w", err)
+		}
+	}
+	return nil
+}
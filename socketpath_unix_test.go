@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package launchd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/tprasadtp/go-launchd/plist"
+)
+
+func TestVerifySocketPath(t *testing.T) {
+	dir, err := os.MkdirTemp("", "launchd-")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "s.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	if err = os.Chmod(path, 0o600); err != nil {
+		t.Fatalf("failed to chmod: %s", err)
+	}
+
+	regular := filepath.Join(dir, "regular")
+	if err = os.WriteFile(regular, nil, 0o600); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+	link := filepath.Join(dir, "link.sock")
+	if err = os.Symlink(path, link); err != nil {
+		t.Fatalf("failed to create symlink: %s", err)
+	}
+
+	uid, gid := os.Getuid(), os.Getgid()
+	other := uid + 1
+
+	tt := []struct {
+		name   string
+		socket plist.Socket
+		err    error
+	}{
+		{name: "empty", err: syscall.EINVAL},
+		{name: "not-exist", socket: plist.Socket{SockPathName: filepath.Join(dir, "x")}, err: os.ErrNotExist},
+		{name: "regular-file", socket: plist.Socket{SockPathName: regular}, err: ErrInsecureSocketPath},
+		{name: "symlink", socket: plist.Socket{SockPathName: link}, err: ErrInsecureSocketPath},
+		{name: "unset", socket: plist.Socket{SockPathName: path}},
+		{
+			name:   "match",
+			socket: plist.Socket{SockPathName: path, SockPathOwner: &uid, SockPathGroup: &gid, SockPathMode: 0o600},
+		},
+		{
+			name:   "owner-mismatch",
+			socket: plist.Socket{SockPathName: path, SockPathOwner: &other},
+			err:    ErrInsecureSocketPath,
+		},
+		{
+			name:   "mode-mismatch",
+			socket: plist.Socket{SockPathName: path, SockPathMode: 0o666},
+			err:    ErrInsecureSocketPath,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifySocketPath(tc.socket)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected error=%s, got=%s", tc.err, err)
+			}
+		})
+	}
+
+	t.Run("repair-mode", func(t *testing.T) {
+		socket := plist.Socket{SockPathName: path, SockPathOwner: &uid, SockPathMode: 0o660}
+		if err := RepairSocketPath(socket); err != nil {
+			t.Fatalf("expected no error, got=%s", err)
+		}
+		if err := VerifySocketPath(socket); err != nil {
+			t.Errorf("expected no error after repair, got=%s", err)
+		}
+	})
+
+	t.Run("repair-not-socket", func(t *testing.T) {
+		err := RepairSocketPath(plist.Socket{SockPathName: regular, SockPathMode: 0o600})
+		if !errors.Is(err, ErrInsecureSocketPath) {
+			t.Errorf("expected error=%s, got=%s", ErrInsecureSocketPath, err)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin
+
+package launchd
+
+import "syscall"
+
+// Socket options and control message types from <netinet/in.h>
+// and <netinet6/in6.h>, which are not all defined by [syscall].
+const (
+	ipRecvPktinfo   = syscall.IP_RECVPKTINFO
+	ipPktinfo       = syscall.IP_PKTINFO
+	ipv6RecvPktinfo = 0x3d
+	ipv6Pktinfo     = 0x2e
+
+	ipTOS          = syscall.IP_TOS
+	ipRecvTOS      = 0x1b
+	ipTOSCmsg      = ipRecvTOS
+	ipv6TClass     = syscall.IPV6_TCLASS
+	ipv6RecvTClass = syscall.IPV6_RECVTCLASS
+
+	ipDontFrag        = 0x1c
+	ipDontFragValue   = 1
+	ipv6DontFrag      = 0x3e
+	ipv6DontFragValue = 1
+)
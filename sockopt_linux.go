@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package launchd
+
+import "syscall"
+
+// Socket options and control message types from <netinet/in.h>.
+// Unlike macOS, IP_PKTINFO is used both to enable and to receive packet info,
+// received TOS is reported with IP_TOS, and don't fragment is set by
+// enabling path MTU discovery.
+const (
+	ipRecvPktinfo   = syscall.IP_PKTINFO
+	ipPktinfo       = syscall.IP_PKTINFO
+	ipv6RecvPktinfo = syscall.IPV6_RECVPKTINFO
+	ipv6Pktinfo     = syscall.IPV6_PKTINFO
+
+	ipTOS          = syscall.IP_TOS
+	ipRecvTOS      = syscall.IP_RECVTOS
+	ipTOSCmsg      = syscall.IP_TOS
+	ipv6TClass     = syscall.IPV6_TCLASS
+	ipv6RecvTClass = syscall.IPV6_RECVTCLASS
+
+	ipDontFrag        = syscall.IP_MTU_DISCOVER
+	ipDontFragValue   = syscall.IP_PMTUDISC_DO
+	ipv6DontFrag      = syscall.IPV6_MTU_DISCOVER
+	ipv6DontFragValue = syscall.IPV6_PMTUDISC_DO
+)
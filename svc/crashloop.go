@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+// Defaults for [CrashLoopOptions].
+const (
+	defaultCrashLoopWindow    = 5 * time.Minute
+	defaultCrashLoopThreshold = 3
+	defaultThrottleInterval   = 10 * time.Second
+	defaultMaxBackoff         = 5 * time.Minute
+)
+
+// CrashLoopOptions are options for [DetectCrashLoop].
+type CrashLoopOptions struct {
+	// Window is the period in which starts are counted. Defaults to 5m.
+	Window time.Duration
+
+	// Threshold is the number of starts within the window, after which
+	// service is considered to be crash looping, if its previous instance
+	// crashed. Defaults to 3.
+	Threshold int
+
+	// ThrottleInterval is the ThrottleInterval of the job, which is the
+	// base of the suggested backoff. Defaults to 10s, same as launchd.
+	ThrottleInterval time.Duration
+
+	// MaxBackoff is the maximum suggested backoff. Defaults to 5m.
+	MaxBackoff time.Duration
+
+	// HistoryFile is the file where start times of the service are
+	// recorded. Defaults to "<label>.starts" in /var/run when running
+	// as root, and in "go-launchd-<uid>" directory in [os.TempDir],
+	// which is created with mode 0700, otherwise. Its directory must
+	// not be writable by other users. Symbolic links and files owned
+	// by other users are rejected.
+	HistoryFile string
+}
+
+// CrashLoopStatus is the result of [DetectCrashLoop].
+type CrashLoopStatus struct {
+	// Label of the service.
+	Label string
+
+	// Runs is the number of times service was started by launchd.
+	Runs int
+
+	// LastExitCode is the exit code of the previous instance.
+	LastExitCode int
+
+	// LastExitReason is the description of the last exit of the service.
+	LastExitReason string
+
+	// Crashed reports whether previous instance exited with non-zero status,
+	// or was killed by a signal other than SIGTERM.
+	Crashed bool
+
+	// RecentStarts is the number of starts within the window,
+	// including the current one.
+	RecentStarts int
+
+	// Looping reports whether service is crash looping.
+	Looping bool
+
+	// Backoff is the suggested delay before retrying external
+	// dependencies. This is zero if service is not crash looping.
+	Backoff time.Duration
+}
+
+// String implements [fmt.Stringer].
+func (s *CrashLoopStatus) String() string {
+	if !s.Looping {
+		return fmt.Sprintf("service(%s) is not crash looping", s.Label)
+	}
+	return fmt.Sprintf("service(%s) is crash looping: started %d times recently, "+
+		"last exit code %d (%s), backing off for %s",
+		s.Label, s.RecentStarts, s.LastExitCode, s.LastExitReason, s.Backoff)
+}
+
+// DetectCrashLoop detects whether the current process is being respawned
+// by launchd in quick succession after crashing, i.e. is crash looping.
+//
+// launchd silently throttles jobs which exit too quickly. This makes crash
+// loops hard to diagnose, as there is no clear diagnostic in logs. Daemons
+// can call this early during startup and log a clear diagnostic, and
+// optionally wait for [CrashLoopStatus.Backoff] before retrying external
+// dependencies.
+//
+// Last exit status of the service is obtained via launchctl and start time
+// of the current process is recorded in the history file, which is locked
+// while being updated. Thus, this must be called once per process.
+//
+//   - [syscall.ESRCH] is returned if process is not managed by launchd.
+//   - [*launchctl.Error] is returned if launchctl fails.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func DetectCrashLoop(ctx context.Context, opts CrashLoopOptions) (*CrashLoopStatus, error) {
+	label := os.Getenv("XPC_SERVICE_NAME")
+	if label == "" || label == "0" {
+		return nil, fmt.Errorf("svc: process is not managed by launchd: %w", syscall.ESRCH)
+	}
+
+	domain, err := launchctl.CurrentDomain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("svc: failed to get current domain: %w", err)
+	}
+
+	svc, err := launchctl.Print(ctx, domain, label)
+	if err != nil {
+		return nil, fmt.Errorf("svc: failed to get service status: %w", err)
+	}
+
+	opts = opts.withDefaults()
+	if opts.HistoryFile == "" {
+		opts.HistoryFile, err = defaultHistoryFile(label)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	starts, err := recordStart(opts.HistoryFile, now, opts.Window)
+	if err != nil {
+		return nil, err
+	}
+	return evaluateCrashLoop(svc, starts, opts), nil
+}
+
+// withDefaults returns options with defaults applied.
+func (o CrashLoopOptions) withDefaults() CrashLoopOptions {
+	if o.Window <= 0 {
+		o.Window = defaultCrashLoopWindow
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = defaultCrashLoopThreshold
+	}
+	if o.ThrottleInterval <= 0 {
+		o.ThrottleInterval = defaultThrottleInterval
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = defaultMaxBackoff
+	}
+	return o
+}
+
+// defaultHistoryFile returns the default history file of the service.
+// For non-root users, this creates a per-user directory in [os.TempDir],
+// as it may be shared with other users, like /tmp.
+func defaultHistoryFile(label string) (string, error) {
+	uid := os.Geteuid()
+	if uid == 0 {
+		return filepath.Join("/var/run", label+".starts"), nil
+	}
+
+	dir := filepath.Join(os.TempDir(), "go-launchd-"+strconv.Itoa(uid))
+	if err := os.Mkdir(dir, 0o700); err != nil && !errors.Is(err, fs.ErrExist) {
+		return "", fmt.Errorf("svc: failed to create history directory: %w", err)
+	}
+	return filepath.Join(dir, label+".starts"), nil
+}
+
+// evaluateCrashLoop evaluates crash loop status from service state and
+// recent start times.
+func evaluateCrashLoop(svc *launchctl.Service, starts []time.Time, opts CrashLoopOptions) *CrashLoopStatus {
+	status := &CrashLoopStatus{
+		Label:          svc.Label,
+		Runs:           svc.Runs,
+		LastExitCode:   svc.LastExitCode,
+		LastExitReason: svc.LastExitReason,
+		Crashed:        svc.Runs > 1 && crashed(svc),
+		RecentStarts:   len(starts),
+	}
+
+	if !status.Crashed || status.RecentStarts < opts.Threshold {
+		return status
+	}
+
+	status.Looping = true
+	status.Backoff = opts.ThrottleInterval
+	for i := opts.Threshold; i < status.RecentStarts && status.Backoff < opts.MaxBackoff; i++ {
+		status.Backoff *= 2
+	}
+	status.Backoff = min(status.Backoff, opts.MaxBackoff)
+	return status
+}
+
+// crashed reports whether last instance of the service exited with
+// non-zero status or was killed by a signal. SIGTERM is not considered
+// a crash, as launchd sends it to stop the service.
+func crashed(svc *launchctl.Service) bool {
+	if svc.LastExitCode != 0 {
+		return true
+	}
+	switch svc.LastExitReason {
+	case "", "(never exited)", "Terminated: 15":
+		return false
+	default:
+		return true
+	}
+}
+
+// recordStart records start time in the history file, pruning entries
+// older than the window, and returns the start times within the window.
+// History file is locked while it is being updated.
+func recordStart(path string, now time.Time, window time.Duration) ([]time.Time, error) {
+	f, err := openHistory(path)
+	if err != nil {
+		return nil, fmt.Errorf("svc: failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	var starts []time.Time
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("svc: failed to read history file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		v, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 64)
+		if err != nil {
+			continue
+		}
+		t := time.Unix(0, v)
+		if now.Sub(t) < window && !t.After(now) {
+			starts = append(starts, t)
+		}
+	}
+	starts = append(starts, now)
+
+	var b strings.Builder
+	for _, t := range starts {
+		b.WriteString(strconv.FormatInt(t.UnixNano(), 10))
+		b.WriteByte('\n')
+	}
+
+	if err = f.Truncate(0); err == nil {
+		_, err = f.WriteAt([]byte(b.String()), 0)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("svc: failed to write history file: %w", err)
+	}
+	return starts, nil
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+func TestEvaluateCrashLoop(t *testing.T) {
+	opts := CrashLoopOptions{}.withDefaults()
+	tt := []struct {
+		name    string
+		svc     launchctl.Service
+		starts  int
+		looping bool
+		backoff time.Duration
+	}{
+		{
+			name:   "first-run",
+			svc:    launchctl.Service{Runs: 1},
+			starts: 1,
+		},
+		{
+			name:   "clean-exit",
+			svc:    launchctl.Service{Runs: 5},
+			starts: 5,
+		},
+		{
+			name:   "crashed-once",
+			svc:    launchctl.Service{Runs: 2, LastExitCode: 1},
+			starts: 2,
+		},
+		{
+			name:    "looping",
+			svc:     launchctl.Service{Runs: 3, LastExitCode: 1},
+			starts:  3,
+			looping: true,
+			backoff: 10 * time.Second,
+		},
+		{
+			name:    "looping-signal",
+			svc:     launchctl.Service{Runs: 3, LastExitReason: "Killed: 9"},
+			starts:  3,
+			looping: true,
+			backoff: 10 * time.Second,
+		},
+		{
+			name:   "stopped",
+			svc:    launchctl.Service{Runs: 3, LastExitReason: "Terminated: 15"},
+			starts: 3,
+		},
+		{
+			name:    "looping-backoff",
+			svc:     launchctl.Service{Runs: 10, LastExitCode: 78},
+			starts:  5,
+			looping: true,
+			backoff: 40 * time.Second,
+		},
+		{
+			name:    "looping-max-backoff",
+			svc:     launchctl.Service{Runs: 100, LastExitCode: 78},
+			starts:  50,
+			looping: true,
+			backoff: 5 * time.Minute,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			starts := make([]time.Time, tc.starts)
+			status := evaluateCrashLoop(&tc.svc, starts, opts)
+			if status.Looping != tc.looping {
+				t.Errorf("expected looping=%t, got=%t", tc.looping, status.Looping)
+			}
+			if status.Backoff != tc.backoff {
+				t.Errorf("expected backoff=%s, got=%s", tc.backoff, status.Backoff)
+			}
+		})
+	}
+}
+
+func TestRecordStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "com.example.svc.starts")
+	now := time.Now()
+	window := time.Minute
+
+	for i, offset := range []time.Duration{-2 * time.Minute, -30 * time.Second, -10 * time.Second} {
+		starts, err := recordStart(path, now.Add(offset), window)
+		if err != nil {
+			t.Fatalf("expected no error, got=%s", err)
+		}
+		if i == 0 && len(starts) != 1 {
+			t.Errorf("expected 1 start, got=%d", len(starts))
+		}
+	}
+
+	starts, err := recordStart(path, now, window)
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+
+	// Start two minutes ago is outside the window.
+	if len(starts) != 3 {
+		t.Errorf("expected 3 starts, got=%d", len(starts))
+	}
+}
+
+func TestDetectCrashLoop_NotManagedByLaunchd(t *testing.T) {
+	t.Setenv("XPC_SERVICE_NAME", "")
+	_, err := DetectCrashLoop(context.Background(), CrashLoopOptions{})
+	if !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("expected error=%s, got=%s", syscall.ESRCH, err)
+	}
+}
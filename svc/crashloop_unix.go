@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package svc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// openHistory opens the history file for reading and writing, creating it
+// if necessary, and locks it exclusively. Directories writable by other
+// users, symbolic links and files not owned by the effective user are
+// rejected.
+func openHistory(path string) (*os.File, error) {
+	if err := checkHistoryDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|syscall.O_NOFOLLOW, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !fi.Mode().IsRegular() || !ok || int(st.Uid) != os.Geteuid() {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a regular file owned by uid(%d): %w", path, os.Geteuid(), syscall.EPERM)
+	}
+
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, os.NewSyscallError("flock", err)
+	}
+	return f, nil
+}
+
+// checkHistoryDir checks that directory is owned by the effective user
+// or root, and is not writable by group or other users, who could
+// otherwise replace the history file.
+func checkHistoryDir(dir string) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	switch {
+	case !fi.IsDir():
+		return fmt.Errorf("%s is not a directory: %w", dir, syscall.ENOTDIR)
+	case !ok || (int(st.Uid) != os.Geteuid() && st.Uid != 0):
+		return fmt.Errorf("%s is not owned by uid(%d) or root: %w", dir, os.Geteuid(), syscall.EPERM)
+	case fi.Mode().Perm()&0o022 != 0:
+		return fmt.Errorf("%s is writable by other users: %w", dir, syscall.EPERM)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package svc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRecordStart_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.WriteFile(target, []byte("data"), 0o600); err != nil {
+		t.Fatalf("failed to create file: %s", err)
+	}
+	path := filepath.Join(dir, "com.example.svc.starts")
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatalf("failed to create symlink: %s", err)
+	}
+
+	if _, err := recordStart(path, time.Now(), time.Minute); !errors.Is(err, syscall.ELOOP) {
+		t.Errorf("expected error=%s, got=%s", syscall.ELOOP, err)
+	}
+	if b, _ := os.ReadFile(target); string(b) != "data" {
+		t.Errorf("expected symlink target not to be modified, got=%s", b)
+	}
+}
+
+func TestRecordStart_SharedDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatalf("failed to chmod dir: %s", err)
+	}
+
+	path := filepath.Join(dir, "com.example.svc.starts")
+	if _, err := recordStart(path, time.Now(), time.Minute); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("expected error=%s, got=%s", syscall.EPERM, err)
+	}
+	if _, err := os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected history file not to be created, got=%s", err)
+	}
+}
+
+func TestDefaultHistoryFile(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("history file of root is in /var/run")
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+
+	path, err := defaultHistoryFile("com.example.svc")
+	if err != nil {
+		t.Fatalf("expected no error, got=%s", err)
+	}
+	fi, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("expected history directory to exist, got=%s", err)
+	}
+	if fi.Mode().Perm() != 0o700 {
+		t.Errorf("expected mode=%s, got=%s", os.FileMode(0o700), fi.Mode().Perm())
+	}
+	if _, err = recordStart(path, time.Now(), time.Minute); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
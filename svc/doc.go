@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+// Package svc provides a runner for services managed by launchd.
+//
+// It is comparable to [golang.org/x/sys/windows/svc], but for launchd.
+// [Run] activates sockets declared by the service, starts the service,
+// and stops it gracefully when launchd asks it to, i.e. on SIGTERM, within
+// the ExitTimeOut budget of the job. Exiting when the service is idle is
+// treated as success, as launchd starts on-demand services again when
+// required.
+//
+// Services which do not use socket activation can use the runner on
+// all platforms.
+package svc
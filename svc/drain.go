@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// Drainer tracks open connections, so that shutdown can wait for in-flight
+// connections to finish and force close the remaining ones when the time
+// budget for stopping the service is exhausted.
+//
+// Connections are tracked either by wrapping listeners with
+// [Drainer.Listener], or by using [Drainer.ConnState] as ConnState
+// hook of [net/http.Server].
+//
+// Use [WithDrainer] to drain connections when [Run] stops the service.
+type Drainer struct {
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}
+	waiter  chan struct{}
+	dropped int
+}
+
+// NewDrainer returns a new [Drainer] with no tracked connections.
+func NewDrainer() *Drainer {
+	return &Drainer{conns: make(map[net.Conn]struct{})}
+}
+
+// add starts tracking the connection.
+func (d *Drainer) add(conn net.Conn) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.conns[conn] = struct{}{}
+}
+
+// remove stops tracking the connection.
+func (d *Drainer) remove(conn net.Conn) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.conns, conn)
+	if len(d.conns) == 0 && d.waiter != nil {
+		close(d.waiter)
+		d.waiter = nil
+	}
+}
+
+// Active returns the number of tracked connections which are open.
+func (d *Drainer) Active() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.conns)
+}
+
+// Dropped returns the number of connections force closed by [Drainer.Drain].
+func (d *Drainer) Dropped() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dropped
+}
+
+// Drain waits for tracked connections to be closed. If ctx is done before
+// that, remaining connections are force closed, and their number is
+// returned along with the context error.
+//
+// Drain does not stop listeners from accepting connections,
+// thus it should be called after they have been closed.
+func (d *Drainer) Drain(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	if len(d.conns) == 0 {
+		d.mu.Unlock()
+		return 0, nil
+	}
+	if d.waiter == nil {
+		d.waiter = make(chan struct{})
+	}
+	waiter := d.waiter
+	d.mu.Unlock()
+
+	select {
+	case <-waiter:
+		return 0, nil
+	case <-ctx.Done():
+	}
+
+	d.mu.Lock()
+	conns := make([]net.Conn, 0, len(d.conns))
+	for conn := range d.conns {
+		conns = append(conns, conn)
+	}
+	d.dropped += len(conns)
+	d.mu.Unlock()
+
+	if len(conns) == 0 {
+		return 0, nil
+	}
+	for _, conn := range conns {
+		conn.Close()
+		d.remove(conn)
+	}
+	return len(conns), fmt.Errorf("svc: dropped %d connections: %w", len(conns), ctx.Err())
+}
+
+// Listener wraps the listener, so that accepted connections
+// are tracked until they are closed.
+func (d *Drainer) Listener(l net.Listener) net.Listener {
+	return &drainListener{Listener: l, drainer: d}
+}
+
+// ConnState tracks connections of [net/http.Server]. It is intended to be
+// used as ConnState of the server. Connections are tracked from
+// [net/http.StateNew] until they are closed or hijacked.
+func (d *Drainer) ConnState(conn net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		d.add(conn)
+	case http.StateHijacked, http.StateClosed:
+		d.remove(conn)
+	}
+}
+
+type drainListener struct {
+	net.Listener
+	drainer *Drainer
+}
+
+// Accept implements [net.Listener].
+func (l *drainListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	c := &drainConn{Conn: conn, drainer: l.drainer}
+	l.drainer.add(c)
+	return c, nil
+}
+
+type drainConn struct {
+	net.Conn
+	drainer *Drainer
+	once    sync.Once
+}
+
+// Close implements [net.Conn].
+func (c *drainConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		c.drainer.remove(c)
+	})
+	return err
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/svc"
+)
+
+// drainListener returns a listener wrapped by drainer, along with
+// a connection accepted from it and the client side of it.
+func drainListener(t *testing.T, d *svc.Drainer) (net.Conn, net.Conn) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	l = d.Listener(l)
+	t.Cleanup(func() {
+		l.Close()
+	})
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+	})
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+	return conn, client
+}
+
+func TestDrainer_Drain(t *testing.T) {
+	d := svc.NewDrainer()
+	conn, _ := drainListener(t, d)
+	if d.Active() != 1 {
+		t.Fatalf("expected active=1, got=%d", d.Active())
+	}
+
+	time.AfterFunc(50*time.Millisecond, func() {
+		conn.Close()
+	})
+
+	dropped, err := d.Drain(context.Background())
+	if err != nil || dropped != 0 {
+		t.Errorf("expected no dropped connections, got=%d, err=%v", dropped, err)
+	}
+	if d.Active() != 0 {
+		t.Errorf("expected active=0, got=%d", d.Active())
+	}
+}
+
+func TestDrainer_Drop(t *testing.T) {
+	d := svc.NewDrainer()
+	_, client := drainListener(t, d)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	dropped, err := d.Drain(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error=%s, got=%s", context.DeadlineExceeded, err)
+	}
+	if dropped != 1 || d.Dropped() != 1 {
+		t.Errorf("expected dropped=1, got=%d(%d)", dropped, d.Dropped())
+	}
+	if d.Active() != 0 {
+		t.Errorf("expected active=0, got=%d", d.Active())
+	}
+
+	// Connection is closed by drainer.
+	_ = client.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if _, err = client.Read(make([]byte, 1)); err == nil {
+		t.Errorf("expected connection to be closed")
+	}
+}
+
+func TestDrainer_ConnState(t *testing.T) {
+	d := svc.NewDrainer()
+	server, client := net.Pipe()
+	defer client.Close()
+
+	d.ConnState(server, http.StateNew)
+	d.ConnState(server, http.StateActive)
+	if d.Active() != 1 {
+		t.Errorf("expected active=1, got=%d", d.Active())
+	}
+
+	d.ConnState(server, http.StateClosed)
+	if d.Active() != 0 {
+		t.Errorf("expected active=0, got=%d", d.Active())
+	}
+}
+
+func TestRun_Drainer(t *testing.T) {
+	d := svc.NewDrainer()
+	drainListener(t, d)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := &service{
+		start: func(_ context.Context, stop <-chan struct{}) error {
+			<-stop
+			return nil
+		},
+		stop: make(chan struct{}),
+	}
+	err := svc.Run(ctx, s, svc.WithExitTimeout(200*time.Millisecond), svc.WithDrainer(d))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error=%s, got=%s", context.DeadlineExceeded, err)
+	}
+	if d.Dropped() != 1 {
+		t.Errorf("expected dropped=1, got=%d", d.Dropped())
+	}
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// IdleExiter tracks activity of the service and reports when the service
+// has been idle for the configured period. This is the standard pattern
+// for on-demand launchd jobs, which exit when idle and are started again
+// by launchd when a new connection arrives on their sockets.
+//
+// Service is idle when it has no outstanding work, i.e. no open connections
+// accepted from wrapped listeners and no work marked with [IdleExiter.Begin],
+// and there has been no activity on wrapped packet connections.
+//
+// Use [WithIdleExiter] to stop the service gracefully with [Run]
+// and exit with status 0 when idle.
+type IdleExiter struct {
+	timeout time.Duration
+
+	mu     sync.Mutex
+	active int
+	last   time.Time
+	timer  *time.Timer
+	done   chan struct{}
+	closed bool
+}
+
+// NewIdleExiter returns a new [IdleExiter] which considers the service
+// idle after it has no outstanding work for the timeout. Idle period
+// starts immediately.
+func NewIdleExiter(timeout time.Duration) *IdleExiter {
+	e := &IdleExiter{
+		timeout: timeout,
+		last:    time.Now(),
+		done:    make(chan struct{}),
+	}
+	e.timer = time.AfterFunc(timeout, e.check)
+	return e
+}
+
+// check is called when timer fires.
+func (e *IdleExiter) check() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed || e.active > 0 {
+		return
+	}
+
+	// Activity may have been recorded after timer fired.
+	if remaining := e.timeout - time.Since(e.last); remaining > 0 {
+		e.timer.Reset(remaining)
+		return
+	}
+
+	e.closed = true
+	close(e.done)
+}
+
+// Touch records activity, which restarts the idle period.
+func (e *IdleExiter) Touch() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.last = time.Now()
+}
+
+// Begin marks start of outstanding work. Service is not considered
+// idle until every call to Begin is matched by a call to [IdleExiter.End].
+func (e *IdleExiter) Begin() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.active++
+	e.last = time.Now()
+}
+
+// End marks end of outstanding work started with [IdleExiter.Begin].
+func (e *IdleExiter) End() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.active == 0 {
+		return
+	}
+
+	e.active--
+	e.last = time.Now()
+	if e.active == 0 && !e.closed {
+		e.timer.Reset(e.timeout)
+	}
+}
+
+// Done returns a channel which is closed when the service is idle.
+func (e *IdleExiter) Done() <-chan struct{} {
+	return e.done
+}
+
+// Wait blocks until the service is idle or context is cancelled.
+// It returns [ErrIdleExit] if service is idle, context error otherwise.
+// Thus, it can be returned from [Service.Start].
+func (e *IdleExiter) Wait(ctx context.Context) error {
+	select {
+	case <-e.done:
+		return ErrIdleExit
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Listener wraps the listener, so that accepted connections are
+// tracked as outstanding work until they are closed.
+func (e *IdleExiter) Listener(l net.Listener) net.Listener {
+	return &idleListener{Listener: l, exiter: e}
+}
+
+// PacketConn wraps the packet connection, so that reads and writes
+// are recorded as activity.
+func (e *IdleExiter) PacketConn(c net.PacketConn) net.PacketConn {
+	return &idlePacketConn{PacketConn: c, exiter: e}
+}
+
+type idleListener struct {
+	net.Listener
+	exiter *IdleExiter
+}
+
+// Accept implements [net.Listener].
+func (l *idleListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.exiter.Begin()
+	return &idleConn{Conn: conn, exiter: l.exiter}, nil
+}
+
+type idleConn struct {
+	net.Conn
+	exiter *IdleExiter
+	once   sync.Once
+}
+
+// Close implements [net.Conn].
+func (c *idleConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.exiter.End)
+	return err
+}
+
+type idlePacketConn struct {
+	net.PacketConn
+	exiter *IdleExiter
+}
+
+// ReadFrom implements [net.PacketConn].
+func (c *idlePacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(p)
+	if n > 0 {
+		c.exiter.Touch()
+	}
+	return n, addr, err
+}
+
+// WriteTo implements [net.PacketConn].
+func (c *idlePacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	n, err := c.PacketConn.WriteTo(p, addr)
+	if n > 0 {
+		c.exiter.Touch()
+	}
+	return n, err
+}
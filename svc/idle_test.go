@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/svc"
+)
+
+const idleTimeout = 50 * time.Millisecond
+
+// isIdle reports whether exiter becomes idle within d.
+func isIdle(e *svc.IdleExiter, d time.Duration) bool {
+	select {
+	case <-e.Done():
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func TestIdleExiter(t *testing.T) {
+	e := svc.NewIdleExiter(idleTimeout)
+	if !isIdle(e, time.Second) {
+		t.Errorf("expected to be idle")
+	}
+
+	err := e.Wait(context.Background())
+	if !errors.Is(err, svc.ErrIdleExit) {
+		t.Errorf("expected error=%s, got=%s", svc.ErrIdleExit, err)
+	}
+}
+
+func TestIdleExiter_BeginEnd(t *testing.T) {
+	e := svc.NewIdleExiter(idleTimeout)
+	e.Begin()
+	if isIdle(e, 4*idleTimeout) {
+		t.Fatalf("expected not to be idle with outstanding work")
+	}
+
+	e.End()
+	if !isIdle(e, time.Second) {
+		t.Errorf("expected to be idle after work is done")
+	}
+}
+
+func TestIdleExiter_Wait(t *testing.T) {
+	e := svc.NewIdleExiter(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := e.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error=%s, got=%s", context.Canceled, err)
+	}
+}
+
+func TestIdleExiter_Listener(t *testing.T) {
+	e := svc.NewIdleExiter(idleTimeout)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	l = e.Listener(l)
+	defer l.Close()
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer client.Close()
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+
+	if isIdle(e, 4*idleTimeout) {
+		t.Fatalf("expected not to be idle with open connections")
+	}
+
+	conn.Close()
+	conn.Close()
+	if !isIdle(e, time.Second) {
+		t.Errorf("expected to be idle after connection is closed")
+	}
+}
+
+func TestRun_IdleExiter(t *testing.T) {
+	e := svc.NewIdleExiter(idleTimeout)
+	s := &service{
+		start: func(_ context.Context, stop <-chan struct{}) error {
+			<-stop
+			return nil
+		},
+		stop: make(chan struct{}),
+	}
+
+	err := svc.Run(context.Background(), s, svc.WithIdleExiter(e))
+	if err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/oslog"
+)
+
+// PanicExitCode is the exit status used by [RecoverAndLog],
+// EX_SOFTWARE from sysexits.h.
+const PanicExitCode = 70
+
+// Hooks replaced by tests.
+//
+//nolint:gochecknoglobals // for tests.
+var (
+	exit             = os.Exit
+	stderr io.Writer = os.Stderr
+)
+
+// RecoverAndLog recovers from a panic in the calling goroutine, writes
+// a report to unified logging and to stderr, which launchd redirects to
+// StandardErrorPath of the job, and exits with [PanicExitCode].
+//
+// It must be deferred directly at the top of the main function and of
+// each goroutine started by the service, as panics cannot be recovered
+// from other goroutines.
+//
+//	go func() {
+//		defer svc.RecoverAndLog()
+//		...
+//	}()
+//
+// As process exits with non-zero status, launchd restarts it if KeepAlive
+// is true or if SuccessfulExit is false, subject to ThrottleInterval.
+// Deferred functions of other goroutines are not run.
+func RecoverAndLog() {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	r := newPanicReport(v, debug.Stack(), time.Now())
+	_, _ = io.WriteString(stderr, r.String())
+	r.log()
+	exit(PanicExitCode)
+}
+
+// panicReport describes a recovered panic.
+type panicReport struct {
+	value any
+	label string
+	pid   int
+	time  time.Time
+	stack []byte
+}
+
+// newPanicReport returns a report for the panic.
+func newPanicReport(v any, stack []byte, now time.Time) *panicReport {
+	label := os.Getenv("XPC_SERVICE_NAME")
+	if label == "" || label == "0" {
+		label = filepath.Base(os.Args[0])
+	}
+	return &panicReport{
+		value: v,
+		label: label,
+		pid:   os.Getpid(),
+		time:  now,
+		stack: stack,
+	}
+}
+
+// String returns the report in a format similar to the one
+// printed by go runtime for unrecovered panics.
+func (r *panicReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "panic: %v\n", r.value)
+	fmt.Fprintf(&b, "label: %s\n", r.label)
+	fmt.Fprintf(&b, "pid: %d\n", r.pid)
+	fmt.Fprintf(&b, "time: %s\n\n", r.time.Format(time.RFC3339Nano))
+	b.Write(r.stack)
+	if len(r.stack) > 0 && r.stack[len(r.stack)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// log writes the report to unified logging as a fault.
+// It is a no-op on platforms where unified logging is not available.
+func (r *panicReport) log() {
+	h, err := oslog.NewHandler(r.label, "panic", nil)
+	if err != nil {
+		return
+	}
+	slog.New(h).LogAttrs(context.Background(), oslog.LevelFault, "panic",
+		slog.String("value", fmt.Sprint(r.value)),
+		slog.Int("pid", r.pid),
+		slog.String("stack", string(r.stack)),
+	)
+}
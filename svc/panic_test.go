@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecoverAndLog(t *testing.T) {
+	var buf bytes.Buffer
+	code := -1
+	oldStderr, oldExit := stderr, exit
+	stderr = &buf
+	exit = func(c int) { code = c }
+	t.Cleanup(func() {
+		stderr, exit = oldStderr, oldExit
+	})
+	t.Setenv("XPC_SERVICE_NAME", "com.example.svc")
+
+	func() {
+		defer RecoverAndLog()
+		panic("boom")
+	}()
+
+	if code != PanicExitCode {
+		t.Errorf("expected exit code=%d, got=%d", PanicExitCode, code)
+	}
+
+	for _, expect := range []string{
+		"panic: boom\n",
+		"label: com.example.svc\n",
+		"goroutine ",
+		"TestRecoverAndLog",
+	} {
+		if !strings.Contains(buf.String(), expect) {
+			t.Errorf("expected report to contain=%q, got=%s", expect, buf.String())
+		}
+	}
+}
+
+func TestRecoverAndLog_NoPanic(t *testing.T) {
+	called := false
+	oldExit := exit
+	exit = func(int) { called = true }
+	t.Cleanup(func() { exit = oldExit })
+
+	func() {
+		defer RecoverAndLog()
+	}()
+
+	if called {
+		t.Errorf("expected no exit without panic")
+	}
+}
+
+func TestPanicReport_String(t *testing.T) {
+	r := &panicReport{
+		value: "boom",
+		label: "com.example.svc",
+		pid:   42,
+		time:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		stack: []byte("goroutine 1 [running]:"),
+	}
+	expect := "panic: boom\nlabel: com.example.svc\npid: 42\ntime: 2024-01-02T03:04:05Z\n\ngoroutine 1 [running]:\n"
+	if got := r.String(); got != expect {
+		t.Errorf("expected=%q, got=%q", expect, got)
+	}
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tprasadtp/go-launchd"
+	"github.com/tprasadtp/go-launchd/internal/unixsock"
+	"github.com/tprasadtp/go-launchd/launchctl"
+	"github.com/tprasadtp/go-launchd/notify"
+)
+
+// Reloadable is implemented by services which can reload their
+// configuration without restarting.
+//
+// [Run] calls Reload when the process receives SIGHUP, or when a reload
+// request is received on the control socket (see [WithControlSocket]).
+// Calls to Reload are serialized, and are never concurrent with Stop.
+// Errors from reloads triggered by SIGHUP are ignored, thus services
+// should log them.
+type Reloadable interface {
+	Reload(ctx context.Context) error
+}
+
+// Control socket protocol messages.
+const (
+	controlReload = "reload"
+	controlOK     = "ok"
+	controlError  = "error: "
+)
+
+// WithControlSocket listens on unix socket at path for control requests,
+// which can be sent with [ReloadSocket]. Unlike signals, requests sent
+// via control socket receive result of the reload. Socket is only
+// accessible to the user running the service, and requests from peers
+// running as other users, except root, are rejected.
+//
+// This has no effect if service does not implement [Reloadable].
+func WithControlSocket(path string) Option {
+	return func(o *options) {
+		o.controlSocket = path
+	}
+}
+
+// reloadRequest is a reload request received on control socket.
+type reloadRequest chan error
+
+// reload reloads the service, publishing reloading state
+// with notify package.
+func reload(ctx context.Context, r Reloadable) error {
+	_ = notify.Reloading()
+	err := r.Reload(ctx)
+	_ = notify.Ready()
+	return err
+}
+
+// listenControl listens on control socket and forwards reload
+// requests to the channel, until listener is closed.
+func listenControl(path string, requests chan<- reloadRequest) (net.Listener, error) {
+	// Remove stale socket from previous run.
+	if _, err := launchd.RemoveStaleUnixSocket(path); err != nil {
+		return nil, fmt.Errorf("svc: control socket: %w", err)
+	}
+
+	l, err := unixsock.Listen(path)
+	if err != nil {
+		return nil, fmt.Errorf("svc: control socket: %w", err)
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveControl(conn, requests)
+		}
+	}()
+	return l, nil
+}
+
+// serveControl serves a single control request.
+func serveControl(conn net.Conn, requests chan<- reloadRequest) {
+	defer conn.Close()
+	if err := unixsock.CheckPeer(conn); err != nil {
+		_, _ = fmt.Fprintf(conn, "%ssvc: %s\n", controlError, err)
+		return
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	if strings.TrimSpace(line) != controlReload {
+		_, _ = fmt.Fprintf(conn, "%sunknown command\n", controlError)
+		return
+	}
+
+	req := make(reloadRequest, 1)
+	select {
+	case requests <- req:
+	case <-time.After(5 * time.Second):
+		_, _ = fmt.Fprintf(conn, "%sservice is not accepting requests\n", controlError)
+		return
+	}
+
+	if err = <-req; err != nil {
+		_, _ = fmt.Fprintf(conn, "%s%s\n", controlError, strings.ReplaceAll(err.Error(), "\n", " "))
+		return
+	}
+	_, _ = fmt.Fprintf(conn, "%s\n", controlOK)
+}
+
+// Reload asks the service with the given label in the domain to reload its
+// configuration, by sending SIGHUP via launchctl. It returns once the signal
+// is delivered, without waiting for reload to complete. Use [ReloadSocket]
+// to wait for the result of the reload.
+//
+//   - [*launchctl.Error] is returned if launchctl fails, for example,
+//     if the service is not running.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func Reload(ctx context.Context, domain launchctl.Domain, label string) error {
+	return launchctl.Kill(ctx, domain, label, syscall.SIGHUP)
+}
+
+// ReloadSocket asks the service listening on control socket at path to
+// reload its configuration and waits for the result. Error returned by
+// [Reloadable.Reload] is returned as an error.
+func ReloadSocket(ctx context.Context, path string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("svc: failed to connect to control socket: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	// Unblock reads when context is cancelled.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err = fmt.Fprintf(conn, "%s\n", controlReload); err != nil {
+		return fmt.Errorf("svc: failed to send reload request: %w", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("svc: reload request: %w", ctx.Err())
+		}
+		return fmt.Errorf("svc: failed to read reload response: %w", err)
+	}
+
+	line = strings.TrimSpace(line)
+	switch {
+	case line == controlOK:
+		return nil
+	case strings.HasPrefix(line, controlError):
+		return errors.New("svc: reload failed: " + strings.TrimPrefix(line, controlError))
+	default:
+		return fmt.Errorf("svc: invalid reload response(%q)", line)
+	}
+}
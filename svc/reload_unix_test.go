@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package svc_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/svc"
+)
+
+type reloadable struct {
+	service
+	reloads atomic.Int32
+	err     error
+	started chan struct{}
+}
+
+func (s *reloadable) Reload(_ context.Context) error {
+	s.reloads.Add(1)
+	return s.err
+}
+
+func newReloadable(err error) *reloadable {
+	s := &reloadable{
+		service: service{stop: make(chan struct{})},
+		err:     err,
+		started: make(chan struct{}),
+	}
+	s.start = func(_ context.Context, stop <-chan struct{}) error {
+		close(s.started)
+		<-stop
+		return nil
+	}
+	return s
+}
+
+// waitFor polls fn until it returns true or timeout expires.
+func waitFor(fn func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if fn() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestRun_ReloadSignal(t *testing.T) {
+	s := newReloadable(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- svc.Run(ctx, s)
+	}()
+
+	// Signal handlers are installed before starting the service.
+	<-s.started
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	ok := waitFor(func() bool {
+		return s.reloads.Load() > 0
+	})
+	if !ok {
+		t.Errorf("expected service to be reloaded on SIGHUP")
+	}
+
+	cancel()
+	if err := <-result; err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
+
+func TestRun_ReloadSocket(t *testing.T) {
+	errReload := errors.New("invalid configuration")
+	tt := []struct {
+		name   string
+		err    error
+		expect string
+	}{
+		{name: "ok"},
+		{name: "error", err: errReload, expect: "invalid configuration"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "control.sock")
+			s := newReloadable(tc.err)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			result := make(chan error, 1)
+			go func() {
+				result <- svc.Run(ctx, s, svc.WithControlSocket(path))
+			}()
+
+			var err error
+			waitFor(func() bool {
+				err = svc.ReloadSocket(context.Background(), path)
+				return !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ECONNREFUSED)
+			})
+
+			if tc.expect == "" && err != nil {
+				t.Errorf("expected no error, got=%s", err)
+			}
+			if tc.expect != "" && (err == nil || !strings.Contains(err.Error(), tc.expect)) {
+				t.Errorf("expected error containing=%s, got=%v", tc.expect, err)
+			}
+			if v := s.reloads.Load(); v != 1 {
+				t.Errorf("expected reloads=1, got=%d", v)
+			}
+
+			cancel()
+			if err = <-result; err != nil {
+				t.Errorf("expected no error, got=%s", err)
+			}
+		})
+	}
+}
+
+func TestRun_ControlSocketPermissions(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "control.sock")
+	s := newReloadable(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- svc.Run(ctx, s, svc.WithControlSocket(path))
+	}()
+	<-s.started
+
+	fi, err := os.Lstat(path)
+	if err != nil {
+		t.Fatalf("failed to stat control socket: %s", err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		t.Errorf("expected socket, got mode=%s", fi.Mode())
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected permissions=0600, got=%#o", perm)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read directory: %s", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only control socket in directory, got=%v", entries)
+	}
+
+	if err = svc.ReloadSocket(context.Background(), path); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+
+	cancel()
+	if err = <-result; err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+	if _, err = os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected control socket to be removed, got=%v", err)
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/launchctl"
+)
+
+// ShutdownMargin is subtracted from exit timeout of the job by
+// [ShutdownContext], to leave time for flushing logs and exiting
+// before launchd sends SIGKILL.
+const ShutdownMargin = 2 * time.Second
+
+// ExitTimeout returns ExitTimeOut of the job which started the current
+// process, as reported by launchd. [DefaultExitTimeout] is returned
+// if the job does not specify it.
+//
+//   - [syscall.ESRCH] is returned if process is not managed by launchd.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func ExitTimeout(ctx context.Context) (time.Duration, error) {
+	label := os.Getenv("XPC_SERVICE_NAME")
+	if label == "" || label == "0" {
+		return 0, fmt.Errorf("svc: process is not managed by launchd: %w", syscall.ESRCH)
+	}
+
+	domain, err := launchctl.CurrentDomain(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("svc: failed to get current domain: %w", err)
+	}
+
+	svc, err := launchctl.Print(ctx, domain, label)
+	if err != nil {
+		return 0, fmt.Errorf("svc: failed to get service status: %w", err)
+	}
+
+	if svc.ExitTimeout <= 0 {
+		return DefaultExitTimeout, nil
+	}
+	return time.Duration(svc.ExitTimeout) * time.Second, nil
+}
+
+// exitTimeoutKey is the context key for exit timeout resolved by [Run].
+type exitTimeoutKey struct{}
+
+// withExitTimeout returns a context carrying the exit timeout.
+func withExitTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, exitTimeoutKey{}, timeout)
+}
+
+// lookupTimeout is the time budget for looking up exit timeout
+// with launchctl in [ShutdownContext].
+const lookupTimeout = time.Second
+
+// ShutdownContext returns a context whose deadline is exit timeout of the
+// job minus [ShutdownMargin], so that it can be passed to functions like
+// [net/http.Server.Shutdown] without the process being killed by launchd
+// while draining connections. If exit timeout cannot be determined, for
+// example if process is not managed by launchd, [DefaultExitTimeout]
+// is used instead.
+//
+// Contexts passed to the service by [Run] carry the exit timeout resolved
+// when the service was started, which is used as is. Otherwise, it is
+// looked up with [ExitTimeout] and time spent doing so is deducted from
+// the deadline.
+//
+// Deadline is relative to the time ShutdownContext is called, thus it
+// should be called when SIGTERM is received. As parent is typically
+// already cancelled at this point, its cancellation is not propagated.
+func ShutdownContext(parent context.Context) (context.Context, context.CancelFunc) {
+	start := time.Now()
+	timeout, ok := parent.Value(exitTimeoutKey{}).(time.Duration)
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), lookupTimeout)
+		var err error
+		timeout, err = ExitTimeout(ctx)
+		cancel()
+		if err != nil {
+			timeout = DefaultExitTimeout
+		}
+	}
+	return context.WithDeadline(context.WithoutCancel(parent), start.Add(shutdownBudget(timeout)))
+}
+
+// shutdownBudget returns the time available for graceful shutdown
+// for the given exit timeout. If exit timeout is too short to subtract
+// [ShutdownMargin], half of it is used instead.
+func shutdownBudget(timeout time.Duration) time.Duration {
+	if timeout <= 2*ShutdownMargin {
+		return timeout / 2
+	}
+	return timeout - ShutdownMargin
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestShutdownBudget(t *testing.T) {
+	tt := []struct {
+		name    string
+		timeout time.Duration
+		expect  time.Duration
+	}{
+		{name: "default", timeout: DefaultExitTimeout, expect: 18 * time.Second},
+		{name: "long", timeout: time.Minute, expect: 58 * time.Second},
+		{name: "short", timeout: 3 * time.Second, expect: 1500 * time.Millisecond},
+		{name: "zero", timeout: 0, expect: 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := shutdownBudget(tc.timeout)
+			if got != tc.expect {
+				t.Errorf("expected=%s, got=%s", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestExitTimeout_NotManaged(t *testing.T) {
+	t.Setenv("XPC_SERVICE_NAME", "0")
+	_, err := ExitTimeout(context.Background())
+	if !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("expected error=%s, got=%s", syscall.ESRCH, err)
+	}
+}
+
+func TestShutdownContext(t *testing.T) {
+	t.Setenv("XPC_SERVICE_NAME", "")
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx, stop := ShutdownContext(parent)
+	defer stop()
+
+	if ctx.Err() != nil {
+		t.Errorf("cancellation of parent must not be propagated")
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatalf("expected context to have a deadline")
+	}
+	remaining := time.Until(deadline)
+	expect := shutdownBudget(DefaultExitTimeout)
+	if remaining > expect || remaining < expect-time.Second {
+		t.Errorf("expected deadline within %s, got=%s", expect, remaining)
+	}
+}
+
+func TestShutdownContext_Resolved(t *testing.T) {
+	// Exit timeout resolved by Run is used even if parent is cancelled.
+	parent, cancel := context.WithCancel(withExitTimeout(context.Background(), time.Minute))
+	cancel()
+
+	ctx, stop := ShutdownContext(parent)
+	defer stop()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatalf("expected context to have a deadline")
+	}
+	remaining := time.Until(deadline)
+	expect := shutdownBudget(time.Minute)
+	if remaining > expect || remaining < expect-time.Second {
+		t.Errorf("expected deadline within %s, got=%s", expect, remaining)
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Event is a lifecycle event of the service.
+type Event int
+
+const (
+	// EventStop indicates that service should stop. This is sent on
+	// SIGTERM, which launchd sends when the job is unloaded or the system
+	// is shutting down, and on SIGINT for interactive use.
+	EventStop Event = iota + 1
+
+	// EventReload indicates that service should reload its configuration.
+	// This is sent on SIGHUP. launchd never sends SIGHUP by itself, but
+	// it is conventionally used to trigger reload, for example with
+	// "launchctl kill SIGHUP <service-target>".
+	EventReload
+)
+
+// String implements [fmt.Stringer].
+func (e Event) String() string {
+	switch e {
+	case EventStop:
+		return "stop"
+	case EventReload:
+		return "reload"
+	default:
+		return fmt.Sprintf("Event(%d)", int(e))
+	}
+}
+
+// eventFor returns the event for the signal.
+func eventFor(sig os.Signal) Event {
+	if sig == syscall.SIGHUP {
+		return EventReload
+	}
+	return EventStop
+}
+
+// NotifyContext is like [signal.NotifyContext], but tuned to launchd
+// semantics. Instead of raw signals, it delivers typed lifecycle events
+// on the returned channel.
+//
+// Returned context is cancelled when the first [EventStop] is received,
+// when the parent context is done or when the returned stop function is
+// called, whichever happens first. [EventReload] does not cancel the
+// context, and can be used to re-read configuration. Reload events which
+// are not received before the next one arrives are coalesced.
+//
+// Event channel is closed after the context is cancelled and signal
+// handling is stopped. Calling stop function releases resources
+// associated with it, thus it should be called as soon as the
+// operations running in the context complete.
+func NotifyContext(parent context.Context) (context.Context, <-chan Event, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt, syscall.SIGHUP)
+
+	events := make(chan Event, 2)
+	go func() {
+		defer close(events)
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-signals:
+				event := eventFor(sig)
+				if event == EventStop {
+					cancel()
+					// Never block on slow receivers, context is already
+					// cancelled. Replace a pending reload event if the
+					// channel is full, as it no longer matters.
+					select {
+					case events <- event:
+					default:
+						select {
+						case <-events:
+						default:
+						}
+						select {
+						case events <- event:
+						default:
+						}
+					}
+					return
+				}
+
+				// Coalesce reload events.
+				select {
+				case events <- event:
+				default:
+				}
+			}
+		}
+	}()
+	return ctx, events, cancel
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package svc_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/svc"
+)
+
+// receive returns the next event or zero if none is received within timeout.
+func receive(events <-chan svc.Event) svc.Event {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		return 0
+	}
+}
+
+func TestNotifyContext(t *testing.T) {
+	ctx, events, stop := svc.NotifyContext(context.Background())
+	defer stop()
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	if e := receive(events); e != svc.EventReload {
+		t.Errorf("expected event=%s, got=%s", svc.EventReload, e)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expected context not to be cancelled on reload")
+	}
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	if e := receive(events); e != svc.EventStop {
+		t.Errorf("expected event=%s, got=%s", svc.EventStop, e)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Errorf("expected context to be cancelled on stop")
+	}
+
+	if _, ok := <-events; ok {
+		t.Errorf("expected event channel to be closed")
+	}
+}
+
+func TestNotifyContext_SlowReceiver(t *testing.T) {
+	ctx, events, stop := svc.NotifyContext(context.Background())
+	defer stop()
+
+	// Fill the event channel with reload events without receiving them.
+	for i := 0; i < 3; i++ {
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("expected context to be cancelled on stop")
+	}
+
+	var last svc.Event
+	for e := range events {
+		last = e
+	}
+	if last != svc.EventStop {
+		t.Errorf("expected last event=%s, got=%s", svc.EventStop, last)
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"syscall"
+)
+
+// Sockets are sockets activated by [Run], keyed by their names
+// in the Sockets dictionary of the job.
+//
+// Files are owned by the runner and are closed after the service
+// has stopped. Listeners and connections built from them are
+// independent of the files and must be closed by the service.
+type Sockets struct {
+	files map[string][]*os.File
+}
+
+// Names returns the names of activated sockets in sorted order.
+func (s *Sockets) Names() []string {
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Files returns files for the socket. Returned files must not be closed.
+//
+//   - [syscall.ENOENT] is returned if socket was not activated.
+func (s *Sockets) Files(name string) ([]*os.File, error) {
+	files, ok := s.files[name]
+	if !ok {
+		return nil, fmt.Errorf("svc: socket(%s) is not activated: %w", name, syscall.ENOENT)
+	}
+	return files, nil
+}
+
+// Listeners returns [net.Listener] for each file of the stream socket.
+//
+// In case of error, a partial list of listeners is returned,
+// along with an error. Caller must close the returned listeners.
+//
+//   - [syscall.ENOENT] is returned if socket was not activated.
+func (s *Sockets) Listeners(name string) ([]net.Listener, error) {
+	files, err := s.Files(name)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(files))
+	for _, f := range files {
+		l, lErr := net.FileListener(f)
+		if lErr != nil {
+			err = errors.Join(err, fmt.Errorf("svc: socket(%s): %w", name, lErr))
+			continue
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, err
+}
+
+// PacketConns returns [net.PacketConn] for each file of the datagram socket.
+//
+// In case of error, a partial list of connections is returned,
+// along with an error. Caller must close the returned connections.
+//
+//   - [syscall.ENOENT] is returned if socket was not activated.
+func (s *Sockets) PacketConns(name string) ([]net.PacketConn, error) {
+	files, err := s.Files(name)
+	if err != nil {
+		return nil, err
+	}
+
+	conns := make([]net.PacketConn, 0, len(files))
+	for _, f := range files {
+		c, cErr := net.FilePacketConn(f)
+		if cErr != nil {
+			err = errors.Join(err, fmt.Errorf("svc: socket(%s): %w", name, cErr))
+			continue
+		}
+		conns = append(conns, c)
+	}
+	return conns, err
+}
+
+// close closes all files.
+func (s *Sockets) close() {
+	for _, files := range s.files {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+// ServeStdio serves the connection passed via stdin and stdout to jobs
+// with inetdCompatibility Wait set to false, by calling handler with it.
+//
+// In this mode launchd accepts the connection and spawns a new instance
+// of the job for each connection, so the process is expected to exit
+// once ServeStdio returns. This allows writing per-connection services
+// as a single function.
+//
+// Once handler returns, write side of the connection is shut down,
+// so that the peer sees EOF even though stdout still refers to the
+// socket, and the connection is closed. Handler may call CloseWrite on
+// the connection if it implements it, to signal EOF to the peer while
+// still reading from it.
+//
+//   - [syscall.ENOTSOCK] is returned if stdin is not a socket.
+//   - [syscall.EINVAL] is returned if stdin is a listening socket.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+//
+// Handler must not read from or write to [os.Stdin] or [os.Stdout]
+// directly, as they refer to the same socket.
+func ServeStdio(handler func(net.Conn)) error {
+	conn, err := launchd.InetdConn()
+	if err != nil {
+		return fmt.Errorf("svc: failed to get inetd connection: %w", err)
+	}
+	serveConn(conn, handler)
+	return nil
+}
+
+// closeWriter is implemented by connections which support half-close,
+// like [*net.TCPConn] and [*net.UnixConn].
+type closeWriter interface {
+	CloseWrite() error
+}
+
+// serveConn calls handler with conn, then shuts down write side of the
+// connection and closes it. Shutting down is required, as closing conn
+// only closes its file descriptor, and the socket remains open via
+// stdout and stderr.
+func serveConn(conn net.Conn, handler func(net.Conn)) {
+	defer conn.Close()
+	handler(conn)
+	if cw, ok := conn.(closeWriter); ok {
+		_ = cw.CloseWrite()
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+func TestServeStdio_NotSocket(t *testing.T) {
+	called := false
+	err := ServeStdio(func(net.Conn) { called = true })
+	if err == nil {
+		t.Errorf("expected error when stdin is not a socket")
+	}
+	if called {
+		t.Errorf("handler must not be called on error")
+	}
+}
+
+func TestServeConn_HalfClose(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer l.Close()
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer client.Close()
+
+	server, err := l.Accept()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err)
+	}
+
+	// Keep a duplicate of the socket open, like stdout of inetd jobs,
+	// so that peer only sees EOF if write side is shut down.
+	// This is not supported on all platforms.
+	if dup, err := server.(*net.TCPConn).File(); err == nil {
+		defer dup.Close()
+	}
+
+	go func() {
+		_, _ = client.Write([]byte("ping"))
+	}()
+
+	serveConn(server, func(c net.Conn) {
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(c, buf); err != nil {
+			t.Errorf("failed to read: %s", err)
+		}
+		_, _ = c.Write([]byte("pong"))
+	})
+
+	got, err := io.ReadAll(client)
+	if err != nil {
+		t.Fatalf("failed to read response: %s", err)
+	}
+	if string(got) != "pong" {
+		t.Errorf("expected=pong, got=%s", got)
+	}
+}
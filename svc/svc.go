@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/tprasadtp/go-launchd"
+)
+
+// DefaultExitTimeout is the default ExitTimeOut of launchd jobs.
+const DefaultExitTimeout = 20 * time.Second
+
+// ErrIdleExit can be returned by [Service.Start] to indicate that the
+// service exited because it was idle. [Run] returns nil in this case,
+// so that the process exits with status 0 and launchd does not
+// consider it as a crash.
+var ErrIdleExit = errors.New("svc: idle exit")
+
+// Service is a service managed by launchd.
+type Service interface {
+	// Start runs the service with activated sockets and blocks until
+	// the service has stopped, either because Stop was called or
+	// because the service has exited on its own, for example when idle.
+	// Context passed to Start is cancelled if the service does not stop
+	// within exit timeout after Stop is called.
+	Start(ctx context.Context, sockets *Sockets) error
+
+	// Stop asks the service to stop and returns once it has stopped.
+	// Context passed to Stop expires when exit timeout is exceeded,
+	// after which launchd sends SIGKILL.
+	Stop(ctx context.Context) error
+}
+
+// SocketActivator is implemented by services which use socket activation.
+type SocketActivator interface {
+	// SocketNames returns names of sockets to be activated before
+	// starting the service. These are keys of the Sockets dictionary
+	// in the plist of the job.
+	SocketNames() []string
+}
+
+// Option configures [Run].
+type Option func(*options)
+
+type options struct {
+	exitTimeout   time.Duration
+	idle          *IdleExiter
+	controlSocket string
+	drainer       *Drainer
+}
+
+// WithExitTimeout sets the time budget for stopping the service.
+// This should be same as ExitTimeOut of the job. Defaults to the value
+// reported by [ExitTimeout], or [DefaultExitTimeout] if it cannot be
+// determined.
+func WithExitTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.exitTimeout = d
+		}
+	}
+}
+
+// WithIdleExiter stops the service gracefully when [IdleExiter]
+// reports that it is idle. [Run] returns nil in this case, unless
+// stopping the service fails.
+func WithIdleExiter(e *IdleExiter) Option {
+	return func(o *options) {
+		o.idle = e
+	}
+}
+
+// WithDrainer drains connections tracked by [Drainer] once [Service.Stop]
+// has returned, i.e. after the service has closed its listeners.
+// Connections which are still open when the shutdown budget, i.e. exit
+// timeout minus [ShutdownMargin], is exhausted are force closed, and
+// [Run] returns the error reported by [Drainer.Drain]. Number of dropped
+// connections is reported by [Drainer.Dropped].
+func WithDrainer(d *Drainer) Option {
+	return func(o *options) {
+		o.drainer = d
+	}
+}
+
+// activate activates sockets declared by the service.
+func activate(s Service) (*Sockets, error) {
+	sockets := &Sockets{files: make(map[string][]*os.File)}
+	activator, ok := s.(SocketActivator)
+	if !ok {
+		return sockets, nil
+	}
+
+	for _, name := range activator.SocketNames() {
+		if _, ok := sockets.files[name]; ok {
+			continue
+		}
+		files, err := launchd.Files(name)
+		if err != nil {
+			sockets.close()
+			return nil, fmt.Errorf("svc: failed to activate socket(%s): %w", name, err)
+		}
+		sockets.files[name] = files
+	}
+	return sockets, nil
+}
+
+// Run runs the service until it stops.
+//
+// Sockets declared by the service via [SocketActivator] are activated
+// and passed to [Service.Start]. When SIGTERM or SIGINT is received or
+// ctx is cancelled, [Service.Stop] is called, with a context which expires
+// after exit timeout (see [WithExitTimeout]). Services which drain
+// connections in Stop can use [ShutdownContext] to leave time to exit.
+//
+// If [WithIdleExiter] is specified, service is also stopped when it is idle.
+// If [WithDrainer] is specified, in-flight connections are drained after
+// [Service.Stop] returns.
+//
+// If service implements [Reloadable], it is reloaded on SIGHUP and on
+// requests received via control socket (see [WithControlSocket]).
+//
+// Run returns nil if service stopped cleanly or exited with [ErrIdleExit].
+// Thus, process should exit with status 0 if Run returns nil and with
+// non-zero status otherwise.
+//
+//   - [context.DeadlineExceeded] is returned if service does not stop
+//     within exit timeout.
+//   - Errors returned by Start and Stop are returned as is.
+//   - Error returned by [Drainer.Drain] is returned if connections
+//     were dropped.
+func Run(ctx context.Context, s Service, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	if o.exitTimeout == 0 {
+		o.exitTimeout = DefaultExitTimeout
+		if timeout, err := ExitTimeout(ctx); err == nil {
+			o.exitTimeout = timeout
+		}
+	}
+
+	sockets, err := activate(s)
+	if err != nil {
+		return err
+	}
+	defer sockets.close()
+
+	reloader, _ := s.(Reloadable)
+	signals := make(chan os.Signal, 1)
+	if reloader != nil {
+		signal.Notify(signals, syscall.SIGTERM, os.Interrupt, syscall.SIGHUP)
+	} else {
+		signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	}
+	defer signal.Stop(signals)
+
+	var requests chan reloadRequest
+	if reloader != nil && o.controlSocket != "" {
+		requests = make(chan reloadRequest)
+		l, err := listenControl(o.controlSocket, requests)
+		if err != nil {
+			return err
+		}
+		defer l.Close()
+	}
+
+	runCtx, cancel := context.WithCancel(withExitTimeout(context.WithoutCancel(ctx), o.exitTimeout))
+	defer cancel()
+
+	var idle <-chan struct{}
+	if o.idle != nil {
+		idle = o.idle.Done()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Start(runCtx, sockets)
+	}()
+
+loop:
+	for {
+		select {
+		case err = <-done:
+			if errors.Is(err, ErrIdleExit) {
+				return nil
+			}
+			return err
+		case sig := <-signals:
+			if eventFor(sig) == EventReload {
+				_ = reload(runCtx, reloader)
+				continue
+			}
+			break loop
+		case req := <-requests:
+			req <- reload(runCtx, reloader)
+		case <-ctx.Done():
+			break loop
+		case <-idle:
+			break loop
+		}
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(withExitTimeout(context.WithoutCancel(ctx), o.exitTimeout), o.exitTimeout)
+	defer stopCancel()
+
+	// Drain budget is counted from the time service is asked to stop,
+	// not from the time Stop returns.
+	drainCtx, drainCancel := context.WithTimeout(stopCtx, shutdownBudget(o.exitTimeout))
+	defer drainCancel()
+
+	stopErr := make(chan error, 1)
+	go func() {
+		stopErr <- s.Stop(stopCtx)
+	}()
+
+	timeout := func() error {
+		cancel()
+		return fmt.Errorf("svc: service did not stop within %s: %w", o.exitTimeout, stopCtx.Err())
+	}
+
+	// Stop may ignore its context, thus waiting for it must also be
+	// bounded by exit timeout.
+	select {
+	case err = <-stopErr:
+	case <-stopCtx.Done():
+		return timeout()
+	}
+
+	// Listeners are closed by Stop, thus no new connections are tracked
+	// by drainer from here on. Drain returns once ctx expires.
+	if o.drainer != nil {
+		_, dErr := o.drainer.Drain(drainCtx)
+		err = errors.Join(err, dErr)
+	}
+
+	select {
+	case sErr := <-done:
+		if errors.Is(sErr, ErrIdleExit) {
+			sErr = nil
+		}
+		return errors.Join(sErr, err)
+	case <-stopCtx.Done():
+		return timeout()
+	}
+}
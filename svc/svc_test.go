@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package svc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/svc"
+)
+
+type service struct {
+	start   func(ctx context.Context, stop <-chan struct{}) error
+	stop    chan struct{}
+	stopErr error
+	names   []string
+}
+
+func (s *service) Start(ctx context.Context, _ *svc.Sockets) error {
+	return s.start(ctx, s.stop)
+}
+
+func (s *service) Stop(_ context.Context) error {
+	close(s.stop)
+	return s.stopErr
+}
+
+type activated struct {
+	service
+}
+
+func (s *activated) SocketNames() []string {
+	return s.names
+}
+
+func TestRun(t *testing.T) {
+	errStart := errors.New("start failed")
+	errStop := errors.New("stop failed")
+	blocking := func(_ context.Context, stop <-chan struct{}) error {
+		<-stop
+		return nil
+	}
+
+	tt := []struct {
+		name    string
+		start   func(ctx context.Context, stop <-chan struct{}) error
+		stopErr error
+		cancel  bool
+		expect  error
+	}{
+		{
+			name:  "exit",
+			start: func(context.Context, <-chan struct{}) error { return nil },
+		},
+		{
+			name:  "idle-exit",
+			start: func(context.Context, <-chan struct{}) error { return svc.ErrIdleExit },
+		},
+		{
+			name:   "start-error",
+			start:  func(context.Context, <-chan struct{}) error { return errStart },
+			expect: errStart,
+		},
+		{
+			name:   "stop",
+			start:  blocking,
+			cancel: true,
+		},
+		{
+			name:    "stop-error",
+			start:   blocking,
+			stopErr: errStop,
+			cancel:  true,
+			expect:  errStop,
+		},
+		{
+			name: "stop-timeout",
+			start: func(ctx context.Context, _ <-chan struct{}) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			cancel: true,
+			expect: context.DeadlineExceeded,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+
+			s := &service{start: tc.start, stop: make(chan struct{}), stopErr: tc.stopErr}
+			err := svc.Run(ctx, s, svc.WithExitTimeout(100*time.Millisecond))
+			if tc.expect == nil && err != nil {
+				t.Errorf("expected no error, got=%s", err)
+			}
+			if tc.expect != nil && !errors.Is(err, tc.expect) {
+				t.Errorf("expected error=%s, got=%s", tc.expect, err)
+			}
+		})
+	}
+}
+
+// stuck is a service whose Stop ignores its context and never returns.
+type stuck struct {
+	stopped chan struct{}
+}
+
+func (s *stuck) Start(_ context.Context, _ *svc.Sockets) error {
+	<-s.stopped
+	return nil
+}
+
+func (s *stuck) Stop(_ context.Context) error {
+	close(s.stopped)
+	select {}
+}
+
+func TestRun_StopIgnoresContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- svc.Run(ctx, &stuck{stopped: make(chan struct{})}, svc.WithExitTimeout(100*time.Millisecond))
+	}()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error=%s, got=%s", context.DeadlineExceeded, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Run did not return after exit timeout")
+	}
+}
+
+func TestRun_SocketActivator(t *testing.T) {
+	s := &activated{
+		service: service{
+			start: func(context.Context, <-chan struct{}) error { return nil },
+			stop:  make(chan struct{}),
+			names: []string{"listener"},
+		},
+	}
+
+	err := svc.Run(context.Background(), s)
+	if err == nil {
+		t.Errorf("expected an error as process is not managed by launchd")
+	}
+}
+
+func TestNotifyContext_Stop(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, events, stop := svc.NotifyContext(parent)
+	defer stop()
+
+	cancel()
+	<-ctx.Done()
+	if _, ok := <-events; ok {
+		t.Errorf("expected event channel to be closed")
+	}
+}
+
+func TestEvent_String(t *testing.T) {
+	tt := map[svc.Event]string{
+		svc.EventStop:   "stop",
+		svc.EventReload: "reload",
+		svc.Event(0):    "Event(0)",
+	}
+	for e, expect := range tt {
+		if e.String() != expect {
+			t.Errorf("expected=%s, got=%s", expect, e)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package svc_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tprasadtp/go-launchd/svc"
+)
+
+func TestRun_Signal(t *testing.T) {
+	s := &service{
+		start: func(_ context.Context, stop <-chan struct{}) error {
+			<-stop
+			return nil
+		},
+		stop: make(chan struct{}),
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	}()
+
+	if err := svc.Run(context.Background(), s); err != nil {
+		t.Errorf("expected no error, got=%s", err)
+	}
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: Copyright 2023 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !(cgo && launchd_cgo)
+
+package launchd
+
+import (
+	"syscall"
+	_ "unsafe" // for go:linkname
+)
+
+// syscall_syscall is implemented in package [runtime] and pushed to [syscall].
+//
+// Go 1.23 introduces limitations on use of linknames([GH-67401]). However,
+// it keeps backward compatibility for [runtime.syscall_syscall] via [ef225d1].
+//
+// [runtime.syscall_syscall]: https://go.googlesource.com/go/+/ef225d1c57a97af984af114ee52005314530bbe2/src/runtime/sys_darwin.go#23
+// [ef225d1]: https://go.googlesource.com/go/+/ef225d1c57a97af984af114ee52005314530bbe2
+// [GH-67401]: https://github.com/golang/go/issues/67401
+//
+//go:linkname syscall_syscall syscall.syscall
+//nolint:revive // for linkname
+func syscall_syscall(fn, a1, a2, a3 uintptr) (r1, r2 uintptr, err syscall.Errno)
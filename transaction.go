@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"sync"
+)
+
+// Transaction is an open os_transaction, which marks in-flight work.
+//
+// launchd may terminate jobs with EnableTransactions when they are idle,
+// i.e. when they have no open transactions, by sending SIGKILL instead of
+// SIGTERM. Open transactions defer termination until they are ended,
+// thus jobs can avoid being killed while performing critical work like
+// writing to a file.
+type Transaction struct {
+	once   sync.Once
+	name   []byte  // keeps description alive for the lifetime of transaction.
+	cname  uintptr // C copy of description, used by cgo backend.
+	handle uintptr
+}
+
+// BeginTransaction begins a new transaction with the given description.
+// Transaction must be ended with [EndTransaction] when work is done,
+// otherwise the job will never be considered idle.
+//
+// Transactions are only tracked for jobs with EnableTransactions
+// set in their plist. Otherwise, they have no effect.
+//
+//   - [syscall.EINVAL] is returned if description contains NUL byte.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func BeginTransaction(description string) (*Transaction, error) {
+	return beginTransaction(description)
+}
+
+// EndTransaction ends the transaction. It is safe to call this
+// more than once and with nil transaction.
+func EndTransaction(t *Transaction) {
+	if t == nil {
+		return
+	}
+	t.once.Do(func() {
+		endTransaction(t)
+	})
+}
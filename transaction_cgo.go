@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && cgo && launchd_cgo
+
+package launchd
+
+/*
+#include <stdlib.h>
+
+// os_transaction_create and os_release are exported by libSystem,
+// but os_transaction_create is not declared in public headers.
+extern void *os_transaction_create(const char *description);
+extern void os_release(void *object);
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Os specific implementation of [BeginTransaction].
+func beginTransaction(description string) (*Transaction, error) {
+	if strings.IndexByte(description, 0) >= 0 {
+		return nil, fmt.Errorf("launchd: invalid transaction description: %w", syscall.EINVAL)
+	}
+
+	// Description is kept alive for the lifetime of transaction.
+	cname := C.CString(description)
+	handle := C.os_transaction_create(cname)
+	if handle == nil {
+		C.free(unsafe.Pointer(cname))
+		return nil, fmt.Errorf("launchd: os_transaction_create returned NULL: %w", syscall.ENOMEM)
+	}
+
+	return &Transaction{
+		cname:  uintptr(unsafe.Pointer(cname)),
+		handle: uintptr(handle),
+	}, nil
+}
+
+// Os specific implementation of [EndTransaction].
+func endTransaction(t *Transaction) {
+	if t.handle == 0 {
+		return
+	}
+
+	C.os_release(unsafe.Pointer(t.handle)) //nolint:govet // handle is not managed by go runtime.
+	C.free(unsafe.Pointer(t.cname))        //nolint:govet // cname is not managed by go runtime.
+	t.handle = 0
+	t.cname = 0
+}
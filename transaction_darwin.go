@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !(cgo && launchd_cgo)
+
+package launchd
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+//go:cgo_import_dynamic libc_os_transaction_create os_transaction_create "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_os_transaction_create_addr uintptr
+
+//go:cgo_import_dynamic libc_os_release os_release "/usr/lib/libSystem.B.dylib"
+//nolint:revive,stylecheck,gochecknoglobals // ignore
+var libc_trampoline_os_release_addr uintptr
+
+// Os specific implementation of [BeginTransaction].
+func beginTransaction(description string) (*Transaction, error) {
+	name, err := syscall.ByteSliceFromString(description)
+	if err != nil {
+		return nil, fmt.Errorf("launchd: invalid transaction description: %w", syscall.EINVAL)
+	}
+
+	t := &Transaction{name: name}
+
+	// Call libc function, os_transaction_create.
+	//
+	// os_transaction_t os_transaction_create(const char *description);
+	//
+	// Returned transaction is an os_object, which is released with os_release,
+	// ending the transaction.
+	var pinner runtime.Pinner
+	pinner.Pin(&t.name[0])
+	defer pinner.Unpin()
+
+	r1, _, e1 := syscall_syscall(
+		libc_trampoline_os_transaction_create_addr,
+		uintptr(unsafe.Pointer(&t.name[0])),
+		0,
+		0,
+	)
+
+	if e1 != 0 {
+		return nil, fmt.Errorf("launchd: error calling os_transaction_create: %w", e1)
+	}
+
+	if r1 == 0 {
+		return nil, fmt.Errorf("launchd: os_transaction_create returned NULL: %w", syscall.ENOMEM)
+	}
+
+	t.handle = r1
+	return t, nil
+}
+
+// Os specific implementation of [EndTransaction].
+func endTransaction(t *Transaction) {
+	if t.handle == 0 {
+		return
+	}
+
+	// void os_release(void *object);
+	_, _, _ = syscall_syscall(libc_trampoline_os_release_addr, t.handle, 0, 0)
+	t.handle = 0
+	runtime.KeepAlive(t.name)
+}
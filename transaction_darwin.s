@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !(cgo && launchd_cgo)
+
+#include "textflag.h"
+
+GLOBL	·libc_trampoline_os_transaction_create_addr(SB), RODATA, $8
+DATA	·libc_trampoline_os_transaction_create_addr(SB)/8, $libc_trampoline_os_transaction_create<>(SB)
+TEXT    libc_trampoline_os_transaction_create<>(SB),NOSPLIT,$0-0
+	        JMP	libc_os_transaction_create(SB)
+
+GLOBL	·libc_trampoline_os_release_addr(SB), RODATA, $8
+DATA	·libc_trampoline_os_release_addr(SB)/8, $libc_trampoline_os_release<>(SB)
+TEXT    libc_trampoline_os_release<>(SB),NOSPLIT,$0-0
+	        JMP	libc_os_release(SB)
@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Os specific implementation of [BeginTransaction].
+func beginTransaction(_ string) (*Transaction, error) {
+	return nil, fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
+
+// Os specific implementation of [EndTransaction].
+func endTransaction(_ *Transaction) {}
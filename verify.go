@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+package launchd
+
+import (
+	"errors"
+	"net"
+)
+
+// ErrPeerRequirement is returned by [VerifyPeer] when code signature of
+// the peer process does not satisfy the requirement.
+var ErrPeerRequirement = errors.New("launchd: peer does not satisfy code signing requirement")
+
+// VerifyPeer checks if code signature of the peer process of a unix socket
+// connection is valid and satisfies the designated requirement, in the
+// code signing requirement language, for example,
+//
+//	identifier "com.example.app" and anchor apple generic and certificate leaf[subject.OU] = "TEAMID"
+//
+// Peer process is identified by its audit token, which unlike its process ID,
+// cannot be reused by a different process. This is useful for helper daemons
+// to only accept connections from their own app.
+//
+//   - [ErrPeerRequirement] is returned if peer does not satisfy the requirement,
+//     or if it is not signed.
+//   - [syscall.EINVAL] is returned if requirement is invalid.
+//   - [syscall.EAFNOSUPPORT] is returned if conn is not a unix socket connection.
+//   - [syscall.ENOTSUP] is returned on non-macOS platforms (including iOS).
+func VerifyPeer(conn net.Conn, requirement string) error {
+	return verifyPeer(conn, requirement)
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && cgo && launchd_cgo
+
+package launchd
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework Security
+#include <stdlib.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <Security/Security.h>
+
+// launchd_guest_code returns SecCodeRef of the process identified by the
+// audit token in *code. Caller must release it with CFRelease.
+static OSStatus launchd_guest_code(audit_token_t *token, SecCodeRef *code) {
+	CFDataRef data = CFDataCreate(NULL, (const UInt8 *)token, sizeof(audit_token_t));
+	if (data == NULL) {
+		return errSecAllocate;
+	}
+	const void *keys[] = {kSecGuestAttributeAudit};
+	const void *values[] = {data};
+	CFDictionaryRef attrs = CFDictionaryCreate(NULL, keys, values, 1,
+		&kCFTypeDictionaryKeyCallBacks, &kCFTypeDictionaryValueCallBacks);
+	CFRelease(data);
+	if (attrs == NULL) {
+		return errSecAllocate;
+	}
+	OSStatus status = SecCodeCopyGuestWithAttributes(NULL, attrs, kSecCSDefaultFlags, code);
+	CFRelease(attrs);
+	return status;
+}
+
+// launchd_check_requirement checks if code signature of the process
+// identified by the audit token satisfies the requirement.
+static OSStatus launchd_check_requirement(audit_token_t *token, const char *requirement) {
+	CFStringRef text = CFStringCreateWithCString(NULL, requirement, kCFStringEncodingUTF8);
+	if (text == NULL) {
+		return errSecCSReqInvalid;
+	}
+	SecRequirementRef req = NULL;
+	OSStatus status = SecRequirementCreateWithString(text, kSecCSDefaultFlags, &req);
+	CFRelease(text);
+	if (status != errSecSuccess) {
+		return status;
+	}
+	SecCodeRef code = NULL;
+	status = launchd_guest_code(token, &code);
+	if (status == errSecSuccess) {
+		status = SecCodeCheckValidity(code, kSecCSDefaultFlags, req);
+		CFRelease(code);
+	}
+	CFRelease(req);
+	return status;
+}
+
+// launchd_signing_identifier returns code signing identifier of the process
+// identified by the audit token in *id. Caller must free it.
+static OSStatus launchd_signing_identifier(audit_token_t *token, char **id) {
+	SecCodeRef code = NULL;
+	OSStatus status = launchd_guest_code(token, &code);
+	if (status != errSecSuccess) {
+		return status;
+	}
+	CFDictionaryRef info = NULL;
+	status = SecCodeCopySigningInformation((SecStaticCodeRef)code, kSecCSSigningInformation, &info);
+	CFRelease(code);
+	if (status != errSecSuccess) {
+		return status;
+	}
+
+	// Value is owned by the dictionary, thus must not be released.
+	CFStringRef value = CFDictionaryGetValue(info, kSecCodeInfoIdentifier);
+	if (value == NULL) {
+		CFRelease(info);
+		return errSecCSUnsigned;
+	}
+	CFIndex size = CFStringGetMaximumSizeForEncoding(CFStringGetLength(value), kCFStringEncodingUTF8) + 1;
+	*id = malloc(size);
+	if (*id == NULL || !CFStringGetCString(value, *id, size, kCFStringEncodingUTF8)) {
+		free(*id);
+		*id = NULL;
+		status = errSecAllocate;
+	}
+	CFRelease(info);
+	return status;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// osStatus is an error code returned by macOS frameworks.
+type osStatus int32
+
+// Error implements error interface.
+func (s osStatus) Error() string {
+	return fmt.Sprintf("OSStatus(%d)", int32(s))
+}
+
+// Code signing errors from CSCommon.h.
+const (
+	errSecCSReqFailed    osStatus = -67050
+	errSecCSReqInvalid   osStatus = -67052
+	errSecCSUnsigned     osStatus = -67062
+	errSecCSGuestInvalid osStatus = -67063
+	errSecCSNoSuchCode   osStatus = -67065
+)
+
+// Os specific implementation of [VerifyPeer].
+//
+// Unlike the default implementation, this calls Security framework via cgo.
+// See verify_darwin.go for details.
+func verifyPeer(conn net.Conn, requirement string) error {
+	token, err := peerAuditToken(conn)
+	if err != nil {
+		return err
+	}
+
+	if strings.IndexByte(requirement, 0) >= 0 {
+		return fmt.Errorf("launchd: invalid requirement(%q): %w", requirement, syscall.EINVAL)
+	}
+	creq := C.CString(requirement)
+	defer C.free(unsafe.Pointer(creq))
+
+	ctoken := C.audit_token_t{val: *(*[8]C.uint)(unsafe.Pointer(&token))}
+	err = statusError(C.launchd_check_requirement(&ctoken, creq))
+	var status osStatus
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &status) && (status == errSecCSReqFailed || status == errSecCSUnsigned):
+		return fmt.Errorf("%w: %w", ErrPeerRequirement, err)
+	case errors.As(err, &status) && status == errSecCSReqInvalid:
+		return fmt.Errorf("launchd: invalid requirement(%q): %w", requirement, syscall.EINVAL)
+	default:
+		return fmt.Errorf("launchd: error verifying peer: %w", err)
+	}
+}
+
+// Os specific implementation of [AuditToken.SigningIdentifier].
+func signingIdentifier(t AuditToken) (string, error) {
+	var cid *C.char
+	ctoken := C.audit_token_t{val: *(*[8]C.uint)(unsafe.Pointer(&t))}
+	err := statusError(C.launchd_signing_identifier(&ctoken, &cid))
+	var status osStatus
+	switch {
+	case err == nil:
+		defer C.free(unsafe.Pointer(cid))
+		return C.GoString(cid), nil
+	case errors.As(err, &status) && status == errSecCSUnsigned:
+		return "", fmt.Errorf("%w: %w", ErrPeerRequirement, err)
+	case errors.As(err, &status) && (status == errSecCSNoSuchCode || status == errSecCSGuestInvalid):
+		return "", fmt.Errorf("launchd: process(%d) not found: %w", t.PID(), syscall.ESRCH)
+	default:
+		return "", fmt.Errorf("launchd: error getting signing identifier: %w", err)
+	}
+}
+
+// statusError returns nil if status is errSecSuccess, or [osStatus] otherwise.
+func statusError(status C.OSStatus) error {
+	if status != 0 {
+		return osStatus(status)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !(cgo && launchd_cgo)
+
+package launchd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// Os specific implementation of [VerifyPeer].
+func verifyPeer(conn net.Conn, requirement string) error {
+	token, err := peerAuditToken(conn)
+	if err != nil {
+		return err
+	}
+
+	err = macos.CheckCodeRequirement(macos.AuditToken(token), requirement)
+	var status macos.OSStatus
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &status) && (status == macos.ErrSecCSReqFailed || status == macos.ErrSecCSUnsigned):
+		return fmt.Errorf("%w: %w", ErrPeerRequirement, err)
+	case errors.As(err, &status) && status == macos.ErrSecCSReqInvalid:
+		return fmt.Errorf("launchd: invalid requirement(%q): %w", requirement, syscall.EINVAL)
+	default:
+		return fmt.Errorf("launchd: error verifying peer: %w", err)
+	}
+}
+
+// Os specific implementation of [AuditToken.SigningIdentifier].
+func signingIdentifier(t AuditToken) (string, error) {
+	id, err := macos.SigningIdentifier(macos.AuditToken(t))
+	var status macos.OSStatus
+	switch {
+	case err == nil:
+		return id, nil
+	case errors.As(err, &status) && status == macos.ErrSecCSUnsigned:
+		return "", fmt.Errorf("%w: %w", ErrPeerRequirement, err)
+	case errors.As(err, &status) && (status == macos.ErrSecCSNoSuchCode || status == macos.ErrSecCSGuestInvalid):
+		return "", fmt.Errorf("launchd: process(%d) not found: %w", t.PID(), syscall.ESRCH)
+	default:
+		return "", fmt.Errorf("launchd: error getting signing identifier: %w", err)
+	}
+}
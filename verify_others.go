@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin || ios
+
+package launchd
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// Os specific implementation of [VerifyPeer].
+func verifyPeer(_ net.Conn, _ string) error {
+	return fmt.Errorf("launchd: only supported on macOS: %w", syscall.ENOTSUP)
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && cgo && launchd_cgo
+
+package launchd
+
+/*
+#include <sys/types.h>
+#include <sys/socket.h>
+#include <sys/un.h>
+#include <sys/ucred.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Socket options for unix domain sockets from sys/un.h.
+const (
+	solLocal       = C.SOL_LOCAL
+	localPeerPID   = C.LOCAL_PEERPID
+	localPeerToken = C.LOCAL_PEERTOKEN
+)
+
+// peerXucred sets user and group IDs of creds from LOCAL_PEERCRED
+// of the unix socket fd.
+//
+// Unlike the default implementation, struct xucred is from sys/ucred.h.
+func peerXucred(fd uintptr, creds *Creds) error {
+	var cred C.struct_xucred
+	err := getsockopt(fd, C.SOL_LOCAL, C.LOCAL_PEERCRED, unsafe.Pointer(&cred), uint32(unsafe.Sizeof(cred)))
+	if err != nil {
+		return fmt.Errorf("launchd: error getting peer credentials: %w", os.NewSyscallError("getsockopt", err))
+	}
+	if cred.cr_version != C.XUCRED_VERSION {
+		return fmt.Errorf("launchd: unsupported xucred version(%d): %w", uint32(cred.cr_version), syscall.ENOTSUP)
+	}
+
+	creds.UID = uint32(cred.cr_uid)
+	creds.EUID = uint32(cred.cr_uid)
+	n := min(max(int(cred.cr_ngroups), 0), len(cred.cr_groups))
+	for _, gid := range cred.cr_groups[:n] {
+		creds.Groups = append(creds.Groups, uint32(gid))
+	}
+	if n > 0 {
+		creds.GID = creds.Groups[0]
+	}
+	return nil
+}
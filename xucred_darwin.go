@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright 2024 Prasad Tengse
+// SPDX-License-Identifier: MIT
+
+//go:build darwin && !ios && !(cgo && launchd_cgo)
+
+package launchd
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/tprasadtp/go-launchd/internal/macos"
+)
+
+// Socket options for unix domain sockets from sys/un.h.
+const (
+	solLocal       = macos.SolLocal
+	localPeerPID   = macos.LocalPeerPID
+	localPeerToken = macos.LocalPeerToken
+)
+
+// peerXucred sets user and group IDs of creds from LOCAL_PEERCRED
+// of the unix socket fd.
+func peerXucred(fd uintptr, creds *Creds) error {
+	var cred macos.Xucred
+	err := getsockopt(fd, macos.SolLocal, macos.LocalPeerCred, unsafe.Pointer(&cred), uint32(unsafe.Sizeof(cred)))
+	if err != nil {
+		return fmt.Errorf("launchd: error getting peer credentials: %w", os.NewSyscallError("getsockopt", err))
+	}
+	if cred.Version != macos.XucredVersion {
+		return fmt.Errorf("launchd: unsupported xucred version(%d): %w", cred.Version, syscall.ENOTSUP)
+	}
+
+	creds.UID = cred.UID
+	creds.EUID = cred.UID
+	n := min(max(int(cred.NGroups), 0), len(cred.Groups))
+	if n > 0 {
+		creds.GID = cred.Groups[0]
+		creds.Groups = append([]uint32(nil), cred.Groups[:n]...)
+	}
+	return nil
+}